@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
 	"os"
 	"time"
@@ -10,6 +11,7 @@ import (
 	"github.com/kyma-project/kyma-environment-broker/internal/cis"
 	"github.com/kyma-project/kyma-environment-broker/internal/events"
 	"github.com/kyma-project/kyma-environment-broker/internal/storage"
+	"github.com/kyma-project/kyma-environment-broker/internal/tracing"
 	"github.com/vrischmann/envconfig"
 )
 
@@ -17,6 +19,7 @@ type Config struct {
 	CIS      cis.Config
 	Database storage.Config
 	Broker   broker.ClientConfig
+	Tracing  tracing.Config
 }
 
 func main() {
@@ -36,6 +39,10 @@ func main() {
 	var cfg Config
 	err := envconfig.InitWithPrefix(&cfg, "APP")
 	fatalOnError(err)
+	fatalOnError(cfg.Tracing.Validate())
+
+	shutdownTracing, err := tracing.Init(ctx, cfg.Tracing, "")
+	fatalOnError(err)
 
 	// create CIS client
 	client := cis.NewClient(ctx, cfg.CIS, logger.With("client", "CIS-v2"))
@@ -52,7 +59,11 @@ func main() {
 
 	// create SubAccountCleanerService and execute process
 	sacs := cis.NewSubAccountCleanupService(client, brokerClient, db.Instances(), logger)
-	fatalOnError(sacs.Run())
+	err = sacs.Run(ctx)
+	if shutdownErr := shutdownTracing(context.Background()); shutdownErr != nil {
+		slog.Warn(fmt.Sprintf("while flushing traces: %s", shutdownErr))
+	}
+	fatalOnError(err)
 }
 
 func fatalOnError(err error) {
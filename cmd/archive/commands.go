@@ -0,0 +1,141 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/kyma-project/kyma-environment-broker/internal/archive"
+	"github.com/spf13/cobra"
+)
+
+func NewGetCmd() *cobra.Command {
+	var output string
+	cobraCmd := &cobra.Command{
+		Use:   "get INSTANCE_ID",
+		Short: "Prints an archived instance with its operations timeline rebuilt from events.",
+		Example: `
+	# Print the archived instance
+	kebarchive get 8a7bfd9b-f2f5-43d1-bb67-177d2434053c`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := loadConfig()
+			if err != nil {
+				return err
+			}
+			inspector, closeDB, err := newInspector(cfg)
+			if err != nil {
+				return err
+			}
+			defer closeDB()
+
+			details, err := inspector.Get(args[0])
+			if err != nil {
+				return fmt.Errorf("while getting archived instance %s: %w", args[0], err)
+			}
+			return writeJSON(cmd.OutOrStdout(), output, details)
+		},
+	}
+	cobraCmd.Flags().StringVarP(&output, "output", "o", "", "Write the result to the file instead of the standard output.")
+	return cobraCmd
+}
+
+func NewExportCmd() *cobra.Command {
+	var (
+		filter archive.BundleFilter
+		reason string
+		output string
+	)
+	cobraCmd := &cobra.Command{
+		Use:   "export",
+		Short: "Exports archived instances matching the filters as a signed JSON bundle.",
+		Example: `
+	# Export all archived instances of the subaccount for the legal hold
+	kebarchive export --subaccount 5a1d2f34-0c3b-4a59-9a0e-2a7c1f4e8b21 --reason "legal hold 2026-17" -o bundle.json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if filter.IsEmpty() {
+				return errors.New("at least one filter is required")
+			}
+			cfg, err := loadConfig()
+			if err != nil {
+				return err
+			}
+			inspector, closeDB, err := newInspector(cfg)
+			if err != nil {
+				return err
+			}
+			defer closeDB()
+
+			bundle, err := inspector.Export(filter, reason, cfg.Archive)
+			if err != nil {
+				return fmt.Errorf("while exporting archived instances: %w", err)
+			}
+			return writeJSON(cmd.OutOrStdout(), output, bundle)
+		},
+	}
+	flags := cobraCmd.Flags()
+	flags.StringSliceVar(&filter.GlobalAccountIDs, "account", nil, "Filter by global account IDs.")
+	flags.StringSliceVar(&filter.SubAccountIDs, "subaccount", nil, "Filter by subaccount IDs.")
+	flags.StringSliceVar(&filter.InstanceIDs, "instance-id", nil, "Filter by instance IDs.")
+	flags.StringSliceVar(&filter.RuntimeIDs, "runtime-id", nil, "Filter by the last runtime IDs.")
+	flags.StringSliceVar(&filter.Regions, "region", nil, "Filter by regions.")
+	flags.StringSliceVar(&filter.Plans, "plan", nil, "Filter by plan names.")
+	flags.StringSliceVar(&filter.Shoots, "shoot", nil, "Filter by shoot names.")
+	flags.StringVar(&reason, "reason", "", "Reason of the export stored in the bundle.")
+	flags.StringVarP(&output, "output", "o", "", "Write the bundle to the file instead of the standard output.")
+	return cobraCmd
+}
+
+func NewVerifyCmd() *cobra.Command {
+	var file, publicKeyFile string
+	cobraCmd := &cobra.Command{
+		Use:   "verify",
+		Short: "Verifies the signature of an exported bundle with the public key.",
+		Example: `
+	# Verify the bundle with the public key of the bundle signing key
+	kebarchive verify -f bundle.json --public-key signing-key.pub.pem`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			keyData, err := os.ReadFile(publicKeyFile)
+			if err != nil {
+				return fmt.Errorf("while reading public key: %w", err)
+			}
+			publicKey, err := archive.ParseVerificationKey(keyData)
+			if err != nil {
+				return err
+			}
+			data, err := os.ReadFile(file)
+			if err != nil {
+				return fmt.Errorf("while reading bundle: %w", err)
+			}
+			var bundle archive.Bundle
+			if err := json.Unmarshal(data, &bundle); err != nil {
+				return fmt.Errorf("while decoding bundle: %w", err)
+			}
+			if err := archive.VerifyBundle(bundle, publicKey); err != nil {
+				return err
+			}
+			cmd.Printf("The bundle signature is valid, the bundle contains %d archived instances exported at %s.\n", len(bundle.Instances), bundle.ExportedAt)
+			return nil
+		},
+	}
+	cobraCmd.Flags().StringVarP(&file, "file", "f", "", "The bundle file.")
+	cobraCmd.Flags().StringVar(&publicKeyFile, "public-key", "", "The PEM encoded Ed25519 public key of the bundle signing key.")
+	_ = cobraCmd.MarkFlagRequired("file")
+	_ = cobraCmd.MarkFlagRequired("public-key")
+	return cobraCmd
+}
+
+func writeJSON(stdout io.Writer, output string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("while encoding result: %w", err)
+	}
+	data = append(data, '\n')
+	if output == "" {
+		_, err = stdout.Write(data)
+		return err
+	}
+	return os.WriteFile(output, data, 0o600)
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/kyma-project/kyma-environment-broker/internal/archive"
+	"github.com/kyma-project/kyma-environment-broker/internal/events"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage"
+	"github.com/spf13/cobra"
+	"github.com/vrischmann/envconfig"
+)
+
+var gitCommit string
+
+type Config struct {
+	Database storage.Config
+	Archive  archive.Config
+}
+
+func main() {
+	// logs go to stderr, so the output can be redirected to a file
+	slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, nil)))
+
+	rootCmd := &cobra.Command{
+		Use:   "kebarchive",
+		Short: "A tool for inspecting archived Kyma instances",
+		Long: `A tool for inspecting archived Kyma instances. The database connection and the bundle signing key
+are configured with the APP_DATABASE_* and APP_ARCHIVE_* environment variables, the same as for KEB.
+Bundles are verified with the public key only.`,
+		Version:       gitCommit,
+		SilenceErrors: true,
+		SilenceUsage:  true,
+	}
+	rootCmd.AddCommand(NewGetCmd(), NewExportCmd(), NewVerifyCmd())
+
+	if err := rootCmd.Execute(); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+func loadConfig() (Config, error) {
+	var cfg Config
+	if err := envconfig.InitWithPrefix(&cfg, "APP"); err != nil {
+		return Config{}, fmt.Errorf("while loading configuration: %w", err)
+	}
+	return cfg, nil
+}
+
+// newInspector connects to the KEB database, the returned function closes the connection
+func newInspector(cfg Config) (*archive.Inspector, func(), error) {
+	cipher := storage.NewEncrypter(cfg.Database.SecretKey)
+	db, conn, err := storage.NewFromConfig(cfg.Database, events.Config{}, cipher)
+	if err != nil {
+		return nil, nil, fmt.Errorf("while connecting to the database: %w", err)
+	}
+	return archive.NewInspector(db), func() { _ = conn.Close() }, nil
+}
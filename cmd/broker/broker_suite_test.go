@@ -32,6 +32,7 @@ import (
 	kcMock "github.com/kyma-project/kyma-environment-broker/internal/kubeconfig/automock"
 	"github.com/kyma-project/kyma-environment-broker/internal/metrics"
 	"github.com/kyma-project/kyma-environment-broker/internal/process"
+	"github.com/kyma-project/kyma-environment-broker/internal/process/provisioning"
 	"github.com/kyma-project/kyma-environment-broker/internal/process/steps"
 	"github.com/kyma-project/kyma-environment-broker/internal/provider/configuration"
 	kebRuntime "github.com/kyma-project/kyma-environment-broker/internal/runtime"
@@ -219,21 +220,21 @@ func newBrokerSuiteTest(t *testing.T, o *suiteOptions) *BrokerSuiteTest {
 
 	provisioningQueue := NewProvisioningProcessingQueue(context.Background(), provisionManager, workersAmount, cfg, db, configProvider,
 		k8sClientProvider, cli, gardenerClientWithNamespace, defaultOIDCValues(), log, rulesService,
-		workersProvider(cfg.InfrastructureManager, providerSpec), providerSpec, factory, nil)
+		workersProvider(cfg.InfrastructureManager, providerSpec), providerSpec, factory, nil, nil)
 
 	provisioningQueue.SpeedUp(testSuiteSpeedUpFactor)
 	provisionManager.SpeedUp(testSuiteSpeedUpFactor)
 
 	updateManager := process.NewStagedManager(db.Operations(), eventBroker, time.Hour, cfg.Update, log.With("update", "manager"))
 	updateQueue := NewUpdateProcessingQueue(context.Background(), updateManager, 1, db, *cfg, cli, log, workersProvider(cfg.InfrastructureManager, providerSpec),
-		schemaService, plansSpec, configProvider, providerSpec, gardenerClientWithNamespace, factory, nil)
+		schemaService, plansSpec, configProvider, providerSpec, gardenerClientWithNamespace, factory, nil, nil)
 	updateQueue.SpeedUp(testSuiteSpeedUpFactor)
 	updateManager.SpeedUp(testSuiteSpeedUpFactor)
 
 	deprovisionManager := process.NewStagedManager(db.Operations(), eventBroker, time.Hour, cfg.Deprovisioning, log.With("deprovisioning", "manager"))
 
 	deprovisioningQueue := NewDeprovisioningProcessingQueue(ctx, workersAmount, deprovisionManager, cfg, db,
-		k8sClientProvider, cli, configProvider, gardenerClient, "kyma", log)
+		k8sClientProvider, cli, configProvider, gardenerClient, "kyma", log, nil)
 	deprovisionManager.SpeedUp(testSuiteSpeedUpFactor)
 
 	deprovisioningQueue.SpeedUp(testSuiteSpeedUpFactor)
@@ -256,7 +257,11 @@ func newBrokerSuiteTest(t *testing.T, o *suiteOptions) *BrokerSuiteTest {
 
 	ts.CreateAPI(cfg, db, provisioningQueue, deprovisioningQueue, updateQueue, log, k8sClientProvider, eventBroker, configProvider, plansSpec, rulesService, gardenerClientWithNamespace, factory)
 
-	expirationHandler := expiration.NewHandler(db.Instances(), db.Operations(), deprovisioningQueue, log)
+	expirationHandler := expiration.NewHandler(db.Instances(), db.Operations(), db.Actions(), deprovisioningQueue, expiration.ExtensionConfig{
+		Period:            cfg.Broker.ExpirationExtensionPeriod,
+		MaxExtensions:     cfg.Broker.MaxExpirationExtensions,
+		ExpirationPeriods: broker.ExpirationPeriods(cfg.Broker),
+	}, log)
 	expirationHandler.AttachRoutes(ts.router)
 
 	runtimeHandler := kebRuntime.NewHandler(db, cfg.MaxPaginationPage, cfg.Broker.DefaultRequestRegion, cli, log)
@@ -514,9 +519,12 @@ func (s *BrokerSuiteTest) CreateAPI(cfg *Config, db storage.BrokerStorage, provi
 	fatalOnError(err, log)
 	schemaService := broker.NewSchemaService(providerSpec, planSpec, &defaultOIDC, cfg.Broker, cfg.InfrastructureManager.IngressFilteringPlans, channelResolver, s.kcrVolumeProvider)
 
+	runtimeResourceRenderer := provisioning.NewCreateRuntimeResourceStep(db, fakeKcpK8sClient, cfg.InfrastructureManager, defaultOIDC,
+		workersProvider(cfg.InfrastructureManager, providerSpec), providerSpec, cfg.GlobalAccounts(), nil, cfg.Broker.AuditLogAccess)
+
 	createAPI(s.router, schemaService, servicesConfig, cfg, db, provisioningQueue, deprovisionQueue, updateQueue,
 		log, kcBuilder, skrK8sClientProvider, skrK8sClientProvider, fakeKcpK8sClient, eventBroker,
-		providerSpec, configProvider, planSpec, rulesService, gardenerClient, factory)
+		providerSpec, configProvider, planSpec, rulesService, gardenerClient, factory, runtimeResourceRenderer, nil)
 
 	s.httpServer = httptest.NewServer(s.router)
 }
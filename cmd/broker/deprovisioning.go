@@ -17,7 +17,7 @@ import (
 
 func NewDeprovisioningProcessingQueue(ctx context.Context, workersAmount int, deprovisionManager *process.StagedManager,
 	cfg *Config, db storage.BrokerStorage,
-	k8sClientProvider K8sClientProvider, kcpClient client.Client, configProvider config.Provider, gardenerClient dynamic.Interface, gardenerNamespace string, logs *slog.Logger) *process.Queue {
+	k8sClientProvider K8sClientProvider, kcpClient client.Client, configProvider config.Provider, gardenerClient dynamic.Interface, gardenerNamespace string, logs *slog.Logger, leaser *process.OperationLeaser) *process.Queue {
 
 	deprovisioningSteps := []struct {
 		disabled bool
@@ -68,7 +68,7 @@ func NewDeprovisioningProcessingQueue(ctx context.Context, workersAmount int, de
 		}
 	}
 
-	queue := process.NewQueue(deprovisionManager, logs, "deprovisioning")
+	queue := process.NewQueueWithClassifier(leaser.Executor(deprovisionManager), logs, "deprovisioning", process.NewOperationClassifier(db.Operations(), isLowCostPlan))
 	queue.Run(ctx.Done(), workersAmount)
 
 	return queue
@@ -19,6 +19,7 @@ import (
 	pkg "github.com/kyma-project/kyma-environment-broker/common/runtime"
 	"github.com/kyma-project/kyma-environment-broker/internal"
 	"github.com/kyma-project/kyma-environment-broker/internal/additionalproperties"
+	"github.com/kyma-project/kyma-environment-broker/internal/archive"
 	"github.com/kyma-project/kyma-environment-broker/internal/blocklist"
 	"github.com/kyma-project/kyma-environment-broker/internal/broker"
 	brokerBindings "github.com/kyma-project/kyma-environment-broker/internal/broker/bindings"
@@ -27,6 +28,7 @@ import (
 	"github.com/kyma-project/kyma-environment-broker/internal/event"
 	"github.com/kyma-project/kyma-environment-broker/internal/events"
 	eventshandler "github.com/kyma-project/kyma-environment-broker/internal/events/handler"
+	"github.com/kyma-project/kyma-environment-broker/internal/events/stream"
 	"github.com/kyma-project/kyma-environment-broker/internal/expiration"
 	"github.com/kyma-project/kyma-environment-broker/internal/health"
 	"github.com/kyma-project/kyma-environment-broker/internal/httputil"
@@ -35,7 +37,9 @@ import (
 	"github.com/kyma-project/kyma-environment-broker/internal/kubeconfig"
 	"github.com/kyma-project/kyma-environment-broker/internal/machinesavailability"
 	"github.com/kyma-project/kyma-environment-broker/internal/metrics"
+	"github.com/kyma-project/kyma-environment-broker/internal/operations"
 	"github.com/kyma-project/kyma-environment-broker/internal/process"
+	"github.com/kyma-project/kyma-environment-broker/internal/process/provisioning"
 	"github.com/kyma-project/kyma-environment-broker/internal/provider"
 	"github.com/kyma-project/kyma-environment-broker/internal/provider/configuration"
 	"github.com/kyma-project/kyma-environment-broker/internal/quota"
@@ -43,7 +47,9 @@ import (
 	"github.com/kyma-project/kyma-environment-broker/internal/storage"
 	"github.com/kyma-project/kyma-environment-broker/internal/suspension"
 	"github.com/kyma-project/kyma-environment-broker/internal/swagger"
+	"github.com/kyma-project/kyma-environment-broker/internal/tracing"
 	"github.com/kyma-project/kyma-environment-broker/internal/version"
+	"github.com/kyma-project/kyma-environment-broker/internal/webhook"
 	"github.com/kyma-project/kyma-environment-broker/internal/whitelist"
 	"github.com/kyma-project/kyma-environment-broker/internal/workers"
 
@@ -82,6 +88,10 @@ type Config struct {
 	// is created on the old pod after the new pod's startup scan has already completed.
 	OperationRecoveryDelay time.Duration `envconfig:"default=2m"`
 
+	// OperationLeasing allows running several KEB replicas, which share the operation queues.
+	// If it is enabled, the OperationRecoveryDelay is not used, the operations are recovered when their leases expire.
+	OperationLeasing process.LeasingConfig
+
 	// DevelopmentMode if set to true then errors are returned in http
 	// responses, otherwise errors are only logged and generic message
 	// is returned to client.
@@ -121,10 +131,17 @@ type Config struct {
 	// either ephemeral container filesystem or persistent storage
 	Profiler ProfilerConfig
 
-	Events events.Config
+	Events       events.Config
+	EventsStream stream.Config
 
 	Metrics metrics.Config
 
+	Webhooks webhook.Config
+
+	Archive archive.Config
+
+	Tracing tracing.Config
+
 	Provisioning   process.StagedManagerConfiguration
 	Deprovisioning process.StagedManagerConfiguration
 	Update         process.StagedManagerConfiguration
@@ -142,6 +159,7 @@ type Config struct {
 	PlansConfigurationFilePath string
 
 	Quota                               quota.Config
+	QuotaReservations                   quota.ReservationsConfig
 	QuotaWhitelistedSubaccountsFilePath string
 
 	MachinesAvailabilityEndpoint bool
@@ -269,6 +287,18 @@ func main() {
 	fatalOnError(err, log)
 	err = cfg.InfrastructureManager.Validate()
 	fatalOnError(err, log)
+	err = cfg.EventsStream.Validate()
+	fatalOnError(err, log)
+	err = cfg.Tracing.Validate()
+	fatalOnError(err, log)
+
+	shutdownTracing, err := tracing.Init(ctx, cfg.Tracing, Version)
+	fatalOnError(err, log)
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			log.Warn(fmt.Sprintf("while flushing traces: %s", err))
+		}
+	}()
 
 	log.Info("Starting Kyma Environment Broker")
 
@@ -294,7 +324,7 @@ func main() {
 	// create kubernetes client
 	kcpK8sConfig, err := config.GetConfig()
 	fatalOnError(err, log)
-	kcpK8sClient, err := initClient(kcpK8sConfig)
+	kcpK8sClient, err := initClient(tracing.WrapRestConfig(kcpK8sConfig))
 	fatalOnError(err, log)
 	skrK8sClientProvider := kubeconfig.NewK8sClientFromSecretProvider(kcpK8sClient)
 
@@ -332,7 +362,7 @@ func main() {
 	fatalOnError(err, log)
 	cfg.Gardener.DNSProviders, err = gardener.ReadDNSProvidersValuesFromYAML(cfg.SkrDnsProvidersValuesYAMLFilePath)
 	fatalOnError(err, log)
-	dynamicGardener, err := dynamic.NewForConfig(gardenerClusterConfig)
+	dynamicGardener, err := dynamic.NewForConfig(tracing.WrapRestConfig(gardenerClusterConfig))
 	fatalOnError(err, log)
 
 	gardenerNamespace := fmt.Sprintf("garden-%v", cfg.Gardener.Project)
@@ -343,10 +373,35 @@ func main() {
 
 	// application event broker
 	eventBroker := event.NewPubSub(log)
+	// propagate inserted events to the operation progress stream
+	events.SetPublisher(eventBroker)
 
 	// metrics collectors
 	_ = metrics.Register(ctx, eventBroker, db, cfg.Metrics, gardenerClient, log)
 
+	if cfg.Webhooks.Enabled {
+		var webhookEndpoints []webhook.Endpoint
+		if cfg.Webhooks.EndpointsFilePath != "" {
+			webhookEndpoints, err = webhook.ReadEndpointsFromFile(cfg.Webhooks.EndpointsFilePath)
+			fatalOnError(err, log)
+		}
+		webhookNotifier := webhook.NewNotifier(cfg.Webhooks, webhookEndpoints, db.WebhookDeliveries(), db.Instances(), func(planID string) string {
+			return broker.AvailablePlans.GetPlanNameOrEmpty(broker.PlanIDType(planID))
+		}, eventBroker, log)
+		go webhookNotifier.Run(ctx)
+		log.Info(fmt.Sprintf("Webhook notifications enabled for %d endpoint(s)", len(webhookEndpoints)))
+	}
+
+	// confirm and release quota reservations when operations finish
+	quotaKeeper := quota.NewReservationKeeper(db.QuotaReservations(), log)
+	eventBroker.Subscribe(process.OperationFinished{}, func(_ context.Context, ev interface{}) error {
+		e, ok := ev.(process.OperationFinished)
+		if !ok {
+			return fmt.Errorf("expected process.OperationFinished but got %+v", ev)
+		}
+		return quotaKeeper.OnOperationFinished(e.Operation)
+	})
+
 	rulesService, err := rules.NewRulesServiceFromFile(cfg.HapRuleFilePath, sets.New(broker.AvailablePlans.GetAllPlanNamesAsStrings()...), sets.New([]string(cfg.Broker.EnablePlans)...))
 	fatalOnError(err, log)
 
@@ -402,17 +457,22 @@ func main() {
 
 	log.Info(fmt.Sprintf("Number of globalAccountIds for max pods: %d", len(cfg.MaxPodsWhitelistedGlobalAccountIds)))
 
+	var leaser *process.OperationLeaser
+	if cfg.OperationLeasing.Enabled {
+		leaser = process.NewOperationLeaser(db.OperationLeases(), cfg.OperationLeasing, log.With("service", "operationLeaser"))
+	}
+
 	// run queues
 	provisionManager := process.NewStagedManager(db.Operations(), eventBroker, cfg.Broker.OperationTimeout, cfg.Provisioning, log.With("provisioning", "manager"))
 	provisionQueue := NewProvisioningProcessingQueue(ctx, provisionManager, cfg.Provisioning.WorkersAmount, &cfg, db, configProvider,
-		skrK8sClientProvider, kcpK8sClient, gardenerClient, oidcDefaultValues, log, rulesService, workersProvider, providerSpec, factory, kcrVolumeProvider)
+		skrK8sClientProvider, kcpK8sClient, gardenerClient, oidcDefaultValues, log, rulesService, workersProvider, providerSpec, factory, kcrVolumeProvider, leaser)
 
 	deprovisionManager := process.NewStagedManager(db.Operations(), eventBroker, cfg.Broker.OperationTimeout, cfg.Deprovisioning, log.With("deprovisioning", "manager"))
 	deprovisionQueue := NewDeprovisioningProcessingQueue(ctx, cfg.Deprovisioning.WorkersAmount, deprovisionManager, &cfg, db,
-		skrK8sClientProvider, kcpK8sClient, configProvider, dynamicGardener, gardenerNamespace, log)
+		skrK8sClientProvider, kcpK8sClient, configProvider, dynamicGardener, gardenerNamespace, log, leaser)
 
 	updateManager := process.NewStagedManager(db.Operations(), eventBroker, cfg.Broker.OperationTimeout, cfg.Update, log.With("update", "manager"))
-	updateQueue := NewUpdateProcessingQueue(ctx, updateManager, cfg.Update.WorkersAmount, db, cfg, kcpK8sClient, log, workersProvider, schemaService, plansSpec, configProvider, providerSpec, gardenerClient, factory, kcrVolumeProvider)
+	updateQueue := NewUpdateProcessingQueue(ctx, updateManager, cfg.Update.WorkersAmount, db, cfg, kcpK8sClient, log, workersProvider, schemaService, plansSpec, configProvider, providerSpec, gardenerClient, factory, kcrVolumeProvider, leaser)
 	/***/
 	servicesConfig, err := broker.NewServicesConfigFromFile(cfg.CatalogFilePath)
 	fatalOnError(err, log)
@@ -426,18 +486,33 @@ func main() {
 	// Apply panic recovery middleware to all HTTP endpoints
 	router.Use(httputil.PanicRecoveryMiddleware(log))
 
+	// renders the Runtime resource for dry-run requests, the step is not a part of the provisioning queue
+	runtimeResourceRenderer := provisioning.NewCreateRuntimeResourceStep(db, kcpK8sClient, cfg.InfrastructureManager, oidcDefaultValues, workersProvider, providerSpec, cfg.GlobalAccounts(), kcrVolumeProvider, cfg.Broker.AuditLogAccess)
+
 	createAPI(router, schemaService, servicesConfig, &cfg, db, provisionQueue, deprovisionQueue, updateQueue, log,
 		kcBuilder, skrK8sClientProvider, skrK8sClientProvider, kcpK8sClient, eventBroker,
-		providerSpec, configProvider, plansSpec, rulesService, gardenerClient, factory)
+		providerSpec, configProvider, plansSpec, rulesService, gardenerClient, factory, runtimeResourceRenderer, leaser)
 
 	// create metrics endpoint
 	router.Handle("/metrics", promhttp.Handler())
 
 	// create SKR kubeconfig endpoint
-	kcHandler := kubeconfig.NewHandler(db, kcBuilder, cfg.Kubeconfig.AllowOrigins, log.With("service", "kubeconfigHandle"))
+	tokenIssuer := kubeconfig.NewServiceAccountTokenIssuer(skrK8sClientProvider, kcBuilder, cfg.Kubeconfig.TokenClusterRole)
+	kcHandler := kubeconfig.NewHandler(db, kcBuilder, tokenIssuer, cfg.Kubeconfig, log.With("service", "kubeconfigHandle"))
 	kcHandler.AttachRoutes(router)
 
-	if !cfg.DisableProcessOperationsInProgress {
+	if cfg.OperationLeasing.Enabled {
+		queues := map[internal.OperationType]*process.Queue{
+			internal.OperationTypeProvision:   provisionQueue,
+			internal.OperationTypeDeprovision: deprovisionQueue,
+			internal.OperationTypeUpdate:      updateQueue,
+		}
+		if cfg.DisableProcessOperationsInProgress {
+			log.Info("Skipping processing operation in progress, only leases of the processed operations are renewed")
+			queues = nil
+		}
+		go leaser.Run(ctx, queues)
+	} else if !cfg.DisableProcessOperationsInProgress {
 		// Delayed scan to recover in-progress operations after a rolling deployment.
 		// Using a delay ensures the old pod has fully stopped, so operations created
 		// on the old pod after this pod started are not missed.
@@ -470,9 +545,33 @@ func main() {
 	additionalPropertiesHandler.AttachRoutes(router)
 
 	// create expiration endpoint
-	expirationHandler := expiration.NewHandler(db.Instances(), db.Operations(), deprovisionQueue, log)
+	expirationHandler := expiration.NewHandler(db.Instances(), db.Operations(), db.Actions(), deprovisionQueue, expiration.ExtensionConfig{
+		Period:            cfg.Broker.ExpirationExtensionPeriod,
+		MaxExtensions:     cfg.Broker.MaxExpirationExtensions,
+		ExpirationPeriods: broker.ExpirationPeriods(cfg.Broker),
+	}, log)
 	expirationHandler.AttachRoutes(router)
 
+	// create operations administration endpoint
+	operationsHandler := operations.NewHandler(db.Operations(), db.Actions(), map[internal.OperationType]operations.Queue{
+		internal.OperationTypeProvision:   provisionQueue,
+		internal.OperationTypeDeprovision: deprovisionQueue,
+		internal.OperationTypeUpdate:      updateQueue,
+	}, eventBroker, log)
+	operationsHandler.AttachRoutes(router)
+
+	// create archived instances endpoint
+	if cfg.Archive.BundleSigningKey != "" {
+		_, err := archive.ParseSigningKey([]byte(cfg.Archive.BundleSigningKey))
+		fatalOnError(err, log)
+	}
+	archiveHandler := archive.NewHandler(archive.NewInspector(db), cfg.Archive, log)
+	archiveHandler.AttachRoutes(router)
+
+	// create operation progress stream endpoint
+	streamHandler := stream.NewHandler(stream.NewBroadcaster(eventBroker, log), cfg.EventsStream, log)
+	streamHandler.AttachRoutes(router)
+
 	router.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
 		http.StripPrefix("/", http.FileServer(http.Dir("/swagger"))).ServeHTTP(w, r)
 	})
@@ -482,7 +581,7 @@ func main() {
 		router.ServeHTTP(rec, r)
 		log.Info(fmt.Sprintf("Call handled: method=%s url=%s statusCode=%d size=%d", r.Method, r.URL.Path, rec.StatusCode, rec.Size))
 	})
-	fatalOnError(http.ListenAndServe(cfg.Broker.Host+":"+cfg.Broker.Port, svr), log)
+	fatalOnError(http.ListenAndServe(cfg.Broker.Host+":"+cfg.Broker.Port, tracing.NewHandler(svr, "broker")), log)
 }
 
 func logConfiguration(logs *slog.Logger, cfg Config) {
@@ -510,6 +609,8 @@ func logConfiguration(logs *slog.Logger, cfg Config) {
 	logs.Info(fmt.Sprintf("Metrics.OperationResultFinishedOperationRetentionPeriod: %s", cfg.Metrics.OperationResultFinishedOperationRetentionPeriod))
 	logs.Info(fmt.Sprintf("Metrics.BindingsStatsPollingInterval: %s", cfg.Metrics.BindingsStatsPollingInterval))
 
+	logs.Info(fmt.Sprintf("Tracing: %s", cfg.Tracing))
+
 	logCACertBundleDigest(logs)
 }
 
@@ -565,7 +666,7 @@ func createAPI(router *httputil.Router, schemaService *broker.SchemaService, ser
 	provisionQueue, deprovisionQueue, updateQueue *process.Queue, logs *slog.Logger, kcBuilder kubeconfig.KcBuilder, clientProvider K8sClientProvider,
 	kubeconfigProvider KubeconfigProvider, kcpK8sClient client.Client, publisher event.Publisher,
 	providerSpec *configuration.ProviderSpec, configProvider kebConfig.Provider, planSpec *configuration.PlanSpecifications, rulesService *rules.RulesService,
-	gardenerClient *gardener.Client, factory hyperscalers.Factory) {
+	gardenerClient *gardener.Client, factory hyperscalers.Factory, runtimeResourceRenderer broker.RuntimeResourceRenderer, leaser *process.OperationLeaser) {
 
 	if cfg.MachinesAvailabilityEndpoint {
 		machinesAvailability := machinesavailability.NewHandlerCB(providerSpec, rulesService, gardenerClient, factory, logs)
@@ -590,10 +691,16 @@ func createAPI(router *httputil.Router, schemaService *broker.SchemaService, ser
 	fatalOnError(err, logs)
 	logs.Info(fmt.Sprintf("Number of globalAccountIds allowed for gvisor: %d", len(gvisorWhitelistedGlobalAccountIds)))
 
-	quotaClient := quota.NewClient(context.Background(), cfg.Quota, logs)
+	quotaClient := quota.NewClient(context.Background(), cfg.Quota, logs).
+		WithMetrics(quota.NewMetrics(prometheus.DefaultRegisterer, "kcp_keb_v2"))
 	quotaWhitelistedSubaccountIds, err := whitelist.ReadWhitelistedIdsFromFile(cfg.QuotaWhitelistedSubaccountsFilePath)
 	fatalOnError(err, logs)
 	logs.Info(fmt.Sprintf("Number of subaccountIds with unlimited quota: %d", len(quotaWhitelistedSubaccountIds)))
+	if cfg.Broker.CheckQuotaLimit && cfg.QuotaReservations.ReconciliationInterval > 0 {
+		go quota.NewReconciler(cfg.QuotaReservations, db, quotaClient, func(planID string) string {
+			return broker.AvailablePlans.GetPlanNameOrEmpty(broker.PlanIDType(planID))
+		}, quotaWhitelistedSubaccountIds, leaser, logs).Run(context.Background())
+	}
 
 	var operationBlocklist blocklist.OperationBlocklist
 	if cfg.OperationBlocklistFilePath != "" {
@@ -603,6 +710,8 @@ func createAPI(router *httputil.Router, schemaService *broker.SchemaService, ser
 	operationBlocklist, err = operationBlocklist.WithPlanValidator(broker.AvailablePlans)
 	fatalOnError(err, logs)
 
+	bindingsManagers := brokerBindings.NewBindingsManagers(clientProvider, kubeconfigProvider, kcpK8sClient)
+
 	// create KymaEnvironmentBroker endpoints
 	kymaEnvBroker := &broker.KymaEnvironmentBroker{
 		ServicesEndpoint: broker.NewServices(cfg.Broker, schemaService, servicesConfig),
@@ -620,10 +729,10 @@ func createAPI(router *httputil.Router, schemaService *broker.SchemaService, ser
 			rulesService, gardenerClient, factory, operationBlocklist),
 		GetInstanceEndpoint:          broker.NewGetInstance(cfg.Broker, db.Instances(), db.Operations(), kcBuilder, logs),
 		LastOperationEndpoint:        broker.NewLastOperation(db.Operations(), db.InstancesArchived(), logs),
-		BindEndpoint:                 broker.NewBind(cfg.Broker.Binding, db, logs, clientProvider, kubeconfigProvider, publisher),
-		UnbindEndpoint:               broker.NewUnbind(logs, db, brokerBindings.NewServiceAccountBindingsManager(clientProvider, kubeconfigProvider), publisher),
+		BindEndpoint:                 broker.NewBind(cfg.Broker.Binding, db, logs, bindingsManagers, publisher),
+		UnbindEndpoint:               broker.NewUnbind(logs, db, bindingsManagers, publisher),
 		GetBindingEndpoint:           broker.NewGetBinding(logs, db),
-		LastBindingOperationEndpoint: broker.NewLastBindingOperation(logs),
+		LastBindingOperationEndpoint: broker.NewLastBindingOperation(cfg.Broker.Binding, db, logs),
 	}
 
 	// Wrap broker with panic recovery for all OSB endpoints
@@ -633,10 +742,14 @@ func createAPI(router *httputil.Router, schemaService *broker.SchemaService, ser
 	subRouter, err := router.NewSubRouter(brokerAPISubrouterName)
 	fatalOnError(err, logs)
 	broker.AttachRoutes(subRouter, brokerWithPanicRecovery, logs, cfg.Broker.Binding.CreateBindingTimeout, cfg.Broker.DefaultRequestRegion, prefixes)
+	// create dry-run endpoints for provisioning and update requests
+	broker.NewDryRun(kymaEnvBroker.ProvisionEndpoint, kymaEnvBroker.UpdateEndpoint, runtimeResourceRenderer, logs).AttachRoutes(subRouter, prefixes)
+	// create the binding renewal endpoint
+	broker.NewRenewBinding(cfg.Broker.Binding, db, logs, bindingsManagers, publisher).AttachRoutes(subRouter, prefixes)
 	router.Handle("/oauth/", http.StripPrefix("/oauth", subRouter))
 
 	// create events endpoint
-	eventsHandler := eventshandler.NewHandler(db.Events(), db.Instances())
+	eventsHandler := eventshandler.NewHandler(db.Events(), db.Instances(), cfg.MaxPaginationPage)
 	router.Handle("/events", eventsHandler)
 
 	versionHandler := version.NewHandler(Version)
@@ -681,6 +794,11 @@ func initClient(cfg *rest.Config) (client.Client, error) {
 	return cli, nil
 }
 
+// isLowCostPlan returns true for plans of trial and free instances, their operations get a lower priority in the queues
+func isLowCostPlan(planID string) bool {
+	return broker.IsTrialPlan(planID) || broker.IsFreemiumPlan(planID)
+}
+
 func fatalOnError(err error, log *slog.Logger) {
 	if err != nil {
 		log.Error(err.Error())
@@ -32,7 +32,7 @@ const (
 func NewProvisioningProcessingQueue(ctx context.Context, provisionManager *process.StagedManager, workersAmount int, cfg *Config,
 	db storage.BrokerStorage, configProvider config.Provider,
 	k8sClientProvider provisioning.K8sClientProvider, k8sClient client.Client, gardenerClient *gardener.Client, defaultOIDC pkg.OIDCConfigDTO, logs *slog.Logger, rulesService *rules.RulesService,
-	workersProvider *workers.Provider, providerSpec *configuration.ProviderSpec, factory hyperscalers.Factory, kcrVolumeProvider *provider.KCRVolumeProvider) *process.Queue {
+	workersProvider *workers.Provider, providerSpec *configuration.ProviderSpec, factory hyperscalers.Factory, kcrVolumeProvider *provider.KCRVolumeProvider, leaser *process.OperationLeaser) *process.Queue {
 
 	provisioningSteps := []struct {
 		disabled  bool
@@ -90,7 +90,7 @@ func NewProvisioningProcessingQueue(ctx context.Context, provisionManager *proce
 		}
 	}
 
-	queue := process.NewQueue(provisionManager, logs, "provisioning")
+	queue := process.NewQueueWithClassifier(leaser.Executor(provisionManager), logs, "provisioning", process.NewOperationClassifier(db.Operations(), isLowCostPlan))
 	queue.Run(ctx.Done(), workersAmount)
 
 	return queue
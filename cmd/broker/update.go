@@ -22,7 +22,7 @@ import (
 
 func NewUpdateProcessingQueue(ctx context.Context, manager *process.StagedManager, workersAmount int, db storage.BrokerStorage,
 	cfg Config, kcpClient client.Client, logs *slog.Logger, workersProvider *workers.Provider, schemaService *broker.SchemaService, planSpec *configuration.PlanSpecifications, configProvider config.Provider,
-	providerSpec *configuration.ProviderSpec, gardenerClient *gardener.Client, factory hyperscalers.Factory, kcrVolumeProvider *provider.KCRVolumeProvider) *process.Queue {
+	providerSpec *configuration.ProviderSpec, gardenerClient *gardener.Client, factory hyperscalers.Factory, kcrVolumeProvider *provider.KCRVolumeProvider, leaser *process.OperationLeaser) *process.Queue {
 
 	regions, err := provider.ReadPlatformRegionMappingFromFile(cfg.TrialRegionMappingFilePath)
 	if err != nil {
@@ -37,6 +37,11 @@ func NewUpdateProcessingQueue(ctx context.Context, manager *process.StagedManage
 		step      process.Step
 		condition process.StepCondition
 	}{
+		{
+			stage:    "cluster",
+			step:     update.NewMaintenanceWindowStep(db),
+			disabled: !cfg.Broker.MaintenanceWindowEnabled,
+		},
 		{
 			stage: "cluster",
 			step:  update.NewInitialisationStep(db),
@@ -67,7 +72,7 @@ func NewUpdateProcessingQueue(ctx context.Context, manager *process.StagedManage
 			}
 		}
 	}
-	queue := process.NewQueue(manager, logs, "update-processing")
+	queue := process.NewQueueWithClassifier(leaser.Executor(manager), logs, "update-processing", process.NewOperationClassifier(db.Operations(), isLowCostPlan))
 	queue.Run(ctx.Done(), workersAmount)
 
 	return queue
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/kyma-project/kyma-environment-broker/internal/events"
+	"github.com/kyma-project/kyma-environment-broker/internal/eventsretention"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage"
+	"github.com/vrischmann/envconfig"
+)
+
+type Config struct {
+	Database storage.Config
+	Job      eventsretention.Config
+}
+
+func main() {
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	slog.SetDefault(logger)
+
+	slog.Info("Starting events retention job")
+
+	var cfg Config
+	fatalOnError(envconfig.InitWithPrefix(&cfg, "APP"))
+
+	if cfg.Job.DryRun {
+		slog.Info("Dry run only - no changes")
+	}
+
+	cipher := storage.NewEncrypter(cfg.Database.SecretKey)
+	db, conn, err := storage.NewFromConfig(cfg.Database, events.Config{}, cipher)
+	fatalOnError(err)
+	defer func() { _ = conn.Close() }()
+
+	svc := eventsretention.NewService(db.EventsRetention(), cfg.Job, logger)
+	result, err := svc.Run()
+	fatalOnError(err)
+
+	slog.Info(fmt.Sprintf("Events retention job finished: %d deprovisioned instances, %d events archived, %d events dropped, %d expired events deleted",
+		result.DeprovisionedInstances, result.ArchivedEvents, result.DroppedEvents, result.ExpiredEvents))
+}
+
+func fatalOnError(err error) {
+	if err != nil {
+		slog.Error(err.Error())
+		os.Exit(1)
+	}
+}
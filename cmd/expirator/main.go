@@ -10,6 +10,7 @@ import (
 	"github.com/kyma-project/kyma-environment-broker/internal"
 	"github.com/kyma-project/kyma-environment-broker/internal/broker"
 	"github.com/kyma-project/kyma-environment-broker/internal/events"
+	"github.com/kyma-project/kyma-environment-broker/internal/expiration"
 	"github.com/kyma-project/kyma-environment-broker/internal/ptr"
 	"github.com/kyma-project/kyma-environment-broker/internal/storage"
 	"github.com/kyma-project/kyma-environment-broker/internal/storage/dbmodel"
@@ -28,9 +29,11 @@ type Config struct {
 	Broker           broker.ClientConfig
 	DryRun           bool          `envconfig:"default=true"`
 	ExpirationPeriod time.Duration `envconfig:"default=720h"` // 30 days
+	ExtensionPeriod  time.Duration `envconfig:"default=168h"`
 	TestRun          bool          `envconfig:"default=false"`
 	TestSubaccountID string        `envconfig:"default=prow-keb-trial-suspension"`
 	PlanID           string        `envconfig:"default=7d55d31d-35ae-4438-bf13-6ffdfa107d9f"`
+	Warnings         expiration.WarningConfig
 }
 
 type Result struct {
@@ -40,19 +43,22 @@ type Result struct {
 	suspensionsAcceptedCount int
 	onlyMarkedAsExpiredCount int
 	failuresCount            int
+	warningsSentCount        int
 }
 
 type CleanupService struct {
 	cfg             Config
 	instanceStorage storage.Instances
 	brokerClient    BrokerClient
+	warner          *expiration.Warner
 }
 
-func newCleanupService(cfg Config, brokerClient BrokerClient, instances storage.Instances) *CleanupService {
+func newCleanupService(cfg Config, brokerClient BrokerClient, instances storage.Instances, warner *expiration.Warner) *CleanupService {
 	return &CleanupService{
 		cfg:             cfg,
 		instanceStorage: instances,
 		brokerClient:    brokerClient,
+		warner:          warner,
 	}
 }
 
@@ -73,7 +79,7 @@ func main() {
 		slog.Info("Dry run only - no changes")
 	}
 
-	slog.Info(fmt.Sprintf("Expiration period: %+v", cfg.ExpirationPeriod))
+	slog.Info(fmt.Sprintf("Expiration period: %+v, extension period: %+v", cfg.ExpirationPeriod, cfg.ExtensionPeriod))
 	slog.Info(fmt.Sprintf("PlanID: %s", cfg.PlanID))
 
 	ctx := context.Background()
@@ -84,19 +90,27 @@ func main() {
 	db, conn, err := storage.NewFromConfig(cfg.Database, events.Config{}, cipher)
 	fatalOnError(err)
 	defer func() { _ = conn.Close() }()
-	svc := newCleanupService(cfg, brokerClient, db.Instances())
+
+	var warner *expiration.Warner
+	if cfg.Warnings.Enabled {
+		notifier, err := expiration.NewNotifier(cfg.Warnings, logger)
+		fatalOnError(err)
+		warner = expiration.NewWarner(notifier, db.Actions(), cfg.Warnings.Period, logger)
+	}
+	svc := newCleanupService(cfg, brokerClient, db.Instances(), warner)
 
 	result, err := svc.PerformCleanup()
 	fatalOnError(err)
 
 	slog.Info(fmt.Sprintf(
-		"Instances: %+v, to expire: %+v, left non-expired: %+v, suspension under way: %+v, just marked expired: %+v, failures: %+v",
+		"Instances: %+v, to expire: %+v, left non-expired: %+v, suspension under way: %+v, just marked expired: %+v, failures: %+v, warnings sent: %+v",
 		result.count,
 		result.instancesToExpireCount,
 		result.instancesToBeLeftCount,
 		result.suspensionsAcceptedCount,
 		result.onlyMarkedAsExpiredCount,
 		result.failuresCount,
+		result.warningsSentCount,
 	))
 
 	slog.Info("Expirator job finished successfully!")
@@ -115,13 +129,14 @@ func (s *CleanupService) PerformCleanup() (Result, error) {
 
 	instancesToExpire, instancesToExpireCount := s.filterInstances(
 		instances,
-		func(instance internal.Instance) bool { return time.Since(instance.CreatedAt) >= s.cfg.ExpirationPeriod },
+		func(instance internal.Instance) bool { return !time.Now().Before(s.expirationTime(instance)) },
 	)
 
 	instancesToBeLeftCount := count - instancesToExpireCount
 
 	if s.cfg.DryRun {
 		s.logInstances(instancesToExpire)
+		s.logUpcomingExpirations(instances)
 		return Result{
 			count:                    count,
 			instancesToExpireCount:   instancesToExpireCount,
@@ -132,6 +147,7 @@ func (s *CleanupService) PerformCleanup() (Result, error) {
 		}, nil
 	}
 
+	warningsSentCount := s.warnInstances(instances)
 	suspensionsAcceptedCount, onlyMarkedAsExpiredCount, failuresCount := s.cleanupInstances(instancesToExpire)
 
 	return Result{
@@ -141,9 +157,46 @@ func (s *CleanupService) PerformCleanup() (Result, error) {
 		suspensionsAcceptedCount: suspensionsAcceptedCount,
 		onlyMarkedAsExpiredCount: onlyMarkedAsExpiredCount,
 		failuresCount:            failuresCount,
+		warningsSentCount:        warningsSentCount,
 	}, nil
 }
 
+func (s *CleanupService) expirationTime(instance internal.Instance) time.Time {
+	return instance.ExpirationTime(s.cfg.ExpirationPeriod, s.cfg.ExtensionPeriod)
+}
+
+// warnInstances sends the warnings to the instances which expire soon
+func (s *CleanupService) warnInstances(instances []internal.Instance) int {
+	if s.warner == nil {
+		return 0
+	}
+	var warningsSent int
+	for _, instance := range instances {
+		sent, err := s.warner.Warn(context.Background(), instance, s.expirationTime(instance))
+		if err != nil {
+			// ignoring errors - only logging
+			slog.Error(fmt.Sprintf("while sending expiration warning for instanceID: %s, error: %s", instance.InstanceID, err))
+			continue
+		}
+		if sent {
+			warningsSent += 1
+		}
+	}
+	return warningsSent
+}
+
+func (s *CleanupService) logUpcomingExpirations(instances []internal.Instance) {
+	if s.warner == nil {
+		return
+	}
+	for _, instance := range instances {
+		expiresAt := s.expirationTime(instance)
+		if left := time.Until(expiresAt); left > 0 && left <= s.cfg.Warnings.Period {
+			slog.Info(fmt.Sprintf("instanceId: %+v expires at %+v, a warning would be sent", instance.InstanceID, expiresAt))
+		}
+	}
+}
+
 func (s *CleanupService) getInstances(filter dbmodel.InstanceFilter) ([]internal.Instance, int, error) {
 
 	instances, _, totalCount, err := s.instanceStorage.List(filter)
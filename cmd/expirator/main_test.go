@@ -96,6 +96,23 @@ func TestCleanup(t *testing.T) {
 				failuresCount:            0,
 			},
 		},
+		"should not expire instance with extended expiration": {
+			modifyInstance: func(i *internal.Instance) {
+				i.ExpirationExtensions = 1
+			},
+			config: Config{
+				PlanID:          broker.TrialPlanID,
+				ExtensionPeriod: 1 * time.Hour,
+			},
+			expectedResult: Result{
+				count:                    1,
+				instancesToExpireCount:   0,
+				instancesToBeLeftCount:   1,
+				suspensionsAcceptedCount: 0,
+				onlyMarkedAsExpiredCount: 0,
+				failuresCount:            0,
+			},
+		},
 		"should not expire instance before expiration period": {
 			modifyInstance: func(i *internal.Instance) {},
 			config: Config{
@@ -136,6 +153,7 @@ func TestCleanup(t *testing.T) {
 				tc.config,
 				&mockBrokerClient{},
 				db.Instances(),
+				nil,
 			)
 
 			result, err := svc.PerformCleanup()
@@ -3,10 +3,12 @@ package main
 import (
 	_ "embed"
 	"encoding/json"
+	"flag"
 	"fmt"
 	"log/slog"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -45,6 +47,7 @@ type rangeCache struct {
 // cache is the top-level in-memory store populated on each refresh.
 type cache struct {
 	opEvents             []analytics.OpEvent                  // full history, shared across all windows
+	lifecycleEvents      []analytics.OpEvent                  // full history of all instances incl. deprovisioned, for cohorts
 	byRange              map[string]rangeCache                // keys: "all", "7d", "30d", "90d"
 	activeInstanceParams []analytics.ProvisioningParamsWithID // current state from instances table
 	plans                []string                             // from the "all" window
@@ -95,6 +98,75 @@ func buildRangeCache(opEvents []analytics.OpEvent, tr analytics.TimeRange, planI
 	return rangeCache{provParams: provParams, updateParams: updateParams, resp: resp}
 }
 
+// newCache pre-computes the rangeCache of every standard window from the fetched data.
+// lifecycleEvents are only stored, cohorts are cheap enough to be built per request.
+func newCache(opEvents, lifecycleEvents []analytics.OpEvent, activeInstanceParams []analytics.ProvisioningParamsWithID, planIDToName map[string]string, now time.Time, refreshInterval time.Duration) cache {
+	windows := map[string]analytics.TimeRange{
+		"all": {},
+		"7d":  {From: now.AddDate(0, 0, -7)},
+		"30d": {From: now.AddDate(0, 0, -30)},
+		"90d": {From: now.AddDate(0, 0, -90)},
+	}
+
+	// Build the "all" window first to derive trendParams used by all windows.
+	allRC := buildRangeCache(opEvents, analytics.TimeRange{}, planIDToName, nil, activeInstanceParams)
+	trendParams := analytics.TrendParamsFrom(allRC.resp.Combined)
+
+	// Rebuild "all" with trendParams so its Trends field is populated.
+	allRC = buildRangeCache(opEvents, analytics.TimeRange{}, planIDToName, trendParams, activeInstanceParams)
+
+	byRange := make(map[string]rangeCache, len(windows))
+	byRange["all"] = allRC
+	for key, tr := range windows {
+		if key == "all" {
+			continue
+		}
+		byRange[key] = buildRangeCache(opEvents, tr, planIDToName, trendParams, activeInstanceParams)
+	}
+
+	return cache{
+		opEvents:             opEvents,
+		lifecycleEvents:      lifecycleEvents,
+		byRange:              byRange,
+		activeInstanceParams: activeInstanceParams,
+		plans:                allRC.resp.Plans,
+		regionsByPlan:        allRC.resp.RegionsByPlan,
+		cachedAt:             now,
+		nextRefreshAt:        now.Add(refreshInterval),
+	}
+}
+
+// statsFor returns the stats for the time range and plan/region filter from the cache snapshot.
+// It is shared by the JSON API, the export endpoint and the export CLI mode so they all return the same numbers.
+func statsFor(snapshot cache, tr analytics.TimeRange, planFilter, regionFilter string, planIDToName map[string]string) analytics.StatsResponse {
+	// Resolve the rangeCache to use — pre-computed if the window matches, otherwise
+	// slice from the full opEvents in-memory (no DB query in either path).
+	var rc rangeCache
+	if key := matchRangeKey(tr); key != "" {
+		rc = snapshot.byRange[key]
+	} else {
+		// Custom date range: derive in-memory from cached opEvents.
+		allCombined := snapshot.byRange["all"].resp.Combined
+		trendParams := analytics.TrendParamsFrom(allCombined)
+		rc = buildRangeCache(snapshot.opEvents, tr, planIDToName, trendParams, snapshot.activeInstanceParams)
+	}
+
+	var data analytics.StatsResponse
+	if planFilter == "" && regionFilter == "" {
+		data = rc.resp
+		// Always use the full plan/region index for dropdowns.
+		data.Plans = snapshot.plans
+		data.RegionsByPlan = snapshot.regionsByPlan
+	} else {
+		allCombined := snapshot.byRange["all"].resp.Combined
+		trendParams := analytics.TrendParamsFrom(allCombined)
+		data = buildFilteredStats(rc.provParams, rc.updateParams, snapshot.opEvents, snapshot.activeInstanceParams, planFilter, regionFilter, planIDToName, snapshot.plans, snapshot.regionsByPlan, trendParams)
+	}
+	data.CachedAt = snapshot.cachedAt.Format(time.RFC3339)
+	data.NextRefreshAt = snapshot.nextRefreshAt.Format(time.RFC3339)
+	return data
+}
+
 // matchRangeKey returns the cache key ("7d", "30d", "90d", "all") if the TimeRange
 // matches one of the pre-computed windows (within 1-day tolerance for client clock skew).
 // Returns "" if no match — caller must slice from opEvents in-memory.
@@ -125,8 +197,32 @@ func abs(d time.Duration) time.Duration {
 	return d
 }
 
+// exportFlags configure the CLI mode which writes the stats to a file once and exits instead of serving HTTP.
+type exportFlags struct {
+	file   string
+	format string
+	from   string
+	to     string
+	plan   string
+	region string
+}
+
 func main() {
-	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	var export exportFlags
+	flag.StringVar(&export.file, "export", "", "Write the stats to the file and exit instead of starting the server")
+	flag.StringVar(&export.format, "format", string(analytics.ExportFormatCSV), "Export file format: csv or parquet")
+	flag.StringVar(&export.from, "from", "", "Start of the exported time range, YYYY-MM-DD")
+	flag.StringVar(&export.to, "to", "", "End of the exported time range, YYYY-MM-DD")
+	flag.StringVar(&export.plan, "plan", "", "Export stats of the plan only")
+	flag.StringVar(&export.region, "region", "", "Export stats of the region only")
+	flag.Parse()
+
+	// logs go to stderr in the export mode, so the file can also be written to stdout
+	logOutput := os.Stdout
+	if export.file != "" {
+		logOutput = os.Stderr
+	}
+	logger := slog.New(slog.NewJSONHandler(logOutput, nil))
 	slog.SetDefault(logger)
 
 	var cfg Config
@@ -166,6 +262,14 @@ func main() {
 		planIDToName[string(id)] = string(name)
 	}
 
+	if export.file != "" {
+		if err := runExport(export, reader, planIDToName); err != nil {
+			slog.Error("failed to export stats", "error", err)
+			os.Exit(1)
+		}
+		return
+	}
+
 	var (
 		mu         sync.RWMutex
 		c          cache
@@ -207,44 +311,18 @@ func main() {
 			return
 		}
 
-		now := time.Now().UTC()
-		windows := map[string]analytics.TimeRange{
-			"all": {},
-			"7d":  {From: now.AddDate(0, 0, -7)},
-			"30d": {From: now.AddDate(0, 0, -30)},
-			"90d": {From: now.AddDate(0, 0, -90)},
-		}
-
-		// Build the "all" window first to derive trendParams used by all windows.
-		allRC := buildRangeCache(opEvents, analytics.TimeRange{}, planIDToName, nil, activeInstanceParams)
-		trendParams := analytics.TrendParamsFrom(allRC.resp.Combined)
-
-		// Rebuild "all" with trendParams so its Trends field is populated.
-		allRC = buildRangeCache(opEvents, analytics.TimeRange{}, planIDToName, trendParams, activeInstanceParams)
-
-		byRange := make(map[string]rangeCache, len(windows))
-		byRange["all"] = allRC
-		for key, tr := range windows {
-			if key == "all" {
-				continue
-			}
-			byRange[key] = buildRangeCache(opEvents, tr, planIDToName, trendParams, activeInstanceParams)
+		lifecycleEvents, err := reader.FetchLifecycleEvents()
+		if err != nil {
+			slog.Error("failed to fetch lifecycle events", "error", err)
+			return
 		}
 
-		plans, regionsByPlan := allRC.resp.Plans, allRC.resp.RegionsByPlan
+		newC := newCache(opEvents, lifecycleEvents, activeInstanceParams, planIDToName, time.Now().UTC(), cfg.RefreshInterval)
 
 		mu.Lock()
-		c = cache{
-			opEvents:             opEvents,
-			byRange:              byRange,
-			activeInstanceParams: activeInstanceParams,
-			plans:                plans,
-			regionsByPlan:        regionsByPlan,
-			cachedAt:             now,
-			nextRefreshAt:        now.Add(cfg.RefreshInterval),
-		}
+		c = newC
 		mu.Unlock()
-		slog.Info("stats cache refreshed", "total_instances", allRC.resp.TotalInstances)
+		slog.Info("stats cache refreshed", "total_instances", newC.byRange["all"].resp.TotalInstances)
 	}
 
 	runRefresh()
@@ -277,35 +355,80 @@ func main() {
 			return
 		}
 
-		// Resolve the rangeCache to use — pre-computed if the window matches, otherwise
-		// slice from the full opEvents in-memory (no DB query in either path).
-		var rc rangeCache
-		if key := matchRangeKey(tr); key != "" {
-			rc = snapshot.byRange[key]
-		} else {
-			// Custom date range: derive in-memory from cached opEvents.
-			allCombined := snapshot.byRange["all"].resp.Combined
-			trendParams := analytics.TrendParamsFrom(allCombined)
-			rc = buildRangeCache(snapshot.opEvents, tr, planIDToName, trendParams, snapshot.activeInstanceParams)
+		data := statsFor(snapshot, tr, planFilter, regionFilter, planIDToName)
+
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(data); err != nil {
+			slog.Error("failed to encode stats", "error", err)
 		}
+	})
 
-		var data analytics.StatsResponse
-		if planFilter == "" && regionFilter == "" {
-			data = rc.resp
-			// Always use the full plan/region index for dropdowns.
-			data.Plans = snapshot.plans
-			data.RegionsByPlan = snapshot.regionsByPlan
-		} else {
-			allCombined := snapshot.byRange["all"].resp.Combined
-			trendParams := analytics.TrendParamsFrom(allCombined)
-			data = buildFilteredStats(rc.provParams, rc.updateParams, snapshot.opEvents, snapshot.activeInstanceParams, planFilter, regionFilter, planIDToName, snapshot.plans, snapshot.regionsByPlan, trendParams)
+	mux.HandleFunc("/api/export", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		format, err := analytics.ParseExportFormat(r.URL.Query().Get("format"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		tr, err := parseTimeRange(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		mu.RLock()
+		snapshot := c
+		mu.RUnlock()
+
+		if snapshot.byRange == nil {
+			http.Error(w, "data not yet available, try again shortly", http.StatusServiceUnavailable)
+			return
+		}
+
+		data := statsFor(snapshot, tr, r.URL.Query().Get("plan"), r.URL.Query().Get("region"), planIDToName)
+
+		w.Header().Set("Content-Type", format.ContentType())
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", exportFileName(snapshot.cachedAt, format)))
+		if err := analytics.WriteExport(w, format, analytics.ExportRows(data)); err != nil {
+			slog.Error("failed to export stats", "error", err)
+		}
+	})
+
+	mux.HandleFunc("/api/cohorts", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		query, err := parseCohortQuery(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		mu.RLock()
+		snapshot := c
+		mu.RUnlock()
+
+		if snapshot.byRange == nil {
+			http.Error(w, "data not yet available, try again shortly", http.StatusServiceUnavailable)
+			return
+		}
+
+		query.Now = time.Now().UTC()
+		data := analytics.CohortsResponse{
+			Breakdown:     query.Breakdown,
+			Weeks:         query.Weeks,
+			Cohorts:       analytics.BuildCohorts(snapshot.lifecycleEvents, query, planIDToName, []string{broker.TrialPlanName, broker.FreemiumPlanName}),
+			CachedAt:      snapshot.cachedAt.Format(time.RFC3339),
+			NextRefreshAt: snapshot.nextRefreshAt.Format(time.RFC3339),
 		}
-		data.CachedAt = snapshot.cachedAt.Format(time.RFC3339)
-		data.NextRefreshAt = snapshot.nextRefreshAt.Format(time.RFC3339)
 
 		w.Header().Set("Content-Type", "application/json")
 		if err := json.NewEncoder(w).Encode(data); err != nil {
-			slog.Error("failed to encode stats", "error", err)
+			slog.Error("failed to encode cohorts", "error", err)
 		}
 	})
 
@@ -397,24 +520,100 @@ func buildFilteredStats(
 
 // parseTimeRange reads optional ?from=YYYY-MM-DD and ?to=YYYY-MM-DD query params.
 func parseTimeRange(r *http.Request) (analytics.TimeRange, error) {
+	return parseTimeRangeValues(r.URL.Query().Get("from"), r.URL.Query().Get("to"))
+}
+
+// parseTimeRangeValues parses optional YYYY-MM-DD from/to dates, empty values leave the range open.
+func parseTimeRangeValues(from, to string) (analytics.TimeRange, error) {
 	var tr analytics.TimeRange
-	if s := r.URL.Query().Get("from"); s != "" {
-		t, err := time.Parse("2006-01-02", s)
+	if from != "" {
+		t, err := time.Parse("2006-01-02", from)
 		if err != nil {
-			return tr, fmt.Errorf("invalid 'from' date %q, expected YYYY-MM-DD", s)
+			return tr, fmt.Errorf("invalid 'from' date %q, expected YYYY-MM-DD", from)
 		}
 		tr.From = t.UTC()
 	}
-	if s := r.URL.Query().Get("to"); s != "" {
-		t, err := time.Parse("2006-01-02", s)
+	if to != "" {
+		t, err := time.Parse("2006-01-02", to)
 		if err != nil {
-			return tr, fmt.Errorf("invalid 'to' date %q, expected YYYY-MM-DD", s)
+			return tr, fmt.Errorf("invalid 'to' date %q, expected YYYY-MM-DD", to)
 		}
 		tr.To = t.UTC()
 	}
 	return tr, nil
 }
 
+// parseCohortQuery reads the optional from, to, plan, region, breakdown and weeks query params.
+func parseCohortQuery(r *http.Request) (analytics.CohortQuery, error) {
+	tr, err := parseTimeRange(r)
+	if err != nil {
+		return analytics.CohortQuery{}, err
+	}
+	breakdown, err := analytics.ParseCohortBreakdown(r.URL.Query().Get("breakdown"))
+	if err != nil {
+		return analytics.CohortQuery{}, err
+	}
+	weeks := analytics.DefaultCohortWeeks
+	if s := r.URL.Query().Get("weeks"); s != "" {
+		weeks, err = strconv.Atoi(s)
+		if err != nil || weeks < 1 || weeks > analytics.MaxCohortWeeks {
+			return analytics.CohortQuery{}, fmt.Errorf("invalid 'weeks' %q, expected a number from 1 to %d", s, analytics.MaxCohortWeeks)
+		}
+	}
+	return analytics.CohortQuery{
+		TimeRange: tr,
+		Plan:      r.URL.Query().Get("plan"),
+		Region:    r.URL.Query().Get("region"),
+		Breakdown: breakdown,
+		Weeks:     weeks,
+	}, nil
+}
+
+// runExport fetches the data once, computes the stats with the same pipeline as the server and writes them to the file.
+// The file name "-" writes to the standard output.
+func runExport(export exportFlags, reader *analytics.DBReader, planIDToName map[string]string) error {
+	format, err := analytics.ParseExportFormat(export.format)
+	if err != nil {
+		return err
+	}
+	tr, err := parseTimeRangeValues(export.from, export.to)
+	if err != nil {
+		return err
+	}
+
+	opEvents, err := reader.FetchOpEventsInRange(analytics.TimeRange{})
+	if err != nil {
+		return fmt.Errorf("while fetching op events: %w", err)
+	}
+	activeInstanceParams, err := reader.FetchActiveInstanceParams()
+	if err != nil {
+		return fmt.Errorf("while fetching active instance params: %w", err)
+	}
+	snapshot := newCache(opEvents, nil, activeInstanceParams, planIDToName, time.Now().UTC(), 0)
+	rows := analytics.ExportRows(statsFor(snapshot, tr, export.plan, export.region, planIDToName))
+
+	if export.file == "-" {
+		return analytics.WriteExport(os.Stdout, format, rows)
+	}
+	f, err := os.Create(export.file)
+	if err != nil {
+		return fmt.Errorf("while creating export file: %w", err)
+	}
+	if err := analytics.WriteExport(f, format, rows); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("while closing export file: %w", err)
+	}
+	slog.Info("stats exported", "file", export.file, "format", format, "rows", len(rows))
+	return nil
+}
+
+func exportFileName(cachedAt time.Time, format analytics.ExportFormat) string {
+	return fmt.Sprintf("keb-analytics-stats-%s.%s", cachedAt.Format("20060102T150405Z"), format)
+}
+
 func indexHTMLReader() *strings.Reader {
 	return strings.NewReader(indexHTML)
 }
@@ -2,6 +2,8 @@ package main
 
 import (
 	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 	"time"
 
@@ -76,3 +78,64 @@ func TestMatchRangeKey_WithToDate(t *testing.T) {
 	}
 	assert.Equal(t, "", matchRangeKey(tr))
 }
+
+// TestStatsFor_FilteredExportMatchesStats verifies that the export rows are built from the same
+// filtered stats as the JSON API, so the exported numbers match the UI.
+func TestStatsFor_FilteredExportMatchesStats(t *testing.T) {
+	provEvent := func(instanceID, planID, region, machineType string) analytics.OpEvent {
+		p := internal.ProvisioningParameters{
+			PlanID:     planID,
+			Parameters: pkg.ProvisioningParametersDTO{Region: strPtr(region), MachineType: strPtr(machineType)},
+		}
+		raw, err := json.Marshal(p)
+		require.NoError(t, err)
+		return analytics.OpEvent{InstanceID: instanceID, CreatedAt: "2024-01-01", Type: "provision", RawParams: string(raw)}
+	}
+	opEvents := []analytics.OpEvent{
+		provEvent("i1", "aws-plan-id", "eu-central-1", "m6i.xlarge"),
+		provEvent("i2", "aws-plan-id", "us-east-1", "m6i.xlarge"),
+		provEvent("i3", "gcp-plan-id", "europe-west3", "n2-standard-4"),
+	}
+	planIDToName := map[string]string{"aws-plan-id": "aws", "gcp-plan-id": "gcp"}
+	snapshot := newCache(opEvents, nil, nil, planIDToName, time.Now().UTC(), time.Hour)
+
+	stats := statsFor(snapshot, analytics.TimeRange{}, "aws", "eu-central-1", planIDToName)
+	rows := analytics.ExportRows(stats)
+
+	assert.Equal(t, 1, stats.TotalInstances)
+	require.NotEmpty(t, rows)
+	for _, row := range rows {
+		if row.Section == analytics.ExportSectionProvisioning {
+			assert.Equal(t, int64(stats.Provisioning.CountFor(row.Parameter)), row.Count)
+			assert.Equal(t, int64(1), row.Total)
+		}
+	}
+}
+
+func TestParseTimeRangeValues(t *testing.T) {
+	tr, err := parseTimeRangeValues("2025-01-01", "")
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), tr.From)
+	assert.True(t, tr.To.IsZero())
+
+	_, err = parseTimeRangeValues("", "01.01.2025")
+	assert.Error(t, err)
+}
+
+func TestParseCohortQuery(t *testing.T) {
+	query, err := parseCohortQuery(httptest.NewRequest(http.MethodGet, "/api/cohorts?from=2025-01-01&plan=aws&breakdown=region&weeks=8", nil))
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), query.TimeRange.From)
+	assert.Equal(t, "aws", query.Plan)
+	assert.Equal(t, analytics.CohortBreakdownRegion, query.Breakdown)
+	assert.Equal(t, 8, query.Weeks)
+
+	query, err = parseCohortQuery(httptest.NewRequest(http.MethodGet, "/api/cohorts", nil))
+	require.NoError(t, err)
+	assert.Equal(t, analytics.DefaultCohortWeeks, query.Weeks)
+
+	_, err = parseCohortQuery(httptest.NewRequest(http.MethodGet, "/api/cohorts?weeks=100", nil))
+	assert.Error(t, err)
+	_, err = parseCohortQuery(httptest.NewRequest(http.MethodGet, "/api/cohorts?breakdown=subaccount", nil))
+	assert.Error(t, err)
+}
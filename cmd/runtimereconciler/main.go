@@ -4,14 +4,18 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
+	"net/http"
 	"os"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 
+	"github.com/kyma-project/kyma-environment-broker/internal/bindingsreconciler"
 	btpmanager "github.com/kyma-project/kyma-environment-broker/internal/btpmanager/credentials"
 	"github.com/kyma-project/kyma-environment-broker/internal/events"
+	"github.com/kyma-project/kyma-environment-broker/internal/kubeconfig"
 	"github.com/kyma-project/kyma-environment-broker/internal/storage"
 	"github.com/vrischmann/envconfig"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -26,6 +30,7 @@ type Config struct {
 	JobInterval            int    `envconfig:"default=24"`
 	JobReconciliationDelay string `envconfig:"default=0s"`
 	MetricsPort            string `envconfig:"default=8081"`
+	BindingsReconciler     bindingsreconciler.Config
 }
 
 const AppPrefix = "runtime_reconciler"
@@ -46,7 +51,7 @@ func main() {
 	fatalOnError(err, logs)
 	logs.Info("runtime-reconciler config loaded")
 
-	if !cfg.JobEnabled {
+	if !cfg.JobEnabled && !cfg.BindingsReconciler.Enabled {
 		logs.Info("job disabled, module stopped.")
 		return
 	}
@@ -65,21 +70,42 @@ func main() {
 
 	metricsRegistry := prometheus.NewRegistry()
 	metricsRegistry.MustRegister(collectors.NewGoCollector())
+	serveMetrics(metricsRegistry, cfg.MetricsPort, logs)
 
 	kcpK8sConfig, err := config.GetConfig()
 	fatalOnError(err, logs)
 	kcpK8sClient, err := client.New(kcpK8sConfig, client.Options{})
 	fatalOnError(err, logs)
 
-	btpOperatorManager := btpmanager.NewManager(ctx, kcpK8sClient, db.Instances(), logs, cfg.DryRun)
+	if cfg.JobEnabled {
+		btpOperatorManager := btpmanager.NewManager(ctx, kcpK8sClient, db.Instances(), logs, cfg.DryRun)
 
-	btpManagerCredentialsJob := btpmanager.NewJob(btpOperatorManager, logs, metricsRegistry, cfg.MetricsPort, AppPrefix)
-	logs.Info(fmt.Sprintf("runtime-reconciler created job every %d m", cfg.JobInterval))
-	btpManagerCredentialsJob.Start(cfg.JobInterval, jobReconciliationDelay)
+		btpManagerCredentialsJob := btpmanager.NewJob(btpOperatorManager, logs, metricsRegistry, AppPrefix)
+		logs.Info(fmt.Sprintf("runtime-reconciler created job every %d m", cfg.JobInterval))
+		btpManagerCredentialsJob.Start(cfg.JobInterval, jobReconciliationDelay)
+	}
+
+	if cfg.BindingsReconciler.Enabled {
+		logs.Info(fmt.Sprintf("bindings reconciler running every %s as dry run? %t", cfg.BindingsReconciler.Interval, cfg.BindingsReconciler.DryRun))
+		bindingsReconciler := bindingsreconciler.NewReconciler(cfg.BindingsReconciler, db.Instances(), db.Bindings(),
+			kubeconfig.NewK8sClientFromSecretProvider(kcpK8sClient), bindingsreconciler.NewMetrics(metricsRegistry, AppPrefix), logs)
+		bindingsReconciler.Start(ctx)
+	}
 
 	<-ctx.Done()
 }
 
+func serveMetrics(metricsRegistry *prometheus.Registry, port string, log *slog.Logger) {
+	http.Handle("/metrics", promhttp.HandlerFor(metricsRegistry, promhttp.HandlerOpts{Registry: metricsRegistry}))
+
+	go func() {
+		err := http.ListenAndServe(fmt.Sprintf(":%s", port), nil)
+		if err != nil {
+			log.Error(fmt.Sprintf("while serving metrics: %s", err))
+		}
+	}()
+}
+
 func fatalOnError(err error, log *slog.Logger) {
 	if err != nil {
 		log.Error(err.Error())
@@ -26,10 +26,51 @@ type EventDTO struct {
 }
 
 type EventFilter struct {
-	InstanceIDs  []string
-	OperationIDs []string
+	InstanceIDs      []string
+	OperationIDs     []string
+	GlobalAccountIDs []string
+	SubAccountIDs    []string
+	// Regions filters events of instances in the given provider regions, including archived instances
+	Regions []string
+	Levels  []EventLevel
+	// From and To limit the creation time of events, zero values are ignored
+	From time.Time
+	To   time.Time
+	// Message filters events containing the given text, case-insensitive
+	Message string
+
+	// PageSize limits the number of returned events, zero means no limit
+	PageSize int
+	// After returns only events following the given position in the order of the creation time
+	After *EventPosition
+}
+
+// EventPosition identifies an event in the order in which events are returned
+type EventPosition struct {
+	CreatedAt time.Time
+	ID        string
+}
+
+// EventsPage is returned by the /events API if the pagination is requested
+type EventsPage struct {
+	Data       []EventDTO `json:"data"`
+	Count      int        `json:"count"`
+	NextCursor string     `json:"nextCursor,omitempty"`
 }
 
+const (
+	InstanceIDsParam      = "instance_ids"
+	RuntimeIDsParam       = "runtime_ids"
+	OperationIDsParam     = "operation_ids"
+	GlobalAccountIDsParam = "global_account_ids"
+	SubAccountIDsParam    = "subaccount_ids"
+	RegionParam           = "region"
+	LevelsParam           = "levels"
+	FromParam             = "from"
+	ToParam               = "to"
+	MessageParam          = "message"
+)
+
 // Client is the interface to interact with the KEB /events API as an HTTP client using OIDC ID token in JWT format.
 type Client interface {
 	ListEvents(instanceIDs []string) ([]EventDTO, error)
@@ -59,7 +100,7 @@ func (c *client) ListEvents(instanceIDs []string) ([]EventDTO, error) {
 		return events, fmt.Errorf("while creating request: %v", err)
 	}
 	q := req.URL.Query()
-	q.Add("instance_ids", strings.Join(instanceIDs, ","))
+	q.Add(InstanceIDsParam, strings.Join(instanceIDs, ","))
 	req.URL.RawQuery = q.Encode()
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
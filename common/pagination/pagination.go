@@ -1,9 +1,12 @@
 package pagination
 
 import (
+	"encoding/base64"
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 )
 
 func ConvertPageSizeAndOrderedColumnToSQL(pageSize, page int, orderedColumn string) (string, error) {
@@ -37,6 +40,7 @@ func ConvertPageAndPageSizeToOffset(pageSize, page int) int {
 const (
 	PageSizeParam = "page_size"
 	PageParam     = "page"
+	CursorParam   = "cursor"
 )
 
 func ExtractPaginationConfigFromRequest(req *http.Request, maxPage int) (int, int, error) {
@@ -84,3 +88,62 @@ func ExtractPaginationConfigFromRequest(req *http.Request, maxPage int) (int, in
 
 	return pageSize, page, nil
 }
+
+// EncodeCursor returns an opaque cursor pointing to the item with the given creation time and ID,
+// used by endpoints which return items ordered by the creation time
+func EncodeCursor(createdAt time.Time, id string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%s|%s", createdAt.UTC().Format(time.RFC3339Nano), id)))
+}
+
+// DecodeCursor returns the creation time and ID of the item the cursor points to
+func DecodeCursor(cursor string) (time.Time, string, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, "", fmt.Errorf("cursor is malformed")
+	}
+	createdAt, id, found := strings.Cut(string(decoded), "|")
+	if !found || id == "" {
+		return time.Time{}, "", fmt.Errorf("cursor is malformed")
+	}
+	t, err := time.Parse(time.RFC3339Nano, createdAt)
+	if err != nil {
+		return time.Time{}, "", fmt.Errorf("cursor is malformed")
+	}
+	return t, id, nil
+}
+
+// ExtractCursorPaginationFromRequest returns the page size and the cursor of the cursor-based pagination.
+// The page size is 0 if neither the page size nor the cursor is given, and defaults to maxPage if only the cursor is given.
+func ExtractCursorPaginationFromRequest(req *http.Request, maxPage int) (int, string, error) {
+	params := req.URL.Query()
+	cursorArr, cursorFound := params[CursorParam]
+	if len(cursorArr) > 1 {
+		return 0, "", fmt.Errorf("cursor has to be one parameter")
+	}
+	cursor := ""
+	if cursorFound {
+		cursor = cursorArr[0]
+	}
+
+	pageSizeArr, pageSizeFound := params[PageSizeParam]
+	if !pageSizeFound {
+		if cursorFound {
+			return maxPage, cursor, nil
+		}
+		return 0, "", nil
+	}
+	if len(pageSizeArr) > 1 {
+		return 0, "", fmt.Errorf("pageSize has to be one parameter")
+	}
+	pageSize, err := strconv.Atoi(pageSizeArr[0])
+	if err != nil {
+		return 0, "", fmt.Errorf("pageSize has to be an integer")
+	}
+	if pageSize > maxPage {
+		return 0, "", fmt.Errorf("pageSize is bigger than maxPage(%d)", maxPage)
+	}
+	if pageSize < 1 {
+		return 0, "", fmt.Errorf("pageSize cannot be smaller than 1")
+	}
+	return pageSize, cursor, nil
+}
@@ -110,6 +110,7 @@ type ProvisioningParametersDTO struct {
 	Gvisor                    *GvisorDTO                 `json:"gvisor,omitempty"`
 	AdditionalVolumeSizeGi    *int                       `json:"additionalVolumeSizeGi,omitempty"`
 	AuditLogAccess            *bool                      `json:"auditLogAccess,omitempty"`
+	MaintenanceWindow         *MaintenanceWindowDTO      `json:"maintenanceWindow,omitempty"`
 }
 
 func (p ProvisioningParametersDTO) ValidateAdditionalVolumeSizeGi() error {
@@ -119,6 +120,79 @@ func (p ProvisioningParametersDTO) ValidateAdditionalVolumeSizeGi() error {
 	return nil
 }
 
+const (
+	// MaintenanceWindowLayout is the time format of the Gardener shoot maintenance time window, for example, 220000+0100
+	MaintenanceWindowLayout = "150405-0700"
+
+	minMaintenanceWindowDuration = 30 * time.Minute
+	maxMaintenanceWindowDuration = 6 * time.Hour
+	secondsPerDay                = 24 * 60 * 60
+)
+
+// MaintenanceWindowDTO defines a daily time window in which disruptive updates are applied.
+// Begin and End use the Gardener shoot maintenance time window format, the window can span midnight.
+type MaintenanceWindowDTO struct {
+	Begin string `json:"begin"`
+	End   string `json:"end"`
+}
+
+func (w MaintenanceWindowDTO) Validate() error {
+	begin, err := parseMaintenanceWindowTime(w.Begin)
+	if err != nil {
+		return fmt.Errorf("invalid maintenanceWindow begin %q, expected format HHMMSS+ZZZZ, for example, 220000+0000", w.Begin)
+	}
+	end, err := parseMaintenanceWindowTime(w.End)
+	if err != nil {
+		return fmt.Errorf("invalid maintenanceWindow end %q, expected format HHMMSS+ZZZZ, for example, 230000+0000", w.End)
+	}
+	duration := time.Duration((end-begin+secondsPerDay)%secondsPerDay) * time.Second
+	if duration < minMaintenanceWindowDuration || duration > maxMaintenanceWindowDuration {
+		return fmt.Errorf("maintenanceWindow must be between %s and %s long, got %s", minMaintenanceWindowDuration, maxMaintenanceWindowDuration, duration)
+	}
+	return nil
+}
+
+// Contains returns true if the given time is inside the window, the window must be valid
+func (w MaintenanceWindowDTO) Contains(t time.Time) bool {
+	begin, end := w.bounds()
+	now := secondOfDay(t)
+	if begin <= end {
+		return now >= begin && now < end
+	}
+	return now >= begin || now < end
+}
+
+// NextBegin returns the first time after the given one at which the window opens, the window must be valid
+func (w MaintenanceWindowDTO) NextBegin(t time.Time) time.Time {
+	begin, _ := w.bounds()
+	utc := t.UTC()
+	next := time.Date(utc.Year(), utc.Month(), utc.Day(), 0, 0, 0, 0, time.UTC).Add(time.Duration(begin) * time.Second)
+	if !next.After(t) {
+		next = next.Add(24 * time.Hour)
+	}
+	return next
+}
+
+func (w MaintenanceWindowDTO) bounds() (int, int) {
+	begin, _ := parseMaintenanceWindowTime(w.Begin)
+	end, _ := parseMaintenanceWindowTime(w.End)
+	return begin, end
+}
+
+// parseMaintenanceWindowTime returns the second of the day in UTC
+func parseMaintenanceWindowTime(value string) (int, error) {
+	t, err := time.Parse(MaintenanceWindowLayout, value)
+	if err != nil {
+		return 0, err
+	}
+	return secondOfDay(t), nil
+}
+
+func secondOfDay(t time.Time) int {
+	utc := t.UTC()
+	return utc.Hour()*3600 + utc.Minute()*60 + utc.Second()
+}
+
 type GvisorDTO struct {
 	Enabled bool `json:"enabled"`
 }
@@ -393,8 +467,12 @@ type BindingDTO struct {
 type ActionType string
 
 const (
-	PlanUpdateActionType         ActionType = "plan_update"
-	SubaccountMovementActionType ActionType = "subaccount_movement"
+	PlanUpdateActionType           ActionType = "plan_update"
+	SubaccountMovementActionType   ActionType = "subaccount_movement"
+	OperationStateChangeActionType ActionType = "operation_state_change"
+	KubeconfigDownloadActionType   ActionType = "kubeconfig_download"
+	ExpirationWarningActionType    ActionType = "expiration_warning"
+	ExpirationExtensionActionType  ActionType = "expiration_extension"
 )
 
 type Action struct {
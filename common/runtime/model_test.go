@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -167,3 +168,49 @@ func TestCheckDuplicateWorkerNodePoolKeys(t *testing.T) {
 		})
 	}
 }
+
+func TestMaintenanceWindowValidate(t *testing.T) {
+	for _, w := range []MaintenanceWindowDTO{
+		{Begin: "220000+0000", End: "230000+0000"},
+		{Begin: "230000+0100", End: "030000+0100"},
+		{Begin: "010000-0500", End: "070000-0500"},
+	} {
+		require.NoError(t, w.Validate(), "expected valid window: %+v", w)
+	}
+
+	for w, msg := range map[MaintenanceWindowDTO]string{
+		{Begin: "22:00", End: "230000+0000"}:       "invalid maintenanceWindow begin",
+		{Begin: "220000+0000", End: "250000+0000"}: "invalid maintenanceWindow end",
+		{Begin: "220000+0000", End: "221000+0000"}: "must be between 30m0s and 6h0m0s long",
+		{Begin: "220000+0000", End: "050000+0000"}: "must be between 30m0s and 6h0m0s long",
+		{Begin: "220000+0000", End: "220000+0000"}: "must be between 30m0s and 6h0m0s long",
+	} {
+		err := w.Validate()
+		require.Error(t, err, "expected invalid window: %+v", w)
+		assert.Contains(t, err.Error(), msg)
+	}
+}
+
+func TestMaintenanceWindowContains(t *testing.T) {
+	at := func(value string) time.Time {
+		tm, err := time.Parse(time.RFC3339, value)
+		require.NoError(t, err)
+		return tm
+	}
+
+	window := MaintenanceWindowDTO{Begin: "220000+0000", End: "230000+0000"}
+	assert.False(t, window.Contains(at("2026-10-17T21:59:59Z")))
+	assert.True(t, window.Contains(at("2026-10-17T22:00:00Z")))
+	assert.True(t, window.Contains(at("2026-10-17T22:30:00+00:00")))
+	assert.False(t, window.Contains(at("2026-10-17T23:00:00Z")))
+	assert.Equal(t, at("2026-10-17T22:00:00Z"), window.NextBegin(at("2026-10-17T10:00:00Z")))
+	assert.Equal(t, at("2026-10-18T22:00:00Z"), window.NextBegin(at("2026-10-17T22:00:00Z")))
+
+	// the window spans midnight in UTC
+	window = MaintenanceWindowDTO{Begin: "230000+0200", End: "030000+0200"}
+	assert.False(t, window.Contains(at("2026-10-17T20:59:00Z")))
+	assert.True(t, window.Contains(at("2026-10-17T21:00:00Z")))
+	assert.True(t, window.Contains(at("2026-10-18T00:30:00Z")))
+	assert.False(t, window.Contains(at("2026-10-18T01:00:00Z")))
+	assert.Equal(t, at("2026-10-18T21:00:00Z"), window.NextBegin(at("2026-10-18T00:30:00Z")))
+}
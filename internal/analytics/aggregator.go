@@ -26,8 +26,11 @@ const (
 )
 
 const (
-	opTypeProvision = "provision"
-	opTypeUpdate    = "update"
+	opTypeProvision   = "provision"
+	opTypeUpdate      = "update"
+	opTypeDeprovision = "deprovision"
+	// opTypeSuspension marks a temporary deprovisioning, which keeps the instance
+	opTypeSuspension = "suspension"
 )
 
 // provisioningFieldConfig controls per-field behavior for ProvisioningParametersDTO.
@@ -0,0 +1,210 @@
+package analytics
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+)
+
+// Breakdowns of the cohorts returned by BuildCohorts.
+const (
+	CohortBreakdownNone       = ""
+	CohortBreakdownPlan       = "plan"
+	CohortBreakdownRegion     = "region"
+	CohortBreakdownPlanRegion = "plan_region"
+)
+
+const (
+	DefaultCohortWeeks = 12
+	MaxCohortWeeks     = 52
+)
+
+// CohortQuery selects the cohorts built by BuildCohorts.
+type CohortQuery struct {
+	TimeRange TimeRange // filters instances by provisioning day
+	Plan      string    // filters instances by plan name at provisioning time
+	Region    string    // filters instances by provisioning region
+	Breakdown string    // one of the CohortBreakdown* values
+	Weeks     int       // number of weeks after the cohort week to report
+	Now       time.Time // weeks which have not started yet are not reported; zero reports all Weeks
+}
+
+// ParseCohortBreakdown validates the breakdown name.
+func ParseCohortBreakdown(s string) (string, error) {
+	switch s {
+	case CohortBreakdownNone, CohortBreakdownPlan, CohortBreakdownRegion, CohortBreakdownPlanRegion:
+		return s, nil
+	}
+	return "", fmt.Errorf("invalid breakdown %q, expected %q, %q or %q", s, CohortBreakdownPlan, CohortBreakdownRegion, CohortBreakdownPlanRegion)
+}
+
+// cohortInstance holds the provisioning week and the first day each milestone was reached; zero if never.
+type cohortInstance struct {
+	provisioned   string
+	week          time.Time
+	plan          string
+	region        string
+	updated       time.Time
+	upgraded      time.Time
+	suspended     time.Time
+	deprovisioned time.Time
+}
+
+type cohortKey struct {
+	week   time.Time
+	plan   string
+	region string
+}
+
+// BuildCohorts groups instances by the week of their first provisioning and counts, for every following week,
+// how many of them were updated, upgraded from a trial or free plan to a paid plan, suspended, or deprovisioned by then.
+// Events must be sorted by day ASC (as returned by FetchLifecycleEvents). Events of instances without a provisioning
+// event are ignored. planIDToName maps plan UUID → plan name; an update from one of freePlans to another plan counts as an upgrade.
+func BuildCohorts(events []OpEvent, q CohortQuery, planIDToName map[string]string, freePlans []string) []Cohort {
+	const layout = "2006-01-02"
+	instances := make(map[string]*cohortInstance)
+	for _, ev := range events {
+		day, err := time.Parse(layout, ev.CreatedAt)
+		if err != nil {
+			continue
+		}
+		inst := instances[ev.InstanceID]
+		if ev.Type == opTypeProvision {
+			// only the first provisioning defines the cohort of the instance
+			if inst != nil || ev.ParsedProv == nil {
+				continue
+			}
+			instances[ev.InstanceID] = &cohortInstance{
+				provisioned: ev.CreatedAt,
+				week:        weekStart(day),
+				plan:        planName(ev.ParsedProv.PlanID, planIDToName),
+				region:      ev.Region,
+			}
+			continue
+		}
+		if inst == nil {
+			continue
+		}
+		switch ev.Type {
+		case opTypeUpdate:
+			setFirst(&inst.updated, day)
+			if ev.UpdatedPlanID != "" && slices.Contains(freePlans, inst.plan) && !slices.Contains(freePlans, planName(ev.UpdatedPlanID, planIDToName)) {
+				setFirst(&inst.upgraded, day)
+			}
+		case opTypeSuspension:
+			setFirst(&inst.suspended, day)
+		case opTypeDeprovision:
+			setFirst(&inst.deprovisioned, day)
+		}
+	}
+
+	weeks := q.Weeks
+	if weeks <= 0 {
+		weeks = DefaultCohortWeeks
+	}
+	byKey := make(map[cohortKey][]*cohortInstance)
+	for _, inst := range instances {
+		if !inRange(inst.provisioned, q.TimeRange) {
+			continue
+		}
+		if (q.Plan != "" && inst.plan != q.Plan) || (q.Region != "" && inst.region != q.Region) {
+			continue
+		}
+		key := cohortKey{week: inst.week}
+		if q.Breakdown == CohortBreakdownPlan || q.Breakdown == CohortBreakdownPlanRegion {
+			key.plan = inst.plan
+		}
+		if q.Breakdown == CohortBreakdownRegion || q.Breakdown == CohortBreakdownPlanRegion {
+			key.region = inst.region
+		}
+		byKey[key] = append(byKey[key], inst)
+	}
+
+	cohorts := make([]Cohort, 0, len(byKey))
+	for key, members := range byKey {
+		cohorts = append(cohorts, buildCohort(key, members, reportedWeeks(key.week, weeks, q.Now)))
+	}
+	sort.Slice(cohorts, func(i, j int) bool {
+		if cohorts[i].Week != cohorts[j].Week {
+			return cohorts[i].Week < cohorts[j].Week
+		}
+		if cohorts[i].Plan != cohorts[j].Plan {
+			return cohorts[i].Plan < cohorts[j].Plan
+		}
+		return cohorts[i].Region < cohorts[j].Region
+	})
+	return cohorts
+}
+
+// buildCohort counts the milestones of the members reached within each of the reported weeks.
+func buildCohort(key cohortKey, members []*cohortInstance, weeks int) Cohort {
+	result := make([]CohortWeek, weeks+1)
+	for k := range result {
+		result[k].Week = k
+	}
+	for _, inst := range members {
+		for k := weekOffset(key.week, inst.updated); k >= 0 && k <= weeks; k++ {
+			result[k].Updated++
+		}
+		for k := weekOffset(key.week, inst.upgraded); k >= 0 && k <= weeks; k++ {
+			result[k].Upgraded++
+		}
+		for k := weekOffset(key.week, inst.suspended); k >= 0 && k <= weeks; k++ {
+			result[k].Suspended++
+		}
+		for k := weekOffset(key.week, inst.deprovisioned); k >= 0 && k <= weeks; k++ {
+			result[k].Deprovisioned++
+		}
+	}
+	return Cohort{
+		Week:   key.week.Format("2006-01-02"),
+		Plan:   key.plan,
+		Region: key.region,
+		Size:   len(members),
+		Weeks:  result,
+	}
+}
+
+// reportedWeeks limits the number of weeks to the ones which already started at now.
+func reportedWeeks(week time.Time, weeks int, now time.Time) int {
+	if now.IsZero() {
+		return weeks
+	}
+	elapsed := weekOffset(week, now)
+	switch {
+	case elapsed < 0:
+		return 0
+	case elapsed < weeks:
+		return elapsed
+	}
+	return weeks
+}
+
+// weekOffset returns the number of whole weeks between the cohort week and the day, -1 if the day is zero.
+func weekOffset(week, day time.Time) int {
+	if day.IsZero() {
+		return -1
+	}
+	return int(day.Sub(week).Hours()/24) / 7
+}
+
+// weekStart returns the Monday of the week of the day.
+func weekStart(day time.Time) time.Time {
+	offset := (int(day.Weekday()) + 6) % 7
+	return time.Date(day.Year(), day.Month(), day.Day()-offset, 0, 0, 0, 0, time.UTC)
+}
+
+func setFirst(t *time.Time, day time.Time) {
+	if t.IsZero() {
+		*t = day
+	}
+}
+
+// planName returns the plan name for the plan ID, falling back to the raw ID.
+func planName(planID string, planIDToName map[string]string) string {
+	if name := planIDToName[planID]; name != "" {
+		return name
+	}
+	return planID
+}
@@ -0,0 +1,121 @@
+package analytics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kyma-project/kyma-environment-broker/internal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	cohortPlanIDToName = map[string]string{"trial-id": "trial", "aws-id": "aws"}
+	cohortFreePlans    = []string{"trial", "free"}
+)
+
+func lifecycleProv(instanceID, day, planID, region string) OpEvent {
+	p := internal.ProvisioningParameters{PlanID: planID}
+	return OpEvent{InstanceID: instanceID, CreatedAt: day, Type: opTypeProvision, ParsedProv: &p, Region: region}
+}
+
+func lifecycleEvent(instanceID, day, opType string) OpEvent {
+	return OpEvent{InstanceID: instanceID, CreatedAt: day, Type: opType}
+}
+
+// fixLifecycleEvents returns instances provisioned in the weeks of Monday 2025-01-06 and Monday 2025-01-13.
+func fixLifecycleEvents() []OpEvent {
+	return []OpEvent{
+		lifecycleProv("i1", "2025-01-06", "trial-id", testRegion),
+		lifecycleProv("i2", "2025-01-08", "aws-id", testRegion),
+		lifecycleProv("i3", "2025-01-12", "aws-id", testRegion2),
+		lifecycleEvent("i2", "2025-01-12", opTypeUpdate),
+		lifecycleProv("i4", "2025-01-13", "aws-id", testRegion),
+		lifecycleEvent("i1", "2025-01-14", opTypeSuspension),
+		{InstanceID: "i1", CreatedAt: "2025-01-21", Type: opTypeUpdate, UpdatedPlanID: "aws-id"},
+		lifecycleEvent("i2", "2025-01-22", opTypeUpdate),
+		lifecycleEvent("i3", "2025-01-27", opTypeDeprovision),
+		// events of instances without provisioning are ignored
+		lifecycleEvent("unknown", "2025-01-27", opTypeDeprovision),
+	}
+}
+
+func TestBuildCohorts_CumulativeMilestones(t *testing.T) {
+	cohorts := BuildCohorts(fixLifecycleEvents(), CohortQuery{Weeks: 4}, cohortPlanIDToName, cohortFreePlans)
+
+	require.Len(t, cohorts, 2)
+	first := cohorts[0]
+	assert.Equal(t, "2025-01-06", first.Week)
+	assert.Equal(t, 3, first.Size)
+	assert.Equal(t, []CohortWeek{
+		{Week: 0, Updated: 1},
+		{Week: 1, Updated: 1, Suspended: 1},
+		{Week: 2, Updated: 2, Upgraded: 1, Suspended: 1},
+		{Week: 3, Updated: 2, Upgraded: 1, Suspended: 1, Deprovisioned: 1},
+		{Week: 4, Updated: 2, Upgraded: 1, Suspended: 1, Deprovisioned: 1},
+	}, first.Weeks)
+
+	assert.Equal(t, "2025-01-13", cohorts[1].Week)
+	assert.Equal(t, 1, cohorts[1].Size)
+	assert.Len(t, cohorts[1].Weeks, 5)
+}
+
+func TestBuildCohorts_LimitsWeeksToElapsed(t *testing.T) {
+	now := time.Date(2025, 1, 21, 12, 0, 0, 0, time.UTC)
+	cohorts := BuildCohorts(fixLifecycleEvents(), CohortQuery{Weeks: 4, Now: now}, cohortPlanIDToName, cohortFreePlans)
+
+	require.Len(t, cohorts, 2)
+	assert.Len(t, cohorts[0].Weeks, 3)
+	assert.Len(t, cohorts[1].Weeks, 2)
+}
+
+func TestBuildCohorts_BreakdownByPlanAndRegion(t *testing.T) {
+	cohorts := BuildCohorts(fixLifecycleEvents(), CohortQuery{Breakdown: CohortBreakdownPlanRegion, Weeks: 1}, cohortPlanIDToName, cohortFreePlans)
+
+	require.Len(t, cohorts, 4)
+	assert.Equal(t, Cohort{Week: "2025-01-06", Plan: "aws", Region: testRegion, Size: 1,
+		Weeks: []CohortWeek{{Week: 0, Updated: 1}, {Week: 1, Updated: 1}}}, cohorts[0])
+	assert.Equal(t, "aws", cohorts[1].Plan)
+	assert.Equal(t, testRegion2, cohorts[1].Region)
+	assert.Equal(t, "trial", cohorts[2].Plan)
+	assert.Equal(t, "2025-01-13", cohorts[3].Week)
+}
+
+func TestBuildCohorts_Filters(t *testing.T) {
+	cohorts := BuildCohorts(fixLifecycleEvents(), CohortQuery{Plan: "aws", Region: testRegion, Breakdown: CohortBreakdownPlan}, cohortPlanIDToName, cohortFreePlans)
+
+	require.Len(t, cohorts, 2)
+	assert.Equal(t, "aws", cohorts[0].Plan)
+	assert.Empty(t, cohorts[0].Region)
+	assert.Equal(t, 1, cohorts[0].Size)
+	assert.Len(t, cohorts[0].Weeks, DefaultCohortWeeks+1)
+
+	cohorts = BuildCohorts(fixLifecycleEvents(), CohortQuery{TimeRange: TimeRange{From: time.Date(2025, 1, 13, 0, 0, 0, 0, time.UTC)}}, cohortPlanIDToName, cohortFreePlans)
+	require.Len(t, cohorts, 1)
+	assert.Equal(t, "2025-01-13", cohorts[0].Week)
+}
+
+func TestBuildCohorts_FirstProvisioningDefinesCohort(t *testing.T) {
+	events := []OpEvent{
+		lifecycleProv("i1", "2025-01-06", "aws-id", testRegion),
+		lifecycleEvent("i1", "2025-01-07", opTypeSuspension),
+		lifecycleProv("i1", "2025-01-20", "aws-id", testRegion),
+	}
+
+	cohorts := BuildCohorts(events, CohortQuery{Weeks: 1}, cohortPlanIDToName, cohortFreePlans)
+
+	require.Len(t, cohorts, 1)
+	assert.Equal(t, "2025-01-06", cohorts[0].Week)
+	assert.Equal(t, 1, cohorts[0].Weeks[0].Suspended)
+}
+
+func TestParseCohortBreakdown(t *testing.T) {
+	for _, breakdown := range []string{CohortBreakdownNone, CohortBreakdownPlan, CohortBreakdownRegion, CohortBreakdownPlanRegion} {
+		parsed, err := ParseCohortBreakdown(breakdown)
+		require.NoError(t, err)
+		assert.Equal(t, breakdown, parsed)
+	}
+
+	_, err := ParseCohortBreakdown("subaccount")
+	assert.Error(t, err)
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"sort"
 	"time"
 
 	"github.com/gocraft/dbr"
@@ -47,13 +48,16 @@ type UpdateParamsWithID struct {
 // OpEvent is a single provisioning or update operation used for trend computation.
 // ParsedProv and ParsedUpdate are pre-parsed at fetch time to avoid repeated JSON
 // unmarshalling during aggregation and trend computation.
+// Lifecycle events used for cohort computation also include "deprovision" and "suspension" events.
 type OpEvent struct {
-	InstanceID   string
-	CreatedAt    string                           // YYYY-MM-DD
-	Type         string                           // "provision" or "update"
-	RawParams    string                           // provisioning_parameters JSON for provision ops; updating_parameters JSON for update ops
-	ParsedProv   *internal.ProvisioningParameters // non-nil for provision events
-	ParsedUpdate *internal.UpdatingParametersDTO  // non-nil for update events
+	InstanceID    string
+	CreatedAt     string                           // YYYY-MM-DD
+	Type          string                           // "provision" or "update"; lifecycle events also "deprovision" or "suspension"
+	RawParams     string                           // provisioning_parameters JSON for provision ops; updating_parameters JSON for update ops
+	ParsedProv    *internal.ProvisioningParameters // non-nil for provision events
+	ParsedUpdate  *internal.UpdatingParametersDTO  // non-nil for update events
+	UpdatedPlanID string                           // new plan ID of lifecycle update events which changed the plan
+	Region        string                           // provider region of lifecycle provision events
 }
 
 // FetchOpEventsInRange returns all succeeded provisioning and update operations on active
@@ -118,6 +122,85 @@ WHERE o.type IN ('provision', 'update')
 	return result, nil
 }
 
+// FetchLifecycleEvents returns the lifecycle events of all instances, including deprovisioned ones,
+// ordered by day ASC. Used for cohort computation. Events come from two sources:
+//   - succeeded provision, update and deprovision operations; temporary deprovisionings are returned as "suspension" events
+//   - archived instances, whose operations were already deleted; they contribute a provision and a deprovision event
+//     built from the archived plan, region and timestamps
+//
+// The region of both sources is the provider region of the runtime, the same value which is archived.
+func (r *DBReader) FetchLifecycleEvents() ([]OpEvent, error) {
+	q := `
+SELECT o.instance_id, TO_CHAR(o.created_at, 'YYYY-MM-DD') AS created_date,
+       CASE WHEN o.type = 'deprovision' AND o.data->>'temporary' = 'true' THEN 'suspension' ELSE o.type END AS type,
+       COALESCE(CASE WHEN o.type = 'provision' THEN o.provisioning_parameters::text END, '') AS raw_params,
+       COALESCE(CASE WHEN o.type = 'update' THEN o.data->>'updated_plan_id' END, '') AS updated_plan_id,
+       COALESCE(CASE WHEN o.type = 'provision' THEN o.data->'runtime_operation'->>'region' END, '') AS region
+FROM operations o
+WHERE o.type IN ('provision', 'update', 'deprovision')
+  AND o.state = 'succeeded'
+ORDER BY o.created_at ASC`
+	var rows []struct {
+		InstanceID    string `db:"instance_id"`
+		CreatedDate   string `db:"created_date"`
+		Type          string `db:"type"`
+		RawParams     string `db:"raw_params"`
+		UpdatedPlanID string `db:"updated_plan_id"`
+		Region        string `db:"region"`
+	}
+	if _, err := r.session.SelectBySql(q).Load(&rows); err != nil {
+		return nil, fmt.Errorf("fetching lifecycle events: %w", err)
+	}
+
+	qArchived := `
+SELECT instance_id, plan_id, region,
+       TO_CHAR(provisioning_started_at, 'YYYY-MM-DD') AS provisioned_date,
+       TO_CHAR(first_deprovisioning_started_at, 'YYYY-MM-DD') AS deprovisioned_date
+FROM instances_archived
+WHERE COALESCE(provisioning_state, 'succeeded') = 'succeeded'`
+	var archivedRows []struct {
+		InstanceID        string `db:"instance_id"`
+		PlanID            string `db:"plan_id"`
+		Region            string `db:"region"`
+		ProvisionedDate   string `db:"provisioned_date"`
+		DeprovisionedDate string `db:"deprovisioned_date"`
+	}
+	if _, err := r.session.SelectBySql(qArchived).Load(&archivedRows); err != nil {
+		return nil, fmt.Errorf("fetching archived instances: %w", err)
+	}
+
+	result := make([]OpEvent, 0, len(rows)+2*len(archivedRows))
+	for _, row := range rows {
+		ev := OpEvent{
+			InstanceID:    row.InstanceID,
+			CreatedAt:     row.CreatedDate,
+			Type:          row.Type,
+			RawParams:     row.RawParams,
+			UpdatedPlanID: row.UpdatedPlanID,
+			Region:        row.Region,
+		}
+		if row.Type == opTypeProvision {
+			p, err := parseProvisioningParameters(row.RawParams)
+			if err != nil {
+				slog.Warn("analytics: skipping malformed provisioning_parameters", "instance_id", row.InstanceID, "error", err)
+				continue
+			}
+			ev.ParsedProv = &p
+		}
+		result = append(result, ev)
+	}
+	for _, row := range archivedRows {
+		p := internal.ProvisioningParameters{PlanID: row.PlanID}
+		result = append(result,
+			OpEvent{InstanceID: row.InstanceID, CreatedAt: row.ProvisionedDate, Type: opTypeProvision, ParsedProv: &p, Region: row.Region},
+			OpEvent{InstanceID: row.InstanceID, CreatedAt: row.DeprovisionedDate, Type: opTypeDeprovision},
+		)
+	}
+	// YYYY-MM-DD strings sort chronologically; the stable sort keeps the operations order within a day
+	sort.SliceStable(result, func(i, j int) bool { return result[i].CreatedAt < result[j].CreatedAt })
+	return result, nil
+}
+
 func parseProvisioningParameters(raw string) (internal.ProvisioningParameters, error) {
 	if raw == "" {
 		return internal.ProvisioningParameters{}, fmt.Errorf("empty provisioning_parameters")
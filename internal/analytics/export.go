@@ -0,0 +1,150 @@
+package analytics
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+// ExportFormat is the file format of exported stats.
+type ExportFormat string
+
+const (
+	ExportFormatCSV     ExportFormat = "csv"
+	ExportFormatParquet ExportFormat = "parquet"
+)
+
+// Sections of the exported rows, one per StatsResponse field.
+const (
+	ExportSectionProvisioning = "provisioning"
+	ExportSectionUpdates      = "updates"
+	ExportSectionCombined     = "combined"
+	ExportSectionDistribution = "distribution"
+	ExportSectionTrend        = "trend"
+)
+
+// ParseExportFormat returns the ExportFormat for the given name, defaulting to CSV when empty.
+func ParseExportFormat(s string) (ExportFormat, error) {
+	switch ExportFormat(s) {
+	case "", ExportFormatCSV:
+		return ExportFormatCSV, nil
+	case ExportFormatParquet:
+		return ExportFormatParquet, nil
+	}
+	return "", fmt.Errorf("unsupported export format %q, expected %q or %q", s, ExportFormatCSV, ExportFormatParquet)
+}
+
+// ContentType returns the MIME type of the format.
+func (f ExportFormat) ContentType() string {
+	if f == ExportFormatParquet {
+		return "application/vnd.apache.parquet"
+	}
+	return "text/csv"
+}
+
+// ExportRow is a single row of the exported stats. All sections share one flat schema so the
+// export is a single table that can be loaded into a spreadsheet or a query engine as is:
+//   - provisioning/updates/combined rows: count is set_count, total is the ParameterStat total
+//   - distribution rows: value is the parameter value, count is the number of active instances
+//     with that value, total is the number of active instances with the parameter set
+//   - trend rows: date is the trend day, count and total are the TrendPoint fields
+type ExportRow struct {
+	Section   string `parquet:"section"`
+	Parameter string `parquet:"parameter"`
+	Value     string `parquet:"value"`
+	Date      string `parquet:"date"`
+	Count     int64  `parquet:"count"`
+	Total     int64  `parquet:"total"`
+}
+
+var exportCSVHeader = []string{"section", "parameter", "value", "date", "count", "total"}
+
+// ExportRows flattens ParameterStats, DistributionStat and TrendStat of the response into rows.
+// Distribution values are sorted alphabetically so the output is stable.
+func ExportRows(resp StatsResponse) []ExportRow {
+	var rows []ExportRow
+	for _, section := range []struct {
+		name  string
+		stats ParameterStats
+	}{
+		{ExportSectionProvisioning, resp.Provisioning},
+		{ExportSectionUpdates, resp.Updates},
+		{ExportSectionCombined, resp.Combined},
+	} {
+		for _, p := range section.stats.Parameters {
+			rows = append(rows, ExportRow{Section: section.name, Parameter: p.Parameter, Count: int64(p.SetCount), Total: int64(p.Total)})
+		}
+	}
+
+	for _, d := range resp.Distributions {
+		values := make([]string, 0, len(d.Values))
+		total := 0
+		for v, n := range d.Values {
+			values = append(values, v)
+			total += n
+		}
+		sort.Strings(values)
+		for _, v := range values {
+			rows = append(rows, ExportRow{Section: ExportSectionDistribution, Parameter: d.Parameter, Value: v, Count: int64(d.Values[v]), Total: int64(total)})
+		}
+	}
+
+	for _, t := range resp.Trends {
+		for _, p := range t.Points {
+			rows = append(rows, ExportRow{Section: ExportSectionTrend, Parameter: t.Parameter, Date: p.Date, Count: int64(p.Count), Total: int64(p.Total)})
+		}
+	}
+	return rows
+}
+
+// WriteExport writes the rows to w in the given format.
+func WriteExport(w io.Writer, format ExportFormat, rows []ExportRow) error {
+	switch format {
+	case ExportFormatCSV:
+		return WriteCSV(w, rows)
+	case ExportFormatParquet:
+		return WriteParquet(w, rows)
+	}
+	return fmt.Errorf("unsupported export format %q", format)
+}
+
+// WriteCSV writes the rows as CSV with a header line. Text cells are escaped, so spreadsheets do not evaluate them as formulas.
+func WriteCSV(w io.Writer, rows []ExportRow) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(exportCSVHeader); err != nil {
+		return fmt.Errorf("while writing CSV header: %w", err)
+	}
+	for _, r := range rows {
+		record := []string{csvText(r.Section), csvText(r.Parameter), csvText(r.Value), csvText(r.Date), strconv.FormatInt(r.Count, 10), strconv.FormatInt(r.Total, 10)}
+		if err := cw.Write(record); err != nil {
+			return fmt.Errorf("while writing CSV row: %w", err)
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// csvText prefixes the cell with a single quote if it starts with a character that spreadsheets interpret as a formula
+func csvText(cell string) string {
+	if cell != "" && strings.ContainsRune("=+-@\t\r", rune(cell[0])) {
+		return "'" + cell
+	}
+	return cell
+}
+
+// WriteParquet writes the rows as a single Parquet file.
+func WriteParquet(w io.Writer, rows []ExportRow) error {
+	pw := parquet.NewGenericWriter[ExportRow](w)
+	if _, err := pw.Write(rows); err != nil {
+		return fmt.Errorf("while writing Parquet rows: %w", err)
+	}
+	if err := pw.Close(); err != nil {
+		return fmt.Errorf("while closing Parquet writer: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package analytics
+
+import (
+	"bytes"
+	"encoding/csv"
+	"testing"
+
+	"github.com/parquet-go/parquet-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func fixExportStats() StatsResponse {
+	return StatsResponse{
+		Provisioning: ParameterStats{Parameters: []ParameterStat{{Parameter: "region", SetCount: 10, Total: 12}}},
+		Updates:      ParameterStats{Parameters: []ParameterStat{{Parameter: "machineType", SetCount: 3, Total: 5}}},
+		Combined:     ParameterStats{Parameters: []ParameterStat{{Parameter: "machineType", SetCount: 7, Total: 12}}},
+		Distributions: []DistributionStat{
+			{Parameter: "machineType", Values: map[string]int{testMachineType: 4, "m5.xlarge": 2}},
+		},
+		Trends: []TrendStat{
+			{Parameter: "machineType", Points: []TrendPoint{{Date: "2025-01-01", Count: 1, Total: 2}, {Date: "2025-01-02", Count: 3, Total: 4}}},
+		},
+	}
+}
+
+func TestExportRows_FlattensAllSections(t *testing.T) {
+	rows := ExportRows(fixExportStats())
+
+	assert.Equal(t, []ExportRow{
+		{Section: ExportSectionProvisioning, Parameter: "region", Count: 10, Total: 12},
+		{Section: ExportSectionUpdates, Parameter: "machineType", Count: 3, Total: 5},
+		{Section: ExportSectionCombined, Parameter: "machineType", Count: 7, Total: 12},
+		{Section: ExportSectionDistribution, Parameter: "machineType", Value: "m5.xlarge", Count: 2, Total: 6},
+		{Section: ExportSectionDistribution, Parameter: "machineType", Value: testMachineType, Count: 4, Total: 6},
+		{Section: ExportSectionTrend, Parameter: "machineType", Date: "2025-01-01", Count: 1, Total: 2},
+		{Section: ExportSectionTrend, Parameter: "machineType", Date: "2025-01-02", Count: 3, Total: 4},
+	}, rows)
+}
+
+func TestExportRows_EmptyStats(t *testing.T) {
+	assert.Empty(t, ExportRows(StatsResponse{}))
+}
+
+func TestWriteCSV_WritesHeaderAndRows(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteExport(&buf, ExportFormatCSV, ExportRows(fixExportStats())))
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 8)
+	assert.Equal(t, []string{"section", "parameter", "value", "date", "count", "total"}, records[0])
+	assert.Equal(t, []string{"provisioning", "region", "", "", "10", "12"}, records[1])
+	assert.Equal(t, []string{"distribution", "machineType", testMachineType, "", "4", "6"}, records[5])
+	assert.Equal(t, []string{"trend", "machineType", "", "2025-01-02", "3", "4"}, records[7])
+}
+
+func TestWriteCSV_EscapesFormulas(t *testing.T) {
+	rows := []ExportRow{
+		{Section: ExportSectionDistribution, Parameter: "name", Value: "=HYPERLINK(\"https://example.com\")", Count: 1, Total: 1},
+		{Section: ExportSectionDistribution, Parameter: "name", Value: "+1", Count: 1, Total: 1},
+		{Section: ExportSectionDistribution, Parameter: "name", Value: "-1", Count: 1, Total: 1},
+		{Section: ExportSectionDistribution, Parameter: "name", Value: "@SUM(A1)", Count: 1, Total: 1},
+		{Section: ExportSectionDistribution, Parameter: "name", Value: "\tvalue", Count: 1, Total: 1},
+		{Section: ExportSectionDistribution, Parameter: "name", Value: "\rvalue", Count: 1, Total: 1},
+		{Section: ExportSectionDistribution, Parameter: "name", Value: "a=b", Count: 1, Total: 1},
+	}
+	var buf bytes.Buffer
+	require.NoError(t, WriteCSV(&buf, rows))
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	require.NoError(t, err)
+	var values []string
+	for _, record := range records[1:] {
+		values = append(values, record[2])
+	}
+	assert.Equal(t, []string{"'=HYPERLINK(\"https://example.com\")", "'+1", "'-1", "'@SUM(A1)", "'\tvalue", "'\rvalue", "a=b"}, values)
+}
+
+func TestWriteParquet_RoundTrip(t *testing.T) {
+	rows := ExportRows(fixExportStats())
+	var buf bytes.Buffer
+	require.NoError(t, WriteExport(&buf, ExportFormatParquet, rows))
+
+	read, err := parquet.Read[ExportRow](bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	assert.Equal(t, rows, read)
+}
+
+func TestParseExportFormat(t *testing.T) {
+	for input, expected := range map[string]ExportFormat{"": ExportFormatCSV, "csv": ExportFormatCSV, "parquet": ExportFormatParquet} {
+		format, err := ParseExportFormat(input)
+		require.NoError(t, err)
+		assert.Equal(t, expected, format)
+	}
+
+	_, err := ParseExportFormat("xlsx")
+	assert.Error(t, err)
+}
@@ -55,3 +55,31 @@ type StatsResponse struct {
 	CachedAt       string              `json:"cached_at"`       // RFC3339 timestamp of last cache refresh
 	NextRefreshAt  string              `json:"next_refresh_at"` // RFC3339 timestamp of next scheduled refresh
 }
+
+// CohortWeek holds the cumulative number of cohort instances which reached each lifecycle milestone
+// within Week weeks after the cohort week.
+type CohortWeek struct {
+	Week          int `json:"week"`          // weeks since the cohort week, 0 is the cohort week itself
+	Updated       int `json:"updated"`       // instances with at least one succeeded update
+	Upgraded      int `json:"upgraded"`      // trial or free instances updated to a paid plan
+	Suspended     int `json:"suspended"`     // instances suspended at least once
+	Deprovisioned int `json:"deprovisioned"` // instances deprovisioned
+}
+
+// Cohort groups the instances provisioned in the same week, optionally broken down by plan and region.
+type Cohort struct {
+	Week   string       `json:"week"`   // YYYY-MM-DD of the Monday of the provisioning week
+	Plan   string       `json:"plan"`   // plan at provisioning time; empty if not broken down by plan
+	Region string       `json:"region"` // provisioning region; empty if not broken down by region
+	Size   int          `json:"size"`   // number of instances provisioned in the week
+	Weeks  []CohortWeek `json:"weeks"`  // one entry per elapsed week, up to the requested number of weeks
+}
+
+// CohortsResponse is the top-level JSON returned by GET /api/cohorts.
+type CohortsResponse struct {
+	Breakdown     string   `json:"breakdown"`
+	Weeks         int      `json:"weeks"`
+	Cohorts       []Cohort `json:"cohorts"`
+	CachedAt      string   `json:"cached_at"`       // RFC3339 timestamp of last cache refresh
+	NextRefreshAt string   `json:"next_refresh_at"` // RFC3339 timestamp of next scheduled refresh
+}
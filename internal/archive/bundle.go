@@ -0,0 +1,177 @@
+package archive
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/kyma-project/kyma-environment-broker/internal/storage/dbmodel"
+)
+
+const BundleSignatureAlgorithm = "Ed25519"
+
+var (
+	ErrTooManyInstances  = errors.New("too many archived instances")
+	ErrInvalidSignature  = errors.New("invalid bundle signature")
+	ErrSigningKeyMissing = errors.New("bundle signing key is not configured")
+	ErrInvalidKey        = errors.New("invalid bundle key")
+)
+
+type Config struct {
+	// BundleSigningKey is the PEM encoded PKCS #8 Ed25519 private key used to sign exported bundles,
+	// exporting bundles is disabled if it is empty
+	BundleSigningKey string `envconfig:"optional"`
+	// MaxExportedInstances limits the number of archived instances in one bundle
+	MaxExportedInstances int `envconfig:"default=100"`
+}
+
+// BundleFilter contains the filters of InstancesArchived.List used to select the exported instances
+type BundleFilter struct {
+	GlobalAccountIDs []string `json:"globalAccountIDs,omitempty"`
+	SubAccountIDs    []string `json:"subAccountIDs,omitempty"`
+	InstanceIDs      []string `json:"instanceIDs,omitempty"`
+	RuntimeIDs       []string `json:"runtimeIDs,omitempty"`
+	Regions          []string `json:"regions,omitempty"`
+	Plans            []string `json:"plans,omitempty"`
+	Shoots           []string `json:"shoots,omitempty"`
+}
+
+func (f BundleFilter) IsEmpty() bool {
+	return len(f.GlobalAccountIDs) == 0 && len(f.SubAccountIDs) == 0 && len(f.InstanceIDs) == 0 && len(f.RuntimeIDs) == 0 &&
+		len(f.Regions) == 0 && len(f.Plans) == 0 && len(f.Shoots) == 0
+}
+
+func (f BundleFilter) InstanceFilter() dbmodel.InstanceFilter {
+	return dbmodel.InstanceFilter{
+		GlobalAccountIDs: f.GlobalAccountIDs,
+		SubAccountIDs:    f.SubAccountIDs,
+		InstanceIDs:      f.InstanceIDs,
+		RuntimeIDs:       f.RuntimeIDs,
+		Regions:          f.Regions,
+		Plans:            f.Plans,
+		Shoots:           f.Shoots,
+	}
+}
+
+// BundleContent is the signed part of the bundle
+type BundleContent struct {
+	ExportedAt time.Time         `json:"exportedAt"`
+	Reason     string            `json:"reason,omitempty"`
+	Filter     BundleFilter      `json:"filter"`
+	Instances  []InstanceDetails `json:"instances"`
+}
+
+// Bundle is an export of archived instances for the legal hold, the signature allows to verify the content was not modified
+type Bundle struct {
+	BundleContent
+	Signature BundleSignature `json:"signature"`
+}
+
+type BundleSignature struct {
+	Algorithm string `json:"algorithm"`
+	// Digest is the hex encoded SHA-256 of the JSON encoded bundle content
+	Digest string `json:"digest"`
+	// Value is the hex encoded Ed25519 signature of the JSON encoded bundle content
+	Value string `json:"value"`
+}
+
+// Export returns a signed bundle with the archived instances matching the filter
+func (i *Inspector) Export(filter BundleFilter, reason string, cfg Config) (Bundle, error) {
+	if cfg.BundleSigningKey == "" {
+		return Bundle{}, ErrSigningKeyMissing
+	}
+	key, err := ParseSigningKey([]byte(cfg.BundleSigningKey))
+	if err != nil {
+		return Bundle{}, err
+	}
+	instances, err := i.List(filter.InstanceFilter(), cfg.MaxExportedInstances)
+	if err != nil {
+		return Bundle{}, err
+	}
+	return NewBundle(BundleContent{
+		ExportedAt: time.Now().UTC(),
+		Reason:     reason,
+		Filter:     filter,
+		Instances:  instances,
+	}, key)
+}
+
+func NewBundle(content BundleContent, key ed25519.PrivateKey) (Bundle, error) {
+	signature, err := sign(content, key)
+	if err != nil {
+		return Bundle{}, err
+	}
+	return Bundle{BundleContent: content, Signature: signature}, nil
+}
+
+// VerifyBundle checks with the public key that the bundle content matches its signature
+func VerifyBundle(bundle Bundle, key ed25519.PublicKey) error {
+	if bundle.Signature.Algorithm != BundleSignatureAlgorithm {
+		return fmt.Errorf("%w: unsupported algorithm %q", ErrInvalidSignature, bundle.Signature.Algorithm)
+	}
+	data, err := json.Marshal(bundle.BundleContent)
+	if err != nil {
+		return fmt.Errorf("while encoding bundle content: %w", err)
+	}
+	signature, err := hex.DecodeString(bundle.Signature.Value)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidSignature, err)
+	}
+	if !ed25519.Verify(key, data, signature) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+func sign(content BundleContent, key ed25519.PrivateKey) (BundleSignature, error) {
+	data, err := json.Marshal(content)
+	if err != nil {
+		return BundleSignature{}, fmt.Errorf("while encoding bundle content: %w", err)
+	}
+	digest := sha256.Sum256(data)
+	return BundleSignature{
+		Algorithm: BundleSignatureAlgorithm,
+		Digest:    hex.EncodeToString(digest[:]),
+		Value:     hex.EncodeToString(ed25519.Sign(key, data)),
+	}, nil
+}
+
+// ParseSigningKey parses the PEM encoded PKCS #8 Ed25519 private key, for example, generated with `openssl genpkey -algorithm ed25519`
+func ParseSigningKey(data []byte) (ed25519.PrivateKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%w: the signing key is not PEM encoded", ErrInvalidKey)
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidKey, err)
+	}
+	privateKey, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("%w: the signing key is not an Ed25519 key", ErrInvalidKey)
+	}
+	return privateKey, nil
+}
+
+// ParseVerificationKey parses the PEM encoded PKIX Ed25519 public key, for example, extracted with `openssl pkey -pubout`
+func ParseVerificationKey(data []byte) (ed25519.PublicKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%w: the public key is not PEM encoded", ErrInvalidKey)
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidKey, err)
+	}
+	publicKey, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("%w: the public key is not an Ed25519 key", ErrInvalidKey)
+	}
+	return publicKey, nil
+}
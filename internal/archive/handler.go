@@ -0,0 +1,96 @@
+package archive
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	pkg "github.com/kyma-project/kyma-environment-broker/common/runtime"
+	"github.com/kyma-project/kyma-environment-broker/internal/httputil"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage/dberr"
+)
+
+const reasonParam = "reason"
+
+type router interface {
+	HandleFunc(pattern string, handler func(http.ResponseWriter, *http.Request))
+}
+
+type Handler struct {
+	inspector *Inspector
+	cfg       Config
+	log       *slog.Logger
+}
+
+func NewHandler(inspector *Inspector, cfg Config, log *slog.Logger) *Handler {
+	return &Handler{
+		inspector: inspector,
+		cfg:       cfg,
+		log:       log.With("service", "ArchivedInstancesEndpoint"),
+	}
+}
+
+func (h *Handler) AttachRoutes(r router) {
+	r.HandleFunc("GET /archived-instances/export", h.exportBundle)
+	r.HandleFunc("GET /archived-instances/{instance_id}", h.getInstance)
+}
+
+func (h *Handler) getInstance(w http.ResponseWriter, req *http.Request) {
+	instanceID := req.PathValue("instance_id")
+	logger := h.log.With("instanceID", instanceID)
+
+	details, err := h.inspector.Get(instanceID)
+	if err != nil {
+		logger.Warn(fmt.Sprintf("unable to get archived instance: %s", err.Error()))
+		switch {
+		case dberr.IsNotFound(err):
+			httputil.WriteErrorResponse(w, http.StatusNotFound, fmt.Errorf("archived instance %s not found", instanceID))
+		default:
+			httputil.WriteErrorResponse(w, http.StatusInternalServerError, err)
+		}
+		return
+	}
+	httputil.WriteResponse(w, http.StatusOK, details)
+}
+
+func (h *Handler) exportBundle(w http.ResponseWriter, req *http.Request) {
+	filter := BundleFilterFromQuery(req)
+	if filter.IsEmpty() {
+		httputil.WriteErrorResponse(w, http.StatusBadRequest, errors.New("at least one filter is required"))
+		return
+	}
+	reason := req.URL.Query().Get(reasonParam)
+	h.log.Info(fmt.Sprintf("Export of archived instances requested, filter: %+v, reason: %q", filter, reason))
+
+	bundle, err := h.inspector.Export(filter, reason, h.cfg)
+	if err != nil {
+		h.log.Warn(fmt.Sprintf("unable to export archived instances: %s", err.Error()))
+		switch {
+		case errors.Is(err, ErrSigningKeyMissing):
+			httputil.WriteErrorResponse(w, http.StatusNotImplemented, err)
+		case errors.Is(err, ErrTooManyInstances):
+			httputil.WriteErrorResponse(w, http.StatusUnprocessableEntity, err)
+		default:
+			httputil.WriteErrorResponse(w, http.StatusInternalServerError, err)
+		}
+		return
+	}
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fmt.Sprintf("archived-instances-%s.json", bundle.ExportedAt.Format("20060102T150405Z"))))
+	httputil.WriteResponse(w, http.StatusOK, bundle)
+	h.log.Info(fmt.Sprintf("Exported %d archived instances", len(bundle.Instances)))
+}
+
+// BundleFilterFromQuery reads the filters with the same query parameters as the runtimes endpoint
+func BundleFilterFromQuery(req *http.Request) BundleFilter {
+	query := req.URL.Query()
+	return BundleFilter{
+		GlobalAccountIDs: query[pkg.GlobalAccountIDParam],
+		SubAccountIDs:    query[pkg.SubAccountIDParam],
+		InstanceIDs:      query[pkg.InstanceIDParam],
+		RuntimeIDs:       query[pkg.RuntimeIDParam],
+		Regions:          query[pkg.RegionParam],
+		Plans:            query[pkg.PlanParam],
+		Shoots:           query[pkg.ShootParam],
+	}
+}
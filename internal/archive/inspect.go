@@ -0,0 +1,245 @@
+package archive
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/kyma-project/kyma-environment-broker/common/events"
+	"github.com/kyma-project/kyma-environment-broker/internal"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage/dbmodel"
+)
+
+// TimelineSource tells where the type and the state of an operation in the timeline come from
+type TimelineSource string
+
+const (
+	// TimelineSourceOperation means the operation still exists in the operations table
+	TimelineSourceOperation TimelineSource = "operation"
+	// TimelineSourceInferred means the type and the state are inferred from the timestamps of the archived instance
+	TimelineSourceInferred TimelineSource = "inferred"
+	// TimelineSourceEvents means only the events of the operation are known
+	TimelineSourceEvents TimelineSource = "events"
+)
+
+// InstanceDetails is an archived instance with its operations timeline rebuilt from events
+type InstanceDetails struct {
+	Instance   ArchivedInstance    `json:"instance"`
+	Operations []OperationTimeline `json:"operations"`
+	// InstanceEvents are events of the instance which are not related to any operation
+	InstanceEvents []TimelineEvent `json:"instanceEvents,omitempty"`
+}
+
+type ArchivedInstance struct {
+	InstanceID                  string `json:"instanceID"`
+	GlobalAccountID             string `json:"globalAccountID"`
+	SubaccountID                string `json:"subaccountID"`
+	SubscriptionGlobalAccountID string `json:"subscriptionGlobalAccountID,omitempty"`
+	PlanID                      string `json:"planID"`
+	PlanName                    string `json:"planName"`
+	SubaccountRegion            string `json:"subaccountRegion"`
+	Region                      string `json:"region"`
+	Provider                    string `json:"provider"`
+	LastRuntimeID               string `json:"lastRuntimeID"`
+	InternalUser                bool   `json:"internalUser"`
+	ShootName                   string `json:"shootName"`
+
+	ProvisioningStartedAt         time.Time `json:"provisioningStartedAt"`
+	ProvisioningFinishedAt        time.Time `json:"provisioningFinishedAt"`
+	ProvisioningState             string    `json:"provisioningState"`
+	FirstDeprovisioningStartedAt  time.Time `json:"firstDeprovisioningStartedAt"`
+	FirstDeprovisioningFinishedAt time.Time `json:"firstDeprovisioningFinishedAt"`
+	LastDeprovisioningFinishedAt  time.Time `json:"lastDeprovisioningFinishedAt"`
+}
+
+type OperationTimeline struct {
+	OperationID string                 `json:"operationID"`
+	Type        internal.OperationType `json:"type,omitempty"`
+	State       string                 `json:"state,omitempty"`
+	Source      TimelineSource         `json:"source"`
+	StartedAt   time.Time              `json:"startedAt"`
+	FinishedAt  time.Time              `json:"finishedAt"`
+	Errors      int                    `json:"errors"`
+	Events      []TimelineEvent        `json:"events"`
+}
+
+type TimelineEvent struct {
+	ID        string            `json:"id"`
+	Level     events.EventLevel `json:"level"`
+	Message   string            `json:"message"`
+	CreatedAt time.Time         `json:"createdAt"`
+}
+
+// EventsReader provides the events of an instance, including the events archived by the events retention
+type EventsReader interface {
+	ListWithArchivedByInstanceID(instanceID string) ([]events.EventDTO, error)
+}
+
+// Inspector retrieves archived instances for support cases. It does not recreate the instances, the operations
+// deleted by the archiving are rebuilt from the events of the instance.
+type Inspector struct {
+	archived   storage.InstancesArchived
+	operations storage.Operations
+	events     EventsReader
+}
+
+func NewInspector(db storage.BrokerStorage) *Inspector {
+	return &Inspector{
+		archived:   db.InstancesArchived(),
+		operations: db.Operations(),
+		events:     db.ArchivedEvents(),
+	}
+}
+
+// Get returns dberr.NotFound if the instance is not archived
+func (i *Inspector) Get(instanceID string) (InstanceDetails, error) {
+	instance, err := i.archived.GetByInstanceID(instanceID)
+	if err != nil {
+		return InstanceDetails{}, err
+	}
+	return i.details(instance)
+}
+
+// List returns the archived instances matching the filter, it fails if there are more than limit of them
+func (i *Inspector) List(filter dbmodel.InstanceFilter, limit int) ([]InstanceDetails, error) {
+	filter.Page = 1
+	filter.PageSize = limit + 1
+	instances, count, _, err := i.archived.List(filter)
+	if err != nil {
+		return nil, fmt.Errorf("while listing archived instances: %w", err)
+	}
+	if count > limit {
+		return nil, fmt.Errorf("%w: more than %d archived instances match the filter", ErrTooManyInstances, limit)
+	}
+	result := make([]InstanceDetails, 0, len(instances))
+	for _, instance := range instances {
+		details, err := i.details(instance)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, details)
+	}
+	return result, nil
+}
+
+func (i *Inspector) details(instance internal.InstanceArchived) (InstanceDetails, error) {
+	instanceEvents, err := i.events.ListWithArchivedByInstanceID(instance.InstanceID)
+	if err != nil {
+		return InstanceDetails{}, fmt.Errorf("while getting events of instance %s: %w", instance.InstanceID, err)
+	}
+	// operations are kept if the archiving runs without deleting them
+	operations, err := i.operations.ListOperationsByInstanceID(instance.InstanceID)
+	if err != nil {
+		return InstanceDetails{}, fmt.Errorf("while getting operations of instance %s: %w", instance.InstanceID, err)
+	}
+	return NewInstanceDetails(instance, operations, instanceEvents), nil
+}
+
+// NewInstanceDetails rebuilds the operations timeline from events of the instance. The type and the state of an operation
+// are taken from the operation if it still exists, otherwise the first provisioning and deprovisioning operations
+// are recognized by the timestamps of the archived instance.
+func NewInstanceDetails(instance internal.InstanceArchived, operations []internal.Operation, instanceEvents []events.EventDTO) InstanceDetails {
+	details := InstanceDetails{Instance: newArchivedInstance(instance)}
+
+	timelines := make(map[string]*OperationTimeline)
+	var order []string
+	for _, ev := range instanceEvents {
+		timelineEvent := TimelineEvent{ID: ev.ID, Level: ev.Level, Message: ev.Message, CreatedAt: ev.CreatedAt}
+		if ev.OperationID == nil || *ev.OperationID == "" {
+			details.InstanceEvents = append(details.InstanceEvents, timelineEvent)
+			continue
+		}
+		timeline, found := timelines[*ev.OperationID]
+		if !found {
+			timeline = &OperationTimeline{OperationID: *ev.OperationID, Source: TimelineSourceEvents, StartedAt: ev.CreatedAt}
+			timelines[*ev.OperationID] = timeline
+			order = append(order, *ev.OperationID)
+		}
+		timeline.Events = append(timeline.Events, timelineEvent)
+		timeline.StartedAt = minTime(timeline.StartedAt, ev.CreatedAt)
+		if ev.CreatedAt.After(timeline.FinishedAt) {
+			timeline.FinishedAt = ev.CreatedAt
+		}
+		if ev.Level == events.ErrorEventLevel {
+			timeline.Errors++
+		}
+	}
+
+	for _, operation := range operations {
+		timeline, found := timelines[operation.ID]
+		if !found {
+			timeline = &OperationTimeline{OperationID: operation.ID, StartedAt: operation.CreatedAt, FinishedAt: operation.UpdatedAt}
+			timelines[operation.ID] = timeline
+			order = append(order, operation.ID)
+		}
+		timeline.Type = operation.Type
+		timeline.State = string(operation.State)
+		timeline.Source = TimelineSourceOperation
+		timeline.StartedAt = minTime(timeline.StartedAt, operation.CreatedAt)
+		if operation.UpdatedAt.After(timeline.FinishedAt) {
+			timeline.FinishedAt = operation.UpdatedAt
+		}
+	}
+
+	for _, id := range order {
+		timeline := timelines[id]
+		if timeline.Source == TimelineSourceEvents {
+			inferOperation(timeline, instance)
+		}
+		details.Operations = append(details.Operations, *timeline)
+	}
+	slices.SortStableFunc(details.Operations, func(a, b OperationTimeline) int {
+		return a.StartedAt.Compare(b.StartedAt)
+	})
+	return details
+}
+
+func inferOperation(timeline *OperationTimeline, instance internal.InstanceArchived) {
+	switch {
+	case within(timeline.StartedAt, instance.ProvisioningStartedAt, instance.ProvisioningFinishedAt):
+		timeline.Type = internal.OperationTypeProvision
+		timeline.State = string(instance.ProvisioningState)
+		timeline.Source = TimelineSourceInferred
+	case within(timeline.StartedAt, instance.FirstDeprovisioningStartedAt, instance.FirstDeprovisioningFinishedAt):
+		timeline.Type = internal.OperationTypeDeprovision
+		timeline.Source = TimelineSourceInferred
+	}
+}
+
+func within(t, from, to time.Time) bool {
+	if from.IsZero() || to.IsZero() {
+		return false
+	}
+	return !t.Before(from) && !t.After(to)
+}
+
+func minTime(a, b time.Time) time.Time {
+	if a.IsZero() || b.Before(a) {
+		return b
+	}
+	return a
+}
+
+func newArchivedInstance(instance internal.InstanceArchived) ArchivedInstance {
+	return ArchivedInstance{
+		InstanceID:                    instance.InstanceID,
+		GlobalAccountID:               instance.GlobalAccountID,
+		SubaccountID:                  instance.SubaccountID,
+		SubscriptionGlobalAccountID:   instance.SubscriptionGlobalAccountID,
+		PlanID:                        instance.PlanID,
+		PlanName:                      instance.PlanName,
+		SubaccountRegion:              instance.SubaccountRegion,
+		Region:                        instance.Region,
+		Provider:                      instance.Provider,
+		LastRuntimeID:                 instance.LastRuntimeID,
+		InternalUser:                  instance.InternalUser,
+		ShootName:                     instance.ShootName,
+		ProvisioningStartedAt:         instance.ProvisioningStartedAt,
+		ProvisioningFinishedAt:        instance.ProvisioningFinishedAt,
+		ProvisioningState:             string(instance.ProvisioningState),
+		FirstDeprovisioningStartedAt:  instance.FirstDeprovisioningStartedAt,
+		FirstDeprovisioningFinishedAt: instance.FirstDeprovisioningFinishedAt,
+		LastDeprovisioningFinishedAt:  instance.LastDeprovisioningFinishedAt,
+	}
+}
@@ -0,0 +1,249 @@
+package archive
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/kyma-project/kyma-environment-broker/common/events"
+	"github.com/kyma-project/kyma-environment-broker/internal"
+	"github.com/kyma-project/kyma-environment-broker/internal/httputil"
+	"github.com/kyma-project/kyma-environment-broker/internal/ptr"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage"
+	"github.com/pivotal-cf/brokerapi/v12/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var provisionedAt = time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+
+type fakeEventsReader struct {
+	events []events.EventDTO
+}
+
+func (f *fakeEventsReader) ListWithArchivedByInstanceID(instanceID string) ([]events.EventDTO, error) {
+	var result []events.EventDTO
+	for _, ev := range f.events {
+		if *ev.InstanceID == instanceID {
+			result = append(result, ev)
+		}
+	}
+	return result, nil
+}
+
+func TestNewInstanceDetails(t *testing.T) {
+	t.Run("should rebuild operations timeline from events", func(t *testing.T) {
+		// given
+		instance := fixArchivedInstance("instance-1", "subaccount-1")
+
+		// when
+		details := NewInstanceDetails(instance, nil, fixInstanceEvents("instance-1"))
+
+		// then
+		assert.Equal(t, "instance-1", details.Instance.InstanceID)
+		assert.Equal(t, "succeeded", details.Instance.ProvisioningState)
+		require.Len(t, details.Operations, 3)
+
+		assert.Equal(t, "provisioning-op", details.Operations[0].OperationID)
+		assert.Equal(t, internal.OperationTypeProvision, details.Operations[0].Type)
+		assert.Equal(t, string(domain.Succeeded), details.Operations[0].State)
+		assert.Equal(t, TimelineSourceInferred, details.Operations[0].Source)
+		assert.Equal(t, provisionedAt.Add(time.Minute), details.Operations[0].StartedAt)
+		assert.Equal(t, provisionedAt.Add(10*time.Minute), details.Operations[0].FinishedAt)
+		assert.Len(t, details.Operations[0].Events, 2)
+
+		assert.Equal(t, "update-op", details.Operations[1].OperationID)
+		assert.Empty(t, details.Operations[1].Type)
+		assert.Equal(t, TimelineSourceEvents, details.Operations[1].Source)
+		assert.Equal(t, 1, details.Operations[1].Errors)
+
+		assert.Equal(t, "deprovisioning-op", details.Operations[2].OperationID)
+		assert.Equal(t, internal.OperationTypeDeprovision, details.Operations[2].Type)
+		assert.Equal(t, TimelineSourceInferred, details.Operations[2].Source)
+
+		require.Len(t, details.InstanceEvents, 1)
+		assert.Equal(t, "instance event", details.InstanceEvents[0].Message)
+	})
+
+	t.Run("should take type and state from existing operations", func(t *testing.T) {
+		// given
+		instance := fixArchivedInstance("instance-1", "subaccount-1")
+		operations := []internal.Operation{
+			{ID: "update-op", InstanceID: "instance-1", Type: internal.OperationTypeUpdate, State: domain.Failed,
+				CreatedAt: provisionedAt.Add(24*time.Hour - time.Minute), UpdatedAt: provisionedAt.Add(25 * time.Hour)},
+			{ID: "upgrade-op", InstanceID: "instance-1", Type: internal.OperationTypeUpgradeCluster, State: domain.Succeeded,
+				CreatedAt: provisionedAt.Add(30 * time.Hour), UpdatedAt: provisionedAt.Add(31 * time.Hour)},
+		}
+
+		// when
+		details := NewInstanceDetails(instance, operations, fixInstanceEvents("instance-1"))
+
+		// then
+		require.Len(t, details.Operations, 4)
+		update := details.Operations[1]
+		assert.Equal(t, "update-op", update.OperationID)
+		assert.Equal(t, internal.OperationTypeUpdate, update.Type)
+		assert.Equal(t, string(domain.Failed), update.State)
+		assert.Equal(t, TimelineSourceOperation, update.Source)
+		assert.Equal(t, provisionedAt.Add(24*time.Hour-time.Minute), update.StartedAt)
+		assert.Equal(t, provisionedAt.Add(25*time.Hour), update.FinishedAt)
+
+		upgrade := details.Operations[2]
+		assert.Equal(t, "upgrade-op", upgrade.OperationID)
+		assert.Equal(t, TimelineSourceOperation, upgrade.Source)
+		assert.Empty(t, upgrade.Events)
+	})
+}
+
+func TestHandler(t *testing.T) {
+	db := storage.NewMemoryStorage()
+	require.NoError(t, db.InstancesArchived().Insert(fixArchivedInstance("instance-1", "subaccount-1")))
+	require.NoError(t, db.InstancesArchived().Insert(fixArchivedInstance("instance-2", "subaccount-1")))
+	require.NoError(t, db.InstancesArchived().Insert(fixArchivedInstance("instance-3", "subaccount-2")))
+	inspector := &Inspector{
+		archived:   db.InstancesArchived(),
+		operations: db.Operations(),
+		events:     &fakeEventsReader{events: append(fixInstanceEvents("instance-1"), fixInstanceEvents("instance-2")...)},
+	}
+	log := slog.New(slog.NewTextHandler(os.Stderr, nil))
+
+	newRouter := func(cfg Config) *httputil.Router {
+		router := httputil.NewRouter()
+		NewHandler(inspector, cfg, log).AttachRoutes(router)
+		return router
+	}
+	publicKey, signingKey := fixSigningKey(t)
+	otherPublicKey, _ := fixSigningKey(t)
+	cfg := Config{BundleSigningKey: signingKey, MaxExportedInstances: 2}
+
+	t.Run("should return archived instance with timeline", func(t *testing.T) {
+		// when
+		resp := httptest.NewRecorder()
+		newRouter(cfg).ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/archived-instances/instance-1", nil))
+
+		// then
+		require.Equal(t, http.StatusOK, resp.Code)
+		var details InstanceDetails
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &details))
+		assert.Equal(t, "subaccount-1", details.Instance.SubaccountID)
+		assert.Len(t, details.Operations, 3)
+	})
+
+	t.Run("should return not found for not archived instance", func(t *testing.T) {
+		// when
+		resp := httptest.NewRecorder()
+		newRouter(cfg).ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/archived-instances/unknown", nil))
+
+		// then
+		assert.Equal(t, http.StatusNotFound, resp.Code)
+	})
+
+	t.Run("should export signed bundle", func(t *testing.T) {
+		// when
+		resp := httptest.NewRecorder()
+		newRouter(cfg).ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/archived-instances/export?subaccount=subaccount-1&reason=legal+hold", nil))
+
+		// then
+		require.Equal(t, http.StatusOK, resp.Code)
+		assert.Contains(t, resp.Header().Get("Content-Disposition"), "attachment")
+		var bundle Bundle
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &bundle))
+		assert.Equal(t, "legal hold", bundle.Reason)
+		assert.Equal(t, []string{"subaccount-1"}, bundle.Filter.SubAccountIDs)
+		assert.Len(t, bundle.Instances, 2)
+		assert.Equal(t, BundleSignatureAlgorithm, bundle.Signature.Algorithm)
+		assert.NoError(t, VerifyBundle(bundle, publicKey))
+		assert.ErrorIs(t, VerifyBundle(bundle, otherPublicKey), ErrInvalidSignature)
+
+		bundle.Instances[0].Operations[0].Events[0].Message = "modified"
+		assert.ErrorIs(t, VerifyBundle(bundle, publicKey), ErrInvalidSignature)
+	})
+
+	t.Run("should reject export without filters", func(t *testing.T) {
+		// when
+		resp := httptest.NewRecorder()
+		newRouter(cfg).ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/archived-instances/export", nil))
+
+		// then
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+	})
+
+	t.Run("should reject export of too many instances", func(t *testing.T) {
+		// when
+		resp := httptest.NewRecorder()
+		newRouter(Config{BundleSigningKey: signingKey, MaxExportedInstances: 1}).
+			ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/archived-instances/export?subaccount=subaccount-1", nil))
+
+		// then
+		assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+	})
+
+	t.Run("should not export without signing key", func(t *testing.T) {
+		// when
+		resp := httptest.NewRecorder()
+		newRouter(Config{MaxExportedInstances: 2}).
+			ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/archived-instances/export?subaccount=subaccount-1", nil))
+
+		// then
+		assert.Equal(t, http.StatusNotImplemented, resp.Code)
+	})
+
+	t.Run("should reject invalid keys", func(t *testing.T) {
+		_, err := ParseSigningKey([]byte("signing-key"))
+		assert.ErrorIs(t, err, ErrInvalidKey)
+		_, err = ParseVerificationKey([]byte(signingKey))
+		assert.ErrorIs(t, err, ErrInvalidKey)
+	})
+}
+
+// fixSigningKey returns a new Ed25519 key pair, the private key is PEM encoded as in the bundle signing key Secret
+func fixSigningKey(t *testing.T) (ed25519.PublicKey, string) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	require.NoError(t, err)
+	return publicKey, string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+}
+
+func fixArchivedInstance(instanceID, subaccountID string) internal.InstanceArchived {
+	return internal.InstanceArchived{
+		InstanceID:                    instanceID,
+		GlobalAccountID:               "global-account-1",
+		SubaccountID:                  subaccountID,
+		PlanName:                      "aws",
+		ProvisioningStartedAt:         provisionedAt,
+		ProvisioningFinishedAt:        provisionedAt.Add(15 * time.Minute),
+		ProvisioningState:             domain.Succeeded,
+		FirstDeprovisioningStartedAt:  provisionedAt.Add(48 * time.Hour),
+		FirstDeprovisioningFinishedAt: provisionedAt.Add(49 * time.Hour),
+		LastDeprovisioningFinishedAt:  provisionedAt.Add(49 * time.Hour),
+	}
+}
+
+func fixInstanceEvents(instanceID string) []events.EventDTO {
+	event := func(operationID string, level events.EventLevel, message string, createdAt time.Time) events.EventDTO {
+		return events.EventDTO{
+			ID:          instanceID + "-" + createdAt.Format(time.RFC3339),
+			Level:       level,
+			InstanceID:  ptr.String(instanceID),
+			OperationID: ptr.String(operationID),
+			Message:     message,
+			CreatedAt:   createdAt,
+		}
+	}
+	return []events.EventDTO{
+		event("provisioning-op", events.InfoEventLevel, "processing step: start", provisionedAt.Add(time.Minute)),
+		event("provisioning-op", events.InfoEventLevel, "processing step: create runtime", provisionedAt.Add(10*time.Minute)),
+		event("", events.InfoEventLevel, "instance event", provisionedAt.Add(20*time.Minute)),
+		event("update-op", events.ErrorEventLevel, "step failed", provisionedAt.Add(24*time.Hour)),
+		event("deprovisioning-op", events.InfoEventLevel, "processing step: remove runtime", provisionedAt.Add(48*time.Hour+time.Minute)),
+	}
+}
@@ -0,0 +1,50 @@
+package bindingsreconciler
+
+import (
+	bindings "github.com/kyma-project/kyma-environment-broker/internal/broker/bindings"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var resourceKinds = []string{"ServiceAccount", "ClusterRole", "ClusterRoleBinding", "Role", "RoleBinding"}
+
+type Metrics struct {
+	orphanedResources *prometheus.GaugeVec
+	removedResources  *prometheus.CounterVec
+	failedRuntimes    prometheus.Gauge
+}
+
+func NewMetrics(reg prometheus.Registerer, namespace string) *Metrics {
+	m := &Metrics{
+		orphanedResources: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "orphaned_binding_resources",
+			Help:      "Resources of orphaned or expired bindings found on runtimes in the last bindings reconciliation.",
+		}, []string{"kind"}),
+		removedResources: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "removed_binding_resources_total",
+			Help:      "Resources of orphaned or expired bindings removed from runtimes.",
+		}, []string{"kind"}),
+		failedRuntimes: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "binding_reconciliation_failed_runtimes",
+			Help:      "Runtimes whose bindings could not be reconciled in the last bindings reconciliation.",
+		}),
+	}
+	reg.MustRegister(m.orphanedResources, m.removedResources, m.failedRuntimes)
+	return m
+}
+
+func (m *Metrics) setDrift(drift map[string]int, failedRuntimes int) {
+	for _, kind := range resourceKinds {
+		m.orphanedResources.WithLabelValues(kind).Set(float64(drift[kind]))
+	}
+	m.failedRuntimes.Set(float64(failedRuntimes))
+}
+
+func (m *Metrics) resourcesRemoved(resources []bindings.BindingResource) {
+	for _, resource := range resources {
+		m.removedResources.WithLabelValues(resource.Kind).Inc()
+	}
+}
@@ -0,0 +1,213 @@
+package bindingsreconciler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/kyma-project/kyma-environment-broker/internal"
+	bindings "github.com/kyma-project/kyma-environment-broker/internal/broker/bindings"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage/dberr"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage/dbmodel"
+
+	"k8s.io/client-go/kubernetes"
+)
+
+type Config struct {
+	Enabled  bool          `envconfig:"default=false"`
+	DryRun   bool          `envconfig:"default=true"`
+	Interval time.Duration `envconfig:"default=1h"`
+	// GracePeriod protects resources of bindings which are being created, younger resources are never removed
+	GracePeriod time.Duration `envconfig:"default=10m"`
+	// MaxRuntimesPerRun limits the number of runtimes checked in one run, the next run continues with the following runtimes
+	MaxRuntimesPerRun int `envconfig:"default=100"`
+	// MaxDeletionsPerRun limits the number of bindings whose resources are removed in one run
+	MaxDeletionsPerRun int `envconfig:"default=50"`
+}
+
+type ClientProvider interface {
+	K8sClientSetForRuntimeID(runtimeID string) (kubernetes.Interface, error)
+}
+
+type Result struct {
+	Runtimes         int
+	FailedRuntimes   int
+	OrphanedBindings int
+	ExpiredBindings  int
+	RemovedBindings  int
+}
+
+// Reconciler removes the service accounts and RBAC objects created by KEB for bindings from runtimes when the binding
+// no longer exists in the storage or is expired, for example, because the runtime was not reachable during the unbinding.
+type Reconciler struct {
+	cfg            Config
+	instances      storage.Instances
+	bindings       storage.Bindings
+	clientProvider ClientProvider
+	metrics        *Metrics
+	log            *slog.Logger
+	now            func() time.Time
+
+	// offset is the position of the first runtime checked in the next run
+	offset int
+}
+
+func NewReconciler(cfg Config, instances storage.Instances, bindingsStorage storage.Bindings, clientProvider ClientProvider, metrics *Metrics, log *slog.Logger) *Reconciler {
+	return &Reconciler{
+		cfg:            cfg,
+		instances:      instances,
+		bindings:       bindingsStorage,
+		clientProvider: clientProvider,
+		metrics:        metrics,
+		log:            log.With("service", "bindings-reconciler"),
+		now:            time.Now,
+	}
+}
+
+// Start runs the reconciliation periodically until the context is cancelled
+func (r *Reconciler) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(r.cfg.Interval)
+		defer ticker.Stop()
+		for {
+			result, err := r.Run(ctx)
+			if err != nil {
+				r.log.Error(fmt.Sprintf("bindings reconciliation failed: %s", err))
+			} else {
+				r.log.Info(fmt.Sprintf("bindings reconciliation finished: %d runtimes checked, %d failed, %d orphaned and %d expired bindings found, %d bindings removed",
+					result.Runtimes, result.FailedRuntimes, result.OrphanedBindings, result.ExpiredBindings, result.RemovedBindings))
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Run checks the next runtimes and removes the resources of orphaned and expired bindings. In the dry run mode, the drift is only reported.
+func (r *Reconciler) Run(ctx context.Context) (Result, error) {
+	var result Result
+	instances, err := r.candidates()
+	if err != nil {
+		return result, err
+	}
+
+	drift := map[string]int{}
+	for _, instance := range instances {
+		result.Runtimes++
+		err := r.reconcileRuntime(ctx, instance, drift, &result)
+		if err != nil {
+			r.log.Warn(fmt.Sprintf("unable to reconcile bindings of runtime %s (instance %s): %s", instance.RuntimeID, instance.InstanceID, err))
+			result.FailedRuntimes++
+		}
+	}
+
+	r.metrics.setDrift(drift, result.FailedRuntimes)
+	return result, nil
+}
+
+// candidates returns the runtimes checked in this run. The runtimes are sorted, so consecutive runs go through all of them.
+func (r *Reconciler) candidates() ([]internal.Instance, error) {
+	allInstances, _, _, err := r.instances.List(dbmodel.InstanceFilter{})
+	if err != nil {
+		return nil, fmt.Errorf("while getting instances: %w", err)
+	}
+
+	var instances []internal.Instance
+	for _, instance := range allInstances {
+		if instance.Reconcilable {
+			instances = append(instances, instance)
+		}
+	}
+	sort.Slice(instances, func(i, j int) bool { return instances[i].InstanceID < instances[j].InstanceID })
+
+	if r.cfg.MaxRuntimesPerRun <= 0 || len(instances) <= r.cfg.MaxRuntimesPerRun {
+		r.offset = 0
+		return instances, nil
+	}
+	if r.offset >= len(instances) {
+		r.offset = 0
+	}
+	selected := make([]internal.Instance, 0, r.cfg.MaxRuntimesPerRun)
+	for i := 0; i < r.cfg.MaxRuntimesPerRun; i++ {
+		selected = append(selected, instances[(r.offset+i)%len(instances)])
+	}
+	r.offset = (r.offset + r.cfg.MaxRuntimesPerRun) % len(instances)
+	return selected, nil
+}
+
+func (r *Reconciler) reconcileRuntime(ctx context.Context, instance internal.Instance, drift map[string]int, result *Result) error {
+	clientset, err := r.clientProvider.K8sClientSetForRuntimeID(instance.RuntimeID)
+	if err != nil {
+		return fmt.Errorf("while creating a runtime client: %w", err)
+	}
+	resources, err := bindings.ListBindingResources(ctx, clientset)
+	if err != nil {
+		return err
+	}
+
+	resourcesByBinding := map[string][]bindings.BindingResource{}
+	var bindingIDs []string
+	for _, resource := range resources {
+		if _, found := resourcesByBinding[resource.BindingID]; !found {
+			bindingIDs = append(bindingIDs, resource.BindingID)
+		}
+		resourcesByBinding[resource.BindingID] = append(resourcesByBinding[resource.BindingID], resource)
+	}
+
+	now := r.now()
+	for _, bindingID := range bindingIDs {
+		bindingResources := resourcesByBinding[bindingID]
+		if r.withinGracePeriod(bindingResources, now) {
+			continue
+		}
+
+		binding, err := r.bindings.Get(instance.InstanceID, bindingID)
+		switch {
+		case dberr.IsNotFound(err):
+			result.OrphanedBindings++
+			r.log.Info(fmt.Sprintf("found %d resources of binding %s on runtime %s without the binding in the storage", len(bindingResources), bindingID, instance.RuntimeID))
+		case err != nil:
+			return fmt.Errorf("while getting binding %s: %w", bindingID, err)
+		case binding.ExpiresAt.Before(now):
+			result.ExpiredBindings++
+			r.log.Info(fmt.Sprintf("found %d resources of binding %s on runtime %s expired at %s", len(bindingResources), bindingID, instance.RuntimeID, binding.ExpiresAt.Format(time.RFC3339)))
+		default:
+			continue
+		}
+
+		for _, resource := range bindingResources {
+			drift[resource.Kind]++
+		}
+		if r.cfg.DryRun {
+			continue
+		}
+		if result.RemovedBindings >= r.cfg.MaxDeletionsPerRun {
+			r.log.Info(fmt.Sprintf("limit of %d removed bindings per run reached, binding %s on runtime %s is removed in the next run", r.cfg.MaxDeletionsPerRun, bindingID, instance.RuntimeID))
+			continue
+		}
+		if err := bindings.DeleteBindingResources(ctx, clientset, bindingID); err != nil {
+			return fmt.Errorf("while removing resources of binding %s: %w", bindingID, err)
+		}
+		result.RemovedBindings++
+		r.metrics.resourcesRemoved(bindingResources)
+		r.log.Info(fmt.Sprintf("removed resources of binding %s from runtime %s", bindingID, instance.RuntimeID))
+	}
+
+	return nil
+}
+
+func (r *Reconciler) withinGracePeriod(resources []bindings.BindingResource, now time.Time) bool {
+	for _, resource := range resources {
+		if resource.CreatedAt.Add(r.cfg.GracePeriod).After(now) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,227 @@
+package bindingsreconciler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	bindings "github.com/kyma-project/kyma-environment-broker/internal/broker/bindings"
+	"github.com/kyma-project/kyma-environment-broker/internal/fixture"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	mv1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestReconciler(t *testing.T) {
+	t.Run("should only report the drift in the dry run mode", func(t *testing.T) {
+		// given
+		db, provider := fixRuntimes(t, "runtime-1")
+		fixBindingResources(provider, "runtime-1", "orphaned", time.Now().Add(-time.Hour))
+		cfg := fixConfig()
+		cfg.DryRun = true
+		reconciler, metrics := fixReconciler(cfg, db, provider)
+
+		// when
+		result, err := reconciler.Run(context.Background())
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.OrphanedBindings)
+		assert.Equal(t, 0, result.RemovedBindings)
+		assertServiceAccountExists(t, provider, "runtime-1", "orphaned", true)
+		assert.Equal(t, float64(1), testutil.ToFloat64(metrics.orphanedResources.WithLabelValues("ClusterRoleBinding")))
+		assert.Equal(t, float64(0), testutil.ToFloat64(metrics.removedResources.WithLabelValues("ClusterRoleBinding")))
+	})
+
+	t.Run("should remove resources of orphaned and expired bindings", func(t *testing.T) {
+		// given
+		db, provider := fixRuntimes(t, "runtime-1")
+		created := time.Now().Add(-time.Hour)
+		fixBindingResources(provider, "runtime-1", "orphaned", created)
+		fixBindingResources(provider, "runtime-1", "expired", created)
+		fixBindingResources(provider, "runtime-1", "active", created)
+		fixBindingResources(provider, "runtime-1", "young", time.Now())
+		insertBinding(t, db, "runtime-1", "expired", time.Now().Add(-time.Minute))
+		insertBinding(t, db, "runtime-1", "active", time.Now().Add(time.Hour))
+		reconciler, metrics := fixReconciler(fixConfig(), db, provider)
+
+		// when
+		result, err := reconciler.Run(context.Background())
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, Result{Runtimes: 1, OrphanedBindings: 1, ExpiredBindings: 1, RemovedBindings: 2}, result)
+		assertServiceAccountExists(t, provider, "runtime-1", "orphaned", false)
+		assertServiceAccountExists(t, provider, "runtime-1", "expired", false)
+		assertServiceAccountExists(t, provider, "runtime-1", "active", true)
+		assertServiceAccountExists(t, provider, "runtime-1", "young", true)
+
+		clientset := provider.clientsets["runtime-1"]
+		_, err = clientset.RbacV1().RoleBindings("team-a").Get(context.Background(), bindings.BindingName("orphaned"), mv1.GetOptions{})
+		assert.True(t, apierrors.IsNotFound(err))
+		_, err = clientset.RbacV1().ClusterRoles().Get(context.Background(), bindings.BindingName("orphaned"), mv1.GetOptions{})
+		assert.True(t, apierrors.IsNotFound(err))
+		assert.Equal(t, float64(2), testutil.ToFloat64(metrics.removedResources.WithLabelValues("ServiceAccount")))
+	})
+
+	t.Run("should respect the limit of removed bindings", func(t *testing.T) {
+		// given
+		db, provider := fixRuntimes(t, "runtime-1")
+		for i := 0; i < 3; i++ {
+			fixBindingResources(provider, "runtime-1", fmt.Sprintf("orphaned-%d", i), time.Now().Add(-time.Hour))
+		}
+		cfg := fixConfig()
+		cfg.MaxDeletionsPerRun = 2
+		reconciler, _ := fixReconciler(cfg, db, provider)
+
+		// when
+		result, err := reconciler.Run(context.Background())
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 3, result.OrphanedBindings)
+		assert.Equal(t, 2, result.RemovedBindings)
+
+		// when
+		result, err = reconciler.Run(context.Background())
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.OrphanedBindings)
+		assert.Equal(t, 1, result.RemovedBindings)
+	})
+
+	t.Run("should check the limited number of runtimes in consecutive runs", func(t *testing.T) {
+		// given
+		db, provider := fixRuntimes(t, "runtime-1", "runtime-2", "runtime-3")
+		for _, runtimeID := range []string{"runtime-1", "runtime-2", "runtime-3"} {
+			fixBindingResources(provider, runtimeID, "orphaned", time.Now().Add(-time.Hour))
+		}
+		cfg := fixConfig()
+		cfg.MaxRuntimesPerRun = 2
+		reconciler, _ := fixReconciler(cfg, db, provider)
+
+		// when
+		first, err := reconciler.Run(context.Background())
+		require.NoError(t, err)
+		second, err := reconciler.Run(context.Background())
+		require.NoError(t, err)
+
+		// then
+		assert.Equal(t, 2, first.Runtimes)
+		assert.Equal(t, 2, second.Runtimes)
+		for _, runtimeID := range []string{"runtime-1", "runtime-2", "runtime-3"} {
+			assertServiceAccountExists(t, provider, runtimeID, "orphaned", false)
+		}
+	})
+
+	t.Run("should continue when a runtime is not reachable", func(t *testing.T) {
+		// given
+		db, provider := fixRuntimes(t, "runtime-1", "unreachable")
+		delete(provider.clientsets, "unreachable")
+		fixBindingResources(provider, "runtime-1", "orphaned", time.Now().Add(-time.Hour))
+		reconciler, metrics := fixReconciler(fixConfig(), db, provider)
+
+		// when
+		result, err := reconciler.Run(context.Background())
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.FailedRuntimes)
+		assert.Equal(t, 1, result.RemovedBindings)
+		assert.Equal(t, float64(1), testutil.ToFloat64(metrics.failedRuntimes))
+	})
+}
+
+type fakeClientProvider struct {
+	clientsets map[string]*fake.Clientset
+}
+
+func (p *fakeClientProvider) K8sClientSetForRuntimeID(runtimeID string) (kubernetes.Interface, error) {
+	clientset, found := p.clientsets[runtimeID]
+	if !found {
+		return nil, fmt.Errorf("runtime %s is not reachable", runtimeID)
+	}
+	return clientset, nil
+}
+
+func fixConfig() Config {
+	return Config{
+		Enabled:            true,
+		GracePeriod:        10 * time.Minute,
+		MaxRuntimesPerRun:  100,
+		MaxDeletionsPerRun: 50,
+	}
+}
+
+func fixReconciler(cfg Config, db storage.BrokerStorage, provider *fakeClientProvider) (*Reconciler, *Metrics) {
+	metrics := NewMetrics(prometheus.NewRegistry(), "test")
+	log := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	return NewReconciler(cfg, db.Instances(), db.Bindings(), provider, metrics, log), metrics
+}
+
+func fixRuntimes(t *testing.T, runtimeIDs ...string) (storage.BrokerStorage, *fakeClientProvider) {
+	db := storage.NewMemoryStorage()
+	provider := &fakeClientProvider{clientsets: map[string]*fake.Clientset{}}
+	for _, runtimeID := range runtimeIDs {
+		instance := fixture.FixInstance(instanceID(runtimeID))
+		instance.RuntimeID = runtimeID
+		instance.Reconcilable = true
+		require.NoError(t, db.Instances().Insert(instance))
+		provider.clientsets[runtimeID] = fake.NewClientset()
+	}
+	return db, provider
+}
+
+func fixBindingResources(provider *fakeClientProvider, runtimeID, bindingID string, created time.Time) {
+	meta := func(namespace string) mv1.ObjectMeta {
+		return mv1.ObjectMeta{
+			Name:              bindings.BindingName(bindingID),
+			Namespace:         namespace,
+			Labels:            map[string]string{"app.kubernetes.io/managed-by": "kcp-kyma-environment-broker"},
+			CreationTimestamp: mv1.NewTime(created),
+		}
+	}
+	objects := []runtime.Object{
+		&v1.ServiceAccount{ObjectMeta: meta(bindings.BindingNamespace)},
+		&rbacv1.ClusterRole{ObjectMeta: meta("")},
+		&rbacv1.ClusterRoleBinding{ObjectMeta: meta("")},
+		&rbacv1.Role{ObjectMeta: meta("team-a")},
+		&rbacv1.RoleBinding{ObjectMeta: meta("team-a")},
+	}
+	for _, object := range objects {
+		_ = provider.clientsets[runtimeID].Tracker().Add(object)
+	}
+}
+
+func insertBinding(t *testing.T, db storage.BrokerStorage, runtimeID, bindingID string, expiresAt time.Time) {
+	binding := fixture.FixBinding(bindingID, fixture.WithInstanceID(instanceID(runtimeID)))
+	binding.ExpiresAt = expiresAt
+	require.NoError(t, db.Bindings().Insert(&binding))
+}
+
+func assertServiceAccountExists(t *testing.T, provider *fakeClientProvider, runtimeID, bindingID string, exists bool) {
+	_, err := provider.clientsets[runtimeID].CoreV1().ServiceAccounts(bindings.BindingNamespace).Get(context.Background(), bindings.BindingName(bindingID), mv1.GetOptions{})
+	if exists {
+		assert.NoError(t, err, "service account of binding %s on runtime %s", bindingID, runtimeID)
+	} else {
+		assert.True(t, apierrors.IsNotFound(err), "service account of binding %s on runtime %s", bindingID, runtimeID)
+	}
+}
+
+func instanceID(runtimeID string) string {
+	return "instance-" + runtimeID
+}
@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"log/slog"
 	"net/http"
+	"reflect"
+	"slices"
 	"strings"
 	"time"
 
@@ -19,20 +21,34 @@ import (
 	"github.com/pivotal-cf/brokerapi/v12/domain/apiresponses"
 
 	"github.com/pivotal-cf/brokerapi/v12/domain"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
 )
 
 const (
 	expiresAtLayout = "2006-01-02T15:04:05.0Z"
+
+	// bindingOperationCreate is returned as the operation of an asynchronous binding creation
+	bindingOperationCreate = "create"
 )
 
+// systemNamespaces cannot be granted to bindings, for example an admin of the kyma-system namespace could create tokens
+// for the service accounts of other bindings. Namespaces with the kube- prefix are rejected as well.
+var systemNamespaces = []string{broker.BindingNamespace, "istio-system"}
+
 type BindingConfig struct {
 	Enabled              bool          `envconfig:"default=false"`
+	AllowedClusterRoles  StringList    `envconfig:"optional"`
 	BindablePlans        StringList    `envconfig:"default=aws"`
 	ExpirationSeconds    int           `envconfig:"default=600"`
 	MaxExpirationSeconds int           `envconfig:"default=7200"`
 	MinExpirationSeconds int           `envconfig:"default=600"`
+	MaxLifetimeSeconds   int           `envconfig:"default=604800"`
 	MaxBindingsCount     int           `envconfig:"default=10"`
 	CreateBindingTimeout time.Duration `envconfig:"default=15s"`
+	AsyncCreationEnabled bool          `envconfig:"default=false"`
+	AsyncCreationTimeout time.Duration `envconfig:"default=5m"`
+	OIDCEnabled          bool          `envconfig:"default=false"`
 }
 
 type BindEndpoint struct {
@@ -41,8 +57,8 @@ type BindEndpoint struct {
 	bindingsStorage   storage.Bindings
 	operationsStorage storage.Operations
 
-	serviceAccountBindingManager broker.BindingsManager
-	publisher                    event.Publisher
+	bindingsManagers broker.BindingsManagers
+	publisher        event.Publisher
 
 	log *slog.Logger
 }
@@ -62,22 +78,27 @@ func (b *BindingContext) CreatedBy() string {
 }
 
 type BindingParams struct {
-	ExpirationSeconds int `json:"expiration_seconds,omitempty"`
+	ExpirationSeconds int      `json:"expiration_seconds,omitempty"`
+	Profile           string   `json:"profile,omitempty"`
+	Namespaces        []string `json:"namespaces,omitempty"`
+	ClusterRole       string   `json:"cluster_role,omitempty"`
+
+	CredentialType string                   `json:"credential_type,omitempty"`
+	Subject        *internal.BindingSubject `json:"subject,omitempty"`
 }
 
 type Credentials struct {
 	Kubeconfig string `json:"kubeconfig"`
 }
 
-func NewBind(cfg BindingConfig, db storage.BrokerStorage, log *slog.Logger, clientProvider broker.ClientProvider, kubeconfigProvider broker.KubeconfigProvider,
-	publisher event.Publisher) *BindEndpoint {
+func NewBind(cfg BindingConfig, db storage.BrokerStorage, log *slog.Logger, bindingsManagers broker.BindingsManagers, publisher event.Publisher) *BindEndpoint {
 	return &BindEndpoint{config: cfg,
-		instancesStorage:             db.Instances(),
-		bindingsStorage:              db.Bindings(),
-		publisher:                    publisher,
-		operationsStorage:            db.Operations(),
-		log:                          log.With("service", "BindEndpoint"),
-		serviceAccountBindingManager: broker.NewServiceAccountBindingsManager(clientProvider, kubeconfigProvider),
+		instancesStorage:  db.Instances(),
+		bindingsStorage:   db.Bindings(),
+		publisher:         publisher,
+		operationsStorage: db.Operations(),
+		log:               log.With("service", "BindEndpoint"),
+		bindingsManagers:  bindingsManagers,
 	}
 }
 
@@ -150,6 +171,11 @@ func (b *BindEndpoint) bind(ctx context.Context, instanceID, bindingID string, d
 		expirationSeconds = parameters.ExpirationSeconds
 	}
 
+	scope, err := b.scopeFromParameters(parameters)
+	if err != nil {
+		return domain.Binding{}, apiresponses.NewFailureResponse(err, http.StatusBadRequest, err.Error())
+	}
+
 	lastOperation, err := b.operationsStorage.GetLastOperation(instance.InstanceID)
 	if err != nil {
 		return domain.Binding{}, apiresponses.NewFailureResponse(fmt.Errorf("failed to get last operation for instance %s", instanceID), http.StatusInternalServerError, fmt.Sprintf("failed to get last operation for instance %s", instanceID))
@@ -164,7 +190,10 @@ func (b *BindEndpoint) bind(ctx context.Context, instanceID, bindingID string, d
 		return domain.Binding{}, apiresponses.NewFailureResponse(errors.New(message), http.StatusBadRequest, message) // Agreed with Provisioning API team to return 400
 	}
 
-	binding, err := b.searchDbForBinding(instanceID, bindingID, expirationSeconds)
+	// the binding is created in the background only if the platform accepts the asynchronous response
+	async := asyncAllowed && b.config.AsyncCreationEnabled
+
+	binding, err := b.searchDbForBinding(instanceID, bindingID, expirationSeconds, scope, async)
 	if err != nil {
 		return domain.Binding{}, err
 	}
@@ -184,22 +213,120 @@ func (b *BindEndpoint) bind(ctx context.Context, instanceID, bindingID string, d
 		return domain.Binding{}, err
 	}
 
-	return b.createNewBinding(ctx, instanceID, bindingID, expirationSeconds, bindingContext, instance)
+	return b.createNewBinding(ctx, instanceID, bindingID, expirationSeconds, scope, bindingContext, instance, async)
+}
+
+// scopeFromParameters validates the requested credential type and role profile and returns the scope granted to the binding.
+// If no profile is requested, the binding gets the cluster-admin permissions.
+func (b *BindEndpoint) scopeFromParameters(parameters BindingParams) (internal.BindingScope, error) {
+	scope := internal.BindingScope{
+		Profile:     internal.BindingProfile(parameters.Profile),
+		Namespaces:  parameters.Namespaces,
+		ClusterRole: parameters.ClusterRole,
+		Subject:     parameters.Subject,
+	}
+	if scope.Profile == "" {
+		scope.Profile = internal.BindingProfileClusterAdmin
+	}
+
+	switch internal.BindingCredentialType(parameters.CredentialType) {
+	case "", internal.BindingCredentialTypeServiceAccount:
+		if scope.Subject != nil {
+			return internal.BindingScope{}, fmt.Errorf("subject is supported only for the %s credential type", internal.BindingCredentialTypeOIDC)
+		}
+	case internal.BindingCredentialTypeOIDC:
+		if !b.config.OIDCEnabled {
+			return internal.BindingScope{}, fmt.Errorf("the %s credential type is not enabled", internal.BindingCredentialTypeOIDC)
+		}
+		if scope.Subject == nil || scope.Subject.Name == "" {
+			return internal.BindingScope{}, fmt.Errorf("subject with a name is required for the %s credential type", internal.BindingCredentialTypeOIDC)
+		}
+		if scope.Subject.Kind != rbacv1.UserKind && scope.Subject.Kind != rbacv1.GroupKind {
+			return internal.BindingScope{}, fmt.Errorf("unsupported subject kind %s, supported kinds: %s, %s", scope.Subject.Kind, rbacv1.UserKind, rbacv1.GroupKind)
+		}
+		if strings.HasPrefix(scope.Subject.Name, broker.SystemSubjectPrefix) {
+			return internal.BindingScope{}, fmt.Errorf("subject name must not have the %s prefix", broker.SystemSubjectPrefix)
+		}
+		scope.CredentialType = internal.BindingCredentialTypeOIDC
+	default:
+		return internal.BindingScope{}, fmt.Errorf("unsupported credential_type %s, supported types: %s, %s", parameters.CredentialType,
+			internal.BindingCredentialTypeServiceAccount, internal.BindingCredentialTypeOIDC)
+	}
+
+	switch scope.Profile {
+	case internal.BindingProfileClusterAdmin, internal.BindingProfileReadOnly:
+		if len(scope.Namespaces) != 0 || scope.ClusterRole != "" {
+			return internal.BindingScope{}, fmt.Errorf("namespaces and cluster_role are not supported for the %s profile", scope.Profile)
+		}
+	case internal.BindingProfileNamespaceAdmin:
+		if len(scope.Namespaces) == 0 {
+			return internal.BindingScope{}, fmt.Errorf("namespaces are required for the %s profile", scope.Profile)
+		}
+		if scope.ClusterRole != "" {
+			return internal.BindingScope{}, fmt.Errorf("cluster_role is not supported for the %s profile", scope.Profile)
+		}
+	case internal.BindingProfileClusterRole:
+		if scope.ClusterRole == "" {
+			return internal.BindingScope{}, fmt.Errorf("cluster_role is required for the %s profile", scope.Profile)
+		}
+		if !b.isClusterRoleAllowed(scope.ClusterRole) {
+			return internal.BindingScope{}, fmt.Errorf("cluster role %s is not allowed", scope.ClusterRole)
+		}
+	default:
+		return internal.BindingScope{}, fmt.Errorf("unsupported profile %s, supported profiles: %s, %s, %s, %s", scope.Profile,
+			internal.BindingProfileClusterAdmin, internal.BindingProfileReadOnly, internal.BindingProfileNamespaceAdmin, internal.BindingProfileClusterRole)
+	}
+
+	seen := map[string]struct{}{}
+	for _, namespace := range scope.Namespaces {
+		if errs := validation.IsDNS1123Label(namespace); len(errs) != 0 {
+			return internal.BindingScope{}, fmt.Errorf("invalid namespace %q: %s", namespace, strings.Join(errs, ", "))
+		}
+		if strings.HasPrefix(namespace, "kube-") || slices.Contains(systemNamespaces, namespace) {
+			return internal.BindingScope{}, fmt.Errorf("namespace %s is a system namespace and cannot be granted", namespace)
+		}
+		if _, found := seen[namespace]; found {
+			return internal.BindingScope{}, fmt.Errorf("namespace %s is duplicated", namespace)
+		}
+		seen[namespace] = struct{}{}
+	}
+
+	return scope, nil
+}
+
+func (b *BindEndpoint) isClusterRoleAllowed(clusterRole string) bool {
+	for _, allowed := range b.config.AllowedClusterRoles {
+		if allowed != "" && allowed == clusterRole {
+			return true
+		}
+	}
+	return false
 }
 
-func (b *BindEndpoint) searchDbForBinding(instanceID string, bindingID string, expirationSeconds int) (*domain.Binding, error) {
+func (b *BindEndpoint) searchDbForBinding(instanceID string, bindingID string, expirationSeconds int, scope internal.BindingScope, async bool) (*domain.Binding, error) {
 	bindingFromDB, err := b.bindingsStorage.Get(instanceID, bindingID)
 	if err != nil && !dberr.IsNotFound(err) {
 		message := fmt.Sprintf("failed to get Kyma binding from storage: %s", err)
 		return nil, apiresponses.NewFailureResponse(errors.New(message), http.StatusInternalServerError, message)
 	}
 	if bindingFromDB != nil {
-		if bindingFromDB.ExpirationSeconds != int64(expirationSeconds) {
+		if bindingFromDB.ExpirationSeconds != int64(expirationSeconds) || !reflect.DeepEqual(normalizedScope(bindingFromDB.Scope), normalizedScope(scope)) {
 			message := "binding already exists but with different parameters"
 			return nil, apiresponses.NewFailureResponse(errors.New(message), http.StatusConflict, message)
 		}
 		if bindingFromDB.ExpiresAt.After(time.Now()) {
+			state, description := bindingState(bindingFromDB, b.config.AsyncCreationTimeout)
+			if state == domain.Failed {
+				message := fmt.Sprintf("binding creation failed, remove the binding before creating it again: %s", description)
+				return nil, apiresponses.NewFailureResponse(errors.New(message), http.StatusUnprocessableEntity, message)
+			}
 			if len(bindingFromDB.Kubeconfig) == 0 {
+				if async {
+					return &domain.Binding{
+						IsAsync:       true,
+						OperationData: bindingOperationCreate,
+					}, nil
+				}
 				message := "binding creation already in progress"
 				return nil, apiresponses.NewFailureResponse(errors.New(message), http.StatusUnprocessableEntity, message)
 			}
@@ -218,6 +345,18 @@ func (b *BindEndpoint) searchDbForBinding(instanceID string, bindingID string, e
 	return nil, nil
 }
 
+// normalizedScope makes scopes comparable regardless of how the binding was stored, because bindings created before
+// the scopes were introduced have no profile and an empty list of namespaces may be stored as nil
+func normalizedScope(scope internal.BindingScope) internal.BindingScope {
+	if scope.Profile == "" {
+		scope.Profile = internal.BindingProfileClusterAdmin
+	}
+	if len(scope.Namespaces) == 0 {
+		scope.Namespaces = nil
+	}
+	return scope
+}
+
 func (b *BindEndpoint) checkAgainstLimit(bindingList []internal.Binding, instanceID string) error {
 	bindingCount := len(bindingList)
 	message := fmt.Sprintf("reaching the maximum (%d) number of non expired bindings for instance %s", b.config.MaxBindingsCount, instanceID)
@@ -243,8 +382,13 @@ func (b *BindEndpoint) checkAgainstLimit(bindingList []internal.Binding, instanc
 	return nil
 }
 
-func (b *BindEndpoint) createNewBinding(ctx context.Context, instanceID string, bindingID string, expirationSeconds int, bindingContext BindingContext, instance *internal.Instance) (domain.Binding, error) {
-	var kubeconfig string
+func (b *BindEndpoint) createNewBinding(ctx context.Context, instanceID string, bindingID string, expirationSeconds int, scope internal.BindingScope, bindingContext BindingContext, instance *internal.Instance, async bool) (domain.Binding, error) {
+	bindingsManager, err := b.bindingsManagers.For(scope.CredentialType)
+	if err != nil {
+		message := fmt.Sprintf("failed to create a Kyma binding: %s", err)
+		return domain.Binding{}, apiresponses.NewFailureResponse(errors.New(message), http.StatusInternalServerError, message)
+	}
+
 	binding := &internal.Binding{
 		ID:         bindingID,
 		InstanceID: instanceID,
@@ -255,9 +399,11 @@ func (b *BindEndpoint) createNewBinding(ctx context.Context, instanceID string,
 		ExpirationSeconds: int64(expirationSeconds),
 		ExpiresAt:         time.Now().Add(time.Duration(expirationSeconds) * time.Second),
 		CreatedBy:         bindingContext.CreatedBy(),
+		Scope:             scope,
+		State:             domain.InProgress,
 	}
 
-	err := b.bindingsStorage.Insert(binding)
+	err = b.bindingsStorage.Insert(binding)
 	switch {
 	case dberr.IsAlreadyExists(err):
 		message := fmt.Sprintf("failed to insert Kyma binding into storage: %s", err)
@@ -267,35 +413,83 @@ func (b *BindEndpoint) createNewBinding(ctx context.Context, instanceID string,
 		return domain.Binding{}, apiresponses.NewFailureResponse(errors.New(message), http.StatusInternalServerError, message)
 	}
 
+	if async {
+		go b.createBindingInBackground(instance, binding, bindingsManager)
+		b.log.Info(fmt.Sprintf("Binding %s for instance %s is created in the background", bindingID, instanceID))
+		return domain.Binding{
+			IsAsync:       true,
+			OperationData: bindingOperationCreate,
+		}, nil
+	}
+
+	err = b.createBindingResources(ctx, instance, binding, bindingsManager)
+	if err != nil {
+		return domain.Binding{}, err
+	}
+
+	return domain.Binding{
+		IsAsync: false,
+		Credentials: Credentials{
+			Kubeconfig: binding.Kubeconfig,
+		},
+		Metadata: domain.BindingMetadata{
+			ExpiresAt: binding.ExpiresAt.Format(expiresAtLayout),
+		},
+	}, nil
+}
+
+// createBindingInBackground creates the binding resources detached from the request, so the creation is not cancelled
+// when the response is sent. The result is stored in the binding state and reported by the last_binding_operation endpoint.
+func (b *BindEndpoint) createBindingInBackground(instance *internal.Instance, binding *internal.Binding, bindingsManager broker.BindingsManager) {
+	ctx, cancel := context.WithTimeout(context.Background(), b.config.AsyncCreationTimeout)
+	defer cancel()
+
+	// the error is already stored in the binding state
+	_ = b.createBindingResources(ctx, instance, binding, bindingsManager)
+
+	// the binding could be removed while its resources were created, the resources must not be left on the runtime then
+	_, err := b.bindingsStorage.Get(binding.InstanceID, binding.ID)
+	if dberr.IsNotFound(err) {
+		b.log.Info(fmt.Sprintf("Binding %s for instance %s was removed during the creation, removing its resources", binding.ID, binding.InstanceID))
+		if err := bindingsManager.Delete(ctx, instance, binding.ID); err != nil {
+			b.log.Error(fmt.Sprintf("failed to remove resources of the removed binding %s: %s", binding.ID, err))
+		}
+	}
+}
+
+// createBindingResources creates the credentials of the binding on the runtime and stores the result of the creation in the binding
+func (b *BindEndpoint) createBindingResources(ctx context.Context, instance *internal.Instance, binding *internal.Binding, bindingsManager broker.BindingsManager) error {
 	// create kubeconfig for the instance
-	var expiresAt time.Time
-	kubeconfig, expiresAt, err = b.serviceAccountBindingManager.Create(ctx, instance, bindingID, expirationSeconds)
+	kubeconfig, expiresAt, err := bindingsManager.Create(ctx, instance, binding.ID, int(binding.ExpirationSeconds), binding.Scope)
 	if err != nil {
 		message := fmt.Sprintf("failed to create a Kyma binding using service account's kubeconfig: %s", err)
-		b.log.Error(fmt.Sprintf("for instance %s %s", instanceID, message))
-		return domain.Binding{}, apiresponses.NewFailureResponse(errors.New(message), http.StatusInternalServerError, message)
+		b.log.Error(fmt.Sprintf("for instance %s %s", binding.InstanceID, message))
+
+		binding.State = domain.Failed
+		binding.Description = message
+		binding.UpdatedAt = time.Now()
+		if err := b.bindingsStorage.Update(binding); err != nil {
+			b.log.Error(fmt.Sprintf("failed to mark binding %s as failed in storage: %s", binding.ID, err))
+		}
+		return apiresponses.NewFailureResponse(errors.New(message), http.StatusInternalServerError, message)
 	}
 
 	binding.ExpiresAt = expiresAt
 	binding.Kubeconfig = kubeconfig
+	binding.State = domain.Succeeded
+	binding.Description = ""
+	binding.UpdatedAt = time.Now()
 
 	err = b.bindingsStorage.Update(binding)
 	if err != nil {
 		message := fmt.Sprintf("failed to update Kyma binding in storage: %s", err)
-		return domain.Binding{}, apiresponses.NewFailureResponse(errors.New(message), http.StatusInternalServerError, message)
+		b.log.Error(fmt.Sprintf("for instance %s %s", binding.InstanceID, message))
+		return apiresponses.NewFailureResponse(errors.New(message), http.StatusInternalServerError, message)
 	}
-	b.log.Info(fmt.Sprintf("Successfully created binding %s for instance %s", bindingID, instanceID))
+	b.log.Info(fmt.Sprintf("Successfully created binding %s for instance %s", binding.ID, binding.InstanceID))
 	b.publisher.Publish(context.Background(), BindingCreated{PlanID: instance.ServicePlanID})
 
-	return domain.Binding{
-		IsAsync: false,
-		Credentials: Credentials{
-			Kubeconfig: kubeconfig,
-		},
-		Metadata: domain.BindingMetadata{
-			ExpiresAt: binding.ExpiresAt.Format(expiresAtLayout),
-		},
-	}, nil
+	return nil
 }
 
 func (b *BindEndpoint) IsPlanBindable(planName string) bool {
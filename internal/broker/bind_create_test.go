@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/kyma-project/kyma-environment-broker/internal"
+	brokerBindings "github.com/kyma-project/kyma-environment-broker/internal/broker/bindings"
 	"github.com/kyma-project/kyma-environment-broker/internal/kubeconfig"
 	"github.com/pivotal-cf/brokerapi/v12/domain/apiresponses"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -42,7 +43,10 @@ func fixBindingConfig() BindingConfig {
 		ExpirationSeconds:    600,
 		MaxExpirationSeconds: maxExpirationSeconds,
 		MinExpirationSeconds: minExpirationSeconds,
+		MaxLifetimeSeconds:   maxLifetimeSeconds,
 		MaxBindingsCount:     maxBindingsCount,
+		AsyncCreationEnabled: true,
+		AsyncCreationTimeout: time.Minute,
 	}
 
 }
@@ -93,7 +97,7 @@ func TestCreateBindingEndpoint_dbInsertionInCaseOfError(t *testing.T) {
 	publisher := event.NewPubSub(log)
 
 	//// api handler
-	bindEndpoint := NewBind(*bindingCfg, db, fixLogger(), &dummyProvider{}, &dummyProvider{}, publisher)
+	bindEndpoint := NewBind(*bindingCfg, db, fixLogger(), brokerBindings.NewBindingsManagers(&dummyProvider{}, &dummyProvider{}, nil), publisher)
 
 	// test relies on checking if got nil on kubeconfig dummyProvider but the instance got inserted either way
 	t.Run("should INSERT binding despite error on k8s api call", func(t *testing.T) {
@@ -297,6 +301,189 @@ func TestCreateBindingEndpoint(t *testing.T) {
 
 }
 
+func TestCreateBindingEndpoint_Scope(t *testing.T) {
+	cfg := fixBindingConfig()
+	cfg.AllowedClusterRoles = StringList{"view", "edit"}
+	bindEndpoint, db := prepareBindingEndpoint(t, cfg)
+
+	t.Run("should store the cluster-admin scope when no profile is requested", func(t *testing.T) {
+		// when
+		_, err := bindEndpoint.Bind(context.Background(), instanceID1, "binding-scope-001", domain.BindDetails{
+			ServiceID: "123",
+			PlanID:    fixture.PlanId,
+		}, false)
+
+		// then
+		require.NoError(t, err)
+		binding, err := db.Bindings().Get(instanceID1, "binding-scope-001")
+		require.NoError(t, err)
+		assert.Equal(t, internal.BindingScope{Profile: internal.BindingProfileClusterAdmin}, binding.Scope)
+	})
+
+	t.Run("should create bindings for the supported profiles", func(t *testing.T) {
+		for bindingID, params := range map[string]string{
+			"binding-scope-002": `{"profile": "read-only"}`,
+			"binding-scope-003": `{"profile": "namespace-admin", "namespaces": ["team-a", "team-b"]}`,
+			"binding-scope-004": `{"profile": "cluster-role", "cluster_role": "view"}`,
+			"binding-scope-005": `{"profile": "cluster-role", "cluster_role": "edit", "namespaces": ["team-a"]}`,
+		} {
+			// when
+			binding, err := bindEndpoint.Bind(context.Background(), instanceID1, bindingID, domain.BindDetails{
+				ServiceID:     "123",
+				PlanID:        fixture.PlanId,
+				RawParameters: json.RawMessage(params),
+			}, false)
+
+			// then
+			require.NoError(t, err, params)
+			assert.NotEmpty(t, binding.Credentials.(Credentials).Kubeconfig)
+		}
+
+		binding, err := db.Bindings().Get(instanceID1, "binding-scope-003")
+		require.NoError(t, err)
+		assert.Equal(t, internal.BindingScope{Profile: internal.BindingProfileNamespaceAdmin, Namespaces: []string{"team-a", "team-b"}}, binding.Scope)
+	})
+
+	t.Run("should return 400 for invalid profile parameters", func(t *testing.T) {
+		for _, params := range []string{
+			`{"profile": "superuser"}`,
+			`{"profile": "read-only", "namespaces": ["team-a"]}`,
+			`{"profile": "cluster-admin", "cluster_role": "view"}`,
+			`{"profile": "namespace-admin"}`,
+			`{"profile": "namespace-admin", "namespaces": ["Team_A"]}`,
+			`{"profile": "namespace-admin", "namespaces": ["team-a", "team-a"]}`,
+			`{"profile": "namespace-admin", "namespaces": ["team-a"], "cluster_role": "view"}`,
+			`{"profile": "namespace-admin", "namespaces": ["kyma-system"]}`,
+			`{"profile": "namespace-admin", "namespaces": ["team-a", "kube-system"]}`,
+			`{"profile": "cluster-role"}`,
+			`{"profile": "cluster-role", "cluster_role": "cluster-admin"}`,
+		} {
+			// when
+			_, err := bindEndpoint.Bind(context.Background(), instanceID1, "binding-scope-invalid", domain.BindDetails{
+				ServiceID:     "123",
+				PlanID:        fixture.PlanId,
+				RawParameters: json.RawMessage(params),
+			}, false)
+
+			// then
+			require.Error(t, err, params)
+			apierr, ok := err.(*apiresponses.FailureResponse)
+			require.True(t, ok)
+			assert.Equal(t, http.StatusBadRequest, apierr.ValidatedStatusCode(nil), params)
+		}
+	})
+
+	t.Run("should report a conflict when the scope differs", func(t *testing.T) {
+		// given
+		_, err := bindEndpoint.Bind(context.Background(), instanceID1, "binding-scope-006", domain.BindDetails{
+			ServiceID:     "123",
+			PlanID:        fixture.PlanId,
+			RawParameters: json.RawMessage(`{"profile": "namespace-admin", "namespaces": ["team-a"]}`),
+		}, false)
+		require.NoError(t, err)
+
+		// when
+		_, err = bindEndpoint.Bind(context.Background(), instanceID1, "binding-scope-006", domain.BindDetails{
+			ServiceID:     "123",
+			PlanID:        fixture.PlanId,
+			RawParameters: json.RawMessage(`{"profile": "namespace-admin", "namespaces": ["team-b"]}`),
+		}, false)
+
+		// then
+		require.Error(t, err)
+		apierr, ok := err.(*apiresponses.FailureResponse)
+		require.True(t, ok)
+		assert.Equal(t, http.StatusConflict, apierr.ValidatedStatusCode(nil))
+
+		// when
+		binding, err := bindEndpoint.Bind(context.Background(), instanceID1, "binding-scope-006", domain.BindDetails{
+			ServiceID:     "123",
+			PlanID:        fixture.PlanId,
+			RawParameters: json.RawMessage(`{"profile": "namespace-admin", "namespaces": ["team-a"]}`),
+		}, false)
+
+		// then
+		require.NoError(t, err)
+		assert.True(t, binding.AlreadyExists)
+	})
+}
+
+func TestCreateBindingEndpoint_OIDC(t *testing.T) {
+	t.Run("should return 400 when OIDC bindings are not enabled", func(t *testing.T) {
+		// given
+		bindEndpoint, _ := prepareBindingEndpoint(t, fixBindingConfig())
+
+		// when
+		_, err := bindEndpoint.Bind(context.Background(), instanceID1, "binding-oidc-001", domain.BindDetails{
+			ServiceID:     "123",
+			PlanID:        fixture.PlanId,
+			RawParameters: json.RawMessage(`{"credential_type": "oidc", "subject": {"kind": "User", "name": "jane@example.com"}}`),
+		}, false)
+
+		// then
+		require.Error(t, err)
+		apierr, ok := err.(*apiresponses.FailureResponse)
+		require.True(t, ok)
+		assert.Equal(t, http.StatusBadRequest, apierr.ValidatedStatusCode(nil))
+	})
+
+	t.Run("should return 400 for invalid OIDC parameters", func(t *testing.T) {
+		// given
+		cfg := fixBindingConfig()
+		cfg.OIDCEnabled = true
+		bindEndpoint, _ := prepareBindingEndpoint(t, cfg)
+
+		for _, params := range []string{
+			`{"credential_type": "certificate"}`,
+			`{"credential_type": "oidc"}`,
+			`{"credential_type": "oidc", "subject": {"kind": "User"}}`,
+			`{"credential_type": "oidc", "subject": {"kind": "ServiceAccount", "name": "default"}}`,
+			`{"credential_type": "service_account", "subject": {"kind": "User", "name": "jane@example.com"}}`,
+			`{"subject": {"kind": "User", "name": "jane@example.com"}}`,
+			`{"credential_type": "oidc", "subject": {"kind": "User", "name": "system:admin"}}`,
+			`{"credential_type": "oidc", "subject": {"kind": "Group", "name": "system:masters"}}`,
+		} {
+			// when
+			_, err := bindEndpoint.Bind(context.Background(), instanceID1, "binding-oidc-invalid", domain.BindDetails{
+				ServiceID:     "123",
+				PlanID:        fixture.PlanId,
+				RawParameters: json.RawMessage(params),
+			}, false)
+
+			// then
+			require.Error(t, err, params)
+			apierr, ok := err.(*apiresponses.FailureResponse)
+			require.True(t, ok)
+			assert.Equal(t, http.StatusBadRequest, apierr.ValidatedStatusCode(nil), params)
+		}
+	})
+
+	t.Run("should store the OIDC scope", func(t *testing.T) {
+		// given
+		cfg := fixBindingConfig()
+		cfg.OIDCEnabled = true
+		bindEndpoint, db := prepareBindingEndpoint(t, cfg)
+
+		// when
+		_, err := bindEndpoint.Bind(context.Background(), instanceID1, "binding-oidc-002", domain.BindDetails{
+			ServiceID:     "123",
+			PlanID:        fixture.PlanId,
+			RawParameters: json.RawMessage(`{"credential_type": "oidc", "profile": "read-only", "subject": {"kind": "Group", "name": "developers"}}`),
+		}, false)
+
+		// then
+		// the test runtime has no Runtime resource with the OIDC configuration, so the credentials are not created
+		require.Error(t, err)
+		binding, err := db.Bindings().Get(instanceID1, "binding-oidc-002")
+		require.NoError(t, err)
+		assert.Equal(t, internal.BindingScope{
+			Profile:        internal.BindingProfileReadOnly,
+			CredentialType: internal.BindingCredentialTypeOIDC,
+			Subject:        &internal.BindingSubject{Kind: "Group", Name: "developers"},
+		}, binding.Scope)
+	})
+}
+
 func TestCreatedBy(t *testing.T) {
 	emptyStr := ""
 	email := "john.smith@email.com"
@@ -381,7 +568,7 @@ func TestCreateSecondBindingWithTheSameIdButDifferentParams(t *testing.T) {
 
 	publisher := event.NewPubSub(log)
 
-	svc := NewBind(*bindingCfg, brokerStorage, fixLogger(), nil, nil, publisher)
+	svc := NewBind(*bindingCfg, brokerStorage, fixLogger(), brokerBindings.NewBindingsManagers(nil, nil, nil), publisher)
 	params := BindingParams{
 		ExpirationSeconds: 601,
 	}
@@ -432,7 +619,7 @@ func TestCreateSecondBindingWithTheSameIdAndParams(t *testing.T) {
 
 	publisher := event.NewPubSub(log)
 
-	svc := NewBind(*bindingCfg, brokerStorage, fixLogger(), nil, nil, publisher)
+	svc := NewBind(*bindingCfg, brokerStorage, fixLogger(), brokerBindings.NewBindingsManagers(nil, nil, nil), publisher)
 	params := BindingParams{
 		ExpirationSeconds: 600,
 	}
@@ -483,7 +670,7 @@ func TestCreateSecondBindingWithTheSameIdAndParamsForExpired(t *testing.T) {
 	// event publisher
 	publisher := event.NewPubSub(log)
 
-	svc := NewBind(*bindingCfg, brokerStorage, fixLogger(), nil, nil, publisher)
+	svc := NewBind(*bindingCfg, brokerStorage, fixLogger(), brokerBindings.NewBindingsManagers(nil, nil, nil), publisher)
 	params := BindingParams{
 		ExpirationSeconds: 600,
 	}
@@ -536,7 +723,7 @@ func TestCreateSecondBindingWithTheSameIdAndParamsForBindingInProgress(t *testin
 	// event publisher
 	publisher := event.NewPubSub(log)
 
-	svc := NewBind(*bindingCfg, brokerStorage, fixLogger(), nil, nil, publisher)
+	svc := NewBind(*bindingCfg, brokerStorage, fixLogger(), brokerBindings.NewBindingsManagers(nil, nil, nil), publisher)
 	params := BindingParams{
 		ExpirationSeconds: 600,
 	}
@@ -587,7 +774,7 @@ func TestCreateSecondBindingWithTheSameIdAndParamsNotExplicitlyDefined(t *testin
 
 	publisher := event.NewPubSub(log)
 
-	svc := NewBind(*bindingCfg, brokerStorage, fixLogger(), nil, nil, publisher)
+	svc := NewBind(*bindingCfg, brokerStorage, fixLogger(), brokerBindings.NewBindingsManagers(nil, nil, nil), publisher)
 
 	// when
 	resp, err := svc.Bind(context.Background(), instanceID, bindingID, domain.BindDetails{}, false)
@@ -613,5 +800,5 @@ func prepareBindingEndpoint(t *testing.T, cfg BindingConfig) (*BindEndpoint, sto
 	err = db.Operations().InsertOperation(operation)
 	require.NoError(t, err)
 
-	return NewBind(cfg, db, log, k8sClientProvider, k8sClientProvider, event.NewPubSub(log)), db
+	return NewBind(cfg, db, log, brokerBindings.NewBindingsManagers(k8sClientProvider, k8sClientProvider, nil), event.NewPubSub(log)), db
 }
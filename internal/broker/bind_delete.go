@@ -22,15 +22,15 @@ type UnbindEndpoint struct {
 	bindingsStorage   storage.Bindings
 	instancesStorage  storage.Instances
 	operationsStorage storage.Operations
-	bindingsManager   broker.BindingsManager
+	bindingsManagers  broker.BindingsManagers
 	publisher         event.Publisher
 }
 
-func NewUnbind(log *slog.Logger, db storage.BrokerStorage, bindingsManager broker.BindingsManager, publisher event.Publisher) *UnbindEndpoint {
+func NewUnbind(log *slog.Logger, db storage.BrokerStorage, bindingsManagers broker.BindingsManagers, publisher event.Publisher) *UnbindEndpoint {
 	return &UnbindEndpoint{log: log.With("service", "UnbindEndpoint"),
 		bindingsStorage:   db.Bindings(),
 		instancesStorage:  db.Instances(),
-		bindingsManager:   bindingsManager,
+		bindingsManagers:  bindingsManagers,
 		operationsStorage: db.Operations(),
 		publisher:         publisher,
 	}
@@ -67,7 +67,7 @@ func (b *UnbindEndpoint) unbind(ctx context.Context, instanceID, bindingID strin
 		return domain.UnbindSpec{}, apiresponses.NewFailureResponse(fmt.Errorf("failed to get instance %s", instanceID), http.StatusInternalServerError, fmt.Sprintf("failed to get instance %s", instanceID))
 	}
 
-	_, err = b.bindingsStorage.Get(instanceID, bindingID)
+	binding, err := b.bindingsStorage.Get(instanceID, bindingID)
 	switch {
 	case dberr.IsNotFound(err):
 		return domain.UnbindSpec{}, apiresponses.ErrBindingDoesNotExist
@@ -81,7 +81,10 @@ func (b *UnbindEndpoint) unbind(ctx context.Context, instanceID, bindingID strin
 	}
 
 	if lastOperation.Type != internal.OperationTypeDeprovision {
-		err = b.bindingsManager.Delete(ctx, instance, bindingID)
+		bindingsManager, err := b.bindingsManagers.For(binding.Scope.CredentialType)
+		if err == nil {
+			err = bindingsManager.Delete(ctx, instance, bindingID)
+		}
 		if err != nil {
 			b.log.Error(fmt.Sprintf("Unbind error during removal of service account resources: %s", err))
 			return domain.UnbindSpec{}, apiresponses.NewFailureResponse(fmt.Errorf("failed to delete binding resources for binding %s and instance %s: %v", bindingID, instanceID, err), http.StatusInternalServerError, fmt.Sprintf("failed to delete resources for binding %s and instance %s: %v", bindingID, instanceID, err))
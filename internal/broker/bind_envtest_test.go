@@ -40,6 +40,7 @@ const (
 	expirationSeconds    = 600
 	maxExpirationSeconds = 7200
 	minExpirationSeconds = 600
+	maxLifetimeSeconds   = 86400
 )
 
 func TestCreateBinding(t *testing.T) {
@@ -105,8 +106,9 @@ func TestCreateBinding(t *testing.T) {
 		Level: slog.LevelDebug,
 	}))
 	publisher := event.NewPubSub(log)
-	svc := NewBind(bindingCfg, db, log, skrK8sClientProvider, skrK8sClientProvider, publisher)
-	unbindSvc := NewUnbind(log, db, brokerBindings.NewServiceAccountBindingsManager(skrK8sClientProvider, skrK8sClientProvider), publisher)
+	bindingsManagers := brokerBindings.NewBindingsManagers(skrK8sClientProvider, skrK8sClientProvider, nil)
+	svc := NewBind(bindingCfg, db, log, bindingsManagers, publisher)
+	unbindSvc := NewUnbind(log, db, bindingsManagers, publisher)
 
 	t.Run("should create a new service binding without error", func(t *testing.T) {
 		// When
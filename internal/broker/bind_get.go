@@ -54,6 +54,11 @@ func (b *GetBindingEndpoint) GetBinding(_ context.Context, instanceID, bindingID
 		return domain.GetBindingSpec{}, apiresponses.NewFailureResponse(errors.New(message), http.StatusNotFound, message)
 	}
 
+	if binding.State == domain.Failed {
+		message := "Binding creation failed"
+		return domain.GetBindingSpec{}, apiresponses.NewFailureResponse(errors.New(message), http.StatusNotFound, message)
+	}
+
 	if len(binding.Kubeconfig) == 0 {
 		message := "Binding creation in progress"
 		return domain.GetBindingSpec{}, apiresponses.NewFailureResponse(errors.New(message), http.StatusNotFound, message)
@@ -69,5 +74,6 @@ func (b *GetBindingEndpoint) GetBinding(_ context.Context, instanceID, bindingID
 		Credentials: Credentials{
 			Kubeconfig: binding.Kubeconfig,
 		},
+		Parameters: normalizedScope(binding.Scope),
 	}, nil
 }
@@ -17,6 +17,52 @@ import (
 
 func TestGetBinding(t *testing.T) {
 
+	t.Run("should return the scope of the binding", func(t *testing.T) {
+		// given
+		bindingsMemory := memory.NewBinding()
+		operationsMemory := memory.NewOperation()
+
+		operation := fixture.FixOperation("operation-001", "test-instance-id", internal.OperationTypeProvision)
+		err := operationsMemory.InsertOperation(operation)
+		require.NoError(t, err)
+
+		err = bindingsMemory.Insert(&internal.Binding{
+			ID:         "test-binding-id",
+			InstanceID: "test-instance-id",
+			ExpiresAt:  time.Now().Add(time.Hour),
+			Kubeconfig: "kubeconfig",
+			Scope:      internal.BindingScope{Profile: internal.BindingProfileNamespaceAdmin, Namespaces: []string{"team-a"}},
+		})
+		require.NoError(t, err)
+		err = bindingsMemory.Insert(&internal.Binding{
+			ID:         "legacy-binding-id",
+			InstanceID: "test-instance-id",
+			ExpiresAt:  time.Now().Add(time.Hour),
+			Kubeconfig: "kubeconfig",
+		})
+		require.NoError(t, err)
+
+		endpoint := &GetBindingEndpoint{
+			bindings:   bindingsMemory,
+			operations: operationsMemory,
+			log:        fixLogger(),
+		}
+
+		// when
+		spec, err := endpoint.GetBinding(context.Background(), "test-instance-id", "test-binding-id", domain.FetchBindingDetails{})
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, internal.BindingScope{Profile: internal.BindingProfileNamespaceAdmin, Namespaces: []string{"team-a"}}, spec.Parameters)
+
+		// when
+		spec, err = endpoint.GetBinding(context.Background(), "test-instance-id", "legacy-binding-id", domain.FetchBindingDetails{})
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, internal.BindingScope{Profile: internal.BindingProfileClusterAdmin}, spec.Parameters)
+	})
+
 	t.Run("should return 404 code for the expired binding", func(t *testing.T) {
 		// given
 		bindingsMemory := memory.NewBinding()
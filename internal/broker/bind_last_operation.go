@@ -2,18 +2,33 @@ package broker
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/kyma-project/kyma-environment-broker/internal"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage/dberr"
 
 	"github.com/pivotal-cf/brokerapi/v12/domain"
+	"github.com/pivotal-cf/brokerapi/v12/domain/apiresponses"
 )
 
 type LastBindingOperationEndpoint struct {
+	config          BindingConfig
+	bindingsStorage storage.Bindings
+
 	log *slog.Logger
 }
 
-func NewLastBindingOperation(log *slog.Logger) *LastBindingOperationEndpoint {
-	return &LastBindingOperationEndpoint{log: log.With("service", "LastBindingOperationEndpoint")}
+func NewLastBindingOperation(cfg BindingConfig, db storage.BrokerStorage, log *slog.Logger) *LastBindingOperationEndpoint {
+	return &LastBindingOperationEndpoint{
+		config:          cfg,
+		bindingsStorage: db.Bindings(),
+		log:             log.With("service", "LastBindingOperationEndpoint"),
+	}
 }
 
 // LastBindingOperation fetches last operation state for a service binding
@@ -24,5 +39,47 @@ func (b *LastBindingOperationEndpoint) LastBindingOperation(ctx context.Context,
 	b.log.Info(fmt.Sprintf("LastBindingOperation bindingID: %s", bindingID))
 	b.log.Info(fmt.Sprintf("LastBindingOperation details: %+v", details))
 
-	return domain.LastOperation{}, fmt.Errorf("not supported")
+	if !b.config.Enabled {
+		return domain.LastOperation{}, fmt.Errorf("not supported")
+	}
+
+	binding, err := b.bindingsStorage.Get(instanceID, bindingID)
+	switch {
+	case dberr.IsNotFound(err):
+		return domain.LastOperation{}, apiresponses.ErrBindingDoesNotExist
+	case err != nil:
+		message := fmt.Sprintf("failed to get Kyma binding from storage: %s", err)
+		return domain.LastOperation{}, apiresponses.NewFailureResponse(errors.New(message), http.StatusInternalServerError, message)
+	}
+
+	state, description := bindingState(binding, b.config.AsyncCreationTimeout)
+	return domain.LastOperation{
+		State:       state,
+		Description: description,
+	}, nil
+}
+
+// bindingState returns the state of the binding creation. A binding still in progress after the creation timeout is reported
+// as failed, because its creation was interrupted, for example by a restart of the broker.
+func bindingState(binding *internal.Binding, creationTimeout time.Duration) (domain.LastOperationState, string) {
+	state := binding.State
+	if state == "" {
+		// bindings created before the state was introduced have no kubeconfig only while they are created
+		state = domain.Succeeded
+		if len(binding.Kubeconfig) == 0 {
+			state = domain.InProgress
+		}
+	}
+
+	switch state {
+	case domain.InProgress:
+		if binding.CreatedAt.Add(creationTimeout).Before(time.Now()) {
+			return domain.Failed, "binding creation was interrupted"
+		}
+		return domain.InProgress, "binding creation in progress"
+	case domain.Failed:
+		return domain.Failed, binding.Description
+	default:
+		return domain.Succeeded, "binding created"
+	}
 }
@@ -0,0 +1,205 @@
+package broker
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/kyma-project/kyma-environment-broker/internal"
+	brokerBindings "github.com/kyma-project/kyma-environment-broker/internal/broker/bindings"
+	"github.com/kyma-project/kyma-environment-broker/internal/fixture"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage"
+
+	"github.com/pivotal-cf/brokerapi/v12/domain"
+	"github.com/pivotal-cf/brokerapi/v12/domain/apiresponses"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAsyncBindingCreation(t *testing.T) {
+	details := domain.BindDetails{
+		ServiceID: "123",
+		PlanID:    fixture.PlanId,
+	}
+
+	t.Run("should create the binding in the background", func(t *testing.T) {
+		// given
+		bindEndpoint, db := prepareBindingEndpoint(t, fixBindingConfig())
+		lastOperationEndpoint := NewLastBindingOperation(fixBindingConfig(), db, fixLogger())
+
+		// when
+		response, err := bindEndpoint.Bind(context.Background(), instanceID1, "async-binding-id", details, true)
+
+		// then
+		require.NoError(t, err)
+		assert.True(t, response.IsAsync)
+		assert.Equal(t, bindingOperationCreate, response.OperationData)
+		assert.Nil(t, response.Credentials)
+
+		assert.Eventually(t, func() bool {
+			lastOperation, err := lastOperationEndpoint.LastBindingOperation(context.Background(), instanceID1, "async-binding-id", domain.PollDetails{})
+			return err == nil && lastOperation.State == domain.Succeeded
+		}, 5*time.Second, 10*time.Millisecond)
+
+		binding, err := db.Bindings().Get(instanceID1, "async-binding-id")
+		require.NoError(t, err)
+		assert.NotEmpty(t, binding.Kubeconfig)
+		assert.Equal(t, domain.Succeeded, binding.State)
+
+		// when
+		response, err = bindEndpoint.Bind(context.Background(), instanceID1, "async-binding-id", details, true)
+
+		// then
+		require.NoError(t, err)
+		assert.True(t, response.AlreadyExists)
+		assert.Equal(t, binding.Kubeconfig, response.Credentials.(Credentials).Kubeconfig)
+	})
+
+	t.Run("should report the failure of the binding creation", func(t *testing.T) {
+		// given
+		db := storage.NewMemoryStorage()
+		require.NoError(t, db.Instances().Insert(fixture.FixInstance(instanceID1)))
+		require.NoError(t, db.Operations().InsertOperation(fixture.FixOperation("operation-id", instanceID1, internal.OperationTypeProvision)))
+		bindEndpoint := NewBind(fixBindingConfig(), db, fixLogger(), brokerBindings.NewBindingsManagers(&dummyProvider{}, &dummyProvider{}, nil), &recordingPublisher{})
+		lastOperationEndpoint := NewLastBindingOperation(fixBindingConfig(), db, fixLogger())
+
+		// when
+		response, err := bindEndpoint.Bind(context.Background(), instanceID1, "failing-binding-id", details, true)
+
+		// then
+		require.NoError(t, err)
+		assert.True(t, response.IsAsync)
+
+		var lastOperation domain.LastOperation
+		assert.Eventually(t, func() bool {
+			lastOperation, err = lastOperationEndpoint.LastBindingOperation(context.Background(), instanceID1, "failing-binding-id", domain.PollDetails{})
+			return err == nil && lastOperation.State == domain.Failed
+		}, 5*time.Second, 10*time.Millisecond)
+		assert.Contains(t, lastOperation.Description, "failed to create a Kyma binding")
+
+		// when
+		_, err = bindEndpoint.Bind(context.Background(), instanceID1, "failing-binding-id", details, true)
+
+		// then
+		apierr, ok := err.(*apiresponses.FailureResponse)
+		require.True(t, ok)
+		assert.Equal(t, http.StatusUnprocessableEntity, apierr.ValidatedStatusCode(nil))
+	})
+
+	t.Run("should return accepted again for the binding in progress", func(t *testing.T) {
+		// given
+		bindEndpoint, db := prepareBindingEndpoint(t, fixBindingConfig())
+		inProgress := fixture.FixBinding("in-progress-binding-id", fixture.WithInstanceID(instanceID1))
+		inProgress.Kubeconfig = ""
+		inProgress.State = domain.InProgress
+		require.NoError(t, db.Bindings().Insert(&inProgress))
+
+		// when
+		response, err := bindEndpoint.Bind(context.Background(), instanceID1, "in-progress-binding-id", details, true)
+
+		// then
+		require.NoError(t, err)
+		assert.True(t, response.IsAsync)
+		assert.False(t, response.AlreadyExists)
+
+		// when
+		_, err = bindEndpoint.Bind(context.Background(), instanceID1, "in-progress-binding-id", details, false)
+
+		// then
+		apierr, ok := err.(*apiresponses.FailureResponse)
+		require.True(t, ok)
+		assert.Equal(t, http.StatusUnprocessableEntity, apierr.ValidatedStatusCode(nil))
+	})
+
+	t.Run("should create the binding synchronously when the asynchronous creation is disabled", func(t *testing.T) {
+		// given
+		cfg := fixBindingConfig()
+		cfg.AsyncCreationEnabled = false
+		bindEndpoint, db := prepareBindingEndpoint(t, cfg)
+
+		// when
+		response, err := bindEndpoint.Bind(context.Background(), instanceID1, "sync-binding-id", details, true)
+
+		// then
+		require.NoError(t, err)
+		assert.False(t, response.IsAsync)
+		assert.NotEmpty(t, response.Credentials.(Credentials).Kubeconfig)
+
+		binding, err := db.Bindings().Get(instanceID1, "sync-binding-id")
+		require.NoError(t, err)
+		assert.Equal(t, domain.Succeeded, binding.State)
+	})
+}
+
+func TestLastBindingOperationEndpoint(t *testing.T) {
+	// given
+	db := storage.NewMemoryStorage()
+	endpoint := NewLastBindingOperation(fixBindingConfig(), db, fixLogger())
+
+	for name, tc := range map[string]struct {
+		binding          func(binding *internal.Binding)
+		expectedState    domain.LastOperationState
+		expectedContains string
+	}{
+		"succeeded binding": {
+			binding:       func(binding *internal.Binding) {},
+			expectedState: domain.Succeeded,
+		},
+		"binding in progress": {
+			binding: func(binding *internal.Binding) {
+				binding.Kubeconfig = ""
+				binding.State = domain.InProgress
+			},
+			expectedState: domain.InProgress,
+		},
+		"failed binding": {
+			binding: func(binding *internal.Binding) {
+				binding.Kubeconfig = ""
+				binding.State = domain.Failed
+				binding.Description = "runtime not reachable"
+			},
+			expectedState:    domain.Failed,
+			expectedContains: "runtime not reachable",
+		},
+		"interrupted binding": {
+			binding: func(binding *internal.Binding) {
+				binding.Kubeconfig = ""
+				binding.State = domain.InProgress
+				binding.CreatedAt = time.Now().Add(-time.Hour)
+			},
+			expectedState:    domain.Failed,
+			expectedContains: "interrupted",
+		},
+		"binding created before the state was introduced": {
+			binding: func(binding *internal.Binding) {
+				binding.State = ""
+			},
+			expectedState: domain.Succeeded,
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			binding := fixture.FixBinding(name, fixture.WithInstanceID(instanceID1))
+			tc.binding(&binding)
+			require.NoError(t, db.Bindings().Insert(&binding))
+
+			// when
+			lastOperation, err := endpoint.LastBindingOperation(context.Background(), instanceID1, name, domain.PollDetails{})
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedState, lastOperation.State)
+			assert.Contains(t, lastOperation.Description, tc.expectedContains)
+		})
+	}
+
+	t.Run("should return 410 for not existing binding", func(t *testing.T) {
+		// when
+		_, err := endpoint.LastBindingOperation(context.Background(), instanceID1, "not-existing", domain.PollDetails{})
+
+		// then
+		apierr, ok := err.(*apiresponses.FailureResponse)
+		require.True(t, ok)
+		assert.Equal(t, http.StatusGone, apierr.ValidatedStatusCode(nil))
+	})
+}
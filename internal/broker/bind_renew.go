@@ -0,0 +1,174 @@
+package broker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/kyma-project/kyma-environment-broker/internal"
+	broker "github.com/kyma-project/kyma-environment-broker/internal/broker/bindings"
+	"github.com/kyma-project/kyma-environment-broker/internal/event"
+	"github.com/kyma-project/kyma-environment-broker/internal/httputil"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage/dberr"
+
+	"github.com/pivotal-cf/brokerapi/v12/domain"
+	"github.com/pivotal-cf/brokerapi/v12/domain/apiresponses"
+)
+
+// RenewBindingEndpoint issues a new token for an existing binding, so the binding can be used longer without creating a new one
+type RenewBindingEndpoint struct {
+	config            BindingConfig
+	instancesStorage  storage.Instances
+	bindingsStorage   storage.Bindings
+	operationsStorage storage.Operations
+	bindingsManagers  broker.BindingsManagers
+	publisher         event.Publisher
+
+	log *slog.Logger
+}
+
+func NewRenewBinding(cfg BindingConfig, db storage.BrokerStorage, log *slog.Logger, bindingsManagers broker.BindingsManagers, publisher event.Publisher) *RenewBindingEndpoint {
+	return &RenewBindingEndpoint{
+		config:            cfg,
+		instancesStorage:  db.Instances(),
+		bindingsStorage:   db.Bindings(),
+		operationsStorage: db.Operations(),
+		bindingsManagers:  bindingsManagers,
+		publisher:         publisher,
+		log:               log.With("service", "RenewBindingEndpoint"),
+	}
+}
+
+// AttachRoutes attaches the renewal route for each prefix. The router must contain the broker API middlewares.
+func (b *RenewBindingEndpoint) AttachRoutes(router *httputil.Router, prefixes []string) {
+	for _, prefix := range prefixes {
+		router.HandleFunc(buildPathPattern(http.MethodPut, prefix, "/v2/service_instances/{instance_id}/service_bindings/{binding_id}/renew"), b.renew)
+	}
+}
+
+func (b *RenewBindingEndpoint) renew(w http.ResponseWriter, req *http.Request) {
+	binding, err := b.Renew(req.Context(), req.PathValue("instance_id"), req.PathValue("binding_id"))
+
+	var failureResponse *apiresponses.FailureResponse
+	switch {
+	case errors.As(err, &failureResponse):
+		b.log.Info(fmt.Sprintf("binding renewal rejected: %s", err))
+		httputil.WriteErrorResponse(w, failureResponse.ValidatedStatusCode(b.log), err)
+	case err != nil:
+		b.log.Error(fmt.Sprintf("binding renewal failed: %s", err))
+		httputil.WriteErrorResponse(w, http.StatusInternalServerError, err)
+	default:
+		httputil.WriteResponse(w, http.StatusOK, apiresponses.BindingResponse{
+			Credentials: binding.Credentials,
+			Metadata:    binding.Metadata,
+		})
+	}
+}
+
+// Renew creates a new token for the service account of the binding and replaces the kubeconfig and the expiration time of the binding.
+// The binding keeps its ID, parameters and permissions, so it is not counted again against the bindings limit.
+// Each renewed token is valid for at most MaxExpirationSeconds. The total lifetime of the binding, counted from its creation,
+// cannot exceed MaxLifetimeSeconds (0 disables the limit), so the last renewed token expires at the end of the lifetime and later renewals are rejected.
+//
+//	PUT /v2/service_instances/{instance_id}/service_bindings/{binding_id}/renew
+func (b *RenewBindingEndpoint) Renew(ctx context.Context, instanceID, bindingID string) (domain.Binding, error) {
+	b.log.Info(fmt.Sprintf("Renew binding %s for instance %s", bindingID, instanceID))
+
+	if !b.config.Enabled {
+		message := "binding is not supported"
+		return domain.Binding{}, apiresponses.NewFailureResponse(errors.New(message), http.StatusUnprocessableEntity, message)
+	}
+
+	instance, err := b.instancesStorage.GetByID(instanceID)
+	switch {
+	case dberr.IsNotFound(err):
+		return domain.Binding{}, apiresponses.ErrInstanceDoesNotExist
+	case err != nil:
+		return domain.Binding{}, apiresponses.NewFailureResponse(fmt.Errorf("failed to get instance %s", instanceID), http.StatusInternalServerError, fmt.Sprintf("failed to get instance %s", instanceID))
+	}
+
+	lastOperation, err := b.operationsStorage.GetLastOperation(instanceID)
+	if err != nil {
+		return domain.Binding{}, apiresponses.NewFailureResponse(fmt.Errorf("failed to get last operation for instance %s", instanceID), http.StatusInternalServerError, fmt.Sprintf("failed to get last operation for instance %s", instanceID))
+	}
+	if lastOperation.Type == internal.OperationTypeDeprovision {
+		message := "Binding not found"
+		return domain.Binding{}, apiresponses.NewFailureResponse(errors.New(message), http.StatusNotFound, message)
+	}
+
+	binding, err := b.bindingsStorage.Get(instanceID, bindingID)
+	switch {
+	case dberr.IsNotFound(err):
+		return domain.Binding{}, apiresponses.ErrBindingDoesNotExist
+	case err != nil:
+		message := fmt.Sprintf("failed to get Kyma binding from storage: %s", err)
+		return domain.Binding{}, apiresponses.NewFailureResponse(errors.New(message), http.StatusInternalServerError, message)
+	}
+
+	if len(binding.Kubeconfig) == 0 {
+		message := "binding creation in progress"
+		return domain.Binding{}, apiresponses.NewFailureResponse(errors.New(message), http.StatusUnprocessableEntity, message)
+	}
+	if binding.ExpiresAt.Before(time.Now()) {
+		message := "binding expired"
+		return domain.Binding{}, apiresponses.NewFailureResponse(errors.New(message), http.StatusNotFound, message)
+	}
+
+	expirationSeconds := b.renewedExpirationSeconds(binding)
+	if expirationSeconds < b.config.MinExpirationSeconds {
+		message := fmt.Sprintf("binding reached its maximum lifetime of %d seconds, create a new binding", b.config.MaxLifetimeSeconds)
+		return domain.Binding{}, apiresponses.NewFailureResponse(errors.New(message), http.StatusUnprocessableEntity, message)
+	}
+
+	bindingsManager, err := b.bindingsManagers.For(binding.Scope.CredentialType)
+	if err != nil {
+		message := fmt.Sprintf("failed to renew the Kyma binding: %s", err)
+		return domain.Binding{}, apiresponses.NewFailureResponse(errors.New(message), http.StatusInternalServerError, message)
+	}
+	kubeconfig, expiresAt, err := bindingsManager.RenewToken(ctx, instance, bindingID, expirationSeconds)
+	if err != nil {
+		message := fmt.Sprintf("failed to renew the Kyma binding token: %s", err)
+		b.log.Error(fmt.Sprintf("for instance %s %s", instanceID, message))
+		return domain.Binding{}, apiresponses.NewFailureResponse(errors.New(message), http.StatusInternalServerError, message)
+	}
+
+	binding.Kubeconfig = kubeconfig
+	binding.ExpiresAt = expiresAt
+	binding.UpdatedAt = time.Now()
+
+	err = b.bindingsStorage.Update(binding)
+	if err != nil {
+		message := fmt.Sprintf("failed to update Kyma binding in storage: %s", err)
+		return domain.Binding{}, apiresponses.NewFailureResponse(errors.New(message), http.StatusInternalServerError, message)
+	}
+	b.log.Info(fmt.Sprintf("Successfully renewed binding %s for instance %s, expires at %s", bindingID, instanceID, expiresAt.Format(time.RFC3339)))
+	b.publisher.Publish(context.Background(), BindingRenewed{PlanID: instance.ServicePlanID})
+
+	return domain.Binding{
+		Credentials: Credentials{
+			Kubeconfig: kubeconfig,
+		},
+		Metadata: domain.BindingMetadata{
+			ExpiresAt: expiresAt.Format(expiresAtLayout),
+		},
+	}, nil
+}
+
+// renewedExpirationSeconds returns the expiration of the renewed token limited by MaxExpirationSeconds and shortened to the lifetime left to the binding
+func (b *RenewBindingEndpoint) renewedExpirationSeconds(binding *internal.Binding) int {
+	expirationSeconds := min(int(binding.ExpirationSeconds), b.config.MaxExpirationSeconds)
+	if b.config.MaxLifetimeSeconds <= 0 {
+		return expirationSeconds
+	}
+	lifetimeEnd := binding.CreatedAt.Add(time.Duration(b.config.MaxLifetimeSeconds) * time.Second)
+	remaining := int(time.Until(lifetimeEnd).Seconds())
+	return min(expirationSeconds, remaining)
+}
+
+type BindingRenewed struct {
+	PlanID string
+}
@@ -0,0 +1,236 @@
+package broker
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kyma-project/kyma-environment-broker/internal"
+	brokerBindings "github.com/kyma-project/kyma-environment-broker/internal/broker/bindings"
+	"github.com/kyma-project/kyma-environment-broker/internal/fixture"
+	"github.com/kyma-project/kyma-environment-broker/internal/httputil"
+	"github.com/kyma-project/kyma-environment-broker/internal/kubeconfig"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage"
+
+	"github.com/pivotal-cf/brokerapi/v12/domain"
+	"github.com/pivotal-cf/brokerapi/v12/domain/apiresponses"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+type recordingPublisher struct {
+	mu     sync.Mutex
+	events []interface{}
+}
+
+func (p *recordingPublisher) Publish(_ context.Context, ev interface{}) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.events = append(p.events, ev)
+}
+
+func TestRenewBindingEndpoint(t *testing.T) {
+	// given
+	db, bindEndpoint, renewEndpoint, publisher := prepareRenewBindingEndpoint(t)
+
+	_, err := bindEndpoint.Bind(context.Background(), instanceID1, "binding-id", domain.BindDetails{
+		ServiceID:     "123",
+		PlanID:        fixture.PlanId,
+		RawParameters: json.RawMessage(`{"expiration_seconds": 1000, "profile": "read-only"}`),
+	}, false)
+	require.NoError(t, err)
+	// the stored kubeconfig and expiration time are changed to see that the renewal replaces them
+	binding, err := db.Bindings().Get(instanceID1, "binding-id")
+	require.NoError(t, err)
+	binding.ExpiresAt = time.Now().Add(time.Minute)
+	binding.Kubeconfig = "old-kubeconfig"
+	require.NoError(t, db.Bindings().Update(binding))
+
+	t.Run("should renew the binding token", func(t *testing.T) {
+		// when
+		renewed, err := renewEndpoint.Renew(context.Background(), instanceID1, "binding-id")
+
+		// then
+		require.NoError(t, err)
+		assert.NotEqual(t, "old-kubeconfig", renewed.Credentials.(Credentials).Kubeconfig)
+
+		stored, err := db.Bindings().Get(instanceID1, "binding-id")
+		require.NoError(t, err)
+		assert.Equal(t, renewed.Credentials.(Credentials).Kubeconfig, stored.Kubeconfig)
+		assert.WithinDuration(t, time.Now().Add(1000*time.Second), stored.ExpiresAt, 10*time.Second)
+		assert.Equal(t, int64(1000), stored.ExpirationSeconds)
+		assert.Equal(t, internal.BindingProfileReadOnly, stored.Scope.Profile, "the scope is not changed")
+		assert.Contains(t, publisher.events, BindingRenewed{PlanID: fixture.PlanId})
+
+		bindings, err := db.Bindings().ListByInstanceID(instanceID1)
+		require.NoError(t, err)
+		assert.Len(t, bindings, 1, "no new binding is created")
+	})
+
+	t.Run("should return the renewed binding over HTTP", func(t *testing.T) {
+		// given
+		router := httputil.NewRouter()
+		renewEndpoint.AttachRoutes(router, []string{""})
+		req := httptest.NewRequest(http.MethodPut, "/v2/service_instances/"+instanceID1+"/service_bindings/binding-id/renew", nil)
+		rec := httptest.NewRecorder()
+
+		// when
+		router.ServeHTTP(rec, req)
+
+		// then
+		require.Equal(t, http.StatusOK, rec.Code)
+		var response apiresponses.BindingResponse
+		require.NoError(t, json.NewDecoder(rec.Body).Decode(&response))
+		assert.NotEmpty(t, response.Credentials.(map[string]interface{})["kubeconfig"])
+		assert.NotEmpty(t, response.Metadata.(map[string]interface{})["expires_at"])
+	})
+
+	t.Run("should renew the binding after the maximum expiration time since its creation", func(t *testing.T) {
+		// given
+		binding, err := db.Bindings().Get(instanceID1, "binding-id")
+		require.NoError(t, err)
+		binding.CreatedAt = time.Now().Add(-(maxExpirationSeconds + 3600) * time.Second)
+		require.NoError(t, db.Bindings().Update(binding))
+
+		// when
+		_, err = renewEndpoint.Renew(context.Background(), instanceID1, "binding-id")
+
+		// then
+		require.NoError(t, err)
+		stored, err := db.Bindings().Get(instanceID1, "binding-id")
+		require.NoError(t, err)
+		assert.WithinDuration(t, time.Now().Add(1000*time.Second), stored.ExpiresAt, 10*time.Second)
+	})
+
+	t.Run("should limit the token to the maximum expiration time", func(t *testing.T) {
+		// given
+		binding, err := db.Bindings().Get(instanceID1, "binding-id")
+		require.NoError(t, err)
+		binding.ExpirationSeconds = maxExpirationSeconds + 1000
+		require.NoError(t, db.Bindings().Update(binding))
+
+		// when
+		_, err = renewEndpoint.Renew(context.Background(), instanceID1, "binding-id")
+
+		// then
+		require.NoError(t, err)
+		stored, err := db.Bindings().Get(instanceID1, "binding-id")
+		require.NoError(t, err)
+		assert.WithinDuration(t, time.Now().Add(maxExpirationSeconds*time.Second), stored.ExpiresAt, 10*time.Second)
+
+		stored.ExpirationSeconds = 1000
+		require.NoError(t, db.Bindings().Update(stored))
+	})
+
+	t.Run("should shorten the token to the remaining lifetime of the binding", func(t *testing.T) {
+		// given
+		binding, err := db.Bindings().Get(instanceID1, "binding-id")
+		require.NoError(t, err)
+		binding.CreatedAt = time.Now().Add(-(maxLifetimeSeconds - 700) * time.Second)
+		require.NoError(t, db.Bindings().Update(binding))
+
+		// when
+		_, err = renewEndpoint.Renew(context.Background(), instanceID1, "binding-id")
+
+		// then
+		require.NoError(t, err)
+		stored, err := db.Bindings().Get(instanceID1, "binding-id")
+		require.NoError(t, err)
+		assert.WithinDuration(t, binding.CreatedAt.Add(maxLifetimeSeconds*time.Second), stored.ExpiresAt, 10*time.Second)
+		assert.Equal(t, int64(1000), stored.ExpirationSeconds, "the requested expiration is not changed")
+	})
+
+	t.Run("should return 422 for binding which reached its maximum lifetime", func(t *testing.T) {
+		// given
+		binding, err := db.Bindings().Get(instanceID1, "binding-id")
+		require.NoError(t, err)
+		binding.CreatedAt = time.Now().Add(-(maxLifetimeSeconds - 300) * time.Second)
+		binding.Kubeconfig = "last-kubeconfig"
+		require.NoError(t, db.Bindings().Update(binding))
+
+		// when
+		_, err = renewEndpoint.Renew(context.Background(), instanceID1, "binding-id")
+
+		// then
+		apierr, ok := err.(*apiresponses.FailureResponse)
+		require.True(t, ok)
+		assert.Equal(t, http.StatusUnprocessableEntity, apierr.ValidatedStatusCode(nil))
+		stored, err := db.Bindings().Get(instanceID1, "binding-id")
+		require.NoError(t, err)
+		assert.Equal(t, "last-kubeconfig", stored.Kubeconfig)
+	})
+
+	t.Run("should return 410 for not existing binding", func(t *testing.T) {
+		// when
+		_, err := renewEndpoint.Renew(context.Background(), instanceID1, "not-existing")
+
+		// then
+		apierr, ok := err.(*apiresponses.FailureResponse)
+		require.True(t, ok)
+		assert.Equal(t, http.StatusGone, apierr.ValidatedStatusCode(nil))
+	})
+
+	t.Run("should return 404 for expired binding", func(t *testing.T) {
+		// given
+		expired := fixture.FixBinding("expired-binding-id")
+		expired.InstanceID = instanceID1
+		expired.ExpiresAt = time.Now().Add(-time.Minute)
+		require.NoError(t, db.Bindings().Insert(&expired))
+
+		// when
+		_, err := renewEndpoint.Renew(context.Background(), instanceID1, "expired-binding-id")
+
+		// then
+		apierr, ok := err.(*apiresponses.FailureResponse)
+		require.True(t, ok)
+		assert.Equal(t, http.StatusNotFound, apierr.ValidatedStatusCode(nil))
+	})
+
+	t.Run("should return 422 for binding in progress", func(t *testing.T) {
+		// given
+		inProgress := fixture.FixBinding("in-progress-binding-id")
+		inProgress.InstanceID = instanceID1
+		inProgress.Kubeconfig = ""
+		require.NoError(t, db.Bindings().Insert(&inProgress))
+
+		// when
+		_, err := renewEndpoint.Renew(context.Background(), instanceID1, "in-progress-binding-id")
+
+		// then
+		apierr, ok := err.(*apiresponses.FailureResponse)
+		require.True(t, ok)
+		assert.Equal(t, http.StatusUnprocessableEntity, apierr.ValidatedStatusCode(nil))
+	})
+
+	t.Run("should return 410 for not existing instance", func(t *testing.T) {
+		// when
+		_, err := renewEndpoint.Renew(context.Background(), "not-existing", "binding-id")
+
+		// then
+		apierr, ok := err.(*apiresponses.FailureResponse)
+		require.True(t, ok)
+		assert.Equal(t, http.StatusGone, apierr.ValidatedStatusCode(nil))
+	})
+}
+
+func prepareRenewBindingEndpoint(t *testing.T) (storage.BrokerStorage, *BindEndpoint, *RenewBindingEndpoint, *recordingPublisher) {
+	sch := internal.NewSchemeForTests(t)
+	k8sClientProvider := kubeconfig.NewFakeK8sClientProvider(fake.NewClientBuilder().WithScheme(sch).Build())
+	db := storage.NewMemoryStorage()
+	publisher := &recordingPublisher{}
+
+	err := db.Instances().Insert(fixture.FixInstance(instanceID1))
+	require.NoError(t, err)
+	err = db.Operations().InsertOperation(fixture.FixOperation("operation-id", instanceID1, internal.OperationTypeProvision))
+	require.NoError(t, err)
+
+	bindingsManagers := brokerBindings.NewBindingsManagers(k8sClientProvider, k8sClientProvider, nil)
+	bindEndpoint := NewBind(fixBindingConfig(), db, fixLogger(), bindingsManagers, publisher)
+	renewEndpoint := NewRenewBinding(fixBindingConfig(), db, fixLogger(), bindingsManagers, publisher)
+	return db, bindEndpoint, renewEndpoint, publisher
+}
@@ -14,23 +14,49 @@ import (
 	"github.com/kyma-project/kyma-environment-broker/internal/ptr"
 	authv1 "k8s.io/api/authentication/v1"
 	v1 "k8s.io/api/core/v1"
-	rbacv1 "k8s.io/api/rbac/v1"
 	mv1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 const (
 	BindingNameFormat = "kyma-binding-%s"
 	BindingNamespace  = "kyma-system"
+
+	managedByLabelKey   = "app.kubernetes.io/managed-by"
+	managedByLabelValue = "kcp-kyma-environment-broker"
 )
 
 type Credentials struct {
 }
 
 type BindingsManager interface {
-	Create(ctx context.Context, instance *internal.Instance, bindingID string, expirationSeconds int) (string, time.Time, error)
+	Create(ctx context.Context, instance *internal.Instance, bindingID string, expirationSeconds int, scope internal.BindingScope) (string, time.Time, error)
+	RenewToken(ctx context.Context, instance *internal.Instance, bindingID string, expirationSeconds int) (string, time.Time, error)
 	Delete(ctx context.Context, instance *internal.Instance, bindingID string) error
 }
 
+// BindingsManagers holds the bindings managers by the type of credentials they create
+type BindingsManagers map[internal.BindingCredentialType]BindingsManager
+
+func NewBindingsManagers(clientProvider ClientProvider, kubeconfigProvider KubeconfigProvider, kcpClient client.Client) BindingsManagers {
+	return BindingsManagers{
+		internal.BindingCredentialTypeServiceAccount: NewServiceAccountBindingsManager(clientProvider, kubeconfigProvider),
+		internal.BindingCredentialTypeOIDC:           NewOIDCBindingsManager(clientProvider, kcpClient, kubeconfigProvider),
+	}
+}
+
+// For returns the bindings manager for the credential type. The empty type means the service account credentials.
+func (m BindingsManagers) For(credentialType internal.BindingCredentialType) (BindingsManager, error) {
+	if credentialType == "" {
+		credentialType = internal.BindingCredentialTypeServiceAccount
+	}
+	manager, found := m[credentialType]
+	if !found {
+		return nil, fmt.Errorf("unsupported credential type %q", credentialType)
+	}
+	return manager, nil
+}
+
 type ClientProvider interface {
 	K8sClientSetForRuntimeID(runtimeID string) (kubernetes.Interface, error)
 }
@@ -51,7 +77,7 @@ func NewServiceAccountBindingsManager(clientProvider ClientProvider, kubeconfigP
 	}
 }
 
-func (c *ServiceAccountBindingsManager) Create(ctx context.Context, instance *internal.Instance, bindingID string, expirationSeconds int) (string, time.Time, error) {
+func (c *ServiceAccountBindingsManager) Create(ctx context.Context, instance *internal.Instance, bindingID string, expirationSeconds int, scope internal.BindingScope) (string, time.Time, error) {
 	clientset, err := c.clientProvider.K8sClientSetForRuntimeID(instance.RuntimeID)
 
 	if err != nil {
@@ -66,7 +92,7 @@ func (c *ServiceAccountBindingsManager) Create(ctx context.Context, instance *in
 			ObjectMeta: mv1.ObjectMeta{
 				Name:      serviceBindingName,
 				Namespace: BindingNamespace,
-				Labels:    map[string]string{"app.kubernetes.io/managed-by": "kcp-kyma-environment-broker"},
+				Labels:    map[string]string{managedByLabelKey: managedByLabelValue},
 			},
 		}, mv1.CreateOptions{})
 
@@ -74,61 +100,38 @@ func (c *ServiceAccountBindingsManager) Create(ctx context.Context, instance *in
 		return "", time.Time{}, fmt.Errorf("while creating a service account: %v", err)
 	}
 
-	_, err = clientset.RbacV1().ClusterRoles().Create(ctx,
-		&rbacv1.ClusterRole{
-			TypeMeta: mv1.TypeMeta{APIVersion: rbacv1.SchemeGroupVersion.String(), Kind: "ClusterRole"},
-			ObjectMeta: mv1.ObjectMeta{
-				Name:   serviceBindingName,
-				Labels: map[string]string{"app.kubernetes.io/managed-by": "kcp-kyma-environment-broker"},
-			},
-			Rules: []rbacv1.PolicyRule{
-				{
-					Verbs:     []string{"*"},
-					APIGroups: []string{"*"},
-					Resources: []string{"*"},
-				},
-			},
-		}, mv1.CreateOptions{})
-	if err != nil && !apierrors.IsAlreadyExists(err) {
-		return "", time.Time{}, fmt.Errorf("while creating a cluster role: %v", err)
+	err = createRBAC(ctx, clientset, serviceBindingName, scope, serviceAccountSubjects(serviceBindingName))
+	if err != nil {
+		return "", time.Time{}, err
 	}
 
-	_, err = clientset.RbacV1().ClusterRoleBindings().Create(ctx, &rbacv1.ClusterRoleBinding{
-		TypeMeta: mv1.TypeMeta{APIVersion: rbacv1.SchemeGroupVersion.String(), Kind: "ClusterRoleBinding"},
-		ObjectMeta: mv1.ObjectMeta{
-			Name:   serviceBindingName,
-			Labels: map[string]string{"app.kubernetes.io/managed-by": "kcp-kyma-environment-broker"},
-		},
-		RoleRef: rbacv1.RoleRef{
-			APIGroup: rbacv1.GroupName,
-			Kind:     "ClusterRole",
-			Name:     serviceBindingName,
-		},
-		Subjects: []rbacv1.Subject{
-			{
-				Kind:      rbacv1.ServiceAccountKind,
-				Namespace: "kyma-system",
-				Name:      serviceBindingName,
-			},
-		},
-	}, mv1.CreateOptions{})
+	return c.createKubeconfig(ctx, clientset, instance, serviceBindingName, expirationSeconds)
+}
 
-	if err != nil && !apierrors.IsAlreadyExists(err) {
-		return "", time.Time{}, fmt.Errorf("while creating a cluster role binding: %v", err)
+// RenewToken requests a new token for the existing service account of the binding and builds a new kubeconfig.
+// The permissions granted to the service account are not changed, and the previous token stays valid until it expires.
+func (c *ServiceAccountBindingsManager) RenewToken(ctx context.Context, instance *internal.Instance, bindingID string, expirationSeconds int) (string, time.Time, error) {
+	clientset, err := c.clientProvider.K8sClientSetForRuntimeID(instance.RuntimeID)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("while creating a runtime client for binding renewal: %v", err)
 	}
 
+	return c.createKubeconfig(ctx, clientset, instance, BindingName(bindingID), expirationSeconds)
+}
+
+func (c *ServiceAccountBindingsManager) createKubeconfig(ctx context.Context, clientset kubernetes.Interface, instance *internal.Instance, serviceBindingName string, expirationSeconds int) (string, time.Time, error) {
 	tokenRequest := &authv1.TokenRequest{
 		ObjectMeta: mv1.ObjectMeta{
 			Name:      serviceBindingName,
-			Namespace: "kyma-system",
-			Labels:    map[string]string{"app.kubernetes.io/managed-by": "kcp-kyma-environment-broker"},
+			Namespace: BindingNamespace,
+			Labels:    map[string]string{managedByLabelKey: managedByLabelValue},
 		},
 		Spec: authv1.TokenRequestSpec{
 			ExpirationSeconds: ptr.Integer64(int64(expirationSeconds)),
 		},
 	}
 
-	tkn, err := clientset.CoreV1().ServiceAccounts("kyma-system").CreateToken(ctx, serviceBindingName, tokenRequest, mv1.CreateOptions{})
+	tkn, err := clientset.CoreV1().ServiceAccounts(BindingNamespace).CreateToken(ctx, serviceBindingName, tokenRequest, mv1.CreateOptions{})
 
 	if err != nil {
 		return "", time.Time{}, fmt.Errorf("while creating a service account kubeconfig: %v", err)
@@ -151,30 +154,7 @@ func (c *ServiceAccountBindingsManager) Delete(ctx context.Context, instance *in
 		return fmt.Errorf("while creating a runtime client for binding creation: %v", err)
 	}
 
-	serviceBindingName := BindingName(bindingID)
-
-	// remove a binding
-	err = clientset.RbacV1().ClusterRoleBindings().Delete(ctx, serviceBindingName, mv1.DeleteOptions{})
-
-	if err != nil && !apierrors.IsNotFound(err) {
-		return fmt.Errorf("while removing a cluster role binding: %v", err)
-	}
-
-	// remove a role
-	err = clientset.RbacV1().ClusterRoles().Delete(ctx, serviceBindingName, mv1.DeleteOptions{})
-
-	if err != nil && !apierrors.IsNotFound(err) {
-		return fmt.Errorf("while removing a cluster role: %v", err)
-	}
-
-	// remove an account
-	err = clientset.CoreV1().ServiceAccounts("kyma-system").Delete(ctx, serviceBindingName, mv1.DeleteOptions{})
-
-	if err != nil && !apierrors.IsNotFound(err) {
-		return fmt.Errorf("while creating a service account: %v", err)
-	}
-
-	return nil
+	return DeleteBindingResources(ctx, clientset, bindingID)
 }
 
 func BindingName(bindingID string) string {
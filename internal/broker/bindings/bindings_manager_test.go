@@ -0,0 +1,126 @@
+package broker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kyma-project/kyma-environment-broker/internal"
+	"github.com/kyma-project/kyma-environment-broker/internal/fixture"
+	"github.com/kyma-project/kyma-environment-broker/internal/kubeconfig"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	mv1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+func TestServiceAccountBindingsManager_Scopes(t *testing.T) {
+	instance := fixture.FixInstance("instance-id")
+
+	t.Run("should create a cluster role with all permissions for the cluster-admin profile", func(t *testing.T) {
+		// given
+		manager, clientset := fixManager(t)
+
+		// when
+		_, _, err := manager.Create(context.Background(), &instance, "admin", 600, internal.BindingScope{Profile: internal.BindingProfileClusterAdmin})
+
+		// then
+		require.NoError(t, err)
+		clusterRole, err := clientset.RbacV1().ClusterRoles().Get(context.Background(), BindingName("admin"), mv1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, clusterAdminRules, clusterRole.Rules)
+		clusterRoleBinding, err := clientset.RbacV1().ClusterRoleBindings().Get(context.Background(), BindingName("admin"), mv1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, BindingName("admin"), clusterRoleBinding.RoleRef.Name)
+	})
+
+	t.Run("should bind the view cluster role for the read-only profile", func(t *testing.T) {
+		// given
+		manager, clientset := fixManager(t)
+
+		// when
+		_, _, err := manager.Create(context.Background(), &instance, "viewer", 600, internal.BindingScope{Profile: internal.BindingProfileReadOnly})
+
+		// then
+		require.NoError(t, err)
+		clusterRoleBinding, err := clientset.RbacV1().ClusterRoleBindings().Get(context.Background(), BindingName("viewer"), mv1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, "view", clusterRoleBinding.RoleRef.Name)
+		_, err = clientset.RbacV1().ClusterRoles().Get(context.Background(), BindingName("viewer"), mv1.GetOptions{})
+		assert.True(t, apierrors.IsNotFound(err), "no cluster role granting access to Secrets is created")
+	})
+
+	t.Run("should create roles and role bindings for the namespace-admin profile", func(t *testing.T) {
+		// given
+		manager, clientset := fixManager(t)
+		name := BindingName("ns-admin")
+
+		// when
+		_, _, err := manager.Create(context.Background(), &instance, "ns-admin", 600, internal.BindingScope{
+			Profile:    internal.BindingProfileNamespaceAdmin,
+			Namespaces: []string{"team-a", "team-b"},
+		})
+
+		// then
+		require.NoError(t, err)
+		for _, namespace := range []string{"team-a", "team-b"} {
+			_, err := clientset.RbacV1().Roles(namespace).Get(context.Background(), name, mv1.GetOptions{})
+			require.NoError(t, err)
+			roleBinding, err := clientset.RbacV1().RoleBindings(namespace).Get(context.Background(), name, mv1.GetOptions{})
+			require.NoError(t, err)
+			assert.Equal(t, "Role", roleBinding.RoleRef.Kind)
+		}
+		_, err = clientset.RbacV1().ClusterRoleBindings().Get(context.Background(), name, mv1.GetOptions{})
+		assert.True(t, apierrors.IsNotFound(err), "no cluster-wide permissions are granted")
+
+		// when
+		err = manager.Delete(context.Background(), &instance, "ns-admin")
+
+		// then
+		require.NoError(t, err)
+		for _, namespace := range []string{"team-a", "team-b"} {
+			_, err := clientset.RbacV1().Roles(namespace).Get(context.Background(), name, mv1.GetOptions{})
+			assert.True(t, apierrors.IsNotFound(err))
+			_, err = clientset.RbacV1().RoleBindings(namespace).Get(context.Background(), name, mv1.GetOptions{})
+			assert.True(t, apierrors.IsNotFound(err))
+		}
+	})
+
+	t.Run("should bind the allow-listed cluster role for the cluster-role profile", func(t *testing.T) {
+		// given
+		manager, clientset := fixManager(t)
+
+		// when
+		_, _, err := manager.Create(context.Background(), &instance, "cluster-view", 600, internal.BindingScope{
+			Profile:     internal.BindingProfileClusterRole,
+			ClusterRole: "view",
+		})
+		require.NoError(t, err)
+		_, _, err = manager.Create(context.Background(), &instance, "ns-edit", 600, internal.BindingScope{
+			Profile:     internal.BindingProfileClusterRole,
+			ClusterRole: "edit",
+			Namespaces:  []string{"team-a"},
+		})
+		require.NoError(t, err)
+
+		// then
+		clusterRoleBinding, err := clientset.RbacV1().ClusterRoleBindings().Get(context.Background(), BindingName("cluster-view"), mv1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, "view", clusterRoleBinding.RoleRef.Name)
+		_, err = clientset.RbacV1().ClusterRoles().Get(context.Background(), BindingName("cluster-view"), mv1.GetOptions{})
+		assert.True(t, apierrors.IsNotFound(err), "the existing cluster role is used")
+
+		roleBinding, err := clientset.RbacV1().RoleBindings("team-a").Get(context.Background(), BindingName("ns-edit"), mv1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, "ClusterRole", roleBinding.RoleRef.Kind)
+		assert.Equal(t, "edit", roleBinding.RoleRef.Name)
+	})
+}
+
+func fixManager(t *testing.T) (*ServiceAccountBindingsManager, kubernetes.Interface) {
+	provider := kubeconfig.NewFakeK8sClientProvider(nil)
+	clientset, err := provider.K8sClientSetForRuntimeID("")
+	require.NoError(t, err)
+	return NewServiceAccountBindingsManager(provider, provider), clientset
+}
@@ -0,0 +1,101 @@
+package broker
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/kyma-project/kyma-environment-broker/internal"
+	"github.com/kyma-project/kyma-environment-broker/internal/kubeconfig"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// SystemSubjectPrefix is the prefix of the users and groups reserved for Kubernetes components
+const SystemSubjectPrefix = "system:"
+
+// OIDCBindingsManager grants the permissions of the binding to an OIDC user or group and returns a kubeconfig with the OIDC exec plugin.
+// The kubeconfig contains no secret, the subject authenticates with its own identity provider.
+type OIDCBindingsManager struct {
+	clientProvider    ClientProvider
+	kubeconfigBuilder *kubeconfig.Builder
+	now               func() time.Time
+}
+
+func NewOIDCBindingsManager(clientProvider ClientProvider, kcpClient client.Client, kubeconfigProvider KubeconfigProvider) *OIDCBindingsManager {
+	return &OIDCBindingsManager{
+		clientProvider:    clientProvider,
+		kubeconfigBuilder: kubeconfig.NewBuilder(kcpClient, kubeconfigProvider),
+		now:               time.Now,
+	}
+}
+
+// Create binds the subject of the scope with the RBAC objects of the scope profile. The returned expiration time only
+// determines when the binding is removed together with its RBAC objects, the kubeconfig itself does not expire.
+func (c *OIDCBindingsManager) Create(ctx context.Context, instance *internal.Instance, bindingID string, expirationSeconds int, scope internal.BindingScope) (string, time.Time, error) {
+	if scope.Subject == nil {
+		return "", time.Time{}, fmt.Errorf("OIDC binding requires a subject")
+	}
+	// the kubeconfig is built first, so no permissions are granted for a runtime without the OIDC configuration
+	kubeconfigContent, oidcConfigs, err := c.kubeconfigBuilder.BuildFromOIDCConfigForBinding(instance)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("while creating an OIDC kubeconfig: %v", err)
+	}
+	subjects, err := oidcSubjects(*scope.Subject, oidcConfigs)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	clientset, err := c.clientProvider.K8sClientSetForRuntimeID(instance.RuntimeID)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("while creating a runtime client for binding creation: %v", err)
+	}
+
+	err = createRBAC(ctx, clientset, BindingName(bindingID), scope, subjects)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	return kubeconfigContent, c.now().Add(time.Duration(expirationSeconds) * time.Second), nil
+}
+
+// RenewToken rebuilds the kubeconfig, so the changes of the OIDC configuration are applied, and extends the binding expiration time.
+func (c *OIDCBindingsManager) RenewToken(_ context.Context, instance *internal.Instance, _ string, expirationSeconds int) (string, time.Time, error) {
+	kubeconfigContent, _, err := c.kubeconfigBuilder.BuildFromOIDCConfigForBinding(instance)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("while creating an OIDC kubeconfig: %v", err)
+	}
+	return kubeconfigContent, c.now().Add(time.Duration(expirationSeconds) * time.Second), nil
+}
+
+func (c *OIDCBindingsManager) Delete(ctx context.Context, instance *internal.Instance, bindingID string) error {
+	clientset, err := c.clientProvider.K8sClientSetForRuntimeID(instance.RuntimeID)
+	if err != nil {
+		return fmt.Errorf("while creating a runtime client for binding removal: %v", err)
+	}
+	return deleteRBAC(ctx, clientset, BindingName(bindingID))
+}
+
+// oidcSubjects returns the RBAC subjects of the binding subject as the API server sees it, that is, with the user name
+// or groups prefix of each OIDC config of the runtime, so the subject is bound whichever context of the kubeconfig is used
+func oidcSubjects(subject internal.BindingSubject, oidcConfigs []kubeconfig.OIDCConfig) ([]rbacv1.Subject, error) {
+	var subjects []rbacv1.Subject
+	for _, config := range oidcConfigs {
+		prefix := config.UsernamePrefix
+		if subject.Kind == rbacv1.GroupKind {
+			prefix = config.GroupsPrefix
+		}
+		name := prefix + subject.Name
+		if strings.HasPrefix(name, SystemSubjectPrefix) {
+			return nil, fmt.Errorf("OIDC subject %s must not have the %s prefix", name, SystemSubjectPrefix)
+		}
+		rbacSubject := rbacv1.Subject{Kind: subject.Kind, APIGroup: rbacv1.GroupName, Name: name}
+		if !slices.Contains(subjects, rbacSubject) {
+			subjects = append(subjects, rbacSubject)
+		}
+	}
+	return subjects, nil
+}
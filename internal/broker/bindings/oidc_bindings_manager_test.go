@@ -0,0 +1,130 @@
+package broker
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kyma-project/kyma-environment-broker/internal"
+	"github.com/kyma-project/kyma-environment-broker/internal/fixture"
+	"github.com/kyma-project/kyma-environment-broker/internal/kubeconfig"
+	"github.com/kyma-project/kyma-environment-broker/internal/ptr"
+
+	gardener "github.com/gardener/gardener/pkg/apis/core/v1beta1"
+	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	mv1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestOIDCBindingsManager(t *testing.T) {
+	// given
+	instance := fixture.FixInstance("instance-id")
+	runtimeResource := &imv1.Runtime{}
+	runtimeResource.ObjectMeta.Name = instance.RuntimeID
+	runtimeResource.ObjectMeta.Namespace = "kcp-system"
+	runtimeResource.Spec.Shoot.Kubernetes.KubeAPIServer.AdditionalOidcConfig = &[]imv1.OIDCConfig{
+		{
+			OIDCConfig: gardener.OIDCConfig{
+				ClientID:       ptr.String("client-id"),
+				IssuerURL:      ptr.String("https://issuer.example.com"),
+				UsernamePrefix: ptr.String("-"),
+				GroupsPrefix:   ptr.String("oidc:"),
+			},
+		},
+		{
+			OIDCConfig: gardener.OIDCConfig{
+				ClientID:       ptr.String("other-client-id"),
+				IssuerURL:      ptr.String("https://other-issuer.example.com"),
+				UsernamePrefix: ptr.String("-"),
+				GroupsPrefix:   ptr.String("-"),
+			},
+		},
+	}
+	kcpClient := fake.NewClientBuilder().WithScheme(internal.NewSchemeForTests(t)).WithRuntimeObjects(runtimeResource).Build()
+	provider := kubeconfig.NewFakeK8sClientProvider(nil)
+	clientset, err := provider.K8sClientSetForRuntimeID(instance.RuntimeID)
+	require.NoError(t, err)
+	manager := NewOIDCBindingsManager(provider, kcpClient, provider)
+	name := BindingName("oidc")
+
+	t.Run("should bind the OIDC group and return the OIDC kubeconfig", func(t *testing.T) {
+		// when
+		kubeconfigContent, expiresAt, err := manager.Create(context.Background(), &instance, "oidc", 600, internal.BindingScope{
+			Profile:        internal.BindingProfileNamespaceAdmin,
+			Namespaces:     []string{"team-a"},
+			CredentialType: internal.BindingCredentialTypeOIDC,
+			Subject:        &internal.BindingSubject{Kind: rbacv1.GroupKind, Name: "developers"},
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.Contains(t, kubeconfigContent, "--oidc-issuer-url=https://issuer.example.com")
+		assert.Contains(t, kubeconfigContent, "--oidc-client-id=client-id")
+		assert.NotContains(t, kubeconfigContent, "token:")
+		assert.False(t, expiresAt.IsZero())
+
+		roleBinding, err := clientset.RbacV1().RoleBindings("team-a").Get(context.Background(), name, mv1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, []rbacv1.Subject{
+			{Kind: rbacv1.GroupKind, APIGroup: rbacv1.GroupName, Name: "oidc:developers"},
+			{Kind: rbacv1.GroupKind, APIGroup: rbacv1.GroupName, Name: "developers"},
+		}, roleBinding.Subjects)
+	})
+
+	t.Run("should bind the OIDC user once for the configs with the same prefix", func(t *testing.T) {
+		// when
+		_, _, err := manager.Create(context.Background(), &instance, "oidc-user", 600, internal.BindingScope{
+			Profile:        internal.BindingProfileClusterAdmin,
+			CredentialType: internal.BindingCredentialTypeOIDC,
+			Subject:        &internal.BindingSubject{Kind: rbacv1.UserKind, Name: "jane@example.com"},
+		})
+
+		// then
+		require.NoError(t, err)
+		clusterRoleBinding, err := clientset.RbacV1().ClusterRoleBindings().Get(context.Background(), BindingName("oidc-user"), mv1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, []rbacv1.Subject{{Kind: rbacv1.UserKind, APIGroup: rbacv1.GroupName, Name: "jane@example.com"}}, clusterRoleBinding.Subjects)
+	})
+
+	t.Run("should renew the kubeconfig", func(t *testing.T) {
+		// when
+		kubeconfigContent, expiresAt, err := manager.RenewToken(context.Background(), &instance, "oidc", 600)
+
+		// then
+		require.NoError(t, err)
+		assert.Contains(t, kubeconfigContent, "--oidc-client-id=client-id")
+		assert.False(t, expiresAt.IsZero())
+	})
+
+	t.Run("should remove the RBAC objects", func(t *testing.T) {
+		// when
+		err := manager.Delete(context.Background(), &instance, "oidc")
+
+		// then
+		require.NoError(t, err)
+		_, err = clientset.RbacV1().RoleBindings("team-a").Get(context.Background(), name, mv1.GetOptions{})
+		assert.True(t, apierrors.IsNotFound(err))
+		_, err = clientset.RbacV1().Roles("team-a").Get(context.Background(), name, mv1.GetOptions{})
+		assert.True(t, apierrors.IsNotFound(err))
+	})
+
+	t.Run("should not grant permissions when the runtime has no OIDC config", func(t *testing.T) {
+		// given
+		other := fixture.FixInstance("other-instance-id")
+
+		// when
+		_, _, err := manager.Create(context.Background(), &other, "other", 600, internal.BindingScope{
+			Profile:        internal.BindingProfileClusterAdmin,
+			CredentialType: internal.BindingCredentialTypeOIDC,
+			Subject:        &internal.BindingSubject{Kind: rbacv1.UserKind, Name: "jane@example.com"},
+		})
+
+		// then
+		require.Error(t, err)
+		_, err = clientset.RbacV1().ClusterRoleBindings().Get(context.Background(), BindingName("other"), mv1.GetOptions{})
+		assert.True(t, apierrors.IsNotFound(err))
+	})
+}
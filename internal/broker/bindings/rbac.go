@@ -0,0 +1,206 @@
+package broker
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kyma-project/kyma-environment-broker/internal"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	mv1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+var (
+	clusterAdminRules = []rbacv1.PolicyRule{
+		{
+			Verbs:     []string{"*"},
+			APIGroups: []string{"*"},
+			Resources: []string{"*"},
+		},
+	}
+)
+
+// readOnlyClusterRole is the built-in role granted to the read-only profile, unlike get on all resources it does not allow reading Secrets
+const readOnlyClusterRole = "view"
+
+// createRBAC grants the subjects the permissions of the given scope. Cluster-wide profiles are granted with a ClusterRoleBinding,
+// namespace-scoped profiles with a RoleBinding in every requested namespace.
+func createRBAC(ctx context.Context, clientset kubernetes.Interface, name string, scope internal.BindingScope, subjects []rbacv1.Subject) error {
+	switch scope.Profile {
+	case internal.BindingProfileClusterAdmin, "":
+		if err := createClusterRole(ctx, clientset, name, clusterAdminRules); err != nil {
+			return err
+		}
+		return createClusterRoleBinding(ctx, clientset, name, name, subjects)
+	case internal.BindingProfileReadOnly:
+		return createClusterRoleBinding(ctx, clientset, name, readOnlyClusterRole, subjects)
+	case internal.BindingProfileNamespaceAdmin:
+		for _, namespace := range scope.Namespaces {
+			if err := createRole(ctx, clientset, namespace, name, clusterAdminRules); err != nil {
+				return err
+			}
+			if err := createRoleBinding(ctx, clientset, namespace, name, "Role", name, subjects); err != nil {
+				return err
+			}
+		}
+		return nil
+	case internal.BindingProfileClusterRole:
+		if len(scope.Namespaces) == 0 {
+			return createClusterRoleBinding(ctx, clientset, name, scope.ClusterRole, subjects)
+		}
+		for _, namespace := range scope.Namespaces {
+			if err := createRoleBinding(ctx, clientset, namespace, name, "ClusterRole", scope.ClusterRole, subjects); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported binding profile %q", scope.Profile)
+	}
+}
+
+func createClusterRole(ctx context.Context, clientset kubernetes.Interface, name string, rules []rbacv1.PolicyRule) error {
+	_, err := clientset.RbacV1().ClusterRoles().Create(ctx,
+		&rbacv1.ClusterRole{
+			TypeMeta: mv1.TypeMeta{APIVersion: rbacv1.SchemeGroupVersion.String(), Kind: "ClusterRole"},
+			ObjectMeta: mv1.ObjectMeta{
+				Name:   name,
+				Labels: map[string]string{managedByLabelKey: managedByLabelValue},
+			},
+			Rules: rules,
+		}, mv1.CreateOptions{})
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("while creating a cluster role: %v", err)
+	}
+	return nil
+}
+
+func createClusterRoleBinding(ctx context.Context, clientset kubernetes.Interface, name, clusterRoleName string, subjects []rbacv1.Subject) error {
+	_, err := clientset.RbacV1().ClusterRoleBindings().Create(ctx, &rbacv1.ClusterRoleBinding{
+		TypeMeta: mv1.TypeMeta{APIVersion: rbacv1.SchemeGroupVersion.String(), Kind: "ClusterRoleBinding"},
+		ObjectMeta: mv1.ObjectMeta{
+			Name:   name,
+			Labels: map[string]string{managedByLabelKey: managedByLabelValue},
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "ClusterRole",
+			Name:     clusterRoleName,
+		},
+		Subjects: subjects,
+	}, mv1.CreateOptions{})
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("while creating a cluster role binding: %v", err)
+	}
+	return nil
+}
+
+func createRole(ctx context.Context, clientset kubernetes.Interface, namespace, name string, rules []rbacv1.PolicyRule) error {
+	_, err := clientset.RbacV1().Roles(namespace).Create(ctx,
+		&rbacv1.Role{
+			TypeMeta: mv1.TypeMeta{APIVersion: rbacv1.SchemeGroupVersion.String(), Kind: "Role"},
+			ObjectMeta: mv1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+				Labels:    map[string]string{managedByLabelKey: managedByLabelValue},
+			},
+			Rules: rules,
+		}, mv1.CreateOptions{})
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("while creating a role in the %s namespace: %v", namespace, err)
+	}
+	return nil
+}
+
+func createRoleBinding(ctx context.Context, clientset kubernetes.Interface, namespace, name, roleKind, roleName string, subjects []rbacv1.Subject) error {
+	_, err := clientset.RbacV1().RoleBindings(namespace).Create(ctx, &rbacv1.RoleBinding{
+		TypeMeta: mv1.TypeMeta{APIVersion: rbacv1.SchemeGroupVersion.String(), Kind: "RoleBinding"},
+		ObjectMeta: mv1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    map[string]string{managedByLabelKey: managedByLabelValue},
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     roleKind,
+			Name:     roleName,
+		},
+		Subjects: subjects,
+	}, mv1.CreateOptions{})
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("while creating a role binding in the %s namespace: %v", namespace, err)
+	}
+	return nil
+}
+
+// deleteRBAC removes the RBAC objects of the binding created for any of the profiles
+func deleteRBAC(ctx context.Context, clientset kubernetes.Interface, serviceBindingName string) error {
+	// remove namespaced bindings and roles created for the namespace-scoped profiles
+	err := deleteNamespacedRBAC(ctx, clientset, serviceBindingName)
+	if err != nil {
+		return err
+	}
+
+	// remove a binding
+	err = clientset.RbacV1().ClusterRoleBindings().Delete(ctx, serviceBindingName, mv1.DeleteOptions{})
+
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("while removing a cluster role binding: %v", err)
+	}
+
+	// remove a role
+	err = clientset.RbacV1().ClusterRoles().Delete(ctx, serviceBindingName, mv1.DeleteOptions{})
+
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("while removing a cluster role: %v", err)
+	}
+
+	return nil
+}
+
+// deleteNamespacedRBAC removes the role bindings and roles of the binding from all namespaces. The namespaces are not taken
+// from the binding scope, so the resources are also removed when the binding was stored without the scope.
+func deleteNamespacedRBAC(ctx context.Context, clientset kubernetes.Interface, name string) error {
+	selector := mv1.ListOptions{LabelSelector: fmt.Sprintf("%s=%s", managedByLabelKey, managedByLabelValue)}
+
+	roleBindings, err := clientset.RbacV1().RoleBindings("").List(ctx, selector)
+	if err != nil {
+		return fmt.Errorf("while listing role bindings: %v", err)
+	}
+	for _, roleBinding := range roleBindings.Items {
+		if roleBinding.Name != name {
+			continue
+		}
+		err = clientset.RbacV1().RoleBindings(roleBinding.Namespace).Delete(ctx, name, mv1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("while removing a role binding in the %s namespace: %v", roleBinding.Namespace, err)
+		}
+	}
+
+	roles, err := clientset.RbacV1().Roles("").List(ctx, selector)
+	if err != nil {
+		return fmt.Errorf("while listing roles: %v", err)
+	}
+	for _, role := range roles.Items {
+		if role.Name != name {
+			continue
+		}
+		err = clientset.RbacV1().Roles(role.Namespace).Delete(ctx, name, mv1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("while removing a role in the %s namespace: %v", role.Namespace, err)
+		}
+	}
+	return nil
+}
+
+func serviceAccountSubjects(name string) []rbacv1.Subject {
+	return []rbacv1.Subject{
+		{
+			Kind:      rbacv1.ServiceAccountKind,
+			Namespace: BindingNamespace,
+			Name:      name,
+		},
+	}
+}
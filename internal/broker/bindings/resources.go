@@ -0,0 +1,101 @@
+package broker
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	mv1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// BindingResource is a service account or an RBAC object created by KEB for a binding on the runtime
+type BindingResource struct {
+	Kind      string
+	Namespace string
+	Name      string
+	BindingID string
+	CreatedAt time.Time
+}
+
+// ListBindingResources returns the service accounts and RBAC objects labeled as managed by KEB and named after a binding
+func ListBindingResources(ctx context.Context, clientset kubernetes.Interface) ([]BindingResource, error) {
+	selector := mv1.ListOptions{LabelSelector: fmt.Sprintf("%s=%s", managedByLabelKey, managedByLabelValue)}
+	var resources []BindingResource
+	add := func(kind string, meta mv1.ObjectMeta) {
+		bindingID, found := strings.CutPrefix(meta.Name, BindingName(""))
+		if !found || bindingID == "" {
+			return
+		}
+		resources = append(resources, BindingResource{
+			Kind:      kind,
+			Namespace: meta.Namespace,
+			Name:      meta.Name,
+			BindingID: bindingID,
+			CreatedAt: meta.CreationTimestamp.Time,
+		})
+	}
+
+	serviceAccounts, err := clientset.CoreV1().ServiceAccounts(BindingNamespace).List(ctx, selector)
+	if err != nil {
+		return nil, fmt.Errorf("while listing service accounts: %v", err)
+	}
+	for _, item := range serviceAccounts.Items {
+		add("ServiceAccount", item.ObjectMeta)
+	}
+
+	clusterRoles, err := clientset.RbacV1().ClusterRoles().List(ctx, selector)
+	if err != nil {
+		return nil, fmt.Errorf("while listing cluster roles: %v", err)
+	}
+	for _, item := range clusterRoles.Items {
+		add("ClusterRole", item.ObjectMeta)
+	}
+
+	clusterRoleBindings, err := clientset.RbacV1().ClusterRoleBindings().List(ctx, selector)
+	if err != nil {
+		return nil, fmt.Errorf("while listing cluster role bindings: %v", err)
+	}
+	for _, item := range clusterRoleBindings.Items {
+		add("ClusterRoleBinding", item.ObjectMeta)
+	}
+
+	roles, err := clientset.RbacV1().Roles("").List(ctx, selector)
+	if err != nil {
+		return nil, fmt.Errorf("while listing roles: %v", err)
+	}
+	for _, item := range roles.Items {
+		add("Role", item.ObjectMeta)
+	}
+
+	roleBindings, err := clientset.RbacV1().RoleBindings("").List(ctx, selector)
+	if err != nil {
+		return nil, fmt.Errorf("while listing role bindings: %v", err)
+	}
+	for _, item := range roleBindings.Items {
+		add("RoleBinding", item.ObjectMeta)
+	}
+
+	return resources, nil
+}
+
+// DeleteBindingResources removes the RBAC objects and the service account of the binding from the runtime
+func DeleteBindingResources(ctx context.Context, clientset kubernetes.Interface, bindingID string) error {
+	serviceBindingName := BindingName(bindingID)
+
+	err := deleteRBAC(ctx, clientset, serviceBindingName)
+	if err != nil {
+		return err
+	}
+
+	// remove an account
+	err = clientset.CoreV1().ServiceAccounts(BindingNamespace).Delete(ctx, serviceBindingName, mv1.DeleteOptions{})
+
+	if err != nil && !apierrors.IsNotFound(err) {
+		return fmt.Errorf("while removing a service account: %v", err)
+	}
+
+	return nil
+}
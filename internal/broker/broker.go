@@ -43,6 +43,10 @@ type Config struct {
 	TrialDocsURL         string        `envconfig:"default="`
 	DualStackDocsURL     string        `envconfig:"default="`
 	DefaultRequestRegion string        `envconfig:"default=cf-eu10"`
+	// ExpirationExtensionPeriod is the time added to the expiration of a trial or free instance by one extension
+	ExpirationExtensionPeriod time.Duration `envconfig:"default=168h"`
+	// MaxExpirationExtensions limits how many times the expiration of one instance can be extended
+	MaxExpirationExtensions int `envconfig:"default=1"`
 	// OperationTimeout is used to check on a top-level if any operation didn't exceed the time for processing.
 	// It is used for provisioning and deprovisioning operations.
 	OperationTimeout time.Duration `envconfig:"default=24h"`
@@ -79,6 +83,9 @@ type Config struct {
 	ACLEnabledPlans StringList `envconfig:"default=false"`
 
 	AuditLogAccess bool `envconfig:"default=false"`
+
+	// enables the maintenanceWindow parameter which defers disruptive updates to the window chosen by the user.
+	MaintenanceWindowEnabled bool `envconfig:"default=false"`
 }
 
 type ServicesConfig map[string]Service
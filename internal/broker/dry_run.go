@@ -0,0 +1,284 @@
+package broker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/kyma-project/kyma-environment-broker/common/hyperscaler/rules"
+	"github.com/kyma-project/kyma-environment-broker/internal"
+	error2 "github.com/kyma-project/kyma-environment-broker/internal/error"
+	"github.com/kyma-project/kyma-environment-broker/internal/httputil"
+	"github.com/kyma-project/kyma-environment-broker/internal/middleware"
+
+	"github.com/google/uuid"
+	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+	"github.com/pivotal-cf/brokerapi/v12/domain"
+	"github.com/pivotal-cf/brokerapi/v12/domain/apiresponses"
+)
+
+const dryRunOperationID = "dry-run"
+
+// RuntimeResourceRenderer builds the Runtime resource which is created for the operation
+type RuntimeResourceRenderer interface {
+	RenderRuntimeResource(ctx context.Context, operation internal.Operation, log *slog.Logger) (*imv1.Runtime, error)
+}
+
+// DryRunResult describes what KEB would do for the provisioning or update request
+type DryRunResult struct {
+	Valid             bool                     `json:"valid"`
+	SchemaErrors      []string                 `json:"schemaErrors,omitempty"`
+	ValidationErrors  []string                 `json:"validationErrors,omitempty"`
+	ProviderValues    *internal.ProviderValues `json:"providerValues,omitempty"`
+	HAPRule           string                   `json:"hapRule,omitempty"`
+	ChangedParameters []string                 `json:"changedParameters,omitempty"`
+	RuntimeResource   *imv1.Runtime            `json:"runtimeResource,omitempty"`
+}
+
+// DryRunEndpoint runs the validation and rendering of provisioning and update requests without creating operations
+type DryRunEndpoint struct {
+	provisionEndpoint *ProvisionEndpoint
+	updateEndpoint    *UpdateEndpoint
+	renderer          RuntimeResourceRenderer
+	log               *slog.Logger
+}
+
+func NewDryRun(provisionEndpoint *ProvisionEndpoint, updateEndpoint *UpdateEndpoint, renderer RuntimeResourceRenderer, log *slog.Logger) *DryRunEndpoint {
+	return &DryRunEndpoint{
+		provisionEndpoint: provisionEndpoint,
+		updateEndpoint:    updateEndpoint,
+		renderer:          renderer,
+		log:               log.With("service", "DryRunEndpoint"),
+	}
+}
+
+// AttachRoutes attaches the dry-run routes for each prefix. The router must contain the broker API middlewares.
+func (b *DryRunEndpoint) AttachRoutes(router *httputil.Router, prefixes []string) {
+	for _, prefix := range prefixes {
+		router.HandleFunc(buildPathPattern(http.MethodPut, prefix, "/v2/service_instances/{instance_id}/dry-run"), b.provisionDryRun)
+		router.HandleFunc(buildPathPattern(http.MethodPatch, prefix, "/v2/service_instances/{instance_id}/dry-run"), b.updateDryRun)
+	}
+}
+
+func (b *DryRunEndpoint) provisionDryRun(w http.ResponseWriter, req *http.Request) {
+	var details domain.ProvisionDetails
+	if err := json.NewDecoder(req.Body).Decode(&details); err != nil {
+		httputil.WriteErrorResponse(w, http.StatusBadRequest, fmt.Errorf("while decoding request body: %w", err))
+		return
+	}
+
+	result, err := b.Provision(req.Context(), req.PathValue("instance_id"), details)
+	b.writeResult(w, result, err)
+}
+
+func (b *DryRunEndpoint) updateDryRun(w http.ResponseWriter, req *http.Request) {
+	var details domain.UpdateDetails
+	if err := json.NewDecoder(req.Body).Decode(&details); err != nil {
+		httputil.WriteErrorResponse(w, http.StatusBadRequest, fmt.Errorf("while decoding request body: %w", err))
+		return
+	}
+
+	result, err := b.Update(req.Context(), req.PathValue("instance_id"), details)
+	b.writeResult(w, result, err)
+}
+
+// Provision runs the provisioning validation and renders the Runtime resource without inserting the operation and the instance
+//
+//	PUT /v2/service_instances/{instance_id}/dry-run
+func (b *DryRunEndpoint) Provision(ctx context.Context, instanceID string, details domain.ProvisionDetails) (DryRunResult, error) {
+	endpoint := b.provisionEndpoint
+	logger := b.log.With("instanceID", instanceID, "planID", details.PlanID)
+	logger.Info("Provisioning dry run requested")
+
+	region, found := middleware.RegionFromContext(ctx)
+	if !found {
+		return DryRunResult{}, apiresponses.NewFailureResponse(errors.New("No region specified in request."), http.StatusInternalServerError, "provisioning dry run")
+	}
+	platformProvider, found := middleware.ProviderFromContext(ctx)
+	if !found {
+		return DryRunResult{}, apiresponses.NewFailureResponse(errors.New("No provider specified in request."), http.StatusInternalServerError, "provisioning dry run")
+	}
+	if len(details.RawParameters) > MaxRawParametersSize {
+		return DryRunResult{}, apiresponses.NewFailureResponse(fmt.Errorf("request parameters too large"), http.StatusBadRequest, "request parameters too large")
+	}
+
+	parameters, err := endpoint.extractInputParameters(details)
+	if err != nil {
+		return DryRunResult{}, apiresponses.NewFailureResponse(err, http.StatusBadRequest, "while extracting input parameters")
+	}
+	ersContext, err := endpoint.extractERSContext(details)
+	if err != nil {
+		return DryRunResult{}, apiresponses.NewFailureResponse(err, http.StatusBadRequest, "while extracting context")
+	}
+	provisioningParameters := newProvisioningParameters(details, ersContext, parameters, region, platformProvider)
+
+	result := DryRunResult{}
+	schemaErrors, err := endpoint.schemaErrors(ctx, details, platformProvider)
+	if err != nil {
+		return DryRunResult{}, apiresponses.NewFailureResponse(err, http.StatusBadRequest, "while validating input parameters")
+	}
+	if len(schemaErrors) > 0 {
+		result.SchemaErrors = schemaErrors
+		return result, nil
+	}
+
+	providerValues, err := endpoint.valuesProvider.ValuesForPlanAndParameters(provisioningParameters)
+	if err != nil {
+		result.ValidationErrors = append(result.ValidationErrors, fmt.Sprintf("unable to provide default values: %s", err))
+		return result, nil
+	}
+	result.ProviderValues = &providerValues
+
+	if err := endpoint.validate(ctx, details, provisioningParameters, logger); err != nil {
+		if error2.IsTemporaryError(err) {
+			return DryRunResult{}, apiresponses.NewFailureResponse(fmt.Errorf("internal error"), http.StatusInternalServerError, err.Error())
+		}
+		result.ValidationErrors = append(result.ValidationErrors, err.Error())
+		return result, nil
+	}
+
+	operation, err := endpoint.newProvisioningOperation(dryRunOperationID, instanceID, provisioningParameters, providerValues, details)
+	if err != nil {
+		return DryRunResult{}, fmt.Errorf("cannot create new operation: %w", err)
+	}
+	operation.RuntimeID = uuid.New().String()
+
+	return b.render(ctx, result, operation.Operation, endpoint.rulesService, logger), nil
+}
+
+// Update runs the update validation and renders the Runtime resource with the updated parameters without storing the changes
+//
+//	PATCH /v2/service_instances/{instance_id}/dry-run
+func (b *DryRunEndpoint) Update(ctx context.Context, instanceID string, details domain.UpdateDetails) (DryRunResult, error) {
+	endpoint := b.updateEndpoint
+	logger := b.log.With("instanceID", instanceID, "planID", details.PlanID)
+	logger.Info("Update dry run requested")
+
+	if len(details.RawParameters) > MaxRawParametersSize {
+		return DryRunResult{}, apiresponses.NewFailureResponse(fmt.Errorf("request parameters too large"), http.StatusBadRequest, "request parameters too large")
+	}
+
+	instance, err := endpoint.instanceStorage.GetByID(instanceID)
+	if err = endpoint.handleGetInstanceError(err, logger, instanceID); err != nil {
+		return DryRunResult{}, err
+	}
+	if instance.IsExpired() {
+		return DryRunResult{}, apiresponses.NewFailureResponse(fmt.Errorf("cannot update an expired instance"), http.StatusBadRequest, "")
+	}
+	lastProvisioningOperation, err := endpoint.checkProvisioningState(instance, logger)
+	if err != nil {
+		return DryRunResult{}, err
+	}
+
+	var ersContext internal.ERSContext
+	if len(details.RawContext) > 0 {
+		if err := json.Unmarshal(details.RawContext, &ersContext); err != nil {
+			return DryRunResult{}, apiresponses.NewFailureResponse(err, http.StatusBadRequest, "unable to unmarshal context")
+		}
+	}
+
+	result := DryRunResult{}
+	schemaErrors, err := endpoint.schemaErrors(details, instance)
+	if err != nil {
+		return DryRunResult{}, apiresponses.NewFailureResponse(err, http.StatusBadRequest, "while validating update parameters")
+	}
+	if len(schemaErrors) > 0 {
+		result.SchemaErrors = schemaErrors
+		return result, nil
+	}
+
+	params, err := endpoint.unmarshalParams(details, logger)
+	if err != nil {
+		return DryRunResult{}, apiresponses.NewFailureResponse(err, http.StatusBadRequest, err.Error())
+	}
+	endpoint.ZeroFieldsForTrialPlan(details, &params)
+
+	providerValues, err := endpoint.valuesProvider.ValuesForPlanAndParameters(instance.Parameters)
+	if err != nil {
+		result.ValidationErrors = append(result.ValidationErrors, fmt.Sprintf("unable to provide default values: %s", err))
+		return result, nil
+	}
+	result.ProviderValues = &providerValues
+
+	// the instance is modified only in memory, the copy keeps the stored state
+	previousInstance := *instance
+	operation := internal.NewUpdateOperation(dryRunOperationID, instance, params)
+	operation.ProviderValues = &providerValues
+	operation.RawParameters = details.RawParameters
+
+	if err := endpoint.validateGvisorAccess(params, instance.GlobalAccountID); err != nil {
+		result.ValidationErrors = append(result.ValidationErrors, err.Error())
+		return result, nil
+	}
+	if err := endpoint.validateUpdateParameters(ctx, &previousInstance, instance, operation, params, providerValues, details, ersContext, logger); err != nil {
+		if error2.IsTemporaryError(err) {
+			return DryRunResult{}, apiresponses.NewFailureResponse(fmt.Errorf("internal error"), http.StatusInternalServerError, err.Error())
+		}
+		result.ValidationErrors = append(result.ValidationErrors, err.Error())
+		return result, nil
+	}
+	changedParameters, err := endpoint.updateInstanceAndOperationParameters(ctx, instance, &params, &operation, details, ersContext, logger)
+	if err != nil {
+		result.ValidationErrors = append(result.ValidationErrors, err.Error())
+		return result, nil
+	}
+	result.ChangedParameters = changedParameters
+
+	// the Runtime resource is rendered from the provisioning data of the instance combined with the updated parameters
+	operation.ProvisioningParameters = instance.Parameters
+	operation.ShootName = lastProvisioningOperation.ShootName
+	operation.ShootDomain = lastProvisioningOperation.ShootDomain
+	operation.KymaResourceNamespace = lastProvisioningOperation.KymaResourceNamespace
+
+	return b.render(ctx, result, operation, endpoint.rulesService, logger), nil
+}
+
+// render completes the result of the successful validation with the matched HAP rule and the Runtime resource
+func (b *DryRunEndpoint) render(ctx context.Context, result DryRunResult, operation internal.Operation, rulesService *rules.RulesService, logger *slog.Logger) DryRunResult {
+	result.Valid = true
+
+	if rulesService != nil {
+		attr := &rules.ProvisioningAttributes{
+			Plan:              AvailablePlans.GetPlanNameOrEmpty(PlanIDType(operation.ProvisioningParameters.PlanID)),
+			PlatformRegion:    operation.ProvisioningParameters.PlatformRegion,
+			HyperscalerRegion: operation.ProviderValues.Region,
+			Hyperscaler:       operation.ProviderValues.ProviderType,
+		}
+		matchedRule, found := rulesService.MatchProvisioningAttributesWithValidRuleset(attr)
+		if found {
+			result.HAPRule = matchedRule.Rule()
+		} else {
+			result.Valid = false
+			result.ValidationErrors = append(result.ValidationErrors, fmt.Sprintf("no matching rule for provisioning attributes %q", attr))
+		}
+	}
+
+	if b.renderer != nil {
+		runtimeCR, err := b.renderer.RenderRuntimeResource(ctx, operation, logger)
+		if err != nil {
+			result.Valid = false
+			result.ValidationErrors = append(result.ValidationErrors, fmt.Sprintf("while rendering Runtime resource: %s", err))
+			return result
+		}
+		result.RuntimeResource = runtimeCR
+	}
+
+	return result
+}
+
+func (b *DryRunEndpoint) writeResult(w http.ResponseWriter, result DryRunResult, err error) {
+	var failureResponse *apiresponses.FailureResponse
+	switch {
+	case errors.As(err, &failureResponse):
+		b.log.Info(fmt.Sprintf("dry run rejected: %s", err))
+		httputil.WriteErrorResponse(w, failureResponse.ValidatedStatusCode(b.log), err)
+	case err != nil:
+		b.log.Error(fmt.Sprintf("dry run failed: %s", err))
+		httputil.WriteErrorResponse(w, http.StatusInternalServerError, err)
+	default:
+		httputil.WriteResponse(w, http.StatusOK, result)
+	}
+}
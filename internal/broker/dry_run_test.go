@@ -0,0 +1,237 @@
+package broker_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/kyma-project/kyma-environment-broker/internal"
+	"github.com/kyma-project/kyma-environment-broker/internal/blocklist"
+	"github.com/kyma-project/kyma-environment-broker/internal/broker"
+	"github.com/kyma-project/kyma-environment-broker/internal/broker/automock"
+	"github.com/kyma-project/kyma-environment-broker/internal/httputil"
+	kcMock "github.com/kyma-project/kyma-environment-broker/internal/kubeconfig/automock"
+	"github.com/kyma-project/kyma-environment-broker/internal/middleware"
+	"github.com/kyma-project/kyma-environment-broker/internal/ptr"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage/dberr"
+
+	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
+	"github.com/pivotal-cf/brokerapi/v12/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDryRun_Provision(t *testing.T) {
+	// given
+	memoryStorage := storage.NewMemoryStorage()
+	queue := &automock.Queue{}
+	renderer := &fakeRuntimeResourceRenderer{}
+	dryRun := broker.NewDryRun(fixDryRunProvisionEndpoint(t, memoryStorage, queue), nil, renderer, fixLogger())
+
+	t.Run("should return provider values and rendered Runtime resource without creating an operation", func(t *testing.T) {
+		// when
+		result, err := dryRun.Provision(fixRequestContext(t, "req-region"), instanceID, domain.ProvisionDetails{
+			ServiceID:     serviceID,
+			PlanID:        planID,
+			RawParameters: json.RawMessage(fmt.Sprintf(`{"name": "%s", "region": "%s"}`, clusterName, clusterRegion)),
+			RawContext:    json.RawMessage(fmt.Sprintf(`{"globalaccount_id": "%s", "subaccount_id": "%s", "user_id": "%s"}`, globalAccountID, subAccountID, userID)),
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.True(t, result.Valid)
+		assert.Empty(t, result.SchemaErrors)
+		assert.Empty(t, result.ValidationErrors)
+		require.NotNil(t, result.ProviderValues)
+		assert.Equal(t, clusterRegion, result.ProviderValues.Region)
+		require.NotNil(t, result.RuntimeResource)
+		assert.Equal(t, clusterName, renderer.operation.ProvisioningParameters.Parameters.Name)
+		assert.Equal(t, "req-region", renderer.operation.ProvisioningParameters.PlatformRegion)
+		assert.NotEmpty(t, renderer.operation.ShootName)
+
+		_, err = memoryStorage.Operations().GetProvisioningOperationByInstanceID(instanceID)
+		assert.True(t, dberr.IsNotFound(err))
+		_, err = memoryStorage.Instances().GetByID(instanceID)
+		assert.True(t, dberr.IsNotFound(err))
+		queue.AssertNotCalled(t, "Add")
+	})
+
+	t.Run("should return JSON schema errors", func(t *testing.T) {
+		// when
+		result, err := dryRun.Provision(fixRequestContext(t, "req-region"), instanceID, domain.ProvisionDetails{
+			ServiceID:     serviceID,
+			PlanID:        planID,
+			RawParameters: json.RawMessage(fmt.Sprintf(`{"name": "%s", "region": "%s", "autoScalerMin": 1}`, clusterName, clusterRegion)),
+			RawContext:    json.RawMessage(fmt.Sprintf(`{"globalaccount_id": "%s", "subaccount_id": "%s", "user_id": "%s"}`, globalAccountID, subAccountID, userID)),
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.False(t, result.Valid)
+		assert.NotEmpty(t, result.SchemaErrors)
+		assert.Nil(t, result.RuntimeResource)
+	})
+
+	t.Run("should return validation errors", func(t *testing.T) {
+		// when
+		result, err := dryRun.Provision(fixRequestContext(t, "req-region"), instanceID, domain.ProvisionDetails{
+			ServiceID:     serviceID,
+			PlanID:        planID,
+			RawParameters: json.RawMessage(fmt.Sprintf(`{"name": "%s", "region": "%s", "autoScalerMin": 5, "autoScalerMax": 4}`, clusterName, clusterRegion)),
+			RawContext:    json.RawMessage(fmt.Sprintf(`{"globalaccount_id": "%s", "subaccount_id": "%s", "user_id": "%s"}`, globalAccountID, subAccountID, userID)),
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.False(t, result.Valid)
+		require.Len(t, result.ValidationErrors, 1)
+		assert.Contains(t, result.ValidationErrors[0], "AutoScalerMax 4 should be larger than AutoScalerMin 5")
+		assert.Nil(t, result.RuntimeResource)
+	})
+}
+
+func TestDryRun_Update(t *testing.T) {
+	// given
+	instance := internal.Instance{
+		InstanceID:    instanceID,
+		ServicePlanID: broker.AWSPlanID,
+		Parameters: internal.ProvisioningParameters{
+			PlanID: broker.AWSPlanID,
+			ErsContext: internal.ERSContext{
+				Active: ptr.Bool(true),
+			},
+		},
+	}
+	st := storage.NewMemoryStorage()
+	require.NoError(t, st.Instances().Insert(instance))
+	provisioning := fixProvisioningOperation("01")
+	provisioning.ProviderValues = &internal.ProviderValues{
+		ProviderType: "aws",
+	}
+	require.NoError(t, st.Operations().InsertProvisioningOperation(provisioning))
+
+	q := &automock.Queue{}
+	renderer := &fakeRuntimeResourceRenderer{}
+	svc := broker.NewUpdate(broker.Config{}, st, &handler{}, true, false, true, q, broker.PlansConfig{},
+		fixValueProvider(t), fixLogger(), dashboardConfig, &kcMock.KcBuilder{},
+		fakeKcpK8sClient, newProviderSpec(t), newPlanSpec(t), imConfigFixture, newSchemaService(t), nil, nil, nil, nil, nil, nil, blocklist.OperationBlocklist{})
+	dryRun := broker.NewDryRun(nil, svc, renderer, fixLogger())
+
+	t.Run("should return changed parameters without storing them", func(t *testing.T) {
+		// when
+		result, err := dryRun.Update(context.Background(), instanceID, domain.UpdateDetails{
+			PlanID:        broker.AWSPlanID,
+			RawParameters: json.RawMessage(`{"autoScalerMin": 4, "autoScalerMax": 6}`),
+			RawContext:    json.RawMessage(`{"active":true}`),
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.True(t, result.Valid)
+		assert.Equal(t, []string{"Auto Scaler parameters"}, result.ChangedParameters)
+		require.NotNil(t, result.RuntimeResource)
+		assert.Equal(t, 4, *renderer.operation.ProvisioningParameters.Parameters.AutoScalerMin)
+		assert.Equal(t, provisioning.ShootName, renderer.operation.ShootName)
+
+		stored, err := st.Instances().GetByID(instanceID)
+		require.NoError(t, err)
+		assert.Nil(t, stored.Parameters.Parameters.AutoScalerMin)
+		operations, err := st.Operations().ListOperationsByInstanceID(instanceID)
+		require.NoError(t, err)
+		assert.Len(t, operations, 1)
+		q.AssertNotCalled(t, "Add")
+	})
+
+	t.Run("should return validation errors", func(t *testing.T) {
+		// when
+		result, err := dryRun.Update(context.Background(), instanceID, domain.UpdateDetails{
+			PlanID:        broker.AWSPlanID,
+			RawParameters: json.RawMessage(`{"autoScalerMin": 4, "autoScalerMax": 3}`),
+			RawContext:    json.RawMessage(`{"active":true}`),
+		})
+
+		// then
+		require.NoError(t, err)
+		assert.False(t, result.Valid)
+		require.Len(t, result.ValidationErrors, 1)
+		assert.Contains(t, result.ValidationErrors[0], "AutoScalerMax 3 should be larger than AutoScalerMin 4")
+	})
+
+	t.Run("should return 404 Not Found for missing instance", func(t *testing.T) {
+		// given
+		router := httputil.NewRouter()
+		dryRun.AttachRoutes(router, []string{""})
+		req := httptest.NewRequest(http.MethodPatch, "/v2/service_instances/not-existing/dry-run", bytes.NewBufferString(`{"plan_id": "plan"}`))
+		w := httptest.NewRecorder()
+
+		// when
+		router.ServeHTTP(w, req)
+
+		// then
+		assert.Equal(t, http.StatusNotFound, w.Result().StatusCode)
+	})
+}
+
+func TestDryRun_ProvisionRoute(t *testing.T) {
+	// given
+	memoryStorage := storage.NewMemoryStorage()
+	dryRun := broker.NewDryRun(fixDryRunProvisionEndpoint(t, memoryStorage, &automock.Queue{}), nil, &fakeRuntimeResourceRenderer{}, fixLogger())
+	router := httputil.NewRouter()
+	router.Use(middleware.AddRegionToContext("req-region"))
+	router.Use(middleware.AddProviderToContext())
+	dryRun.AttachRoutes(router, []string{"/{region}"})
+
+	body := fmt.Sprintf(`{"service_id": "%s", "plan_id": "%s", "parameters": {"name": "%s", "region": "%s"}, "context": {"globalaccount_id": "%s", "subaccount_id": "%s", "user_id": "%s"}}`,
+		serviceID, planID, clusterName, clusterRegion, globalAccountID, subAccountID, userID)
+	req := httptest.NewRequest(http.MethodPut, fmt.Sprintf("/cf-eu10/v2/service_instances/%s/dry-run", instanceID), bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+
+	// when
+	router.ServeHTTP(w, req)
+
+	// then
+	require.Equal(t, http.StatusOK, w.Result().StatusCode)
+	var result broker.DryRunResult
+	require.NoError(t, json.NewDecoder(w.Result().Body).Decode(&result))
+	assert.True(t, result.Valid)
+	assert.NotNil(t, result.RuntimeResource)
+	_, err := memoryStorage.Instances().GetByID(instanceID)
+	assert.True(t, dberr.IsNotFound(err))
+}
+
+func fixDryRunProvisionEndpoint(t *testing.T, st storage.BrokerStorage, queue broker.Queue) *broker.ProvisionEndpoint {
+	return broker.NewFakeProvisionEndpointBuilder().
+		WithConfig(broker.Config{
+			EnablePlans:          []string{"gcp", "azure"},
+			URL:                  brokerURL,
+			OnlySingleTrialPerGA: true}).
+		WithGardenerConfig(fixGardenerConfig()).
+		WithInfrastructureManager(imConfigFixture).
+		WithStorage(st).
+		WithQueue(queue).
+		WithLogger(fixLogger()).
+		WithDashboardConfig(dashboardConfig).
+		WithKubeconfigBuilder(&kcMock.KcBuilder{}).
+		WithSchemaService(newSchemaService(t)).
+		WithConfigurationProvider(newProviderSpec(t)).
+		WithValuesProvider(fixValueProvider(t)).
+		Build()
+}
+
+type fakeRuntimeResourceRenderer struct {
+	operation internal.Operation
+}
+
+func (f *fakeRuntimeResourceRenderer) RenderRuntimeResource(_ context.Context, operation internal.Operation, _ *slog.Logger) (*imv1.Runtime, error) {
+	f.operation = operation
+	runtime := &imv1.Runtime{}
+	runtime.Name = operation.RuntimeID
+	runtime.Spec.Shoot.Name = operation.ShootName
+	return runtime, nil
+}
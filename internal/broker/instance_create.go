@@ -72,7 +72,7 @@ type (
 	}
 
 	QuotaClient interface {
-		GetQuota(subAccountID, planName string) (int, error)
+		GetQuota(ctx context.Context, subAccountID, planName string) (int, error)
 	}
 )
 
@@ -102,6 +102,7 @@ type ProvisionEndpoint struct {
 	providerSpec           ConfigurationProvider
 	planSpec               *configuration.PlanSpecifications
 	quotaClient            QuotaClient
+	quotaReservations      storage.QuotaReservations
 	quotaWhitelist         whitelist.Set
 	rulesService           *rules.RulesService
 	gardenerClient         *gardener.Client
@@ -170,6 +171,7 @@ func NewProvision(brokerConfig Config,
 		schemaService:           schemaService,
 		providerConfigProvider:  providerConfigProvider,
 		quotaClient:             quotaClient,
+		quotaReservations:       db.QuotaReservations(),
 		quotaWhitelist:          quotaWhitelist,
 		rulesService:            rulesService,
 		gardenerClient:          gardenerClient,
@@ -214,20 +216,7 @@ func (b *ProvisionEndpoint) Provision(ctx context.Context, instanceID string, de
 	if b.config.MonitorAdditionalProperties {
 		b.monitorAdditionalProperties(instanceID, ersContext, details.RawParameters)
 	}
-	provisioningParameters := internal.ProvisioningParameters{
-		PlanID:           details.PlanID,
-		ServiceID:        details.ServiceID,
-		ErsContext:       ersContext,
-		Parameters:       parameters,
-		PlatformRegion:   region,
-		PlatformProvider: platformProvider,
-	}
-	// TODO: remove once we implemented proper filtering of parameters - removing parameters that are not supported by the plan
-	if details.PlanID == TrialPlanID {
-		provisioningParameters.Parameters.MachineType = nil
-		provisioningParameters.Parameters.AutoScalerMin = nil
-		provisioningParameters.Parameters.AutoScalerMax = nil
-	}
+	provisioningParameters := newProvisioningParameters(details, ersContext, parameters, region, platformProvider)
 	providerValues, err := b.valuesProvider.ValuesForPlanAndParameters(provisioningParameters)
 	if err != nil {
 		errMsg := fmt.Sprintf("unable to provide default values for instance %s: %s", instanceID, err)
@@ -259,29 +248,26 @@ func (b *ProvisionEndpoint) Provision(ctx context.Context, instanceID string, de
 		return b.handleExistingOperation(existingOperation, provisioningParameters)
 	}
 
-	shootName := gardener.CreateShootName()
-	shootDomainSuffix := strings.Trim(b.shootDomain, ".")
-
 	dashboardURL := b.createDashboardURL(details.PlanID, instanceID)
 
 	// create and save new operation
-	operation, err := internal.NewProvisioningOperationWithID(operationID, instanceID, provisioningParameters)
+	operation, err := b.newProvisioningOperation(operationID, instanceID, provisioningParameters, providerValues, details)
 	if err != nil {
 		logger.Error(fmt.Sprintf("cannot create new operation: %s", err))
 		return domain.ProvisionedServiceSpec{}, fmt.Errorf("cannot create new operation")
 	}
-
-	operation.ProviderValues = &providerValues
-	operation.ShootName = shootName
-	operation.ShootDomain = fmt.Sprintf("%s.%s", shootName, shootDomainSuffix)
-	operation.ShootDNSProviders = b.shootDnsProviders
-	operation.DashboardURL = dashboardURL
-	operation.RawParameters = details.RawParameters
 	logger.Info(fmt.Sprintf("Runtime ShootDomain: %s", operation.ShootDomain))
 
+	quotaReserved, err := b.reserveQuota(ctx, instanceID, operationID, provisioningParameters)
+	if err != nil {
+		logger.Warn(fmt.Sprintf("unable to reserve quota: %s", err))
+		return domain.ProvisionedServiceSpec{}, err
+	}
+
 	err = b.operationsStorage.InsertOperation(operation.Operation)
 	if err != nil {
 		logger.Error(fmt.Sprintf("cannot save operation: %s", err))
+		b.releaseQuota(quotaReserved, operationID, logger)
 		return domain.ProvisionedServiceSpec{}, fmt.Errorf("cannot save operation")
 	}
 
@@ -300,6 +286,7 @@ func (b *ProvisionEndpoint) Provision(ctx context.Context, instanceID string, de
 	err = b.instanceStorage.Insert(instance)
 	if err != nil {
 		logger.Error(fmt.Sprintf("cannot save instance in storage: %s", err))
+		b.releaseQuota(quotaReserved, operationID, logger)
 		return domain.ProvisionedServiceSpec{}, fmt.Errorf("cannot save instance")
 	}
 
@@ -324,6 +311,69 @@ func (b *ProvisionEndpoint) Provision(ctx context.Context, instanceID string, de
 	}, nil
 }
 
+// reserveQuota returns true if the quota was reserved for the instance, the quota is not reserved when the quota limit is not checked
+func (b *ProvisionEndpoint) reserveQuota(ctx context.Context, instanceID, operationID string, provisioningParameters internal.ProvisioningParameters) (bool, error) {
+	subAccountID := provisioningParameters.ErsContext.SubAccountID
+	if !b.config.CheckQuotaLimit || whitelist.IsWhitelisted(subAccountID, b.quotaWhitelist) {
+		return false, nil
+	}
+	err := reserveQuota(ctx, b.quotaReservations, b.quotaClient, internal.QuotaReservation{
+		InstanceID:   instanceID,
+		SubAccountID: subAccountID,
+		PlanID:       provisioningParameters.PlanID,
+		OperationID:  operationID,
+	}, false)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (b *ProvisionEndpoint) releaseQuota(reserved bool, operationID string, logger *slog.Logger) {
+	if !reserved {
+		return
+	}
+	if err := b.quotaReservations.Release(operationID); err != nil {
+		logger.Error(fmt.Sprintf("unable to release the quota reservation: %s", err))
+	}
+}
+
+func newProvisioningParameters(details domain.ProvisionDetails, ersContext internal.ERSContext, parameters pkg.ProvisioningParametersDTO, region string, platformProvider pkg.CloudProvider) internal.ProvisioningParameters {
+	provisioningParameters := internal.ProvisioningParameters{
+		PlanID:           details.PlanID,
+		ServiceID:        details.ServiceID,
+		ErsContext:       ersContext,
+		Parameters:       parameters,
+		PlatformRegion:   region,
+		PlatformProvider: platformProvider,
+	}
+	// TODO: remove once we implemented proper filtering of parameters - removing parameters that are not supported by the plan
+	if details.PlanID == TrialPlanID {
+		provisioningParameters.Parameters.MachineType = nil
+		provisioningParameters.Parameters.AutoScalerMin = nil
+		provisioningParameters.Parameters.AutoScalerMax = nil
+	}
+	return provisioningParameters
+}
+
+func (b *ProvisionEndpoint) newProvisioningOperation(operationID, instanceID string, provisioningParameters internal.ProvisioningParameters, providerValues internal.ProviderValues, details domain.ProvisionDetails) (internal.ProvisioningOperation, error) {
+	operation, err := internal.NewProvisioningOperationWithID(operationID, instanceID, provisioningParameters)
+	if err != nil {
+		return internal.ProvisioningOperation{}, err
+	}
+
+	shootName := gardener.CreateShootName()
+	shootDomainSuffix := strings.Trim(b.shootDomain, ".")
+
+	operation.ProviderValues = &providerValues
+	operation.ShootName = shootName
+	operation.ShootDomain = fmt.Sprintf("%s.%s", shootName, shootDomainSuffix)
+	operation.ShootDNSProviders = b.shootDnsProviders
+	operation.DashboardURL = b.createDashboardURL(details.PlanID, instanceID)
+	operation.RawParameters = details.RawParameters
+	return operation, nil
+}
+
 func logParametersWithMaskedKubeconfig(parameters pkg.ProvisioningParametersDTO, logger *slog.Logger) {
 	parameters.Kubeconfig = maskedKubeconfig
 	logger.Info(fmt.Sprintf("Runtime parameters: %+v", parameters))
@@ -369,7 +419,7 @@ func (b *ProvisionEndpoint) validate(ctx context.Context, details domain.Provisi
 	}
 
 	if b.config.CheckQuotaLimit && whitelist.IsNotWhitelisted(provisioningParameters.ErsContext.SubAccountID, b.quotaWhitelist) {
-		if err := validateQuotaLimit(b.instanceStorage, b.quotaClient, provisioningParameters.ErsContext.SubAccountID, provisioningParameters.PlanID, false); err != nil {
+		if err := validateQuotaLimit(ctx, b.quotaReservations, b.quotaClient, provisioningParameters.ErsContext.SubAccountID, provisioningParameters.PlanID, false); err != nil {
 			return err
 		}
 	}
@@ -424,18 +474,16 @@ func (b *ProvisionEndpoint) validate(ctx context.Context, details domain.Provisi
 		return apiresponses.NewFailureResponse(err, http.StatusBadRequest, err.Error())
 	}
 
-	planValidator, err := b.validator(&details, provisioningParameters.PlatformProvider, ctx)
-	if err != nil {
-		return fmt.Errorf("while creating plan validator: %w", err)
+	if err := validateMaintenanceWindow(b.config, provisioningParameters.PlanID, parameters.MaintenanceWindow); err != nil {
+		return apiresponses.NewFailureResponse(err, http.StatusBadRequest, err.Error())
 	}
 
-	var rawParameters any
-	if err = json.Unmarshal(details.RawParameters, &rawParameters); err != nil {
-		return fmt.Errorf("while unmarshaling raw parameters: %w", err)
+	schemaErrors, err := b.schemaErrors(ctx, details, provisioningParameters.PlatformProvider)
+	if err != nil {
+		return err
 	}
-
-	if err = planValidator.Validate(rawParameters); err != nil {
-		return fmt.Errorf("while validating input parameters: %s", validator.FormatError(err))
+	if len(schemaErrors) > 0 {
+		return fmt.Errorf("while validating input parameters: %s", strings.Join(schemaErrors, ", "))
 	}
 
 	// EU Access
@@ -925,6 +973,24 @@ func (b *ProvisionEndpoint) validator(details *domain.ProvisionDetails, provider
 	return validator.NewFromSchema(plan.Schemas.Instance.Create.Parameters)
 }
 
+// schemaErrors validates the raw parameters against the JSON schema of the plan and returns the reported violations
+func (b *ProvisionEndpoint) schemaErrors(ctx context.Context, details domain.ProvisionDetails, provider pkg.CloudProvider) ([]string, error) {
+	planValidator, err := b.validator(&details, provider, ctx)
+	if err != nil {
+		return nil, fmt.Errorf("while creating plan validator: %w", err)
+	}
+
+	var rawParameters any
+	if err = json.Unmarshal(details.RawParameters, &rawParameters); err != nil {
+		return nil, fmt.Errorf("while unmarshaling raw parameters: %w", err)
+	}
+
+	if err = planValidator.Validate(rawParameters); err != nil {
+		return validator.ErrorMessages(err), nil
+	}
+	return nil, nil
+}
+
 func (b *ProvisionEndpoint) createDashboardURL(planID, instanceID string) string {
 	return fmt.Sprintf("%s/?kubeconfigID=%s", b.dashboardConfig.LandscapeURL, instanceID)
 }
@@ -1086,15 +1152,11 @@ func validateOverlapping(n1 net.IPNet, n2 net.IPNet) error {
 	return nil
 }
 
-func validateQuotaLimit(instanceStorage storage.Instances, quotaClient QuotaClient, subAccountID, planID string, update bool) error {
-	instanceFilter := dbmodel.InstanceFilter{
-		SubAccountIDs: []string{subAccountID},
-		PlanIDs:       []string{planID},
-	}
-	_, _, usedQuota, err := instanceStorage.List(instanceFilter)
+func validateQuotaLimit(ctx context.Context, quotaReservations storage.QuotaReservations, quotaClient QuotaClient, subAccountID, planID string, update bool) error {
+	usedQuota, err := quotaReservations.CountUsed(subAccountID, planID)
 	if err != nil {
 		return fmt.Errorf(
-			"while listing instances for subaccount %s and plan ID %s: %w",
+			"while counting used quota for subaccount %s and plan ID %s: %w",
 			subAccountID,
 			planID,
 			err,
@@ -1103,19 +1165,53 @@ func validateQuotaLimit(instanceStorage storage.Instances, quotaClient QuotaClie
 
 	if usedQuota > 0 || update {
 		planName := AvailablePlans.GetPlanNameOrEmpty(PlanIDType(planID))
-		assignedQuota, err := quotaClient.GetQuota(subAccountID, planName)
+		assignedQuota, err := quotaClient.GetQuota(ctx, subAccountID, planName)
 		if err != nil {
 			return fmt.Errorf("Failed to get assigned quota for plan %s: %w.", planName, err)
 		}
 
 		if usedQuota >= assignedQuota {
-			return fmt.Errorf("Kyma instances quota exceeded for plan %s. assignedQuota: %d, remainingQuota: 0. Contact your administrator.", planName, assignedQuota)
+			return quotaExceededError(planName, assignedQuota)
 		}
 	}
 
 	return nil
 }
 
+// reserveQuota reserves the quota for the instance, so parallel requests cannot over-commit the entitlement. The first instance of the plan
+// in the subaccount is reserved without asking the entitlements service, the same as validateQuotaLimit does.
+func reserveQuota(ctx context.Context, quotaReservations storage.QuotaReservations, quotaClient QuotaClient, reservation internal.QuotaReservation, update bool) error {
+	if !update {
+		reserved, err := quotaReservations.Reserve(reservation, 1)
+		if err != nil {
+			return fmt.Errorf("while reserving quota for instance %s: %w", reservation.InstanceID, err)
+		}
+		if reserved {
+			return nil
+		}
+	}
+
+	planName := AvailablePlans.GetPlanNameOrEmpty(PlanIDType(reservation.PlanID))
+	assignedQuota, err := quotaClient.GetQuota(ctx, reservation.SubAccountID, planName)
+	if err != nil {
+		err = fmt.Errorf("Failed to get assigned quota for plan %s: %w.", planName, err)
+		return apiresponses.NewFailureResponse(err, http.StatusBadRequest, err.Error())
+	}
+	reserved, err := quotaReservations.Reserve(reservation, assignedQuota)
+	if err != nil {
+		return fmt.Errorf("while reserving quota for instance %s: %w", reservation.InstanceID, err)
+	}
+	if !reserved {
+		err := quotaExceededError(planName, assignedQuota)
+		return apiresponses.NewFailureResponse(err, http.StatusBadRequest, err.Error())
+	}
+	return nil
+}
+
+func quotaExceededError(planName string, assignedQuota int) error {
+	return fmt.Errorf("Kyma instances quota exceeded for plan %s. assignedQuota: %d, remainingQuota: 0. Contact your administrator.", planName, assignedQuota)
+}
+
 func newHyperscalerClient(
 	ctx context.Context,
 	log *slog.Logger,
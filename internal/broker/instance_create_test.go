@@ -2594,7 +2594,7 @@ func TestQuotaLimitCheck(t *testing.T) {
 		assert.NoError(t, err)
 
 		quotaClient := &automock.QuotaClient{}
-		quotaClient.On("GetQuota", subAccountID, broker.AzurePlanName).Return(1, nil)
+		quotaClient.On("GetQuota", mock.Anything, subAccountID, broker.AzurePlanName).Return(1, nil)
 
 		provisionEndpoint := broker.NewFakeProvisionEndpointBuilder().
 			WithConfig(broker.Config{
@@ -2640,7 +2640,7 @@ func TestQuotaLimitCheck(t *testing.T) {
 		assert.NoError(t, err)
 
 		quotaClient := &automock.QuotaClient{}
-		quotaClient.On("GetQuota", subAccountID, broker.AzurePlanName).Return(2, nil)
+		quotaClient.On("GetQuota", mock.Anything, subAccountID, broker.AzurePlanName).Return(2, nil)
 
 		provisionEndpoint := broker.NewFakeProvisionEndpointBuilder().
 			WithConfig(broker.Config{
@@ -2686,7 +2686,7 @@ func TestQuotaLimitCheck(t *testing.T) {
 		assert.NoError(t, err)
 
 		quotaClient := &automock.QuotaClient{}
-		quotaClient.On("GetQuota", subAccountID, broker.AzurePlanName).Return(0, fmt.Errorf("error message"))
+		quotaClient.On("GetQuota", mock.Anything, subAccountID, broker.AzurePlanName).Return(0, fmt.Errorf("error message"))
 
 		provisionEndpoint := broker.NewFakeProvisionEndpointBuilder().
 			WithConfig(broker.Config{
@@ -2732,7 +2732,7 @@ func TestQuotaLimitCheck(t *testing.T) {
 		assert.NoError(t, err)
 
 		quotaClient := &automock.QuotaClient{}
-		quotaClient.On("GetQuota", subAccountID, broker.AzurePlanName).Return(1, nil)
+		quotaClient.On("GetQuota", mock.Anything, subAccountID, broker.AzurePlanName).Return(1, nil)
 
 		provisionEndpoint := broker.NewFakeProvisionEndpointBuilder().
 			WithConfig(broker.Config{
@@ -2768,6 +2768,60 @@ func TestQuotaLimitCheck(t *testing.T) {
 		// then
 		assert.NoError(t, err)
 	})
+
+	t.Run("should reserve quota and reject the next provisioning request over the quota", func(t *testing.T) {
+		// given
+		// #setup memory storage
+		memoryStorage := storage.NewMemoryStorage()
+
+		quotaClient := &automock.QuotaClient{}
+		quotaClient.On("GetQuota", mock.Anything, subAccountID, broker.AzurePlanName).Return(1, nil)
+
+		provisionEndpoint := broker.NewFakeProvisionEndpointBuilder().
+			WithConfig(broker.Config{
+				EnablePlans:          []string{"gcp", "azure"},
+				URL:                  brokerURL,
+				OnlySingleTrialPerGA: true,
+				CheckQuotaLimit:      true}).
+			WithGardenerConfig(fixGardenerConfig()).
+			WithInfrastructureManager(imConfigFixture).
+			WithStorage(memoryStorage).
+			WithQueue(queue).
+			WithLogger(log).
+			WithDashboardConfig(dashboardConfig).
+			WithKubeconfigBuilder(kcBuilder).
+			WithFreemiumWhitelist(whitelist.Set{}).
+			WithSchemaService(newSchemaService(t)).
+			WithConfigurationProvider(newProviderSpec(t)).
+			WithValuesProvider(fixValueProvider(t)).
+			WithConfigMapConfigProvider(config.FakeProviderConfigProvider{}).
+			WithQuotaClient(quotaClient).
+			Build()
+		details := domain.ProvisionDetails{
+			ServiceID:     serviceID,
+			PlanID:        planID,
+			RawParameters: json.RawMessage(fmt.Sprintf(`{"name": "%s", "region": "%s"}`, clusterName, clusterRegion)),
+			RawContext:    json.RawMessage(fmt.Sprintf(`{"globalaccount_id": "%s", "subaccount_id": "%s", "user_id": "%s"}`, globalAccountID, subAccountID, "Test@Test.pl")),
+		}
+
+		// when
+		response, err := provisionEndpoint.Provision(fixRequestContext(t, "req-region"), instanceID, details, true)
+
+		// then
+		require.NoError(t, err)
+		reservations, err := memoryStorage.QuotaReservations().List()
+		require.NoError(t, err)
+		require.Len(t, reservations, 1)
+		assert.Equal(t, instanceID, reservations[0].InstanceID)
+		assert.Equal(t, response.OperationData, reservations[0].OperationID)
+		assert.Equal(t, internal.QuotaReservationReserved, reservations[0].State)
+
+		// when
+		_, err = provisionEndpoint.Provision(fixRequestContext(t, "req-region"), otherInstanceID, details, true)
+
+		// then
+		assert.EqualError(t, err, "Kyma instances quota exceeded for plan azure. assignedQuota: 1, remainingQuota: 0. Contact your administrator.")
+	})
 }
 
 func TestRestrictGA(t *testing.T) {
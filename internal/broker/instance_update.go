@@ -78,6 +78,7 @@ type UpdateEndpoint struct {
 
 	syncEmptyUpdateResponseEnabled bool
 	operationBlocklist             blocklist.OperationBlocklist
+	quotaReservations              storage.QuotaReservations
 }
 
 func NewUpdate(cfg Config,
@@ -126,6 +127,7 @@ func NewUpdate(cfg Config,
 		providerSpec:                             providerSpec,
 		planSpec:                                 planSpec,
 		quotaClient:                              quotaClient,
+		quotaReservations:                        db.QuotaReservations(),
 		quotaWhitelist:                           quotaWhitelist,
 		gvisorWhitelist:                          gvisorWhitelist,
 		rulesService:                             rulesService,
@@ -267,22 +269,35 @@ func (b *UpdateEndpoint) handleGetInstanceError(err error, logger *slog.Logger,
 }
 
 func (b *UpdateEndpoint) validateWithJsonSchemaValidator(details domain.UpdateDetails, instance *internal.Instance) error {
-	if len(details.RawParameters) > 0 {
-		planValidator, err := b.getJsonSchemaValidator(instance.Provider, instance.ServicePlanID, instance.Parameters.PlatformRegion)
-		if err != nil {
-			return fmt.Errorf("while creating plan validator: %w", err)
-		}
-		var rawParameters any
-		if err = json.Unmarshal(details.RawParameters, &rawParameters); err != nil {
-			return fmt.Errorf("while unmarshaling raw parameters: %w", err)
-		}
-		if err = planValidator.Validate(rawParameters); err != nil {
-			return fmt.Errorf("while validating update parameters: %s", validator.FormatError(err))
-		}
+	schemaErrors, err := b.schemaErrors(details, instance)
+	if err != nil {
+		return err
+	}
+	if len(schemaErrors) > 0 {
+		return fmt.Errorf("while validating update parameters: %s", strings.Join(schemaErrors, ", "))
 	}
 	return nil
 }
 
+// schemaErrors validates the raw parameters against the JSON schema of the instance plan and returns the reported violations
+func (b *UpdateEndpoint) schemaErrors(details domain.UpdateDetails, instance *internal.Instance) ([]string, error) {
+	if len(details.RawParameters) == 0 {
+		return nil, nil
+	}
+	planValidator, err := b.getJsonSchemaValidator(instance.Provider, instance.ServicePlanID, instance.Parameters.PlatformRegion)
+	if err != nil {
+		return nil, fmt.Errorf("while creating plan validator: %w", err)
+	}
+	var rawParameters any
+	if err = json.Unmarshal(details.RawParameters, &rawParameters); err != nil {
+		return nil, fmt.Errorf("while unmarshaling raw parameters: %w", err)
+	}
+	if err = planValidator.Validate(rawParameters); err != nil {
+		return validator.ErrorMessages(err), nil
+	}
+	return nil, nil
+}
+
 func shouldUpdate(instance *internal.Instance, details domain.UpdateDetails, ersContext internal.ERSContext) bool {
 	return len(details.RawParameters) != 0 ||
 		details.PlanID != instance.ServicePlanID ||
@@ -323,25 +338,6 @@ func (b *UpdateEndpoint) processUpdateParameters(ctx context.Context, previousIn
 		return domain.UpdateServiceSpec{}, fmt.Errorf("unable to process the request")
 	}
 
-	err = b.validateMachineType(ctx, providerValues, instance, params, logger, details, ersContext)
-	if err != nil {
-		return domain.UpdateServiceSpec{}, err
-	}
-
-	err = b.validateOIDC(params, instance, logger)
-	if err != nil {
-		return domain.UpdateServiceSpec{}, err
-	}
-
-	planID := instance.ServicePlanID
-	if details.PlanID != "" {
-		planID = details.PlanID
-	}
-	err = b.validateACL(params, planID, logger)
-	if err != nil {
-		return domain.UpdateServiceSpec{}, err
-	}
-
 	operationID := uuid.New().String()
 	logger = logger.With("operationID", operationID)
 
@@ -350,39 +346,20 @@ func (b *UpdateEndpoint) processUpdateParameters(ctx context.Context, previousIn
 	operation.ProviderValues = &providerValues
 	operation.RawParameters = details.RawParameters
 
-	if err := operation.ProvisioningParameters.Parameters.AutoScalerParameters.Validate(providerValues.DefaultAutoScalerMin, providerValues.DefaultAutoScalerMax); err != nil {
-		logger.Error(fmt.Sprintf("invalid autoscaler parameters: %s", err.Error()))
-		return domain.UpdateServiceSpec{}, apiresponses.NewFailureResponse(err, http.StatusBadRequest, err.Error())
-	}
-
-	if params.AdditionalVolumeSizeGi != nil && *params.AdditionalVolumeSizeGi < 0 {
-		err := fmt.Errorf("additionalVolumeSizeGi must be >= 0, got %d", *params.AdditionalVolumeSizeGi)
-		return domain.UpdateServiceSpec{}, apiresponses.NewFailureResponse(err, http.StatusUnprocessableEntity, err.Error())
-	}
-
-	if params.AdditionalVolumeSizeGi != nil {
-		planName := AvailablePlans.GetPlanNameOrEmpty(PlanIDType(planID))
-		if !b.config.AdditionalVolumeSizeGIPlans.Contains(planName) {
-			err := fmt.Errorf("additionalVolumeSizeGi is not available for plan %s", planName)
-			return domain.UpdateServiceSpec{}, apiresponses.NewFailureResponse(err, http.StatusBadRequest, err.Error())
-		}
+	if err := b.validateUpdateParameters(ctx, previousInstance, instance, operation, params, providerValues, details, ersContext, logger); err != nil {
+		return domain.UpdateServiceSpec{}, err
 	}
 
-	if err := validateIngressFiltering(operation.ProvisioningParameters, params.IngressFiltering, b.infrastructureManagerConfig.IngressFilteringPlans, logger); err != nil {
-		return domain.UpdateServiceSpec{}, apiresponses.NewFailureResponse(err, http.StatusBadRequest, err.Error())
-	}
+	operation.PreviousParameters = previousInstance.Parameters
 
-	if err := validateAuditLogAccessForPlan(planID, params.AuditLogAccess); err != nil {
-		return domain.UpdateServiceSpec{}, apiresponses.NewFailureResponse(err, http.StatusBadRequest, err.Error())
-	}
-	if err := validateAuditLogAccess(previousInstance, params.AuditLogAccess); err != nil {
-		return domain.UpdateServiceSpec{}, apiresponses.NewFailureResponse(err, http.StatusBadRequest, err.Error())
+	updateStorage, err := b.updateInstanceAndOperationParameters(ctx, instance, &params, &operation, details, ersContext, logger)
+	if err != nil {
+		return domain.UpdateServiceSpec{}, err
 	}
 
-	operation.PreviousParameters = previousInstance.Parameters
-
-	updateStorage, err := b.updateInstanceAndOperationParameters(instance, &params, &operation, details, ersContext, logger)
+	quotaReserved, err := b.reserveQuotaForPlanUpgrade(ctx, instance.InstanceID, operation, ersContext)
 	if err != nil {
+		logger.Warn(fmt.Sprintf("unable to reserve quota: %s", err))
 		return domain.UpdateServiceSpec{}, err
 	}
 
@@ -391,17 +368,20 @@ func (b *UpdateEndpoint) processUpdateParameters(ctx context.Context, previousIn
 		if err != nil {
 			params := strings.Join(updateStorage, ", ")
 			logger.Warn(fmt.Sprintf("unable to update instance with new %v (%s)", params, err.Error()))
+			b.releaseQuota(quotaReserved, operation.ID, logger)
 			return domain.UpdateServiceSpec{}, err
 		}
 		b.insertActionForPlanUpgrade(updateStorage, previousInstance, details, instance, logger)
 	}
 
 	if skipProcessing, lastOperation := b.shouldSkipNewOperation(previousInstance, instance, logger); skipProcessing {
+		b.releaseQuota(quotaReserved, operation.ID, logger)
 		return b.responseWithoutNewOperation(instance, lastOperation, logger)
 	}
 
 	logger.Debug("Creating update operation in the database")
 	if err = b.operationStorage.InsertOperation(operation); err != nil {
+		b.releaseQuota(quotaReserved, operation.ID, logger)
 		return domain.UpdateServiceSpec{}, err
 	}
 
@@ -418,6 +398,59 @@ func (b *UpdateEndpoint) processUpdateParameters(ctx context.Context, previousIn
 	}, nil
 }
 
+func (b *UpdateEndpoint) validateUpdateParameters(ctx context.Context, previousInstance, instance *internal.Instance, operation internal.Operation, params internal.UpdatingParametersDTO, providerValues internal.ProviderValues, details domain.UpdateDetails, ersContext internal.ERSContext, logger *slog.Logger) error {
+	if err := b.validateMachineType(ctx, providerValues, instance, params, logger, details, ersContext); err != nil {
+		return err
+	}
+
+	if err := b.validateOIDC(params, instance, logger); err != nil {
+		return err
+	}
+
+	planID := instance.ServicePlanID
+	if details.PlanID != "" {
+		planID = details.PlanID
+	}
+	if err := b.validateACL(params, planID, logger); err != nil {
+		return err
+	}
+
+	if err := operation.ProvisioningParameters.Parameters.AutoScalerParameters.Validate(providerValues.DefaultAutoScalerMin, providerValues.DefaultAutoScalerMax); err != nil {
+		logger.Error(fmt.Sprintf("invalid autoscaler parameters: %s", err.Error()))
+		return apiresponses.NewFailureResponse(err, http.StatusBadRequest, err.Error())
+	}
+
+	if params.AdditionalVolumeSizeGi != nil && *params.AdditionalVolumeSizeGi < 0 {
+		err := fmt.Errorf("additionalVolumeSizeGi must be >= 0, got %d", *params.AdditionalVolumeSizeGi)
+		return apiresponses.NewFailureResponse(err, http.StatusUnprocessableEntity, err.Error())
+	}
+
+	if params.AdditionalVolumeSizeGi != nil {
+		planName := AvailablePlans.GetPlanNameOrEmpty(PlanIDType(planID))
+		if !b.config.AdditionalVolumeSizeGIPlans.Contains(planName) {
+			err := fmt.Errorf("additionalVolumeSizeGi is not available for plan %s", planName)
+			return apiresponses.NewFailureResponse(err, http.StatusBadRequest, err.Error())
+		}
+	}
+
+	if err := validateIngressFiltering(operation.ProvisioningParameters, params.IngressFiltering, b.infrastructureManagerConfig.IngressFilteringPlans, logger); err != nil {
+		return apiresponses.NewFailureResponse(err, http.StatusBadRequest, err.Error())
+	}
+
+	if err := validateAuditLogAccessForPlan(planID, params.AuditLogAccess); err != nil {
+		return apiresponses.NewFailureResponse(err, http.StatusBadRequest, err.Error())
+	}
+	if err := validateAuditLogAccess(previousInstance, params.AuditLogAccess); err != nil {
+		return apiresponses.NewFailureResponse(err, http.StatusBadRequest, err.Error())
+	}
+
+	if err := validateMaintenanceWindow(b.config, planID, params.MaintenanceWindow); err != nil {
+		return apiresponses.NewFailureResponse(err, http.StatusBadRequest, err.Error())
+	}
+
+	return nil
+}
+
 func (b *UpdateEndpoint) validateMachineType(ctx context.Context, providerValues internal.ProviderValues, instance *internal.Instance, params internal.UpdatingParametersDTO, logger *slog.Logger, details domain.UpdateDetails, ersContext internal.ERSContext) error {
 	if IsExternalLicenseType(ersContext) {
 		planName := AvailablePlans.GetPlanNameOrEmpty(PlanIDType(details.PlanID))
@@ -489,6 +522,17 @@ func validateAuditLogAccessForPlan(planID string, auditLogAccess *bool) error {
 	return nil
 }
 
+func validateMaintenanceWindow(cfg Config, planID string, maintenanceWindow *pkg.MaintenanceWindowDTO) error {
+	if maintenanceWindow == nil {
+		return nil
+	}
+	if !cfg.MaintenanceWindowEnabled || IsTrialPlan(planID) || IsFreemiumPlan(planID) {
+		planName := AvailablePlans.GetPlanNameOrEmpty(PlanIDType(planID))
+		return fmt.Errorf("Maintenance window is not available for %s plan.", planName)
+	}
+	return maintenanceWindow.Validate()
+}
+
 func validateAuditLogAccess(previousInstance *internal.Instance, auditLogAccess *bool) error {
 	if auditLogAccess != nil && !*auditLogAccess && previousInstance.Parameters.Parameters.AuditLogAccess != nil && *previousInstance.Parameters.Parameters.AuditLogAccess {
 		return errors.New("Audit Log Access cannot be disabled once enabled.")
@@ -823,7 +867,7 @@ func (b *UpdateEndpoint) responseWithoutNewOperation(instance *internal.Instance
 	}, nil
 }
 
-func (b *UpdateEndpoint) updateInstanceAndOperationParameters(instance *internal.Instance, params *internal.UpdatingParametersDTO, operation *internal.Operation, details domain.UpdateDetails, ersContext internal.ERSContext, logger *slog.Logger) ([]string, error) {
+func (b *UpdateEndpoint) updateInstanceAndOperationParameters(ctx context.Context, instance *internal.Instance, params *internal.UpdatingParametersDTO, operation *internal.Operation, details domain.UpdateDetails, ersContext internal.ERSContext, logger *slog.Logger) ([]string, error) {
 	var updateStorage []string
 	if details.PlanID != "" && details.PlanID != instance.ServicePlanID {
 		logger.Info(fmt.Sprintf("Plan change requested: %s -> %s", instance.ServicePlanID, details.PlanID))
@@ -835,7 +879,7 @@ func (b *UpdateEndpoint) updateInstanceAndOperationParameters(instance *internal
 			return nil, apiresponses.NewFailureResponse(err, http.StatusBadRequest, err.Error())
 		}
 
-		err := b.isPlanChangePossible(instance, sourcePlanName, targetPlanName, logger, details, ersContext)
+		err := b.isPlanChangePossible(ctx, instance, sourcePlanName, targetPlanName, logger, details, ersContext)
 		if err != nil {
 			return nil, err
 		}
@@ -888,6 +932,11 @@ func (b *UpdateEndpoint) updateInstanceAndOperationParameters(instance *internal
 		updateStorage = append(updateStorage, "Audit Log Access")
 	}
 
+	if params.MaintenanceWindow != nil {
+		instance.Parameters.Parameters.MaintenanceWindow = params.MaintenanceWindow
+		updateStorage = append(updateStorage, "Maintenance Window")
+	}
+
 	if supportsAdditionalWorkerNodePools(details.PlanID) && params.AdditionalWorkerNodePools != nil {
 		instance.Parameters.Parameters.AdditionalWorkerNodePools = b.collectAdditionalWorkerPools(params)
 		updateStorage = append(updateStorage, "Additional Worker Node Pools")
@@ -906,7 +955,7 @@ func (b *UpdateEndpoint) updateInstanceAndOperationParameters(instance *internal
 	return updateStorage, nil
 }
 
-func (b *UpdateEndpoint) isPlanChangePossible(instance *internal.Instance, sourcePlanName string, targetPlanName string, logger *slog.Logger, details domain.UpdateDetails, ersContext internal.ERSContext) error {
+func (b *UpdateEndpoint) isPlanChangePossible(ctx context.Context, instance *internal.Instance, sourcePlanName string, targetPlanName string, logger *slog.Logger, details domain.UpdateDetails, ersContext internal.ERSContext) error {
 	if !b.config.EnablePlanUpgrades || !b.planSpec.IsUpgradableBetween(sourcePlanName, targetPlanName) {
 		logger.Info("Plan change not allowed.")
 		errMsg := fmt.Sprintf("plan upgrade from %s (planID: %s) to %s (planID: %s) is not allowed", sourcePlanName, instance.ServicePlanID, targetPlanName, details.PlanID)
@@ -914,13 +963,39 @@ func (b *UpdateEndpoint) isPlanChangePossible(instance *internal.Instance, sourc
 	}
 
 	if b.config.CheckQuotaLimit && whitelist.IsNotWhitelisted(ersContext.SubAccountID, b.quotaWhitelist) {
-		if err := validateQuotaLimit(b.instanceStorage, b.quotaClient, ersContext.SubAccountID, details.PlanID, true); err != nil {
+		if err := validateQuotaLimit(ctx, b.quotaReservations, b.quotaClient, ersContext.SubAccountID, details.PlanID, true); err != nil {
 			return apiresponses.NewFailureResponse(err, http.StatusBadRequest, err.Error())
 		}
 	}
 	return nil
 }
 
+// reserveQuotaForPlanUpgrade returns true if the quota of the target plan was reserved for the instance
+func (b *UpdateEndpoint) reserveQuotaForPlanUpgrade(ctx context.Context, instanceID string, operation internal.Operation, ersContext internal.ERSContext) (bool, error) {
+	if operation.UpdatedPlanID == "" || !b.config.CheckQuotaLimit || whitelist.IsWhitelisted(ersContext.SubAccountID, b.quotaWhitelist) {
+		return false, nil
+	}
+	err := reserveQuota(ctx, b.quotaReservations, b.quotaClient, internal.QuotaReservation{
+		InstanceID:   instanceID,
+		SubAccountID: ersContext.SubAccountID,
+		PlanID:       operation.UpdatedPlanID,
+		OperationID:  operation.ID,
+	}, true)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (b *UpdateEndpoint) releaseQuota(reserved bool, operationID string, logger *slog.Logger) {
+	if !reserved {
+		return
+	}
+	if err := b.quotaReservations.Release(operationID); err != nil {
+		logger.Error(fmt.Sprintf("unable to release the quota reservation: %s", err))
+	}
+}
+
 func (b *UpdateEndpoint) collectAdministrators(params *internal.UpdatingParametersDTO) []string {
 	newAdministrators := make([]string, 0, len(params.RuntimeAdministrators))
 	newAdministrators = append(newAdministrators, params.RuntimeAdministrators...)
@@ -2145,7 +2145,7 @@ func TestQuotaLimitCheckDuringUpdate(t *testing.T) {
 		err = st.Operations().InsertProvisioningOperation(provisioningOperation)
 		require.NoError(t, err)
 		quotaClient := &automock.QuotaClient{}
-		quotaClient.On("GetQuota", subAccountID, broker.BuildRuntimeAWSPlanName).Return(1, nil)
+		quotaClient.On("GetQuota", mock.Anything, subAccountID, broker.BuildRuntimeAWSPlanName).Return(1, nil)
 		svc := broker.NewUpdate(broker.Config{
 			EnablePlanUpgrades: true,
 			CheckQuotaLimit:    true,
@@ -2187,7 +2187,7 @@ func TestQuotaLimitCheckDuringUpdate(t *testing.T) {
 		})
 		require.NoError(t, err)
 		quotaClient := &automock.QuotaClient{}
-		quotaClient.On("GetQuota", subAccountID, broker.BuildRuntimeAWSPlanName).Return(1, nil)
+		quotaClient.On("GetQuota", mock.Anything, subAccountID, broker.BuildRuntimeAWSPlanName).Return(1, nil)
 		svc := broker.NewUpdate(broker.Config{
 			EnablePlanUpgrades: true,
 			CheckQuotaLimit:    true,
@@ -2229,7 +2229,7 @@ func TestQuotaLimitCheckDuringUpdate(t *testing.T) {
 		})
 		require.NoError(t, err)
 		quotaClient := &automock.QuotaClient{}
-		quotaClient.On("GetQuota", subAccountID, broker.BuildRuntimeAWSPlanName).Return(2, nil)
+		quotaClient.On("GetQuota", mock.Anything, subAccountID, broker.BuildRuntimeAWSPlanName).Return(2, nil)
 		svc := broker.NewUpdate(broker.Config{
 			EnablePlanUpgrades: true,
 			CheckQuotaLimit:    true,
@@ -2271,7 +2271,7 @@ func TestQuotaLimitCheckDuringUpdate(t *testing.T) {
 		})
 		require.NoError(t, err)
 		quotaClient := &automock.QuotaClient{}
-		quotaClient.On("GetQuota", subAccountID, broker.BuildRuntimeAWSPlanName).Return(0, fmt.Errorf("error message"))
+		quotaClient.On("GetQuota", mock.Anything, subAccountID, broker.BuildRuntimeAWSPlanName).Return(0, fmt.Errorf("error message"))
 		svc := broker.NewUpdate(broker.Config{
 			EnablePlanUpgrades: true,
 			CheckQuotaLimit:    true,
@@ -2313,7 +2313,7 @@ func TestQuotaLimitCheckDuringUpdate(t *testing.T) {
 		})
 		require.NoError(t, err)
 		quotaClient := &automock.QuotaClient{}
-		quotaClient.On("GetQuota", subAccountID, broker.BuildRuntimeAWSPlanName).Return(1, nil)
+		quotaClient.On("GetQuota", mock.Anything, subAccountID, broker.BuildRuntimeAWSPlanName).Return(1, nil)
 		svc := broker.NewUpdate(broker.Config{
 			EnablePlanUpgrades: true,
 			CheckQuotaLimit:    true,
@@ -2333,6 +2333,86 @@ func TestQuotaLimitCheckDuringUpdate(t *testing.T) {
 		// then
 		assert.NoError(t, err)
 	})
+
+	t.Run("should reserve quota of the target plan", func(t *testing.T) {
+		st := storage.NewMemoryStorage()
+		err := st.Instances().Insert(instance)
+		require.NoError(t, err)
+		provisioningOperation := fixProvisioningOperation("01")
+		provisioningOperation.ProvisioningParameters.PlanID = broker.AWSPlanID
+		err = st.Operations().InsertProvisioningOperation(provisioningOperation)
+		require.NoError(t, err)
+		quotaClient := &automock.QuotaClient{}
+		quotaClient.On("GetQuota", mock.Anything, subAccountID, broker.BuildRuntimeAWSPlanName).Return(1, nil)
+		svc := broker.NewUpdate(broker.Config{
+			EnablePlanUpgrades: true,
+			CheckQuotaLimit:    true,
+		}, st, &handler{}, true, false, true, q, broker.PlansConfig{},
+			fixValueProvider(t), fixLogger(),
+			dashboardConfig, kcBuilder, fakeKcpK8sClient, newProviderSpec(t), newPlanSpec(t), imConfigFixture, newSchemaService(t), quotaClient, nil, nil, nil, nil, nil, blocklist.OperationBlocklist{})
+
+		// when
+		response, err := svc.Update(context.Background(), instanceID, domain.UpdateDetails{
+			ServiceID:      "",
+			PlanID:         broker.BuildRuntimeAWSPlanID,
+			RawParameters:  json.RawMessage("{}"),
+			PreviousValues: domain.PreviousValues{},
+			RawContext:     json.RawMessage(fmt.Sprintf(`{"subaccount_id": "%s"}`, subAccountID)),
+		}, true)
+
+		// then
+		require.NoError(t, err)
+		reservations, err := st.QuotaReservations().List()
+		require.NoError(t, err)
+		require.Len(t, reservations, 1)
+		assert.Equal(t, internal.QuotaReservation{
+			InstanceID:   instanceID,
+			SubAccountID: subAccountID,
+			PlanID:       broker.BuildRuntimeAWSPlanID,
+			OperationID:  response.OperationData,
+			State:        internal.QuotaReservationReserved,
+			CreatedAt:    reservations[0].CreatedAt,
+			UpdatedAt:    reservations[0].UpdatedAt,
+		}, reservations[0])
+	})
+
+	t.Run("should fail if the quota is reserved for another instance", func(t *testing.T) {
+		st := storage.NewMemoryStorage()
+		err := st.Instances().Insert(instance)
+		require.NoError(t, err)
+		provisioningOperation := fixProvisioningOperation("01")
+		provisioningOperation.ProvisioningParameters.PlanID = broker.AWSPlanID
+		err = st.Operations().InsertProvisioningOperation(provisioningOperation)
+		require.NoError(t, err)
+		reserved, err := st.QuotaReservations().Reserve(internal.QuotaReservation{
+			InstanceID:   otherInstanceID,
+			SubAccountID: subAccountID,
+			PlanID:       broker.BuildRuntimeAWSPlanID,
+			OperationID:  "other-operation",
+		}, 1)
+		require.NoError(t, err)
+		require.True(t, reserved)
+		quotaClient := &automock.QuotaClient{}
+		quotaClient.On("GetQuota", mock.Anything, subAccountID, broker.BuildRuntimeAWSPlanName).Return(1, nil)
+		svc := broker.NewUpdate(broker.Config{
+			EnablePlanUpgrades: true,
+			CheckQuotaLimit:    true,
+		}, st, &handler{}, true, false, true, q, broker.PlansConfig{},
+			fixValueProvider(t), fixLogger(),
+			dashboardConfig, kcBuilder, fakeKcpK8sClient, newProviderSpec(t), newPlanSpec(t), imConfigFixture, newSchemaService(t), quotaClient, nil, nil, nil, nil, nil, blocklist.OperationBlocklist{})
+
+		// when
+		_, err = svc.Update(context.Background(), instanceID, domain.UpdateDetails{
+			ServiceID:      "",
+			PlanID:         broker.BuildRuntimeAWSPlanID,
+			RawParameters:  json.RawMessage("{}"),
+			PreviousValues: domain.PreviousValues{},
+			RawContext:     json.RawMessage(fmt.Sprintf(`{"subaccount_id": "%s"}`, subAccountID)),
+		}, true)
+
+		// then
+		assert.EqualError(t, err, "Kyma instances quota exceeded for plan build-runtime-aws. assignedQuota: 1, remainingQuota: 0. Contact your administrator.")
+	})
 }
 
 func TestZonesDiscoveryDuringUpdate(t *testing.T) {
@@ -3201,6 +3281,81 @@ func TestUpdateAuditLogAccess(t *testing.T) {
 	}
 }
 
+func TestUpdateMaintenanceWindow(t *testing.T) {
+	for tn, tc := range map[string]struct {
+		planID                   string
+		maintenanceWindowEnabled bool
+		rawParameters            string
+		expectedErrMsg           string
+	}{
+		"valid maintenance window": {
+			planID:                   broker.AWSPlanID,
+			maintenanceWindowEnabled: true,
+			rawParameters:            `{"maintenanceWindow": {"begin": "220000+0100", "end": "020000+0100"}}`,
+		},
+		"maintenance window disabled": {
+			planID:         broker.AWSPlanID,
+			rawParameters:  `{"maintenanceWindow": {"begin": "220000+0100", "end": "020000+0100"}}`,
+			expectedErrMsg: "Maintenance window is not available for aws plan.",
+		},
+		"maintenance window for trial plan": {
+			planID:                   broker.TrialPlanID,
+			maintenanceWindowEnabled: true,
+			rawParameters:            `{"maintenanceWindow": {"begin": "220000+0100", "end": "020000+0100"}}`,
+			expectedErrMsg:           "Maintenance window is not available for trial plan.",
+		},
+		"too long maintenance window": {
+			planID:                   broker.AWSPlanID,
+			maintenanceWindowEnabled: true,
+			rawParameters:            `{"maintenanceWindow": {"begin": "220000+0100", "end": "060000+0100"}}`,
+			expectedErrMsg:           "maintenanceWindow must be between 30m0s and 6h0m0s long, got 8h0m0s",
+		},
+	} {
+		t.Run(tn, func(t *testing.T) {
+			// given
+			instance := fixture.FixInstance(instanceID)
+			instance.ServicePlanID = tc.planID
+
+			st := storage.NewMemoryStorage()
+			require.NoError(t, st.Instances().Insert(instance))
+			provisioning := fixProvisioningOperation("provisioning01")
+			provisioning.ProviderValues = &internal.ProviderValues{ProviderType: "aws"}
+			require.NoError(t, st.Operations().InsertProvisioningOperation(provisioning))
+
+			handler := &handler{}
+			q := &automock.Queue{}
+			q.On("Add", mock.AnythingOfType("string"))
+			kcBuilder := &kcMock.KcBuilder{}
+			kcBuilder.On("GetServerURL", mock.Anything).Return("https://kcp.example.com", nil)
+
+			brokerCfg := broker.Config{MaintenanceWindowEnabled: tc.maintenanceWindowEnabled}
+			svc := broker.NewUpdate(brokerCfg, st, handler, true, true, false, q, broker.PlansConfig{},
+				fixValueProvider(t), fixLogger(), dashboardConfig, kcBuilder, fakeKcpK8sClient,
+				newProviderSpec(t), newPlanSpec(t), imConfigFixture,
+				newSchemaServiceWithBrokerConfig(t, brokerCfg),
+				nil, nil, nil, nil, nil, nil, blocklist.OperationBlocklist{})
+
+			// when
+			_, err := svc.Update(context.Background(), instanceID, domain.UpdateDetails{
+				ServiceID:     "",
+				PlanID:        tc.planID,
+				RawParameters: json.RawMessage(tc.rawParameters),
+				RawContext:    json.RawMessage(fmt.Sprintf(`{"globalaccount_id": "%s", "active": true}`, globalAccountID)),
+			}, true)
+
+			// then
+			if tc.expectedErrMsg != "" {
+				require.EqualError(t, err, tc.expectedErrMsg)
+				return
+			}
+			require.NoError(t, err)
+			updated, err := st.Instances().GetByID(instanceID)
+			require.NoError(t, err)
+			assert.Equal(t, &pkg.MaintenanceWindowDTO{Begin: "220000+0100", End: "020000+0100"}, updated.Parameters.Parameters.MaintenanceWindow)
+		})
+	}
+}
+
 func fixValueProvider(t *testing.T) broker.ValuesProvider {
 	planSpec := newPlanSpec(t)
 	return provider.NewPlanSpecificValuesProvider(
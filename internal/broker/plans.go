@@ -68,6 +68,7 @@ type ControlFlagsObject struct {
 	additionalVolumeSizeGiEnabled bool
 	additionalVolumeSizeGiMaxSize int
 	auditLogAccess                bool
+	maintenanceWindow             bool
 }
 
 type AvailablePlansType struct {
@@ -133,7 +134,7 @@ func (ap AvailablePlansType) GetAllPlanNamesAsStrings() []string {
 	return names
 }
 
-func NewControlFlagsObject(ingressFilteringEnabled, gvisorEnabled, rejectUnsupportedParameters, additionalVolumeSizeGiEnabled bool, additionalVolumeSizeGiMaxSize int, auditLogAccess bool) ControlFlagsObject {
+func NewControlFlagsObject(ingressFilteringEnabled, gvisorEnabled, rejectUnsupportedParameters, additionalVolumeSizeGiEnabled bool, additionalVolumeSizeGiMaxSize int, auditLogAccess, maintenanceWindow bool) ControlFlagsObject {
 	return ControlFlagsObject{
 		ingressFilteringEnabled:       ingressFilteringEnabled,
 		gvisorEnabled:                 gvisorEnabled,
@@ -141,6 +142,7 @@ func NewControlFlagsObject(ingressFilteringEnabled, gvisorEnabled, rejectUnsuppo
 		additionalVolumeSizeGiEnabled: additionalVolumeSizeGiEnabled,
 		additionalVolumeSizeGiMaxSize: additionalVolumeSizeGiMaxSize,
 		auditLogAccess:                auditLogAccess,
+		maintenanceWindow:             maintenanceWindow,
 	}
 }
 
@@ -197,6 +199,9 @@ func createSchemaWithProperties(properties ProvisioningProperties,
 	if flags.auditLogAccess {
 		properties.AuditLogAccess = AuditLogAccessProperty()
 	}
+	if flags.maintenanceWindow {
+		properties.MaintenanceWindow = MaintenanceWindowProperty(flags.rejectUnsupportedParameters)
+	}
 
 	if update {
 		return createSchemaWith(properties.UpdateProperties, []string{}, flags.rejectUnsupportedParameters)
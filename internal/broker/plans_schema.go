@@ -27,6 +27,8 @@ const (
 	// alphanumeric start/end, middle can contain '-', '_', '.', max 63 chars, or empty string.
 	// Used for label values and taint values — annotation values are unrestricted.
 	k8sLabelValuePattern = `^([a-zA-Z0-9]([-a-zA-Z0-9_.]{0,61}[a-zA-Z0-9])?)?$`
+	// maintenanceWindowTimePattern matches the Gardener shoot maintenance time format, for example, 220000+0100
+	maintenanceWindowTimePattern = `^([01][0-9]|2[0-3])[0-5][0-9][0-5][0-9][+-][0-9]{4}$`
 )
 
 type RootSchema struct {
@@ -68,6 +70,7 @@ type UpdateProperties struct {
 	Gvisor                    *GvisorType                    `json:"gvisor,omitempty"`
 	AdditionalVolumeSizeGi    *Type                          `json:"additionalVolumeSizeGi,omitempty"`
 	AuditLogAccess            *Type                          `json:"auditLogAccess,omitempty"`
+	MaintenanceWindow         *MaintenanceWindowType         `json:"maintenanceWindow,omitempty"`
 }
 
 type GvisorProperties struct {
@@ -80,6 +83,18 @@ type GvisorType struct {
 	Properties GvisorProperties `json:"properties"`
 }
 
+type MaintenanceWindowProperties struct {
+	Begin Type `json:"begin"`
+	End   Type `json:"end"`
+}
+
+type MaintenanceWindowType struct {
+	Type
+	Properties    MaintenanceWindowProperties `json:"properties"`
+	Required      []string                    `json:"required"`
+	ControlsOrder []string                    `json:"_controlsOrder,omitempty"`
+}
+
 type NetworkingProperties struct {
 	Nodes     Type  `json:"nodes"`
 	Services  Type  `json:"services"`
@@ -798,7 +813,7 @@ func unmarshalOrPanic(from, to interface{}) interface{} {
 }
 
 func DefaultControlsOrder() []string {
-	return []string{"name", "kubeconfig", "shootName", "shootDomain", "region", "colocateControlPlane", "machineType", "autoScalerMin", "autoScalerMax", "additionalVolumeSizeGi", "zonesCount", "gvisor", "additionalWorkerNodePools", "modules", "networking", "accessControlList", "oidc", "administrators", "ingressFiltering", "auditLogAccess"}
+	return []string{"name", "kubeconfig", "shootName", "shootDomain", "region", "colocateControlPlane", "machineType", "autoScalerMin", "autoScalerMax", "additionalVolumeSizeGi", "zonesCount", "gvisor", "additionalWorkerNodePools", "modules", "networking", "accessControlList", "oidc", "administrators", "ingressFiltering", "auditLogAccess", "maintenanceWindow"}
 }
 
 func ToInterfaceSlice(input []string) []interface{} {
@@ -1008,3 +1023,33 @@ func AuditLogAccessProperty() *Type {
 		Description: "Specifies whether Audit Log Access is enabled. Once enabled, you cannot disable it.",
 	}
 }
+
+func MaintenanceWindowProperty(rejectUnsupportedParameters bool) *MaintenanceWindowType {
+	maintenanceWindow := &MaintenanceWindowType{
+		Type: Type{
+			Type:        "object",
+			Title:       "Maintenance Window",
+			Description: "Specifies the daily time window in which disruptive updates, such as plan, machine type, or additional worker node pool changes, are applied. Updates requested outside the window are deferred until the window opens. The window must be between 30 minutes and 6 hours long.",
+		},
+		Required:      []string{"begin", "end"},
+		ControlsOrder: []string{"begin", "end"},
+		Properties: MaintenanceWindowProperties{
+			Begin: Type{
+				Type:        "string",
+				Pattern:     maintenanceWindowTimePattern,
+				Example:     "220000+0000",
+				Description: "Specifies the start of the maintenance window in the HHMMSS+ZZZZ format, the same as the Gardener shoot maintenance time window.",
+			},
+			End: Type{
+				Type:        "string",
+				Pattern:     maintenanceWindowTimePattern,
+				Example:     "230000+0000",
+				Description: "Specifies the end of the maintenance window in the HHMMSS+ZZZZ format, the same as the Gardener shoot maintenance time window.",
+			},
+		},
+	}
+	if rejectUnsupportedParameters {
+		maintenanceWindow.AdditionalProperties = false
+	}
+	return maintenanceWindow
+}
@@ -7,6 +7,7 @@ import (
 	"os"
 	"path"
 	"regexp"
+	"strings"
 	"testing"
 
 	"github.com/kyma-project/kyma-environment-broker/internal/provider/configuration"
@@ -735,6 +736,30 @@ func TestNewAvailablePlans_NonBijectiveMappingReturnsEmptyAvailablePlans(t *test
 	assert.Nil(t, ap)
 }
 
+func TestMaintenanceWindowSchema(t *testing.T) {
+	schemaService := createSchemaServiceWithConfig(t, Config{MaintenanceWindowEnabled: true})
+
+	create, update, available := schemaService.AWSSchemas("cf-eu11")
+	require.True(t, available)
+	assert.Contains(t, (*create)[PropertiesKey], "maintenanceWindow")
+	assert.Contains(t, (*update)[PropertiesKey], "maintenanceWindow")
+	assert.Contains(t, (*update)[ControlsOrderKey], "maintenanceWindow")
+
+	assert.NotContains(t, (*schemaService.TrialSchema(false))[PropertiesKey], "maintenanceWindow")
+	assert.NotContains(t, (*schemaService.FreeSchema(pkg.AWS, "cf-eu11", false))[PropertiesKey], "maintenanceWindow")
+
+	pattern := regexp.MustCompile(maintenanceWindowTimePattern)
+	for _, value := range []string{"220000+0000", "000000-0530", "235959+1400"} {
+		assert.True(t, pattern.MatchString(value), "schema should accept time: %q", value)
+		err := pkg.MaintenanceWindowDTO{Begin: value, End: "100000+0000"}.Validate()
+		assert.False(t, err != nil && strings.Contains(err.Error(), "invalid maintenanceWindow begin"), "backend should accept time: %q", value)
+	}
+	for _, value := range []string{"240000+0000", "22:00", "220000", "220000+00"} {
+		assert.False(t, pattern.MatchString(value), "schema should reject time: %q", value)
+		assert.ErrorContains(t, pkg.MaintenanceWindowDTO{Begin: value, End: "100000+0000"}.Validate(), "invalid maintenanceWindow begin", "backend should reject time: %q", value)
+	}
+}
+
 func createSchemaService(t *testing.T) *SchemaService {
 	return createSchemaServiceWithConfig(t, Config{
 		RejectUnsupportedParameters: true,
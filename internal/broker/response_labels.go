@@ -27,6 +27,14 @@ const (
 	apiServerURLErrorFormat = "while getting APIServerURL: %s"
 )
 
+// ExpirationPeriods returns the expiration periods of instances of the trial and free plans
+func ExpirationPeriods(cfg Config) map[string]time.Duration {
+	return map[string]time.Duration{
+		TrialPlanID:    trialExpireDuration,
+		FreemiumPlanID: cfg.FreeExpirationPeriod,
+	}
+}
+
 func ResponseLabels(instance internal.Instance, brokerURL string, kubeconfigBuilder kubeconfig.KcBuilder) map[string]any {
 	brokerURL, _ = strings.CutPrefix(brokerURL, "https://")
 	brokerURL, _ = strings.CutPrefix(brokerURL, "http://")
@@ -55,13 +63,14 @@ func ResponseLabelsWithExpirationInfo(
 	docsURL string,
 	docsKey string,
 	expireDuration time.Duration,
+	extensionPeriod time.Duration,
 	expiryDetailsKey string,
 	expiredInfoFormat string,
 	kubeconfigBuilder kubeconfig.KcBuilder,
 ) map[string]any {
 	labels := ResponseLabels(instance, brokerURL, kubeconfigBuilder)
 
-	expireTime := instance.CreatedAt.Add(expireDuration)
+	expireTime := instance.ExpirationTime(expireDuration, extensionPeriod)
 	hoursLeft := calculateHoursLeft(expireTime)
 	if instance.IsExpired() {
 		delete(labels, kubeconfigURLKey)
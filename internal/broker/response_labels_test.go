@@ -72,7 +72,7 @@ func TestResponseLabels(t *testing.T) {
 		defer kcBuilder.AssertExpectations(t)
 
 		// when
-		labels := ResponseLabelsWithExpirationInfo(instance, "https://example.com", "https://trial.docs.local", trialDocsKey, trialExpireDuration, trialExpiryDetailsKey, trialExpiredInfoFormat, kcBuilder)
+		labels := ResponseLabelsWithExpirationInfo(instance, "https://example.com", "https://trial.docs.local", trialDocsKey, trialExpireDuration, 0, trialExpiryDetailsKey, trialExpiredInfoFormat, kcBuilder)
 
 		// then
 		require.Len(t, labels, 4)
@@ -92,7 +92,7 @@ func TestResponseLabels(t *testing.T) {
 		expectedMsg := fmt.Sprintf(notExpiredInfoFormat, "today")
 
 		// when
-		labels := ResponseLabelsWithExpirationInfo(instance, "https://example.com", "https://trial.docs.local", trialDocsKey, trialExpireDuration, trialExpiryDetailsKey, trialExpiredInfoFormat, kcBuilder)
+		labels := ResponseLabelsWithExpirationInfo(instance, "https://example.com", "https://trial.docs.local", trialDocsKey, trialExpireDuration, 0, trialExpiryDetailsKey, trialExpiredInfoFormat, kcBuilder)
 
 		// then
 		require.Len(t, labels, 4)
@@ -104,6 +104,23 @@ func TestResponseLabels(t *testing.T) {
 		assert.Equal(t, serverURL, labels["APIServerURL"])
 	})
 
+	t.Run("should return labels with expire info for instance with extended expiration", func(t *testing.T) {
+		// given
+		instance := fixture.FixInstance("instanceID")
+		instance.CreatedAt = time.Now().Add(-trialExpireDuration).Add(time.Hour)
+		instance.ExpirationExtensions = 1
+		kcBuilder := &automock.KcBuilder{}
+		kcBuilder.On("GetServerURL", instance.RuntimeID).Return(serverURL, nil)
+
+		expectedMsg := fmt.Sprintf(notExpiredInfoFormat, "in  7 days")
+
+		// when
+		labels := ResponseLabelsWithExpirationInfo(instance, "https://example.com", "https://trial.docs.local", trialDocsKey, trialExpireDuration, 7*24*time.Hour, trialExpiryDetailsKey, trialExpiredInfoFormat, kcBuilder)
+
+		// then
+		assert.Equal(t, expectedMsg, labels[trialExpiryDetailsKey])
+	})
+
 	t.Run("should return labels with expire info for expired instance", func(t *testing.T) {
 		// given
 		instance := fixture.FixInstance("instanceID")
@@ -114,7 +131,7 @@ func TestResponseLabels(t *testing.T) {
 		kcBuilder.On("GetServerURL", instance.RuntimeID).Return(serverURL, nil)
 
 		// when
-		labels := ResponseLabelsWithExpirationInfo(instance, "https://example.com", "https://trial.docs.local", trialDocsKey, trialExpireDuration, trialExpiryDetailsKey, trialExpiredInfoFormat, kcBuilder)
+		labels := ResponseLabelsWithExpirationInfo(instance, "https://example.com", "https://trial.docs.local", trialDocsKey, trialExpireDuration, 0, trialExpiryDetailsKey, trialExpiredInfoFormat, kcBuilder)
 
 		// then
 		require.Len(t, labels, 3)
@@ -317,6 +317,7 @@ func (s *SchemaService) FreeSchema(provider pkg.CloudProvider, platformRegion st
 	}
 	flags := s.createFlags(FreemiumPlanName)
 	flags.auditLogAccess = false
+	flags.maintenanceWindow = false
 
 	properties := ProvisioningProperties{
 		UpdateProperties: UpdateProperties{
@@ -353,6 +354,7 @@ func (s *SchemaService) FreeSchemas(provider pkg.CloudProvider, platformRegion s
 func (s *SchemaService) TrialSchema(update bool) *map[string]interface{} {
 	flags := s.createFlags(TrialPlanName)
 	flags.auditLogAccess = false
+	flags.maintenanceWindow = false
 
 	properties := ProvisioningProperties{
 		UpdateProperties: UpdateProperties{
@@ -382,6 +384,7 @@ func (s *SchemaService) createFlags(planName string) ControlFlagsObject {
 		s.cfg.AdditionalVolumeSizeGIPlans.Contains(planName),
 		s.cfg.AdditionalVolumeSizeGiMaxSize,
 		s.cfg.AuditLogAccess,
+		s.cfg.MaintenanceWindowEnabled,
 	)
 }
 
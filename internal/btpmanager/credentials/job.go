@@ -3,11 +3,9 @@ package btpmgrcreds
 import (
 	"fmt"
 	"log/slog"
-	"net/http"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
-	"github.com/prometheus/client_golang/prometheus/promhttp"
 
 	"github.com/go-co-op/gocron"
 )
@@ -16,7 +14,6 @@ type Job struct {
 	btpOperatorManager *Manager
 	logs               *slog.Logger
 	metricsRegistry    *prometheus.Registry
-	metricsPort        string
 	appName            string
 }
 
@@ -28,28 +25,17 @@ type ReconcileStats struct {
 	notChangedCnt   int
 }
 
-func NewJob(manager *Manager, logs *slog.Logger, metricsRegistry *prometheus.Registry, metricsPort string, appName string) *Job {
+func NewJob(manager *Manager, logs *slog.Logger, metricsRegistry *prometheus.Registry, appName string) *Job {
 	return &Job{
 		btpOperatorManager: manager,
 		logs:               logs,
 		metricsRegistry:    metricsRegistry,
-		metricsPort:        metricsPort,
 		appName:            appName,
 	}
 }
 
 func (s *Job) Start(autoReconcileInterval int, jobReconciliationDelay time.Duration) {
 	metrics := NewMetrics(s.metricsRegistry, s.appName)
-	promHandler := promhttp.HandlerFor(s.metricsRegistry, promhttp.HandlerOpts{Registry: s.metricsRegistry})
-	http.Handle("/metrics", promHandler)
-
-	go func() {
-		address := fmt.Sprintf(":%s", s.metricsPort)
-		err := http.ListenAndServe(address, nil)
-		if err != nil {
-			s.logs.Error(fmt.Sprintf("while serving metrics: %s", err))
-		}
-	}()
 
 	scheduler := gocron.NewScheduler(time.UTC)
 	_, schedulerErr := scheduler.Every(autoReconcileInterval).Minutes().Do(func() {
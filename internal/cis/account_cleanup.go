@@ -1,17 +1,19 @@
 package cis
 
 import (
+	"context"
 	"fmt"
 	"log/slog"
 	"sync/atomic"
 
 	"github.com/kyma-project/kyma-environment-broker/internal"
 	"github.com/kyma-project/kyma-environment-broker/internal/storage"
+	"github.com/kyma-project/kyma-environment-broker/internal/tracing"
 )
 
 //go:generate mockery --name=CisClient --output=automock
 type CisClient interface {
-	FetchSubaccountsToDelete() ([]string, error)
+	FetchSubaccountsToDelete(ctx context.Context) ([]string, error)
 }
 
 //go:generate mockery --name=BrokerClient --output=automock
@@ -40,10 +42,13 @@ func NewSubAccountCleanupService(client CisClient, brokerClient BrokerClient, st
 	}
 }
 
-func (ac *SubAccountCleanupService) Run() error {
+func (ac *SubAccountCleanupService) Run(ctx context.Context) error {
 	ac.log.Info("Starting Subaccount cleanup job")
 
-	subaccounts, err := ac.client.FetchSubaccountsToDelete()
+	ctx, span := tracing.Tracer().Start(ctx, "subaccount cleanup")
+	defer span.End()
+
+	subaccounts, err := ac.client.FetchSubaccountsToDelete(ctx)
 	if err != nil {
 		return fmt.Errorf("while fetching subaccounts by client: %w", err)
 	}
@@ -2,6 +2,7 @@ package cis
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"log/slog"
 	"os"
@@ -12,6 +13,7 @@ import (
 	"github.com/kyma-project/kyma-environment-broker/internal/storage"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 )
 
 // subAccountTestIDs contains test data in form: InstanceID : SubAccountID
@@ -32,7 +34,7 @@ func TestSubAccountCleanupService_Run(t *testing.T) {
 	t.Run("all instances should be deprovisioned", func(t *testing.T) {
 		// Given
 		cisClient := &mocks.CisClient{}
-		cisClient.On("FetchSubaccountsToDelete").Return(fixSubAccountIDs(), nil)
+		cisClient.On("FetchSubaccountsToDelete", mock.Anything).Return(fixSubAccountIDs(), nil)
 		defer cisClient.AssertExpectations(t)
 
 		brokerClient := &mocks.BrokerClient{}
@@ -51,7 +53,7 @@ func TestSubAccountCleanupService_Run(t *testing.T) {
 		service.chunksAmount = 2
 
 		// When
-		err := service.Run()
+		err := service.Run(context.Background())
 
 		// Then
 		assert.NoError(t, err)
@@ -63,7 +65,7 @@ func TestSubAccountCleanupService_Run(t *testing.T) {
 		brokenInstanceIDTwo := "ad6af000-e647-44ea-a3bb-db8672d5bc7e"
 
 		cisClient := &mocks.CisClient{}
-		cisClient.On("FetchSubaccountsToDelete").Return(fixSubAccountIDs(), nil)
+		cisClient.On("FetchSubaccountsToDelete", mock.Anything).Return(fixSubAccountIDs(), nil)
 		defer cisClient.AssertExpectations(t)
 
 		brokerClient := &mocks.BrokerClient{}
@@ -96,7 +98,7 @@ func TestSubAccountCleanupService_Run(t *testing.T) {
 		service.chunksAmount = 5
 
 		// When
-		err := service.Run()
+		err := service.Run(context.Background())
 
 		// Then
 		assert.NoError(t, err)
@@ -109,7 +111,7 @@ func TestSubAccountCleanupService_Run(t *testing.T) {
 	t.Run("process should return with error", func(t *testing.T) {
 		// Given
 		cisClient := &mocks.CisClient{}
-		cisClient.On("FetchSubaccountsToDelete").Return([]string{}, fmt.Errorf("cannot fetch subaccounts"))
+		cisClient.On("FetchSubaccountsToDelete", mock.Anything).Return([]string{}, fmt.Errorf("cannot fetch subaccounts"))
 		defer cisClient.AssertExpectations(t)
 
 		brokerClient := &mocks.BrokerClient{}
@@ -119,7 +121,7 @@ func TestSubAccountCleanupService_Run(t *testing.T) {
 		service.chunksAmount = 7
 
 		// When
-		err := service.Run()
+		err := service.Run(context.Background())
 
 		// Then
 		assert.Error(t, err)
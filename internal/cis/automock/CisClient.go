@@ -2,20 +2,24 @@
 
 package mocks
 
-import mock "github.com/stretchr/testify/mock"
+import (
+	context "context"
+
+	mock "github.com/stretchr/testify/mock"
+)
 
 // CisClient is an autogenerated mock type for the CisClient type
 type CisClient struct {
 	mock.Mock
 }
 
-// FetchSubAccountsToDelete provides a mock function with given fields:
-func (_m *CisClient) FetchSubaccountsToDelete() ([]string, error) {
-	ret := _m.Called()
+// FetchSubAccountsToDelete provides a mock function with given fields: ctx
+func (_m *CisClient) FetchSubaccountsToDelete(ctx context.Context) ([]string, error) {
+	ret := _m.Called(ctx)
 
 	var r0 []string
-	if rf, ok := ret.Get(0).(func() []string); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(context.Context) []string); ok {
+		r0 = rf(ctx)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]string)
@@ -23,8 +27,8 @@ func (_m *CisClient) FetchSubaccountsToDelete() ([]string, error) {
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(ctx)
 	} else {
 		r1 = ret.Error(1)
 	}
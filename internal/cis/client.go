@@ -10,6 +10,7 @@ import (
 	"time"
 
 	kebError "github.com/kyma-project/kyma-environment-broker/internal/error"
+	"github.com/kyma-project/kyma-environment-broker/internal/tracing"
 
 	"golang.org/x/oauth2/clientcredentials"
 )
@@ -52,7 +53,7 @@ func NewClient(ctx context.Context, config Config, log *slog.Logger) *Client {
 	}
 
 	return &Client{
-		httpClient: httpClientOAuth,
+		httpClient: tracing.NewHTTPClient(httpClientOAuth),
 		config:     config,
 		log:        log,
 	}
@@ -69,10 +70,10 @@ type subaccounts struct {
 	to   time.Time
 }
 
-func (c *Client) FetchSubaccountsToDelete() ([]string, error) {
+func (c *Client) FetchSubaccountsToDelete(ctx context.Context) ([]string, error) {
 	subaccounts := subaccounts{}
 
-	err := c.fetchSubaccountsFromDeleteEvents(&subaccounts)
+	err := c.fetchSubaccountsFromDeleteEvents(ctx, &subaccounts)
 	if err != nil {
 		return []string{}, fmt.Errorf("while fetching subaccounts from delete events: %w", err)
 	}
@@ -83,11 +84,11 @@ func (c *Client) FetchSubaccountsToDelete() ([]string, error) {
 	return subaccounts.ids, nil
 }
 
-func (c *Client) fetchSubaccountsFromDeleteEvents(subaccs *subaccounts) error {
+func (c *Client) fetchSubaccountsFromDeleteEvents(ctx context.Context, subaccs *subaccounts) error {
 	var cursor string
 	var retries int
 	for {
-		cisResponse, err := c.fetchSubaccountDeleteEventsForCursor(cursor)
+		cisResponse, err := c.fetchSubaccountDeleteEventsForCursor(ctx, cursor)
 		if err != nil {
 			if kebError.IsTemporaryError(err) && retries < c.config.MaxRequestRetries {
 				time.Sleep(c.config.RateLimitingInterval)
@@ -109,8 +110,8 @@ func (c *Client) fetchSubaccountsFromDeleteEvents(subaccs *subaccounts) error {
 	return nil
 }
 
-func (c *Client) fetchSubaccountDeleteEventsForCursor(cursor string) (Response, error) {
-	request, err := c.buildRequest(cursor)
+func (c *Client) fetchSubaccountDeleteEventsForCursor(ctx context.Context, cursor string) (Response, error) {
+	request, err := c.buildRequest(ctx, cursor)
 	if err != nil {
 		return Response{}, fmt.Errorf("while building request for event service: %w", err)
 	}
@@ -139,8 +140,8 @@ func (c *Client) fetchSubaccountDeleteEventsForCursor(cursor string) (Response,
 	return cisResponse, nil
 }
 
-func (c *Client) buildRequest(cursor string) (*http.Request, error) {
-	request, err := http.NewRequest(http.MethodGet, fmt.Sprintf(eventServicePath, c.config.EventServiceURL), nil)
+func (c *Client) buildRequest(ctx context.Context, cursor string) (*http.Request, error) {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf(eventServicePath, c.config.EventServiceURL), nil)
 	if err != nil {
 		return nil, fmt.Errorf("while creating request: %w", err)
 	}
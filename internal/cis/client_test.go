@@ -37,7 +37,7 @@ func TestClient_FetchSubaccountsToDelete(t *testing.T) {
 		client.SetHttpClient(testServer.Client())
 
 		// When
-		saList, err := client.FetchSubaccountsToDelete()
+		saList, err := client.FetchSubaccountsToDelete(context.Background())
 
 		// Then
 		require.NoError(t, err)
@@ -63,7 +63,7 @@ func TestClient_FetchSubaccountsToDelete(t *testing.T) {
 		client.SetHttpClient(testServer.Client())
 
 		// When
-		saList, err := client.FetchSubaccountsToDelete()
+		saList, err := client.FetchSubaccountsToDelete(context.Background())
 
 		// Then
 		require.Error(t, err)
@@ -90,7 +90,7 @@ func TestClient_FetchSubaccountsToDelete(t *testing.T) {
 		client.SetHttpClient(testServer.Client())
 
 		// When
-		saList, err := client.FetchSubaccountsToDelete()
+		saList, err := client.FetchSubaccountsToDelete(context.Background())
 
 		// Then
 		require.NoError(t, err)
@@ -118,7 +118,7 @@ func TestClient_FetchSubaccountsToDelete(t *testing.T) {
 		client.SetHttpClient(testServer.Client())
 
 		// When
-		saList, err := client.FetchSubaccountsToDelete()
+		saList, err := client.FetchSubaccountsToDelete(context.Background())
 
 		// Then
 		require.Error(t, err)
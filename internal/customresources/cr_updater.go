@@ -64,8 +64,8 @@ func (u *Updater) Run() error {
 
 		ctxWithTimeout, cancel := context.WithTimeout(u.ctx, k8sRequestInterval)
 
-		kymaRetry := u.patchKymaCRs(item.SubaccountID, item.BetaEnabled, item.UsedForProduction, ctxWithTimeout)
-		runtimeRetry := u.patchRuntimeCRs(item.SubaccountID, item.UsedForProduction, ctxWithTimeout)
+		kymaRetry := u.patchKymaCRs(item.SubaccountID, item.BetaEnabled, item.UsedForProduction, item.Labels, ctxWithTimeout)
+		runtimeRetry := u.patchRuntimeCRs(item.SubaccountID, item.UsedForProduction, item.Labels, ctxWithTimeout)
 		retryRequired := kymaRetry || runtimeRetry
 
 		cancel()
@@ -76,20 +76,34 @@ func (u *Updater) Run() error {
 	}
 }
 
-func (u *Updater) patchKymaCRs(subaccountID, betaEnabled, usedForProduction string, ctx context.Context) bool {
-	return u.patchCRs(u.kymaGVR, "Kyma", subaccountID, map[string]string{
-		BetaEnabledLabelKey:       betaEnabled,
-		UsedForProductionLabelKey: usedForProduction,
-	}, ctx)
+func (u *Updater) patchKymaCRs(subaccountID, betaEnabled, usedForProduction string, syncedLabels map[string]string, ctx context.Context) bool {
+	labelsToSet, labelsToRemove := splitSyncedLabels(syncedLabels)
+	labelsToSet[BetaEnabledLabelKey] = betaEnabled
+	labelsToSet[UsedForProductionLabelKey] = usedForProduction
+	return u.patchCRs(u.kymaGVR, "Kyma", subaccountID, labelsToSet, labelsToRemove, ctx)
 }
 
-func (u *Updater) patchRuntimeCRs(subaccountID, usedForProduction string, ctx context.Context) bool {
-	return u.patchCRs(u.runtimeGVR, "Runtime", subaccountID, map[string]string{
-		UsedForProductionLabelKey: usedForProduction,
-	}, ctx)
+func (u *Updater) patchRuntimeCRs(subaccountID, usedForProduction string, syncedLabels map[string]string, ctx context.Context) bool {
+	labelsToSet, labelsToRemove := splitSyncedLabels(syncedLabels)
+	labelsToSet[UsedForProductionLabelKey] = usedForProduction
+	return u.patchCRs(u.runtimeGVR, "Runtime", subaccountID, labelsToSet, labelsToRemove, ctx)
 }
 
-func (u *Updater) patchCRs(gvr schema.GroupVersionResource, resourceName, subaccountID string, labelsToSet map[string]string, ctx context.Context) bool {
+// splitSyncedLabels separates the labels of synced subaccount attributes into labels to set and labels to remove (empty values)
+func splitSyncedLabels(syncedLabels map[string]string) (map[string]string, []string) {
+	labelsToSet := make(map[string]string, len(syncedLabels)+2)
+	var labelsToRemove []string
+	for k, v := range syncedLabels {
+		if v == "" {
+			labelsToRemove = append(labelsToRemove, k)
+			continue
+		}
+		labelsToSet[k] = v
+	}
+	return labelsToSet, labelsToRemove
+}
+
+func (u *Updater) patchCRs(gvr schema.GroupVersionResource, resourceName, subaccountID string, labelsToSet map[string]string, labelsToRemove []string, ctx context.Context) bool {
 	list, err := u.k8sClient.Resource(gvr).Namespace(namespace).List(ctx, metav1.ListOptions{
 		LabelSelector: fmt.Sprintf(subaccountIdLabelFormat, subaccountID),
 	})
@@ -111,6 +125,9 @@ func (u *Updater) patchCRs(gvr schema.GroupVersionResource, resourceName, subacc
 		for k, v := range labelsToSet {
 			labels[k] = v
 		}
+		for _, k := range labelsToRemove {
+			delete(labels, k)
+		}
 		un.SetLabels(labels)
 		if _, err := u.k8sClient.Resource(gvr).Namespace(namespace).Update(ctx, &un, metav1.UpdateOptions{}); err != nil {
 			u.logger.Warn("while updating " + resourceName + " CR: " + err.Error() + " item will be added back to the queue")
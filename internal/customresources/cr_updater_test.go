@@ -482,4 +482,59 @@ func TestUpdater(t *testing.T) {
 		})
 		require.NoError(t, err)
 	})
+
+	t.Run("should set and remove synced attribute labels on Kyma and Runtime CRs", func(t *testing.T) {
+		// given
+		mockKymaCR := &unstructured.Unstructured{}
+		mockKymaCR.SetGroupVersionKind(gvk)
+		mockKymaCR.SetName(kymaCRName1)
+		mockKymaCR.SetNamespace(namespace)
+		mockKymaCR.SetLabels(map[string]string{subaccountIdLabelKey: subaccountID, "kyma-project.io/subaccount-region": "eu10"})
+		require.NoError(t, unstructured.SetNestedField(mockKymaCR.Object, nil, "metadata", "creationTimestamp"))
+
+		mockRuntimeCR := &unstructured.Unstructured{}
+		mockRuntimeCR.SetGroupVersionKind(runtimeGVKVal)
+		mockRuntimeCR.SetName(runtimeCRName1)
+		mockRuntimeCR.SetNamespace(namespace)
+		mockRuntimeCR.SetLabels(map[string]string{subaccountIdLabelKey: subaccountID, "kyma-project.io/subaccount-region": "eu10"})
+		require.NoError(t, unstructured.SetNestedField(mockRuntimeCR.Object, nil, "metadata", "creationTimestamp"))
+
+		queue := syncqueues.NewPriorityQueueWithCallbacksForSize(log, nil, 4)
+		queue.Insert(syncqueues.QueueElement{
+			SubaccountID:      subaccountID,
+			BetaEnabled:       "true",
+			UsedForProduction: usedForProductionTestValue,
+			ModifiedAt:        time.Now().Unix(),
+			Labels: map[string]string{
+				"kyma-project.io/subaccount-name":   "my-subaccount",
+				"kyma-project.io/subaccount-region": "",
+			},
+		})
+
+		fakeK8sClient := fake.NewSimpleDynamicClientWithCustomListKinds(scheme, listKinds, mockKymaCR, mockRuntimeCR)
+		updater, err := NewUpdater(fakeK8sClient, queue, gvr, runtimeGVR, timeout, context.TODO(), log)
+		require.NoError(t, err)
+
+		// when
+		go func(t *testing.T) {
+			require.NoError(t, updater.Run())
+		}(t)
+
+		// then
+		for _, resource := range []struct {
+			gvr  schema.GroupVersionResource
+			name string
+		}{{gvr, kymaCRName1}, {runtimeGVR, runtimeCRName1}} {
+			err = wait.PollUntilContextTimeout(context.Background(), interval, timeout, true, func(ctx context.Context) (bool, error) {
+				actual, err := fakeK8sClient.Resource(resource.gvr).Namespace(namespace).Get(context.TODO(), resource.name, metav1.GetOptions{})
+				require.NoError(t, err)
+				return actual.GetLabels()["kyma-project.io/subaccount-name"] == "my-subaccount", nil
+			})
+			require.NoError(t, err)
+			actual, err := fakeK8sClient.Resource(resource.gvr).Namespace(namespace).Get(context.TODO(), resource.name, metav1.GetOptions{})
+			require.NoError(t, err)
+			assert.NotContains(t, actual.GetLabels(), "kyma-project.io/subaccount-region")
+			assert.Equal(t, subaccountID, actual.GetLabels()[subaccountIdLabelKey])
+		}
+	})
 }
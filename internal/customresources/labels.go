@@ -1,5 +1,7 @@
 package customresources
 
+import "strings"
+
 const (
 	GlobalAccountIdLabel = "kyma-project.io/global-account-id"
 	InstanceIdLabel      = "kyma-project.io/instance-id"
@@ -14,4 +16,28 @@ const (
 	KymaNameLabel        = "operator.kyma-project.io/kyma-name"
 	ManagedByLabel       = "operator.kyma-project.io/managed-by"
 	InternalLabel        = "operator.kyma-project.io/internal"
+
+	// OperatorLabelPrefix is the prefix of the labels owned by the Kyma operators
+	OperatorLabelPrefix = "operator.kyma-project.io/"
 )
+
+var managedLabels = map[string]struct{}{
+	GlobalAccountIdLabel: {},
+	InstanceIdLabel:      {},
+	RuntimeIdLabel:       {},
+	PlanIdLabel:          {},
+	PlanNameLabel:        {},
+	SubaccountIdLabel:    {},
+	ShootNameLabel:       {},
+	RegionLabel:          {},
+	PlatformRegionLabel:  {},
+	CloudProviderLabel:   {},
+}
+
+// IsManagedLabel returns true for the label keys set by KEB or the Kyma operators, other components must not change them
+func IsManagedLabel(key string) bool {
+	if _, ok := managedLabels[key]; ok {
+		return true
+	}
+	return strings.HasPrefix(key, OperatorLabelPrefix)
+}
@@ -67,6 +67,7 @@ type UpdatingParametersDTO struct {
 	Gvisor                    *pkg.GvisorDTO                 `json:"gvisor,omitempty"`
 	AdditionalVolumeSizeGi    *int                           `json:"additionalVolumeSizeGi,omitempty"`
 	AuditLogAccess            *bool                          `json:"auditLogAccess,omitempty"`
+	MaintenanceWindow         *pkg.MaintenanceWindowDTO      `json:"maintenanceWindow,omitempty"`
 }
 
 func (u UpdatingParametersDTO) UpdateAutoScaler(p *pkg.ProvisioningParametersDTO) bool {
@@ -2,21 +2,25 @@ package events
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/kyma-project/kyma-environment-broker/common/events"
+	"github.com/kyma-project/kyma-environment-broker/common/pagination"
 	"github.com/kyma-project/kyma-environment-broker/internal/storage"
 	"github.com/kyma-project/kyma-environment-broker/internal/storage/dbmodel"
 )
 
 type Handler struct {
-	e storage.Events
-	i storage.Instances
+	e       storage.Events
+	i       storage.Instances
+	maxPage int
 }
 
-func NewHandler(e storage.Events, i storage.Instances) Handler {
-	return Handler{e, i}
+func NewHandler(e storage.Events, i storage.Instances, maxPage int) Handler {
+	return Handler{e, i, maxPage}
 }
 
 func split(s string) []string {
@@ -27,11 +31,26 @@ func split(s string) []string {
 }
 
 func (h Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	instanceId := r.URL.Query().Get("instance_ids")
-	instanceIds := split(instanceId)
-	runtimeId := r.URL.Query().Get("runtime_ids")
-	operationId := r.URL.Query().Get("operation_ids")
-	operationIds := split(operationId)
+	filter, err := filterFromRequest(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	pageSize, cursor, err := pagination.ExtractCursorPaginationFromRequest(r, h.maxPage)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if cursor != "" {
+		createdAt, id, err := pagination.DecodeCursor(cursor)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		filter.After = &events.EventPosition{CreatedAt: createdAt, ID: id}
+	}
+
+	runtimeId := r.URL.Query().Get(events.RuntimeIDsParam)
 	if runtimeId != "" {
 		instances, _, _, err := h.i.List(dbmodel.InstanceFilter{RuntimeIDs: split(runtimeId)})
 		if err != nil {
@@ -39,15 +58,43 @@ func (h Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 		for _, i := range instances {
-			instanceIds = append(instanceIds, i.InstanceID)
+			filter.InstanceIDs = append(filter.InstanceIDs, i.InstanceID)
 		}
 	}
-	events, err := h.e.ListEvents(events.EventFilter{InstanceIDs: instanceIds, OperationIDs: operationIds})
+
+	if pageSize == 0 {
+		// without the pagination parameters all matching events are returned
+		eventList, err := h.e.ListEvents(filter)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		h.write(w, eventList)
+		return
+	}
+
+	// one more event is requested to find out if there is a next page
+	filter.PageSize = pageSize + 1
+	eventList, err := h.e.ListEvents(filter)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusServiceUnavailable)
 		return
 	}
-	bytes, err := json.Marshal(events)
+	page := events.EventsPage{Data: eventList}
+	if len(eventList) > pageSize {
+		page.Data = eventList[:pageSize]
+		last := page.Data[pageSize-1]
+		page.NextCursor = pagination.EncodeCursor(last.CreatedAt, last.ID)
+	}
+	if page.Data == nil {
+		page.Data = []events.EventDTO{}
+	}
+	page.Count = len(page.Data)
+	h.write(w, page)
+}
+
+func (h Handler) write(w http.ResponseWriter, response any) {
+	bytes, err := json.Marshal(response)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusServiceUnavailable)
 		return
@@ -56,3 +103,47 @@ func (h Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), http.StatusServiceUnavailable)
 	}
 }
+
+func filterFromRequest(r *http.Request) (events.EventFilter, error) {
+	query := r.URL.Query()
+	filter := events.EventFilter{
+		InstanceIDs:      split(query.Get(events.InstanceIDsParam)),
+		OperationIDs:     split(query.Get(events.OperationIDsParam)),
+		GlobalAccountIDs: split(query.Get(events.GlobalAccountIDsParam)),
+		SubAccountIDs:    split(query.Get(events.SubAccountIDsParam)),
+		Regions:          split(query.Get(events.RegionParam)),
+		Message:          query.Get(events.MessageParam),
+	}
+
+	for _, level := range split(query.Get(events.LevelsParam)) {
+		switch events.EventLevel(level) {
+		case events.InfoEventLevel, events.ErrorEventLevel:
+			filter.Levels = append(filter.Levels, events.EventLevel(level))
+		default:
+			return filter, fmt.Errorf("unsupported event level %q, supported levels: %s, %s", level, events.InfoEventLevel, events.ErrorEventLevel)
+		}
+	}
+
+	var err error
+	if filter.From, err = parseTime(query.Get(events.FromParam), events.FromParam); err != nil {
+		return filter, err
+	}
+	if filter.To, err = parseTime(query.Get(events.ToParam), events.ToParam); err != nil {
+		return filter, err
+	}
+	if !filter.From.IsZero() && !filter.To.IsZero() && filter.From.After(filter.To) {
+		return filter, fmt.Errorf("%s must not be after %s", events.FromParam, events.ToParam)
+	}
+	return filter, nil
+}
+
+func parseTime(value, param string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s must be a time in the RFC 3339 format: %w", param, err)
+	}
+	return t, nil
+}
@@ -0,0 +1,141 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/kyma-project/kyma-environment-broker/common/events"
+	"github.com/kyma-project/kyma-environment-broker/internal/fixture"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeEvents struct {
+	storage.Events
+	events []events.EventDTO
+	filter events.EventFilter
+}
+
+func (f *fakeEvents) ListEvents(filter events.EventFilter) ([]events.EventDTO, error) {
+	f.filter = filter
+	var result []events.EventDTO
+	for _, ev := range f.events {
+		if filter.After != nil && !ev.CreatedAt.After(filter.After.CreatedAt) {
+			continue
+		}
+		result = append(result, ev)
+	}
+	if filter.PageSize > 0 && len(result) > filter.PageSize {
+		result = result[:filter.PageSize]
+	}
+	return result, nil
+}
+
+func fixHandler(t *testing.T) (Handler, *fakeEvents) {
+	db := storage.NewMemoryStorage()
+	require.NoError(t, db.Instances().Insert(fixture.FixInstance("instance-1")))
+
+	fake := &fakeEvents{}
+	createdAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 4; i++ {
+		fake.events = append(fake.events, events.EventDTO{
+			ID:        fmt.Sprintf("event-%d", i),
+			Level:     events.InfoEventLevel,
+			Message:   "Provisioning started",
+			CreatedAt: createdAt.Add(time.Duration(i) * time.Second),
+		})
+	}
+	return NewHandler(fake, db.Instances(), 2), fake
+}
+
+func call(handler Handler, query string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/events?"+query, nil)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestHandler_Filters(t *testing.T) {
+	// given
+	handler, fake := fixHandler(t)
+
+	// when
+	rr := call(handler, "instance_ids=instance-2&runtime_ids=runtime-instance-1&operation_ids=op-1&global_account_ids=ga-1,ga-2&subaccount_ids=sa-1&region=eu-west-1,westeurope"+
+		"&levels=info,error&from=2024-01-01T00:00:00Z&to=2024-01-02T00:00:00Z&message=quota")
+
+	// then
+	require.Equal(t, http.StatusOK, rr.Code)
+	assert.Equal(t, events.EventFilter{
+		InstanceIDs:      []string{"instance-2", "instance-1"},
+		OperationIDs:     []string{"op-1"},
+		GlobalAccountIDs: []string{"ga-1", "ga-2"},
+		SubAccountIDs:    []string{"sa-1"},
+		Regions:          []string{"eu-west-1", "westeurope"},
+		Levels:           []events.EventLevel{events.InfoEventLevel, events.ErrorEventLevel},
+		From:             time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		To:               time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+		Message:          "quota",
+	}, fake.filter)
+
+	var response []events.EventDTO
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &response))
+	assert.Len(t, response, 4, "events are not paginated without the pagination parameters")
+}
+
+func TestHandler_CursorPagination(t *testing.T) {
+	// given
+	handler, _ := fixHandler(t)
+
+	// when
+	rr := call(handler, "page_size=2")
+
+	// then
+	require.Equal(t, http.StatusOK, rr.Code)
+	var first events.EventsPage
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &first))
+	assert.Equal(t, 2, first.Count)
+	require.NotEmpty(t, first.NextCursor)
+
+	// when
+	rr = call(handler, "cursor="+first.NextCursor)
+
+	// then
+	require.Equal(t, http.StatusOK, rr.Code)
+	var second events.EventsPage
+	require.NoError(t, json.Unmarshal(rr.Body.Bytes(), &second))
+	assert.Equal(t, 2, second.Count)
+	assert.Empty(t, second.NextCursor, "last page has no cursor")
+
+	var ids []string
+	for _, ev := range append(first.Data, second.Data...) {
+		ids = append(ids, ev.ID)
+	}
+	assert.Equal(t, []string{"event-0", "event-1", "event-2", "event-3"}, ids)
+}
+
+func TestHandler_InvalidParameters(t *testing.T) {
+	handler, _ := fixHandler(t)
+
+	for name, query := range map[string]string{
+		"unknown level":      "levels=warning",
+		"malformed time":     "from=yesterday",
+		"reversed range":     "from=2024-02-01T00:00:00Z&to=2024-01-01T00:00:00Z",
+		"malformed cursor":   "cursor=not-a-cursor",
+		"negative page size": "page_size=-1",
+		"page size too big":  "page_size=3",
+	} {
+		t.Run(name, func(t *testing.T) {
+			// when
+			rr := call(handler, query)
+
+			// then
+			assert.Equal(t, http.StatusBadRequest, rr.Code)
+		})
+	}
+}
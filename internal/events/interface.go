@@ -1,24 +1,35 @@
 package events
 
 import (
+	"context"
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/kyma-project/kyma-environment-broker/common/events"
+	"github.com/kyma-project/kyma-environment-broker/internal/event"
 )
 
 type Config struct {
 	Enabled       bool          `envconfig:"default=false"`
-	Retention     time.Duration `envconfig:"default=336h"` // two weeks: 24*14 = 336
 	PollingPeriod time.Duration `envconfig:"default=1h"`
+	// Retention is the age after which events are deleted by KEB, 0 disables the deletion.
+	// It must be 0 if the Events Retention CronJob is enabled, the Job archives events of deprovisioned instances which KEB would delete.
+	Retention time.Duration `envconfig:"default=336h"` // two weeks: 24*14 = 336
 }
 
 var (
-	ev       Interface
-	initLock sync.Mutex
+	ev        Interface
+	initLock  sync.Mutex
+	publisher atomic.Pointer[event.Publisher]
 )
 
+// EventInserted is published for every event inserted with Infof or Errorf, also when storing events is disabled
+type EventInserted struct {
+	Event events.EventDTO
+}
+
 type Interface interface {
 	ListEvents(filter events.EventFilter) ([]events.EventDTO, error)
 	InsertEvent(eventLevel events.EventLevel, message, instanceID, operationID string)
@@ -38,6 +49,11 @@ func New(cfg Config, events Interface) Interface {
 	return ev
 }
 
+// SetPublisher sets the publisher used to propagate inserted events across the application
+func SetPublisher(pub event.Publisher) {
+	publisher.Store(&pub)
+}
+
 func Infof(instanceID, operationID, format string, args ...any) {
 	insertEvent(events.InfoEventLevel, fmt.Sprintf(format, args...), instanceID, operationID)
 }
@@ -50,4 +66,13 @@ func insertEvent(eventLevel events.EventLevel, msg, instanceID, operationID stri
 	if ev != nil {
 		ev.InsertEvent(eventLevel, msg, instanceID, operationID)
 	}
+	if pub := publisher.Load(); pub != nil && *pub != nil {
+		(*pub).Publish(context.Background(), EventInserted{Event: events.EventDTO{
+			Level:       eventLevel,
+			InstanceID:  &instanceID,
+			OperationID: &operationID,
+			Message:     msg,
+			CreatedAt:   time.Now(),
+		}})
+	}
 }
@@ -0,0 +1,313 @@
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	commonEvents "github.com/kyma-project/kyma-environment-broker/common/events"
+	"github.com/kyma-project/kyma-environment-broker/internal"
+	"github.com/kyma-project/kyma-environment-broker/internal/event"
+	"github.com/kyma-project/kyma-environment-broker/internal/events"
+	"github.com/kyma-project/kyma-environment-broker/internal/process"
+	"github.com/pivotal-cf/brokerapi/v12/domain"
+)
+
+const (
+	MessageTypeEvent         = "event"
+	MessageTypeStep          = "step"
+	MessageTypeStageFinished = "stage"
+	MessageTypeFinished      = "finished"
+	MessageTypeRetried       = "retried"
+
+	subscriberBufferSize = 100
+)
+
+type Config struct {
+	KeepAliveInterval time.Duration `envconfig:"default=15s"`
+	// FinishGracePeriod is the time the operation stream stays open after the operation finished.
+	// The events are handled asynchronously, so the last step and stage messages can come after the finished message.
+	FinishGracePeriod time.Duration `envconfig:"default=2s"`
+}
+
+func (c Config) Validate() error {
+	if c.KeepAliveInterval <= 0 {
+		return fmt.Errorf("events stream keep-alive interval must be greater than 0, got %s", c.KeepAliveInterval)
+	}
+	if c.FinishGracePeriod < 0 {
+		return fmt.Errorf("events stream finish grace period must not be negative, got %s", c.FinishGracePeriod)
+	}
+	return nil
+}
+
+// Message is a single entry of the operation progress stream
+type Message struct {
+	Type        string                    `json:"type"`
+	InstanceID  string                    `json:"instanceID"`
+	OperationID string                    `json:"operationID"`
+	Level       commonEvents.EventLevel   `json:"level,omitempty"`
+	Message     string                    `json:"message,omitempty"`
+	Stage       string                    `json:"stage,omitempty"`
+	Step        string                    `json:"step,omitempty"`
+	State       domain.LastOperationState `json:"state,omitempty"`
+	Error       string                    `json:"error,omitempty"`
+	RetryIn     string                    `json:"retryIn,omitempty"`
+	Timestamp   time.Time                 `json:"timestamp"`
+}
+
+// finishesOperation returns true for messages after which no more progress of the operation is expected
+func (m Message) finishesOperation() bool {
+	return m.Type == MessageTypeFinished
+}
+
+type filter struct {
+	instanceID  string
+	operationID string
+}
+
+func (f filter) matches(m Message) bool {
+	if f.instanceID != "" && f.instanceID != m.InstanceID {
+		return false
+	}
+	if f.operationID != "" && f.operationID != m.OperationID {
+		return false
+	}
+	return true
+}
+
+type subscriber struct {
+	filter   filter
+	messages chan Message
+}
+
+// Broadcaster converts events published across the application into progress messages and passes them to the subscribed streams
+type Broadcaster struct {
+	mu          sync.Mutex
+	subscribers map[*subscriber]struct{}
+	log         *slog.Logger
+}
+
+func NewBroadcaster(sub event.Subscriber, log *slog.Logger) *Broadcaster {
+	b := &Broadcaster{
+		subscribers: make(map[*subscriber]struct{}),
+		log:         log.With("service", "OperationProgressStream"),
+	}
+	sub.Subscribe(events.EventInserted{}, b.onEventInserted)
+	sub.Subscribe(process.OperationStepProcessed{}, b.onStepProcessed)
+	sub.Subscribe(process.OperationStageFinished{}, b.onStageFinished)
+	sub.Subscribe(process.OperationFinished{}, b.onOperationFinished)
+	sub.Subscribe(process.OperationRetried{}, b.onOperationRetried)
+	return b
+}
+
+func (b *Broadcaster) subscribe(f filter) *subscriber {
+	s := &subscriber{
+		filter:   f,
+		messages: make(chan Message, subscriberBufferSize),
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subscribers[s] = struct{}{}
+	return s
+}
+
+func (b *Broadcaster) unsubscribe(s *subscriber) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.subscribers, s)
+}
+
+func (b *Broadcaster) broadcast(m Message) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for s := range b.subscribers {
+		if !s.filter.matches(m) {
+			continue
+		}
+		select {
+		case s.messages <- m:
+		default:
+			// the stream does not keep up with the progress, a slow client must not block the event handlers
+			b.log.Warn(fmt.Sprintf("dropping %s message for operation %s, the stream buffer is full", m.Type, m.OperationID))
+		}
+	}
+}
+
+func (b *Broadcaster) onEventInserted(_ context.Context, ev interface{}) error {
+	e, ok := ev.(events.EventInserted)
+	if !ok {
+		return fmt.Errorf("expected events.EventInserted but got %+v", ev)
+	}
+	b.broadcast(Message{
+		Type:        MessageTypeEvent,
+		InstanceID:  stringValue(e.Event.InstanceID),
+		OperationID: stringValue(e.Event.OperationID),
+		Level:       e.Event.Level,
+		Message:     e.Event.Message,
+		Timestamp:   e.Event.CreatedAt,
+	})
+	return nil
+}
+
+func (b *Broadcaster) onStepProcessed(_ context.Context, ev interface{}) error {
+	e, ok := ev.(process.OperationStepProcessed)
+	if !ok {
+		return fmt.Errorf("expected process.OperationStepProcessed but got %+v", ev)
+	}
+	m := operationMessage(MessageTypeStep, e.Operation)
+	m.Step = e.StepName
+	if e.Error != nil {
+		m.Error = e.Error.Error()
+	}
+	if e.When > 0 {
+		m.RetryIn = e.When.String()
+	}
+	b.broadcast(m)
+	return nil
+}
+
+func (b *Broadcaster) onStageFinished(_ context.Context, ev interface{}) error {
+	e, ok := ev.(process.OperationStageFinished)
+	if !ok {
+		return fmt.Errorf("expected process.OperationStageFinished but got %+v", ev)
+	}
+	m := operationMessage(MessageTypeStageFinished, e.Operation)
+	m.Stage = e.Stage
+	b.broadcast(m)
+	return nil
+}
+
+func (b *Broadcaster) onOperationFinished(_ context.Context, ev interface{}) error {
+	e, ok := ev.(process.OperationFinished)
+	if !ok {
+		return fmt.Errorf("expected process.OperationFinished but got %+v", ev)
+	}
+	m := operationMessage(MessageTypeFinished, e.Operation)
+	if e.Operation.State == domain.Failed {
+		m.Error = e.Operation.LastError.Error()
+	}
+	b.broadcast(m)
+	return nil
+}
+
+func (b *Broadcaster) onOperationRetried(_ context.Context, ev interface{}) error {
+	e, ok := ev.(process.OperationRetried)
+	if !ok {
+		return fmt.Errorf("expected process.OperationRetried but got %+v", ev)
+	}
+	b.broadcast(operationMessage(MessageTypeRetried, e.Operation))
+	return nil
+}
+
+func operationMessage(messageType string, operation internal.Operation) Message {
+	return Message{
+		Type:        messageType,
+		InstanceID:  operation.InstanceID,
+		OperationID: operation.ID,
+		State:       operation.State,
+		Message:     operation.Description,
+		Timestamp:   time.Now(),
+	}
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+type router interface {
+	HandleFunc(pattern string, handler func(http.ResponseWriter, *http.Request))
+}
+
+type Handler interface {
+	AttachRoutes(r router)
+}
+
+type handler struct {
+	broadcaster       *Broadcaster
+	keepAliveInterval time.Duration
+	finishGracePeriod time.Duration
+	log               *slog.Logger
+}
+
+func NewHandler(broadcaster *Broadcaster, cfg Config, log *slog.Logger) Handler {
+	return &handler{
+		broadcaster:       broadcaster,
+		keepAliveInterval: cfg.KeepAliveInterval,
+		finishGracePeriod: cfg.FinishGracePeriod,
+		log:               log.With("service", "OperationProgressStreamEndpoint"),
+	}
+}
+
+func (h *handler) AttachRoutes(r router) {
+	r.HandleFunc("GET /events/stream", h.streamProgress)
+}
+
+// streamProgress sends the progress messages of the given instance or operation as server-sent events until the client disconnects
+// or, when the stream is limited to an operation, until the grace period after the operation succeeds or fails passes
+func (h *handler) streamProgress(w http.ResponseWriter, req *http.Request) {
+	f := filter{
+		instanceID:  req.URL.Query().Get("instance_id"),
+		operationID: req.URL.Query().Get("operation_id"),
+	}
+	if f.instanceID == "" && f.operationID == "" {
+		http.Error(w, "instance_id or operation_id query parameter is required", http.StatusBadRequest)
+		return
+	}
+
+	rc := http.NewResponseController(w)
+	s := h.broadcaster.subscribe(f)
+	defer h.broadcaster.unsubscribe(s)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	if err := rc.Flush(); err != nil {
+		h.log.Error(fmt.Sprintf("unable to flush the progress stream: %s", err))
+		return
+	}
+
+	// a nil channel blocks forever, keep-alive comments are not sent if the interval is not set
+	var keepAlive <-chan time.Time
+	if h.keepAliveInterval > 0 {
+		ticker := time.NewTicker(h.keepAliveInterval)
+		defer ticker.Stop()
+		keepAlive = ticker.C
+	}
+	var finished <-chan time.Time
+
+	for {
+		select {
+		case <-req.Context().Done():
+			return
+		case <-finished:
+			return
+		case <-keepAlive:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+		case m := <-s.messages:
+			data, err := json.Marshal(m)
+			if err != nil {
+				h.log.Error(fmt.Sprintf("unable to marshal progress message: %s", err))
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", m.Type, data); err != nil {
+				return
+			}
+			if f.operationID != "" && m.finishesOperation() && finished == nil {
+				finished = time.After(h.finishGracePeriod)
+			}
+		}
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package stream_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kyma-project/kyma-environment-broker/internal"
+	"github.com/kyma-project/kyma-environment-broker/internal/event"
+	"github.com/kyma-project/kyma-environment-broker/internal/events"
+	"github.com/kyma-project/kyma-environment-broker/internal/events/stream"
+	"github.com/kyma-project/kyma-environment-broker/internal/fixture"
+	"github.com/kyma-project/kyma-environment-broker/internal/httputil"
+	"github.com/kyma-project/kyma-environment-broker/internal/process"
+	"github.com/pivotal-cf/brokerapi/v12/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStreamProgress(t *testing.T) {
+	// given
+	log := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}))
+	pubSub := event.NewPubSub(log)
+	events.SetPublisher(pubSub)
+	defer events.SetPublisher(nil)
+
+	router := httputil.NewRouter()
+	stream.NewHandler(stream.NewBroadcaster(pubSub, log), stream.Config{KeepAliveInterval: time.Minute, FinishGracePeriod: 200 * time.Millisecond}, log).AttachRoutes(router)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		router.ServeHTTP(httputil.NewResponseRecorder(w), r)
+	}))
+	defer server.Close()
+
+	operation := fixture.FixOperation("op-id", "instance-id", internal.OperationTypeProvision)
+	otherOperation := fixture.FixOperation("other-op-id", "other-instance-id", internal.OperationTypeProvision)
+
+	t.Run("should stream events and stage transitions of the operation until it is finished", func(t *testing.T) {
+		// given
+		resp, err := http.Get(fmt.Sprintf("%s/events/stream?operation_id=%s", server.URL, operation.ID))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+		reader := bufio.NewReader(resp.Body)
+
+		// when
+		events.Infof(operation.InstanceID, operation.ID, "processing step: %s", "first")
+		events.Infof(otherOperation.InstanceID, otherOperation.ID, "processing step: %s", "first")
+
+		// then
+		msg := readMessage(t, reader)
+		assert.Equal(t, stream.MessageTypeEvent, msg.Type)
+		assert.Equal(t, operation.ID, msg.OperationID)
+		assert.Equal(t, "processing step: first", msg.Message)
+
+		// when
+		pubSub.Publish(context.Background(), process.OperationStageFinished{Stage: "create_runtime", Operation: otherOperation})
+		pubSub.Publish(context.Background(), process.OperationStageFinished{Stage: "create_runtime", Operation: operation})
+
+		// then
+		msg = readMessage(t, reader)
+		assert.Equal(t, stream.MessageTypeStageFinished, msg.Type)
+		assert.Equal(t, operation.ID, msg.OperationID)
+		assert.Equal(t, "create_runtime", msg.Stage)
+
+		// when
+		operation.State = domain.Succeeded
+		pubSub.Publish(context.Background(), process.OperationFinished{Operation: operation})
+
+		// then
+		msg = readMessage(t, reader)
+		assert.Equal(t, stream.MessageTypeFinished, msg.Type)
+		assert.Equal(t, domain.Succeeded, msg.State)
+
+		// when
+		pubSub.Publish(context.Background(), process.OperationStepProcessed{StepProcessed: process.StepProcessed{StepName: "last_step"}, Operation: operation})
+
+		// then
+		msg = readMessage(t, reader)
+		assert.Equal(t, stream.MessageTypeStep, msg.Type)
+		assert.Equal(t, "last_step", msg.Step)
+		rest, err := io.ReadAll(reader)
+		require.NoError(t, err)
+		assert.Equal(t, "\n", string(rest))
+	})
+
+	t.Run("should not fail without the keep-alive interval", func(t *testing.T) {
+		// given
+		router := httputil.NewRouter()
+		stream.NewHandler(stream.NewBroadcaster(pubSub, log), stream.Config{}, log).AttachRoutes(router)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			router.ServeHTTP(httputil.NewResponseRecorder(w), r)
+		}))
+		defer server.Close()
+		resp, err := http.Get(fmt.Sprintf("%s/events/stream?operation_id=%s", server.URL, operation.ID))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		// when
+		pubSub.Publish(context.Background(), process.OperationFinished{Operation: operation})
+
+		// then
+		msg := readMessage(t, bufio.NewReader(resp.Body))
+		assert.Equal(t, stream.MessageTypeFinished, msg.Type)
+	})
+
+	t.Run("should return 400 Bad Request when neither instance nor operation is given", func(t *testing.T) {
+		// when
+		resp, err := http.Get(fmt.Sprintf("%s/events/stream", server.URL))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		// then
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+}
+
+func readMessage(t *testing.T, reader *bufio.Reader) stream.Message {
+	t.Helper()
+	var eventType string
+	for {
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err)
+		line = strings.TrimSuffix(line, "\n")
+		switch {
+		case strings.HasPrefix(line, "event: "):
+			eventType = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			var msg stream.Message
+			require.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &msg))
+			assert.Equal(t, eventType, msg.Type)
+			return msg
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package eventsretention
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/kyma-project/kyma-environment-broker/internal/storage"
+)
+
+type Config struct {
+	DryRun    bool `envconfig:"default=true"`
+	BatchSize int  `envconfig:"default=1000"`
+	// Retention is the period for which events of existing instances are kept
+	Retention time.Duration `envconfig:"default=336h"`
+	// DeprovisionedRetention is the period after the last event of a deprovisioned instance, after which its events are archived or dropped
+	DeprovisionedRetention time.Duration `envconfig:"default=24h"`
+	// ArchiveDeprovisioned moves events of deprovisioned instances to the events archive instead of deleting them
+	ArchiveDeprovisioned bool `envconfig:"default=true"`
+}
+
+type Result struct {
+	DeprovisionedInstances int
+	ArchivedEvents         int
+	DroppedEvents          int
+	ExpiredEvents          int
+}
+
+type Service struct {
+	retention storage.EventsRetention
+	cfg       Config
+	log       *slog.Logger
+	now       func() time.Time
+}
+
+func NewService(retention storage.EventsRetention, cfg Config, log *slog.Logger) *Service {
+	return &Service{
+		retention: retention,
+		cfg:       cfg,
+		log:       log,
+		now:       time.Now,
+	}
+}
+
+// Run archives or drops events of deprovisioned instances and then deletes expired events of the remaining instances.
+// In the dry run mode, only the first batch of deprovisioned instances is evaluated and no events are changed.
+func (s *Service) Run() (Result, error) {
+	var result Result
+	if s.cfg.BatchSize <= 0 {
+		return result, fmt.Errorf("batch size must be positive, got %d", s.cfg.BatchSize)
+	}
+	now := s.now()
+
+	if err := s.processDeprovisioned(now, &result); err != nil {
+		return result, err
+	}
+	if err := s.deleteExpired(now, &result); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+func (s *Service) processDeprovisioned(now time.Time, result *Result) error {
+	lastEventBefore := now.Add(-s.cfg.DeprovisionedRetention)
+	for {
+		instanceIDs, err := s.retention.ListDeprovisionedInstanceIDs(lastEventBefore, s.cfg.BatchSize)
+		if err != nil {
+			return fmt.Errorf("while listing deprovisioned instances: %w", err)
+		}
+		if len(instanceIDs) == 0 {
+			return nil
+		}
+		result.DeprovisionedInstances += len(instanceIDs)
+
+		switch {
+		case s.cfg.DryRun:
+			count, err := s.retention.CountByInstanceIDs(instanceIDs)
+			if err != nil {
+				return fmt.Errorf("while counting events of deprovisioned instances: %w", err)
+			}
+			s.log.Info(fmt.Sprintf("DryRun: %d events of %d deprovisioned instances would be %s", count, len(instanceIDs), s.deprovisionedAction()))
+			if s.cfg.ArchiveDeprovisioned {
+				result.ArchivedEvents += count
+			} else {
+				result.DroppedEvents += count
+			}
+			return nil
+		case s.cfg.ArchiveDeprovisioned:
+			archived, err := s.retention.ArchiveByInstanceIDs(instanceIDs)
+			if err != nil {
+				return fmt.Errorf("while archiving events of deprovisioned instances: %w", err)
+			}
+			result.ArchivedEvents += archived
+		default:
+			dropped, err := s.retention.DeleteByInstanceIDs(instanceIDs)
+			if err != nil {
+				return fmt.Errorf("while deleting events of deprovisioned instances: %w", err)
+			}
+			result.DroppedEvents += dropped
+		}
+		s.log.Info(fmt.Sprintf("Events of %d deprovisioned instances %s", len(instanceIDs), s.deprovisionedAction()))
+
+		if len(instanceIDs) < s.cfg.BatchSize {
+			return nil
+		}
+	}
+}
+
+func (s *Service) deleteExpired(now time.Time, result *Result) error {
+	if s.cfg.Retention <= 0 {
+		s.log.Info("Retention is not set, expired events are not deleted")
+		return nil
+	}
+	createdBefore := now.Add(-s.cfg.Retention)
+
+	if s.cfg.DryRun {
+		count, err := s.retention.CountExpired(createdBefore)
+		if err != nil {
+			return fmt.Errorf("while counting expired events: %w", err)
+		}
+		s.log.Info(fmt.Sprintf("DryRun: %d events created before %s would be deleted", count, createdBefore.Format(time.RFC3339)))
+		result.ExpiredEvents = count
+		return nil
+	}
+
+	for {
+		deleted, err := s.retention.DeleteExpired(createdBefore, s.cfg.BatchSize)
+		if err != nil {
+			return fmt.Errorf("while deleting expired events: %w", err)
+		}
+		result.ExpiredEvents += deleted
+		if deleted < s.cfg.BatchSize {
+			s.log.Info(fmt.Sprintf("Deleted %d events created before %s", result.ExpiredEvents, createdBefore.Format(time.RFC3339)))
+			return nil
+		}
+	}
+}
+
+func (s *Service) deprovisionedAction() string {
+	if s.cfg.ArchiveDeprovisioned {
+		return "archived"
+	}
+	return "dropped"
+}
@@ -0,0 +1,197 @@
+package eventsretention
+
+import (
+	"log/slog"
+	"os"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type event struct {
+	instanceID string
+	createdAt  time.Time
+}
+
+type fakeRetention struct {
+	instances map[string]bool
+	events    []event
+	archived  []event
+}
+
+func (f *fakeRetention) ListDeprovisionedInstanceIDs(lastEventBefore time.Time, limit int) ([]string, error) {
+	lastEvents := map[string]time.Time{}
+	for _, ev := range f.events {
+		if f.instances[ev.instanceID] {
+			continue
+		}
+		if ev.createdAt.After(lastEvents[ev.instanceID]) {
+			lastEvents[ev.instanceID] = ev.createdAt
+		}
+	}
+	var ids []string
+	for id, last := range lastEvents {
+		if last.Before(lastEventBefore) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	if len(ids) > limit {
+		ids = ids[:limit]
+	}
+	return ids, nil
+}
+
+func (f *fakeRetention) CountByInstanceIDs(instanceIDs []string) (int, error) {
+	return len(f.filter(func(ev event) bool { return contains(instanceIDs, ev.instanceID) }, -1, false)), nil
+}
+
+func (f *fakeRetention) ArchiveByInstanceIDs(instanceIDs []string) (int, error) {
+	archived := f.filter(func(ev event) bool { return contains(instanceIDs, ev.instanceID) }, -1, true)
+	f.archived = append(f.archived, archived...)
+	return len(archived), nil
+}
+
+func (f *fakeRetention) DeleteByInstanceIDs(instanceIDs []string) (int, error) {
+	return len(f.filter(func(ev event) bool { return contains(instanceIDs, ev.instanceID) }, -1, true)), nil
+}
+
+func (f *fakeRetention) CountExpired(createdBefore time.Time) (int, error) {
+	return len(f.filter(f.expired(createdBefore), -1, false)), nil
+}
+
+func (f *fakeRetention) DeleteExpired(createdBefore time.Time, limit int) (int, error) {
+	return len(f.filter(f.expired(createdBefore), limit, true)), nil
+}
+
+func (f *fakeRetention) expired(createdBefore time.Time) func(ev event) bool {
+	return func(ev event) bool {
+		return f.instances[ev.instanceID] && ev.createdAt.Before(createdBefore)
+	}
+}
+
+func (f *fakeRetention) filter(matches func(event) bool, limit int, remove bool) []event {
+	var matched, kept []event
+	for _, ev := range f.events {
+		if (limit < 0 || len(matched) < limit) && matches(ev) {
+			matched = append(matched, ev)
+			continue
+		}
+		kept = append(kept, ev)
+	}
+	if remove {
+		f.events = kept
+	}
+	return matched
+}
+
+func contains(ids []string, id string) bool {
+	for _, i := range ids {
+		if i == id {
+			return true
+		}
+	}
+	return false
+}
+
+func fixRetention(now time.Time) *fakeRetention {
+	return &fakeRetention{
+		instances: map[string]bool{"existing": true},
+		events: []event{
+			{instanceID: "existing", createdAt: now.Add(-48 * time.Hour)},
+			{instanceID: "existing", createdAt: now.Add(-30 * time.Hour)},
+			{instanceID: "existing", createdAt: now.Add(-48 * time.Hour)},
+			{instanceID: "existing", createdAt: now},
+			{instanceID: "deprovisioned-1", createdAt: now.Add(-48 * time.Hour)},
+			{instanceID: "deprovisioned-2", createdAt: now.Add(-48 * time.Hour)},
+			{instanceID: "deprovisioned-2", createdAt: now.Add(-47 * time.Hour)},
+			{instanceID: "deprovisioned-3", createdAt: now.Add(-72 * time.Hour)},
+			{instanceID: "recently-deprovisioned", createdAt: now.Add(-time.Hour)},
+		},
+	}
+}
+
+func fixService(retention *fakeRetention, cfg Config, now time.Time) *Service {
+	svc := NewService(retention, cfg, slog.New(slog.NewTextHandler(os.Stdout, nil)))
+	svc.now = func() time.Time { return now }
+	return svc
+}
+
+func TestService_Run(t *testing.T) {
+	// given
+	now := time.Now()
+	retention := fixRetention(now)
+	svc := fixService(retention, Config{
+		BatchSize:              2,
+		Retention:              24 * time.Hour,
+		DeprovisionedRetention: 24 * time.Hour,
+		ArchiveDeprovisioned:   true,
+	}, now)
+
+	// when
+	result, err := svc.Run()
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, Result{DeprovisionedInstances: 3, ArchivedEvents: 4, ExpiredEvents: 3}, result)
+	assert.Len(t, retention.archived, 4)
+	assert.Equal(t, []event{
+		{instanceID: "existing", createdAt: now},
+		{instanceID: "recently-deprovisioned", createdAt: now.Add(-time.Hour)},
+	}, retention.events)
+}
+
+func TestService_Run_DropDeprovisioned(t *testing.T) {
+	// given
+	now := time.Now()
+	retention := fixRetention(now)
+	svc := fixService(retention, Config{
+		BatchSize:              10,
+		Retention:              24 * time.Hour,
+		DeprovisionedRetention: 24 * time.Hour,
+	}, now)
+
+	// when
+	result, err := svc.Run()
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, Result{DeprovisionedInstances: 3, DroppedEvents: 4, ExpiredEvents: 3}, result)
+	assert.Empty(t, retention.archived)
+	assert.Len(t, retention.events, 2)
+}
+
+func TestService_Run_DryRun(t *testing.T) {
+	// given
+	now := time.Now()
+	retention := fixRetention(now)
+	svc := fixService(retention, Config{
+		DryRun:                 true,
+		BatchSize:              2,
+		Retention:              24 * time.Hour,
+		DeprovisionedRetention: 24 * time.Hour,
+		ArchiveDeprovisioned:   true,
+	}, now)
+
+	// when
+	result, err := svc.Run()
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, Result{DeprovisionedInstances: 2, ArchivedEvents: 3, ExpiredEvents: 3}, result, "only the first batch of deprovisioned instances is evaluated")
+	assert.Len(t, retention.events, 9, "no events are changed")
+}
+
+func TestService_Run_InvalidBatchSize(t *testing.T) {
+	// given
+	svc := fixService(fixRetention(time.Now()), Config{}, time.Now())
+
+	// when
+	_, err := svc.Run()
+
+	// then
+	assert.Error(t, err)
+}
@@ -0,0 +1,136 @@
+package expiration
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	pkg "github.com/kyma-project/kyma-environment-broker/common/runtime"
+	"github.com/kyma-project/kyma-environment-broker/internal"
+	"github.com/kyma-project/kyma-environment-broker/internal/httputil"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage/dberr"
+)
+
+type ExtensionConfig struct {
+	// Period is the time added to the expiration by one extension
+	Period time.Duration
+	// MaxExtensions limits how many times the expiration of one instance can be extended
+	MaxExtensions int
+	// ExpirationPeriods contains the expiration periods of instances by the plan ID
+	ExpirationPeriods map[string]time.Duration
+}
+
+type extensionRequest struct {
+	Reason string `json:"reason,omitempty"`
+}
+
+type extensionResponse struct {
+	InstanceID           string    `json:"instanceID"`
+	ExpirationExtensions int       `json:"expirationExtensions"`
+	RemainingExtensions  int       `json:"remainingExtensions"`
+	ExpiresAt            time.Time `json:"expiresAt"`
+}
+
+func (h *handler) extendExpiration(w http.ResponseWriter, req *http.Request) {
+	instanceID := req.PathValue("instance_id")
+	logger := h.log.With("instanceID", instanceID)
+	logger.Info("Expiration extension requested")
+
+	reason, err := readReason(req)
+	if err != nil {
+		httputil.WriteErrorResponse(w, http.StatusBadRequest, err)
+		return
+	}
+
+	instance, err := h.instances.GetByID(instanceID)
+	if err != nil {
+		logger.Error(fmt.Sprintf("unable to get instance: %s", err.Error()))
+		switch {
+		case dberr.IsNotFound(err):
+			httputil.WriteErrorResponse(w, http.StatusNotFound, err)
+		default:
+			httputil.WriteErrorResponse(w, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	expirationPeriod, supported := h.extension.ExpirationPeriods[instance.ServicePlanID]
+	if !supported {
+		msg := fmt.Sprintf("expiration of instances of the plan %s cannot be extended", instance.ServicePlanName)
+		logger.Warn(msg)
+		httputil.WriteErrorResponse(w, http.StatusBadRequest, errors.New(msg))
+		return
+	}
+	if instance.IsExpired() {
+		httputil.WriteErrorResponse(w, http.StatusConflict, fmt.Errorf("instance %s expired at %s", instanceID, instance.ExpiredAt.Format(time.RFC3339)))
+		return
+	}
+	if instance.ExpirationExtensions >= h.extension.MaxExtensions {
+		httputil.WriteErrorResponse(w, http.StatusConflict, fmt.Errorf("expiration of instance %s was already extended %d times, the limit is %d", instanceID, instance.ExpirationExtensions, h.extension.MaxExtensions))
+		return
+	}
+
+	oldExpiresAt := instance.ExpirationTime(expirationPeriod, h.extension.Period)
+	instance.ExpirationExtensions++
+	instance, err = h.instances.Update(*instance)
+	switch {
+	case err == nil:
+	case dberr.IsConflict(err):
+		httputil.WriteErrorResponse(w, http.StatusConflict, fmt.Errorf("instance %s was modified in the meantime, please try again", instanceID))
+		return
+	default:
+		logger.Error(fmt.Sprintf("unable to update the instance in the database after extending the expiration: %s", err.Error()))
+		httputil.WriteErrorResponse(w, http.StatusInternalServerError, err)
+		return
+	}
+	expiresAt := instance.ExpirationTime(expirationPeriod, h.extension.Period)
+	logger.Info(fmt.Sprintf("expiration extended from %s to %s", oldExpiresAt.Format(time.RFC3339), expiresAt.Format(time.RFC3339)))
+
+	h.insertExtensionAction(*instance, oldExpiresAt, expiresAt, reason, logger)
+
+	httputil.WriteResponse(w, http.StatusOK, extensionResponse{
+		InstanceID:           instanceID,
+		ExpirationExtensions: instance.ExpirationExtensions,
+		RemainingExtensions:  h.extension.MaxExtensions - instance.ExpirationExtensions,
+		ExpiresAt:            expiresAt,
+	})
+}
+
+func (h *handler) insertExtensionAction(instance internal.Instance, oldExpiresAt, expiresAt time.Time, reason string, logger *slog.Logger) {
+	message := fmt.Sprintf("Expiration extended by %s (extension %d of %d).", h.extension.Period, instance.ExpirationExtensions, h.extension.MaxExtensions)
+	if reason != "" {
+		message = fmt.Sprintf("%s Reason: %s", message, reason)
+	}
+	if err := h.actions.InsertAction(
+		pkg.ExpirationExtensionActionType,
+		instance.InstanceID,
+		message,
+		oldExpiresAt.UTC().Format(time.RFC3339),
+		expiresAt.UTC().Format(time.RFC3339),
+	); err != nil {
+		logger.Error(fmt.Sprintf("while inserting action %q with message %s for instance ID %s: %v", pkg.ExpirationExtensionActionType, message, instance.InstanceID, err))
+	}
+}
+
+func readReason(req *http.Request) (string, error) {
+	if req.Body == nil {
+		return "", nil
+	}
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return "", err
+	}
+	if len(strings.TrimSpace(string(body))) == 0 {
+		return "", nil
+	}
+	var request extensionRequest
+	if err := json.Unmarshal(body, &request); err != nil {
+		return "", fmt.Errorf("while decoding request body: %w", err)
+	}
+	return request.Reason, nil
+}
@@ -33,21 +33,26 @@ type Handler interface {
 type handler struct {
 	instances           storage.Instances
 	operations          storage.Operations
+	actions             storage.Actions
 	deprovisioningQueue suspension.Adder
+	extension           ExtensionConfig
 	log                 *slog.Logger
 }
 
-func NewHandler(instancesStorage storage.Instances, operationsStorage storage.Operations, deprovisioningQueue suspension.Adder, log *slog.Logger) Handler {
+func NewHandler(instancesStorage storage.Instances, operationsStorage storage.Operations, actionsStorage storage.Actions, deprovisioningQueue suspension.Adder, extension ExtensionConfig, log *slog.Logger) Handler {
 	return &handler{
 		instances:           instancesStorage,
 		operations:          operationsStorage,
+		actions:             actionsStorage,
 		deprovisioningQueue: deprovisioningQueue,
+		extension:           extension,
 		log:                 log.With("service", "ExpirationEndpoint"),
 	}
 }
 
 func (h *handler) AttachRoutes(r router) {
 	r.HandleFunc("PUT /expire/service_instance/{instance_id}", h.expireInstance)
+	r.HandleFunc("PUT /expire/service_instance/{instance_id}/extend", h.extendExpiration)
 }
 
 func (h *handler) expireInstance(w http.ResponseWriter, req *http.Request) {
@@ -8,21 +8,27 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
+	pkg "github.com/kyma-project/kyma-environment-broker/common/runtime"
 	"github.com/kyma-project/kyma-environment-broker/internal/broker"
 	"github.com/kyma-project/kyma-environment-broker/internal/expiration"
 	"github.com/kyma-project/kyma-environment-broker/internal/fixture"
 	"github.com/kyma-project/kyma-environment-broker/internal/httputil"
 	"github.com/kyma-project/kyma-environment-broker/internal/process"
+	"github.com/kyma-project/kyma-environment-broker/internal/ptr"
 	"github.com/kyma-project/kyma-environment-broker/internal/storage"
 	"github.com/pivotal-cf/brokerapi/v12/domain"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
-const requestPathFormat = "/expire/service_instance/%s"
+const (
+	requestPathFormat          = "/expire/service_instance/%s"
+	extensionRequestPathFormat = "/expire/service_instance/%s/extend"
+)
 
 func TestExpiration(t *testing.T) {
 	router := httputil.NewRouter()
@@ -31,7 +37,7 @@ func TestExpiration(t *testing.T) {
 	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
 		Level: slog.LevelInfo,
 	}))
-	handler := expiration.NewHandler(storage.Instances(), storage.Operations(), deprovisioningQueue, logger)
+	handler := expiration.NewHandler(storage.Instances(), storage.Operations(), storage.Actions(), deprovisioningQueue, expiration.ExtensionConfig{}, logger)
 	handler.AttachRoutes(router)
 
 	t.Run("should receive 404 Not Found response", func(t *testing.T) {
@@ -326,3 +332,102 @@ func TestExpiration(t *testing.T) {
 		assert.Equal(t, newSuspensionOp.ID, actualOp.ID)
 	})
 }
+
+func TestExpirationExtension(t *testing.T) {
+	router := httputil.NewRouter()
+	db := storage.NewMemoryStorage()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
+		Level: slog.LevelInfo,
+	}))
+	extension := expiration.ExtensionConfig{
+		Period:            7 * 24 * time.Hour,
+		MaxExtensions:     1,
+		ExpirationPeriods: map[string]time.Duration{broker.TrialPlanID: 14 * 24 * time.Hour},
+	}
+	handler := expiration.NewHandler(db.Instances(), db.Operations(), db.Actions(), process.NewFakeQueue(), extension, logger)
+	handler.AttachRoutes(router)
+
+	extend := func(instanceID, body string) *http.Response {
+		req := httptest.NewRequest("PUT", fmt.Sprintf(extensionRequestPathFormat, instanceID), strings.NewReader(body))
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Result()
+	}
+
+	t.Run("should extend the expiration once", func(t *testing.T) {
+		// given
+		instanceID := "inst-trial-extend"
+		trialInstance := fixture.FixInstance(instanceID)
+		trialInstance.ServicePlanID = broker.TrialPlanID
+		trialInstance.CreatedAt = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+		require.NoError(t, db.Instances().Insert(trialInstance))
+
+		// when
+		resp := extend(instanceID, `{"reason": "workshop"}`)
+
+		// then
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		var response struct {
+			ExpirationExtensions int       `json:"expirationExtensions"`
+			RemainingExtensions  int       `json:"remainingExtensions"`
+			ExpiresAt            time.Time `json:"expiresAt"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&response))
+		assert.Equal(t, 1, response.ExpirationExtensions)
+		assert.Equal(t, 0, response.RemainingExtensions)
+		assert.Equal(t, time.Date(2026, 1, 22, 0, 0, 0, 0, time.UTC), response.ExpiresAt.UTC())
+
+		actualInstance, err := db.Instances().GetByID(instanceID)
+		require.NoError(t, err)
+		assert.Equal(t, 1, actualInstance.ExpirationExtensions)
+
+		actions, err := db.Actions().ListActionsByInstanceID(instanceID)
+		require.NoError(t, err)
+		require.Len(t, actions, 1)
+		assert.Equal(t, pkg.ExpirationExtensionActionType, actions[0].Type)
+		assert.Equal(t, "2026-01-15T00:00:00Z", actions[0].OldValue)
+		assert.Equal(t, "2026-01-22T00:00:00Z", actions[0].NewValue)
+		assert.Contains(t, actions[0].Message, "workshop")
+
+		// when
+		resp = extend(instanceID, "")
+
+		// then
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	})
+
+	t.Run("should not extend the expiration of expired instance", func(t *testing.T) {
+		// given
+		instanceID := "inst-trial-expired"
+		trialInstance := fixture.FixInstance(instanceID)
+		trialInstance.ServicePlanID = broker.TrialPlanID
+		trialInstance.ExpiredAt = ptr.Time(time.Now())
+		require.NoError(t, db.Instances().Insert(trialInstance))
+
+		// when
+		resp := extend(instanceID, "")
+
+		// then
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	})
+
+	t.Run("should not extend the expiration of not trial instance", func(t *testing.T) {
+		// given
+		instanceID := "inst-azure-extend"
+		require.NoError(t, db.Instances().Insert(fixture.FixInstance(instanceID)))
+
+		// when
+		resp := extend(instanceID, "")
+
+		// then
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("should receive 404 Not Found response", func(t *testing.T) {
+		// when
+		resp := extend("not-existing", "")
+
+		// then
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+}
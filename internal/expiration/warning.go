@@ -0,0 +1,169 @@
+package expiration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	pkg "github.com/kyma-project/kyma-environment-broker/common/runtime"
+	"github.com/kyma-project/kyma-environment-broker/internal"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage"
+	"github.com/kyma-project/kyma-environment-broker/internal/webhook"
+)
+
+const (
+	LogNotifierType     = "log"
+	WebhookNotifierType = "webhook"
+
+	warningEvent = "expiration_warning"
+)
+
+type WarningConfig struct {
+	Enabled bool `envconfig:"default=false"`
+	// Period before the expiration in which the warnings are sent, one warning for each remaining day
+	Period time.Duration `envconfig:"default=72h"`
+	// Notifier is "webhook" or "log", the log notifier stands in for an e-mail notification and only writes the warnings to the logs
+	Notifier       string        `envconfig:"default=log"`
+	WebhookURL     string        `envconfig:"optional"`
+	WebhookSecret  string        `envconfig:"optional"`
+	RequestTimeout time.Duration `envconfig:"default=10s"`
+}
+
+// Warning informs that the trial or free instance expires in the given number of days
+type Warning struct {
+	InstanceID      string    `json:"instanceID"`
+	GlobalAccountID string    `json:"globalAccountID"`
+	SubAccountID    string    `json:"subAccountID"`
+	PlanName        string    `json:"planName"`
+	ExpiresAt       time.Time `json:"expiresAt"`
+	DaysLeft        int       `json:"daysLeft"`
+}
+
+type Notifier interface {
+	Notify(ctx context.Context, warning Warning) error
+}
+
+func NewNotifier(cfg WarningConfig, log *slog.Logger) (Notifier, error) {
+	switch cfg.Notifier {
+	case LogNotifierType:
+		return &logNotifier{log: log}, nil
+	case WebhookNotifierType:
+		if cfg.WebhookURL == "" || cfg.WebhookSecret == "" {
+			return nil, fmt.Errorf("webhook URL and secret are required for the %s notifier", WebhookNotifierType)
+		}
+		return &webhookNotifier{
+			url:    cfg.WebhookURL,
+			secret: cfg.WebhookSecret,
+			client: &http.Client{Timeout: cfg.RequestTimeout},
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported notifier %q, supported notifiers: %s, %s", cfg.Notifier, LogNotifierType, WebhookNotifierType)
+	}
+}
+
+type logNotifier struct {
+	log *slog.Logger
+}
+
+func (n *logNotifier) Notify(_ context.Context, warning Warning) error {
+	n.log.Info(fmt.Sprintf("Your Kyma runtime (instance %s, plan %s) in subaccount %s expires in %d days, at %s.",
+		warning.InstanceID, warning.PlanName, warning.SubAccountID, warning.DaysLeft, warning.ExpiresAt.Format(time.RFC3339)))
+	return nil
+}
+
+type webhookNotifier struct {
+	url    string
+	secret string
+	client *http.Client
+}
+
+func (n *webhookNotifier) Notify(ctx context.Context, warning Warning) error {
+	body, err := json.Marshal(warning)
+	if err != nil {
+		return fmt.Errorf("while marshalling the warning: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("while creating the request: %w", err)
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(webhook.EventHeader, warningEvent)
+	req.Header.Set(webhook.TimestampHeader, timestamp)
+	req.Header.Set(webhook.SignatureHeader, "sha256="+webhook.Sign(n.secret, timestamp, body))
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("while sending the warning: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("webhook responded with status code %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Warner sends the warnings about upcoming expirations. Every sent warning is recorded as a runtime action, which
+// prevents sending the same warning again when the job runs more often than once a day.
+type Warner struct {
+	notifier Notifier
+	actions  storage.Actions
+	period   time.Duration
+	log      *slog.Logger
+	now      func() time.Time
+}
+
+func NewWarner(notifier Notifier, actions storage.Actions, period time.Duration, log *slog.Logger) *Warner {
+	return &Warner{
+		notifier: notifier,
+		actions:  actions,
+		period:   period,
+		log:      log,
+		now:      time.Now,
+	}
+}
+
+// Warn sends the warning if the instance expires within the warning period and the warning for the number of remaining days was not sent yet
+func (w *Warner) Warn(ctx context.Context, instance internal.Instance, expiresAt time.Time) (bool, error) {
+	left := expiresAt.Sub(w.now())
+	if left <= 0 || left > w.period {
+		return false, nil
+	}
+	daysLeft := int(math.Ceil(left.Hours() / 24))
+	expiresAtValue := expiresAt.UTC().Format(time.RFC3339)
+	daysLeftValue := strconv.Itoa(daysLeft)
+
+	actions, err := w.actions.ListActionsByInstanceID(instance.InstanceID)
+	if err != nil {
+		return false, fmt.Errorf("while listing actions: %w", err)
+	}
+	for _, action := range actions {
+		if action.Type == pkg.ExpirationWarningActionType && action.OldValue == expiresAtValue && action.NewValue == daysLeftValue {
+			return false, nil
+		}
+	}
+
+	err = w.notifier.Notify(ctx, Warning{
+		InstanceID:      instance.InstanceID,
+		GlobalAccountID: instance.GlobalAccountID,
+		SubAccountID:    instance.SubAccountID,
+		PlanName:        instance.ServicePlanName,
+		ExpiresAt:       expiresAt.UTC(),
+		DaysLeft:        daysLeft,
+	})
+	if err != nil {
+		return false, fmt.Errorf("while sending the warning: %w", err)
+	}
+
+	message := fmt.Sprintf("Expiration warning sent: the instance expires in %d days.", daysLeft)
+	if err := w.actions.InsertAction(pkg.ExpirationWarningActionType, instance.InstanceID, message, expiresAtValue, daysLeftValue); err != nil {
+		w.log.Error(fmt.Sprintf("while inserting action %q for instance ID %s: %v", pkg.ExpirationWarningActionType, instance.InstanceID, err))
+	}
+	return true, nil
+}
@@ -0,0 +1,154 @@
+package expiration
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	pkg "github.com/kyma-project/kyma-environment-broker/common/runtime"
+	"github.com/kyma-project/kyma-environment-broker/internal/fixture"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage"
+	"github.com/kyma-project/kyma-environment-broker/internal/webhook"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWarner_Warn(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	instance := fixture.FixInstance("instance-1")
+
+	fixWarner := func() (*Warner, *recordingNotifier, storage.BrokerStorage) {
+		db := storage.NewMemoryStorage()
+		notifier := &recordingNotifier{}
+		warner := NewWarner(notifier, db.Actions(), 72*time.Hour, slog.New(slog.NewTextHandler(os.Stdout, nil)))
+		warner.now = func() time.Time { return now }
+		return warner, notifier, db
+	}
+
+	t.Run("should send one warning for each remaining day", func(t *testing.T) {
+		// given
+		warner, notifier, db := fixWarner()
+		expiresAt := now.Add(60 * time.Hour)
+
+		// when
+		sent, err := warner.Warn(context.Background(), instance, expiresAt)
+
+		// then
+		require.NoError(t, err)
+		assert.True(t, sent)
+		require.Len(t, notifier.warnings, 1)
+		assert.Equal(t, 3, notifier.warnings[0].DaysLeft)
+		assert.Equal(t, expiresAt, notifier.warnings[0].ExpiresAt)
+
+		actions, err := db.Actions().ListActionsByInstanceID(instance.InstanceID)
+		require.NoError(t, err)
+		require.Len(t, actions, 1)
+		assert.Equal(t, pkg.ExpirationWarningActionType, actions[0].Type)
+
+		// when
+		sent, err = warner.Warn(context.Background(), instance, expiresAt)
+
+		// then
+		require.NoError(t, err)
+		assert.False(t, sent)
+		assert.Len(t, notifier.warnings, 1)
+
+		// when
+		now = now.Add(24 * time.Hour)
+		sent, err = warner.Warn(context.Background(), instance, expiresAt)
+		now = now.Add(-24 * time.Hour)
+
+		// then
+		require.NoError(t, err)
+		assert.True(t, sent)
+		require.Len(t, notifier.warnings, 2)
+		assert.Equal(t, 2, notifier.warnings[1].DaysLeft)
+	})
+
+	t.Run("should warn again when the expiration was extended", func(t *testing.T) {
+		// given
+		warner, notifier, _ := fixWarner()
+		_, err := warner.Warn(context.Background(), instance, now.Add(12*time.Hour))
+		require.NoError(t, err)
+
+		// when
+		sent, err := warner.Warn(context.Background(), instance, now.Add(36*time.Hour))
+
+		// then
+		require.NoError(t, err)
+		assert.True(t, sent)
+		assert.Len(t, notifier.warnings, 2)
+	})
+
+	t.Run("should not warn outside of the warning period", func(t *testing.T) {
+		// given
+		warner, notifier, _ := fixWarner()
+
+		// when
+		early, err := warner.Warn(context.Background(), instance, now.Add(100*time.Hour))
+		require.NoError(t, err)
+		late, err := warner.Warn(context.Background(), instance, now.Add(-time.Hour))
+		require.NoError(t, err)
+
+		// then
+		assert.False(t, early)
+		assert.False(t, late)
+		assert.Empty(t, notifier.warnings)
+	})
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	// given
+	var received Warning
+	var request *http.Request
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		request = r
+		body, _ = io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &received)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	notifier, err := NewNotifier(WarningConfig{Notifier: WebhookNotifierType, WebhookURL: server.URL, WebhookSecret: "secret", RequestTimeout: time.Second}, nil)
+	require.NoError(t, err)
+
+	// when
+	err = notifier.Notify(context.Background(), Warning{InstanceID: "instance-1", DaysLeft: 2})
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, "instance-1", received.InstanceID)
+	assert.Equal(t, 2, received.DaysLeft)
+	assert.Equal(t, warningEvent, request.Header.Get(webhook.EventHeader))
+	expectedSignature := "sha256=" + webhook.Sign("secret", request.Header.Get(webhook.TimestampHeader), body)
+	assert.Equal(t, expectedSignature, request.Header.Get(webhook.SignatureHeader))
+}
+
+func TestNewNotifier(t *testing.T) {
+	_, err := NewNotifier(WarningConfig{Notifier: WebhookNotifierType}, nil)
+	assert.Error(t, err)
+
+	_, err = NewNotifier(WarningConfig{Notifier: "smtp"}, nil)
+	assert.Error(t, err)
+
+	notifier, err := NewNotifier(WarningConfig{Notifier: LogNotifierType}, slog.New(slog.NewTextHandler(os.Stdout, nil)))
+	require.NoError(t, err)
+	assert.NoError(t, notifier.Notify(context.Background(), Warning{InstanceID: "instance-1"}))
+}
+
+type recordingNotifier struct {
+	warnings []Warning
+}
+
+func (n *recordingNotifier) Notify(_ context.Context, warning Warning) error {
+	n.warnings = append(n.warnings, warning)
+	return nil
+}
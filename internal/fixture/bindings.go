@@ -5,6 +5,8 @@ import (
 	"time"
 
 	"github.com/kyma-project/kyma-environment-broker/internal"
+
+	"github.com/pivotal-cf/brokerapi/v12/domain"
 )
 
 type BindingOption func(binding *internal.Binding)
@@ -35,6 +37,8 @@ func FixBinding(id string, opts ...BindingOption) internal.Binding {
 		Kubeconfig:        "kubeconfig",
 		ExpirationSeconds: 600,
 		CreatedBy:         "john.smith@email.com",
+		Scope:             internal.BindingScope{Profile: internal.BindingProfileClusterAdmin},
+		State:             domain.Succeeded,
 	}
 
 	for _, opt := range opts {
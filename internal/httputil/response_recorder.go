@@ -23,3 +23,8 @@ func (rr *ResponseRecorder) Write(b []byte) (int, error) {
 	rr.Size += size
 	return size, err
 }
+
+// Unwrap returns the underlying writer, it allows http.ResponseController to flush streamed responses
+func (rr *ResponseRecorder) Unwrap() http.ResponseWriter {
+	return rr.ResponseWriter
+}
@@ -8,6 +8,7 @@ import (
 
 	pkg "github.com/kyma-project/kyma-environment-broker/common/runtime"
 	"github.com/kyma-project/kyma-environment-broker/internal/provider/configuration"
+	"github.com/kyma-project/kyma-environment-broker/internal/tracing"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
@@ -129,5 +130,6 @@ func newAWSConfig(ctx context.Context, key, secret, region string) (aws.Config,
 		ctx,
 		config.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(key, secret, "")),
 		config.WithRegion(region),
+		config.WithHTTPClient(tracing.NewHTTPClient(nil)),
 	)
 }
@@ -7,7 +7,6 @@ import (
 	"sync"
 	"time"
 
-	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
 	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
@@ -41,7 +40,7 @@ type SKUsClientFactory func(subscriptionID string, credential *azidentity.Client
 
 func defaultSKUsClientFactory(subscriptionID string, credential *azidentity.ClientSecretCredential, cloudConfig cloud.Configuration) (ResourceSKUsAPI, error) {
 	return armcompute.NewResourceSKUsClient(subscriptionID, credential, &arm.ClientOptions{
-		ClientOptions: azcore.ClientOptions{Cloud: cloudConfig},
+		ClientOptions: clientOptions(cloudConfig),
 	})
 }
 
@@ -130,7 +129,7 @@ func (c *AzureCache) fillAll(ctx context.Context) {
 func (c *AzureCache) fillRegionWithRetry(ctx context.Context, creds AzureCredentials, cloudConfig cloud.Configuration, region string) error {
 	credential, err := azidentity.NewClientSecretCredential(creds.TenantID, creds.ClientID, creds.ClientSecret,
 		&azidentity.ClientSecretCredentialOptions{
-			ClientOptions: azcore.ClientOptions{Cloud: cloudConfig},
+			ClientOptions: clientOptions(cloudConfig),
 		},
 	)
 	if err != nil {
@@ -5,16 +5,24 @@ import (
 	"encoding/base64"
 	"fmt"
 	"log/slog"
+	"net/http"
 	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
 	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v6"
 	pkg "github.com/kyma-project/kyma-environment-broker/common/runtime"
 	"github.com/kyma-project/kyma-environment-broker/internal/provider/configuration"
+	"github.com/kyma-project/kyma-environment-broker/internal/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+	"go.opentelemetry.io/otel/trace"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
@@ -40,6 +48,35 @@ type AzureClient struct {
 	cacheLoaded  bool
 }
 
+// clientOptions returns the options of the Azure clients, every attempt of their requests is traced.
+// The default azcore transport is kept, so its TLS, proxy and timeout settings apply.
+func clientOptions(cloudConfig cloud.Configuration) azcore.ClientOptions {
+	return azcore.ClientOptions{Cloud: cloudConfig, PerRetryPolicies: []policy.Policy{tracingPolicy{}}}
+}
+
+// tracingPolicy records a client span for every attempt of a request and propagates the trace context to Azure
+type tracingPolicy struct{}
+
+func (tracingPolicy) Do(req *policy.Request) (*http.Response, error) {
+	raw := req.Raw()
+	ctx, span := tracing.Tracer().Start(raw.Context(), fmt.Sprintf("HTTP %s", raw.Method),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.HTTPRequestMethodKey.String(raw.Method), semconv.URLFull(raw.URL.String())))
+	defer span.End()
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(raw.Header))
+
+	resp, err := req.Next()
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return resp, err
+	}
+	span.SetAttributes(semconv.HTTPResponseStatusCode(resp.StatusCode))
+	if resp.StatusCode >= http.StatusBadRequest {
+		span.SetStatus(codes.Error, resp.Status)
+	}
+	return resp, nil
+}
+
 func NewClientFromSecret(ctx context.Context, providerSpec *configuration.ProviderSpec, secret *unstructured.Unstructured, region string, cloudConfig cloud.Configuration) (*AzureClient, error) {
 	creds, err := ExtractCredentials(secret)
 	if err != nil {
@@ -48,7 +85,7 @@ func NewClientFromSecret(ctx context.Context, providerSpec *configuration.Provid
 
 	credential, err := azidentity.NewClientSecretCredential(creds.TenantID, creds.ClientID, creds.ClientSecret,
 		&azidentity.ClientSecretCredentialOptions{
-			ClientOptions: azcore.ClientOptions{Cloud: cloudConfig},
+			ClientOptions: clientOptions(cloudConfig),
 		},
 	)
 	if err != nil {
@@ -57,7 +94,7 @@ func NewClientFromSecret(ctx context.Context, providerSpec *configuration.Provid
 
 	skusClient, err := armcompute.NewResourceSKUsClient(creds.SubscriptionID, credential,
 		&arm.ClientOptions{
-			ClientOptions: azcore.ClientOptions{Cloud: cloudConfig},
+			ClientOptions: clientOptions(cloudConfig),
 		},
 	)
 	if err != nil {
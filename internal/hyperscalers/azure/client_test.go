@@ -4,14 +4,21 @@ import (
 	"context"
 	"encoding/base64"
 	"fmt"
+	"net/http"
 	"strings"
 	"testing"
 
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
 	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/compute/armcompute/v6"
 	"github.com/kyma-project/kyma-environment-broker/internal/provider/configuration"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace/noop"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
@@ -315,3 +322,41 @@ func (m *callbackMockSKUsAPI) NewListPager(_ *armcompute.ResourceSKUsClientListO
 		},
 	})
 }
+
+func TestClientOptions_TracesRequests(t *testing.T) {
+	// given
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(noop.NewTracerProvider())
+		otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator())
+	})
+	options := clientOptions(cloud.AzurePublic)
+	assert.Nil(t, options.Transport, "the default azcore transport is kept")
+	transport := &fakeTransporter{}
+	options.Transport = transport
+	pipeline := runtime.NewPipeline("test", "v1.0.0", runtime.PipelineOptions{}, &options)
+	req, err := runtime.NewRequest(context.Background(), http.MethodGet, "https://management.azure.com/skus")
+	require.NoError(t, err)
+
+	// when
+	resp, err := pipeline.Do(req)
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "HTTP GET", spans[0].Name())
+	assert.Contains(t, transport.traceparent, spans[0].SpanContext().TraceID().String())
+}
+
+type fakeTransporter struct {
+	traceparent string
+}
+
+func (f *fakeTransporter) Do(req *http.Request) (*http.Response, error) {
+	f.traceparent = req.Header.Get("traceparent")
+	return &http.Response{StatusCode: http.StatusOK, Header: http.Header{}, Body: http.NoBody, Request: req}, nil
+}
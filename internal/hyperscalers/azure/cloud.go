@@ -6,7 +6,6 @@ import (
 	"log/slog"
 	"time"
 
-	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
 	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
@@ -72,7 +71,7 @@ func probeCloud(ctx context.Context, creds AzureCredentials, cfg cloud.Configura
 	credential, err := azidentity.NewClientSecretCredential(
 		creds.TenantID, creds.ClientID, creds.ClientSecret,
 		&azidentity.ClientSecretCredentialOptions{
-			ClientOptions: azcore.ClientOptions{Cloud: cfg},
+			ClientOptions: clientOptions(cfg),
 		},
 	)
 	if err != nil {
@@ -5,15 +5,31 @@ import (
 	"context"
 	"fmt"
 	"text/template"
+	"time"
 
+	gardener "github.com/gardener/gardener/pkg/apis/core/v1beta1"
 	imv1 "github.com/kyma-project/infrastructure-manager/api/v1"
 	"github.com/kyma-project/kyma-environment-broker/internal"
 	"gopkg.in/yaml.v3"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
+// disabledOIDCPrefix is the OIDC prefix value which disables the prefix
+const disabledOIDCPrefix = "-"
+
 type Config struct {
 	AllowOrigins string
+	// TokenEnabled allows authenticated callers to download a kubeconfig with a short-lived service account token
+	TokenEnabled     bool          `envconfig:"default=false"`
+	TokenExpiration  time.Duration `envconfig:"default=1h"`
+	TokenClusterRole string        `envconfig:"default=view"`
+	// TokenGlobalAccountClaim and TokenSubaccountClaim are the token claims which allow callers of the instance's global account
+	// or subaccount to download a kubeconfig with a token, empty values allow only the runtime administrators
+	TokenGlobalAccountClaim string
+	TokenSubaccountClaim    string
+	// RateLimit is the maximum number of kubeconfig downloads of one instance within the RateLimitInterval, 0 disables the limit
+	RateLimit         int           `envconfig:"default=60"`
+	RateLimitInterval time.Duration `envconfig:"default=1h"`
 }
 
 type Builder struct {
@@ -44,6 +60,9 @@ type OIDCConfig struct {
 	Name      string
 	IssuerURL string
 	ClientID  string
+	// UsernamePrefix and GroupsPrefix are added by the API server to the user name and groups authenticated with the config
+	UsernamePrefix string
+	GroupsPrefix   string
 }
 
 func (b *Builder) BuildFromAdminKubeconfigForBinding(runtimeID string, token string, clusterName string) (string, error) {
@@ -100,6 +119,41 @@ func (b *Builder) Build(instance *internal.Instance) (string, error) {
 		return "", fmt.Errorf("while fetching oidc data: %w", err)
 	}
 
+	return b.buildWithOIDCConfigs(instance, kubeCfg, OIDCConfigs)
+}
+
+// BuildFromOIDCConfigForBinding builds a kubeconfig which authenticates with the OIDC exec plugin configured from the Runtime resource.
+// In contrast to Build, it fails if the Runtime resource has no OIDC configuration, because such a kubeconfig has no user.
+// The OIDC configs used in the kubeconfig are returned, so the caller can apply their prefixes to the bound subjects.
+func (b *Builder) BuildFromOIDCConfigForBinding(instance *internal.Instance) (string, []OIDCConfig, error) {
+	if b.kcpClient == nil {
+		return "", nil, fmt.Errorf("KCP client is not configured")
+	}
+	kubeconfigContent, err := b.kubeconfigProvider.KubeconfigForRuntimeID(instance.RuntimeID)
+	if err != nil {
+		return "", nil, err
+	}
+	kubeCfg, err := b.unmarshal(kubeconfigContent)
+	if err != nil {
+		return "", nil, fmt.Errorf("during unmarshal invocation: %w", err)
+	}
+
+	OIDCConfigs, err := b.getOidcDataFromRuntimeResource(instance.RuntimeID, instance.Parameters.Parameters.Name)
+	if err != nil {
+		return "", nil, fmt.Errorf("while fetching oidc data: %w", err)
+	}
+	if len(OIDCConfigs) == 0 {
+		return "", nil, fmt.Errorf("Runtime Resource contains no OIDC config")
+	}
+
+	content, err := b.buildWithOIDCConfigs(instance, kubeCfg, OIDCConfigs)
+	if err != nil {
+		return "", nil, err
+	}
+	return content, OIDCConfigs, nil
+}
+
+func (b *Builder) buildWithOIDCConfigs(instance *internal.Instance, kubeCfg *kubeconfig, OIDCConfigs []OIDCConfig) (string, error) {
 	return b.parseTemplate(kubeconfigData{
 		ContextName: instance.Parameters.Parameters.Name,
 		CAData:      kubeCfg.Clusters[0].Cluster.CertificateAuthorityData,
@@ -179,10 +233,35 @@ func (b *Builder) getOidcDataFromRuntimeResource(id string, currentContext strin
 			name = fmt.Sprintf("%s-%d", currentContext, i+1)
 		}
 		oidcConfigs = append(oidcConfigs, OIDCConfig{
-			Name:      name,
-			IssuerURL: *config.IssuerURL,
-			ClientID:  *config.ClientID,
+			Name:           name,
+			IssuerURL:      *config.IssuerURL,
+			ClientID:       *config.ClientID,
+			UsernamePrefix: usernamePrefix(config.OIDCConfig),
+			GroupsPrefix:   groupsPrefix(config.OIDCConfig),
 		})
 	}
 	return oidcConfigs, nil
 }
+
+// usernamePrefix returns the prefix added by the API server to the OIDC user name. As in the API server,
+// "-" disables the prefix, and without a prefix the issuer URL is used unless the user name is taken from the email claim.
+func usernamePrefix(config gardener.OIDCConfig) string {
+	if config.UsernamePrefix != nil {
+		if *config.UsernamePrefix == disabledOIDCPrefix {
+			return ""
+		}
+		return *config.UsernamePrefix
+	}
+	if config.UsernameClaim != nil && *config.UsernameClaim == "email" {
+		return ""
+	}
+	return *config.IssuerURL + "#"
+}
+
+// groupsPrefix returns the prefix added by the API server to the OIDC groups, "-" disables the prefix
+func groupsPrefix(config gardener.OIDCConfig) string {
+	if config.GroupsPrefix == nil || *config.GroupsPrefix == disabledOIDCPrefix {
+		return ""
+	}
+	return *config.GroupsPrefix
+}
@@ -182,6 +182,117 @@ func TestBuilder_BuildFromRuntimeResource_MultipleAdditionalOIDC(t *testing.T) {
 	})
 }
 
+func TestBuilder_BuildFromOIDCConfigForBinding(t *testing.T) {
+	err := imv1.AddToScheme(scheme.Scheme)
+	assert.NoError(t, err)
+
+	runtimeResource := &imv1.Runtime{}
+	runtimeResource.ObjectMeta.Name = runtimeID
+	runtimeResource.ObjectMeta.Namespace = kcpNamespace
+	runtimeResource.Spec.Shoot.Kubernetes.KubeAPIServer.AdditionalOidcConfig = &[]imv1.OIDCConfig{
+		{
+			OIDCConfig: gardener.OIDCConfig{
+				ClientID:  ptr.String(clientID),
+				IssuerURL: ptr.String(issuerURL),
+			},
+		},
+	}
+	runtimeWithoutOIDC := &imv1.Runtime{}
+	runtimeWithoutOIDC.ObjectMeta.Name = "runtime-without-oidc"
+	runtimeWithoutOIDC.ObjectMeta.Namespace = kcpNamespace
+	runtimeWithoutOIDC.Spec.Shoot.Kubernetes.KubeAPIServer.AdditionalOidcConfig = &[]imv1.OIDCConfig{}
+	runtimeWithPrefixes := &imv1.Runtime{}
+	runtimeWithPrefixes.ObjectMeta.Name = "runtime-with-prefixes"
+	runtimeWithPrefixes.ObjectMeta.Namespace = kcpNamespace
+	runtimeWithPrefixes.Spec.Shoot.Kubernetes.KubeAPIServer.AdditionalOidcConfig = &[]imv1.OIDCConfig{
+		{
+			OIDCConfig: gardener.OIDCConfig{
+				ClientID:       ptr.String(clientID),
+				IssuerURL:      ptr.String(issuerURL),
+				UsernamePrefix: ptr.String("oidc:"),
+				GroupsPrefix:   ptr.String("oidc-groups:"),
+			},
+		},
+		{
+			OIDCConfig: gardener.OIDCConfig{
+				ClientID:       ptr.String(clientID),
+				IssuerURL:      ptr.String(issuerURL),
+				UsernamePrefix: ptr.String("-"),
+				GroupsPrefix:   ptr.String("-"),
+			},
+		},
+		{
+			OIDCConfig: gardener.OIDCConfig{
+				ClientID:      ptr.String(clientID),
+				IssuerURL:     ptr.String(issuerURL),
+				UsernameClaim: ptr.String("email"),
+			},
+		},
+	}
+
+	kcpClient := fake.NewClientBuilder().WithRuntimeObjects(runtimeResource, runtimeWithoutOIDC, runtimeWithPrefixes).Build()
+	builder := NewBuilder(kcpClient, NewFakeKubeconfigProvider(skrKubeconfig()))
+	instance := &internal.Instance{
+		RuntimeID:       runtimeID,
+		GlobalAccountID: globalAccountID,
+		Parameters: internal.ProvisioningParameters{
+			Parameters: pkg.ProvisioningParametersDTO{
+				Name: clusterName,
+			},
+		},
+	}
+
+	t.Run("should build the kubeconfig with the OIDC exec plugin", func(t *testing.T) {
+		// when
+		kubeconfig, oidcConfigs, err := builder.BuildFromOIDCConfigForBinding(instance)
+
+		// then
+		require.NoError(t, err)
+		require.Equal(t, newKubeconfig(), kubeconfig)
+		require.Len(t, oidcConfigs, 1)
+		assert.Equal(t, issuerURL+"#", oidcConfigs[0].UsernamePrefix, "the issuer URL is the default user name prefix")
+		assert.Empty(t, oidcConfigs[0].GroupsPrefix)
+	})
+
+	t.Run("should return the OIDC prefixes of the Runtime resource", func(t *testing.T) {
+		// given
+		withPrefixes := *instance
+		withPrefixes.RuntimeID = "runtime-with-prefixes"
+
+		// when
+		_, oidcConfigs, err := builder.BuildFromOIDCConfigForBinding(&withPrefixes)
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, oidcConfigs, 3)
+		assert.Equal(t, "oidc:", oidcConfigs[0].UsernamePrefix)
+		assert.Equal(t, "oidc-groups:", oidcConfigs[0].GroupsPrefix)
+		assert.Empty(t, oidcConfigs[1].UsernamePrefix)
+		assert.Empty(t, oidcConfigs[1].GroupsPrefix)
+		assert.Empty(t, oidcConfigs[2].UsernamePrefix, "no default prefix for the email claim")
+	})
+
+	t.Run("should fail when the Runtime resource has no OIDC config", func(t *testing.T) {
+		// given
+		withoutOIDC := *instance
+		withoutOIDC.RuntimeID = "runtime-without-oidc"
+
+		// when
+		_, _, err := builder.BuildFromOIDCConfigForBinding(&withoutOIDC)
+
+		// then
+		require.EqualError(t, err, "Runtime Resource contains no OIDC config")
+	})
+
+	t.Run("should fail without the KCP client", func(t *testing.T) {
+		// when
+		_, _, err := NewBuilder(nil, NewFakeKubeconfigProvider(skrKubeconfig())).BuildFromOIDCConfigForBinding(instance)
+
+		// then
+		require.Error(t, err)
+	})
+}
+
 func skrKubeconfig() *string {
 	kc := `
 ---
@@ -1,13 +1,19 @@
 package kubeconfig
 
 import (
+	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
+	"math"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/kennygrant/sanitize"
+	pkg "github.com/kyma-project/kyma-environment-broker/common/runtime"
 	"github.com/kyma-project/kyma-environment-broker/internal"
 	"github.com/kyma-project/kyma-environment-broker/internal/httputil"
 	"github.com/kyma-project/kyma-environment-broker/internal/storage"
@@ -15,7 +21,16 @@ import (
 	"github.com/pivotal-cf/brokerapi/v12/domain"
 )
 
-const attachmentName = "kubeconfig.yaml"
+const (
+	attachmentName = "kubeconfig.yaml"
+
+	oidcKubeconfigType  = "oidc"
+	tokenKubeconfigType = "token"
+	anonymousCaller     = "anonymous"
+
+	// jwtPayloadHeader is the header with the payload of the token verified by the Istio request authentication
+	jwtPayloadHeader = "X-Jwt-Payload"
+)
 
 //go:generate mockery --name=KcBuilder --output=automock --outpkg=automock --case=underscore
 
@@ -30,24 +45,31 @@ type router interface {
 
 type Handler struct {
 	kubeconfigBuilder KcBuilder
-	allowOrigins      string
+	tokenIssuer       TokenIssuer
+	cfg               Config
 	instanceStorage   storage.Instances
 	operationStorage  storage.Operations
+	actions           storage.Actions
+	rateLimiter       *rateLimiter
 	log               *slog.Logger
 }
 
-func NewHandler(storage storage.BrokerStorage, b KcBuilder, origins string, log *slog.Logger) *Handler {
+func NewHandler(storage storage.BrokerStorage, b KcBuilder, tokenIssuer TokenIssuer, cfg Config, log *slog.Logger) *Handler {
 	return &Handler{
 		instanceStorage:   storage.Instances(),
 		operationStorage:  storage.Operations(),
+		actions:           storage.Actions(),
 		kubeconfigBuilder: b,
-		allowOrigins:      origins,
+		tokenIssuer:       tokenIssuer,
+		cfg:               cfg,
+		rateLimiter:       newRateLimiter(cfg.RateLimit, cfg.RateLimitInterval),
 		log:               log,
 	}
 }
 
 func (h *Handler) AttachRoutes(r router) {
 	r.HandleFunc("GET /kubeconfig/{instance_id}", h.GetKubeconfig)
+	r.HandleFunc("GET /kubeconfig/{instance_id}/token", h.GetTokenKubeconfig)
 	r.HandleFunc("GET /kubeconfig/", h.GetKubeconfig)
 }
 
@@ -64,21 +86,94 @@ func (h *Handler) GetKubeconfig(w http.ResponseWriter, r *http.Request) {
 
 	h.specifyAllowOriginHeader(r, w)
 
+	instance, found := h.provisionedInstance(w, instanceID)
+	if !found || !h.allowDownload(w, instanceID) {
+		return
+	}
+
+	newKubeconfig, err := h.kubeconfigBuilder.Build(instance)
+	if err != nil {
+		msgFmt := "while building kubeconfig: %s"
+		if IsNotFound(err) {
+			h.log.Info(fmt.Sprintf(msgFmt, err))
+			h.handleResponse(w, http.StatusNotFound, errors.New("kubeconfig does not exist"))
+			return
+		}
+		h.log.Error(fmt.Sprintf(msgFmt, err))
+		h.handleResponse(w, http.StatusInternalServerError, fmt.Errorf("cannot fetch SKR kubeconfig: %s", err))
+		return
+	}
+
+	caller := anonymousCaller
+	if claims, err := verifiedClaims(r); err == nil && claims.identity() != "" {
+		caller = claims.identity()
+	}
+	h.recordDownload(instanceID, caller, oidcKubeconfigType, fmt.Sprintf("Kubeconfig with OIDC credentials downloaded by %s", caller))
+	writeToResponse(w, newKubeconfig, h.log)
+}
+
+// GetTokenKubeconfig returns a kubeconfig with a short-lived token of the caller. The caller must be a runtime administrator
+// of the instance or belong to its global account or subaccount.
+//
+//	GET /kubeconfig/{instance_id}/token
+func (h *Handler) GetTokenKubeconfig(w http.ResponseWriter, r *http.Request) {
+	instanceID := r.PathValue("instance_id")
+	h.specifyAllowOriginHeader(r, w)
+
+	if !h.cfg.TokenEnabled {
+		h.handleResponse(w, http.StatusBadRequest, fmt.Errorf("kubeconfig with a token is not enabled"))
+		return
+	}
+
+	claims, err := verifiedClaims(r)
+	if err != nil || claims.identity() == "" {
+		h.handleResponse(w, http.StatusUnauthorized, fmt.Errorf("kubeconfig with a token requires an authenticated caller"))
+		return
+	}
+	caller := claims.identity()
+
+	instance, found := h.provisionedInstance(w, instanceID)
+	if !found {
+		return
+	}
+	if !h.authorized(instance, claims) {
+		h.log.Info(fmt.Sprintf("kubeconfig with a token of instance %s denied for %s", instanceID, caller))
+		h.handleResponse(w, http.StatusForbidden, fmt.Errorf("%s is not allowed to get a kubeconfig with a token for instance %s", caller, instanceID))
+		return
+	}
+	if !h.allowDownload(w, instanceID) {
+		return
+	}
+
+	newKubeconfig, expiresAt, err := h.tokenIssuer.Issue(r.Context(), instance, caller, h.cfg.TokenExpiration)
+	if err != nil {
+		h.log.Error(fmt.Sprintf("while issuing a kubeconfig with a token for instance %s: %s", instance.InstanceID, err))
+		h.handleResponse(w, http.StatusInternalServerError, fmt.Errorf("cannot issue SKR kubeconfig with a token: %s", err))
+		return
+	}
+
+	h.recordDownload(instance.InstanceID, caller, tokenKubeconfigType,
+		fmt.Sprintf("Kubeconfig with a token expiring at %s downloaded by %s", expiresAt.UTC().Format(time.RFC3339), caller))
+	writeToResponse(w, newKubeconfig, h.log)
+}
+
+// provisionedInstance returns the instance whose runtime is provisioned, otherwise it writes the error response
+func (h *Handler) provisionedInstance(w http.ResponseWriter, instanceID string) (*internal.Instance, bool) {
 	instance, err := h.instanceStorage.GetByID(instanceID)
 	switch {
 	case err == nil:
 	case dberr.IsNotFound(err):
 		h.handleResponse(w, http.StatusNotFound, fmt.Errorf("instance with ID %s does not exist", instanceID))
-		return
+		return nil, false
 	default:
 		h.log.Error(fmt.Sprintf("while getting instance for a kubeconfig, error: %s", err))
 		h.handleResponse(w, http.StatusInternalServerError, err)
-		return
+		return nil, false
 	}
 
 	if instance.RuntimeID == "" {
 		h.handleResponse(w, http.StatusNotFound, fmt.Errorf("kubeconfig for instance %s does not exist. Provisioning could be in progress, please try again later", instanceID))
-		return
+		return nil, false
 	}
 
 	operation, err := h.operationStorage.GetProvisioningOperationByInstanceID(instanceID)
@@ -86,41 +181,102 @@ func (h *Handler) GetKubeconfig(w http.ResponseWriter, r *http.Request) {
 	case err == nil:
 	case dberr.IsNotFound(err):
 		h.handleResponse(w, http.StatusNotFound, fmt.Errorf("provisioning operation for instance with ID %s does not exist", instanceID))
-		return
+		return nil, false
 	default:
 		h.log.Error(fmt.Sprintf("while getting provision operation for kubeconfig, error: %s", err))
 		h.handleResponse(w, http.StatusInternalServerError, err)
-		return
+		return nil, false
 	}
 
 	if operation.InstanceID != instanceID {
 		h.handleResponse(w, http.StatusBadRequest, fmt.Errorf("mismatch between operation and instance"))
-		return
+		return nil, false
 	}
 
 	switch operation.State {
 	case domain.InProgress, internal.OperationStatePending:
 		h.handleResponse(w, http.StatusNotFound, fmt.Errorf("provisioning operation for instance %s is in progress state, kubeconfig not exist yet, please try again later", instanceID))
-		return
+		return nil, false
 	case domain.Failed:
 		h.handleResponse(w, http.StatusNotFound, fmt.Errorf("provisioning operation for instance %s failed, kubeconfig does not exist", instanceID))
-		return
+		return nil, false
+	}
+	return instance, true
+}
+
+func (h *Handler) allowDownload(w http.ResponseWriter, instanceID string) bool {
+	if allowed, retryAfter := h.rateLimiter.allow(instanceID); !allowed {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+		h.handleResponse(w, http.StatusTooManyRequests, fmt.Errorf("kubeconfig of instance %s was downloaded too many times, please try again later", instanceID))
+		return false
 	}
+	return true
+}
 
-	newKubeconfig, err := h.kubeconfigBuilder.Build(instance)
-	if err != nil {
-		msgFmt := "while building kubeconfig: %s"
-		if IsNotFound(err) {
-			h.log.Info(fmt.Sprintf(msgFmt, err))
-			h.handleResponse(w, http.StatusNotFound, errors.New("kubeconfig does not exist"))
-			return
+// authorized checks if the caller is a runtime administrator of the instance or if the configured account claims
+// of the caller match the global account or the subaccount of the instance
+func (h *Handler) authorized(instance *internal.Instance, claims callerClaims) bool {
+	administrators := instance.Parameters.Parameters.RuntimeAdministrators
+	if len(administrators) == 0 {
+		// the user who created the instance is the default administrator
+		administrators = []string{instance.Parameters.ErsContext.UserID}
+	}
+	for _, administrator := range administrators {
+		if administrator != "" && (administrator == claims.String("email") || administrator == claims.String("sub")) {
+			return true
 		}
-		h.log.Error(fmt.Sprintf(msgFmt, err))
-		h.handleResponse(w, http.StatusInternalServerError, fmt.Errorf("cannot fetch SKR kubeconfig: %s", err))
-		return
 	}
 
-	writeToResponse(w, newKubeconfig, h.log)
+	if h.cfg.TokenGlobalAccountClaim != "" && claims.String(h.cfg.TokenGlobalAccountClaim) == instance.GlobalAccountID && instance.GlobalAccountID != "" {
+		return true
+	}
+	if h.cfg.TokenSubaccountClaim != "" && claims.String(h.cfg.TokenSubaccountClaim) == instance.SubAccountID && instance.SubAccountID != "" {
+		return true
+	}
+	return false
+}
+
+// recordDownload stores the download as a runtime action, a failure is only logged so the caller still gets the kubeconfig
+func (h *Handler) recordDownload(instanceID, caller, kubeconfigType, message string) {
+	h.log.Info(fmt.Sprintf("kubeconfig (%s) of instance %s downloaded by %s", kubeconfigType, instanceID, caller))
+	err := h.actions.InsertAction(pkg.KubeconfigDownloadActionType, instanceID, message, "", kubeconfigType)
+	if err != nil {
+		h.log.Error(fmt.Sprintf("while recording the kubeconfig download of instance %s: %s", instanceID, err))
+	}
+}
+
+// callerClaims are the claims of the caller's token
+type callerClaims map[string]interface{}
+
+func (c callerClaims) String(name string) string {
+	value, _ := c[name].(string)
+	return value
+}
+
+// identity returns the e-mail or the subject of the caller
+func (c callerClaims) identity() string {
+	if email := c.String("email"); email != "" {
+		return email
+	}
+	return c.String("sub")
+}
+
+// verifiedClaims returns the claims of the token verified by Istio. Istio sets the JWT payload header only for a valid token,
+// and the header sent by the client is removed by the virtual service, so the header cannot be forged.
+func verifiedClaims(r *http.Request) (callerClaims, error) {
+	payload := r.Header.Get(jwtPayloadHeader)
+	if payload == "" {
+		return nil, fmt.Errorf("the request has no verified token")
+	}
+	decoded, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(payload, "="))
+	if err != nil {
+		return nil, fmt.Errorf("while decoding the token payload: %w", err)
+	}
+	claims := callerClaims{}
+	if err := json.Unmarshal(decoded, &claims); err != nil {
+		return nil, fmt.Errorf("while unmarshalling the token payload: %w", err)
+	}
+	return claims, nil
 }
 
 func (h *Handler) handleResponse(w http.ResponseWriter, code int, err error) {
@@ -138,12 +294,12 @@ func (h *Handler) specifyAllowOriginHeader(r *http.Request, w http.ResponseWrite
 		return
 	}
 
-	if h.allowOrigins == "*" {
+	if h.cfg.AllowOrigins == "*" {
 		w.Header().Set("Access-Control-Allow-Origin", "*")
 		return
 	}
 
-	for _, o := range strings.Split(h.allowOrigins, ",") {
+	for _, o := range strings.Split(h.cfg.AllowOrigins, ",") {
 		if o == origin {
 			w.Header().Set("Access-Control-Allow-Origin", sanitize.HTML(origin))
 			return
@@ -1,6 +1,8 @@
 package kubeconfig
 
 import (
+	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -9,12 +11,17 @@ import (
 	"net/http/httptest"
 	"os"
 	"testing"
+	"time"
 
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/kyma-project/kyma-environment-broker/common/runtime"
 	"github.com/kyma-project/kyma-environment-broker/internal"
 	"github.com/kyma-project/kyma-environment-broker/internal/httputil"
 	"github.com/kyma-project/kyma-environment-broker/internal/kubeconfig/automock"
 	"github.com/kyma-project/kyma-environment-broker/internal/storage"
 	"github.com/pivotal-cf/brokerapi/v12/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
@@ -22,6 +29,8 @@ const (
 	instanceID        = "93241a34-8ab5-4f10-978e-eaa6f8ad551c"
 	operationID       = "306f2406-e972-4fae-8edd-50fc50e56817"
 	instanceRuntimeID = "e04813ba-244a-4150-8670-506c37959388"
+
+	instanceSubAccountID = "5b0e4b8e-30a6-4c4a-b7a4-3d0c2b1f7e21"
 )
 
 func TestHandler_GetKubeconfig(t *testing.T) {
@@ -132,7 +141,7 @@ func TestHandler_GetKubeconfig(t *testing.T) {
 
 			router := httputil.NewRouter()
 
-			handler := NewHandler(db, builder, "", log)
+			handler := NewHandler(db, builder, nil, Config{}, log)
 			handler.AttachRoutes(router)
 
 			server := httptest.NewServer(router)
@@ -214,7 +223,7 @@ func TestHandler_specifyAllowOriginHeader(t *testing.T) {
 			request := &http.Request{Header: d.requestHeader}
 			response := &httptest.ResponseRecorder{}
 
-			handler := NewHandler(storage.NewMemoryStorage(), nil, d.origins, nil)
+			handler := NewHandler(storage.NewMemoryStorage(), nil, nil, Config{AllowOrigins: d.origins}, nil)
 
 			// when
 			handler.specifyAllowOriginHeader(request, response)
@@ -229,3 +238,258 @@ func TestHandler_specifyAllowOriginHeader(t *testing.T) {
 		})
 	}
 }
+
+func TestHandler_KubeconfigDownloads(t *testing.T) {
+	t.Run("should record the download of the OIDC kubeconfig", func(t *testing.T) {
+		// given
+		db, instance := fixProvisionedInstance(t)
+		builder := &automock.KcBuilder{}
+		builder.On("Build", mock.Anything).Return("--kubeconfig file", nil)
+		server := fixKubeconfigServer(db, builder, nil, Config{})
+
+		// when
+		response := getKubeconfig(t, server.URL, "", fixVerifiedPayload(t, map[string]interface{}{"sub": "user-id", "email": "john.doe@example.com"}))
+
+		// then
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+		actions, err := db.Actions().ListActionsByInstanceID(instance.InstanceID)
+		require.NoError(t, err)
+		require.Len(t, actions, 1)
+		assert.Equal(t, runtime.KubeconfigDownloadActionType, actions[0].Type)
+		assert.Equal(t, oidcKubeconfigType, actions[0].NewValue)
+		assert.Contains(t, actions[0].Message, "john.doe@example.com")
+	})
+
+	t.Run("should record an anonymous download", func(t *testing.T) {
+		// given
+		db, instance := fixProvisionedInstance(t)
+		builder := &automock.KcBuilder{}
+		builder.On("Build", mock.Anything).Return("--kubeconfig file", nil)
+		server := fixKubeconfigServer(db, builder, nil, Config{})
+
+		// when
+		response := getKubeconfig(t, server.URL, "", "")
+
+		// then
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+		actions, err := db.Actions().ListActionsByInstanceID(instance.InstanceID)
+		require.NoError(t, err)
+		require.Len(t, actions, 1)
+		assert.Contains(t, actions[0].Message, anonymousCaller)
+	})
+
+	t.Run("should return the kubeconfig with a token to the runtime administrator", func(t *testing.T) {
+		// given
+		db, instance := fixProvisionedInstance(t)
+		issuer := &fakeTokenIssuer{kubeconfig: "--token kubeconfig", expiresAt: time.Now().Add(time.Hour)}
+		server := fixKubeconfigServer(db, nil, issuer, Config{TokenEnabled: true, TokenExpiration: time.Hour})
+
+		// when
+		response := getKubeconfig(t, server.URL, tokenKubeconfigType, fixVerifiedPayload(t, map[string]interface{}{"sub": "user-id", "email": "admin@example.com"}))
+
+		// then
+		require.Equal(t, http.StatusOK, response.StatusCode)
+		body, err := io.ReadAll(response.Body)
+		require.NoError(t, err)
+		assert.Equal(t, "--token kubeconfig", string(body))
+		assert.Equal(t, time.Hour, issuer.expiration)
+		assert.Equal(t, "admin@example.com", issuer.caller)
+
+		actions, err := db.Actions().ListActionsByInstanceID(instance.InstanceID)
+		require.NoError(t, err)
+		require.Len(t, actions, 1)
+		assert.Equal(t, tokenKubeconfigType, actions[0].NewValue)
+		assert.Contains(t, actions[0].Message, "admin@example.com")
+	})
+
+	t.Run("should return the kubeconfig with a token to the caller of the instance subaccount", func(t *testing.T) {
+		// given
+		db, _ := fixProvisionedInstance(t)
+		issuer := &fakeTokenIssuer{kubeconfig: "--token kubeconfig", expiresAt: time.Now().Add(time.Hour)}
+		server := fixKubeconfigServer(db, nil, issuer, Config{TokenEnabled: true, TokenExpiration: time.Hour, TokenSubaccountClaim: "subaccount_id"})
+
+		// when
+		response := getKubeconfig(t, server.URL, tokenKubeconfigType, fixVerifiedPayload(t, map[string]interface{}{"sub": "user-id", "subaccount_id": instanceSubAccountID}))
+
+		// then
+		assert.Equal(t, http.StatusOK, response.StatusCode)
+		assert.Equal(t, "user-id", issuer.caller)
+	})
+
+	t.Run("should reject the kubeconfig with a token for a caller of another account", func(t *testing.T) {
+		// given
+		db, instance := fixProvisionedInstance(t)
+		issuer := &fakeTokenIssuer{}
+		server := fixKubeconfigServer(db, nil, issuer, Config{TokenEnabled: true, TokenGlobalAccountClaim: "global_account_id", TokenSubaccountClaim: "subaccount_id"})
+
+		// when
+		response := getKubeconfig(t, server.URL, tokenKubeconfigType, fixVerifiedPayload(t, map[string]interface{}{
+			"email":             "john.doe@example.com",
+			"global_account_id": "other-global-account",
+			"subaccount_id":     "other-subaccount",
+		}))
+
+		// then
+		assert.Equal(t, http.StatusForbidden, response.StatusCode)
+		assert.Empty(t, issuer.caller, "no token is issued")
+		actions, err := db.Actions().ListActionsByInstanceID(instance.InstanceID)
+		require.NoError(t, err)
+		assert.Empty(t, actions)
+	})
+
+	t.Run("should reject the kubeconfig with a token for an anonymous caller", func(t *testing.T) {
+		// given
+		db, _ := fixProvisionedInstance(t)
+		server := fixKubeconfigServer(db, nil, &fakeTokenIssuer{}, Config{TokenEnabled: true})
+
+		// when
+		response := getKubeconfig(t, server.URL, tokenKubeconfigType, "")
+
+		// then
+		assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+	})
+
+	t.Run("should ignore the unverified bearer token", func(t *testing.T) {
+		// given
+		db, _ := fixProvisionedInstance(t)
+		server := fixKubeconfigServer(db, nil, &fakeTokenIssuer{}, Config{TokenEnabled: true})
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"email": "admin@example.com"}).SignedString([]byte("secret"))
+		require.NoError(t, err)
+		request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("%s/kubeconfig/%s/token", server.URL, instanceID), nil)
+		require.NoError(t, err)
+		request.Header.Set("Authorization", "Bearer "+token)
+
+		// when
+		response, err := http.DefaultClient.Do(request)
+		require.NoError(t, err)
+		defer response.Body.Close()
+
+		// then
+		assert.Equal(t, http.StatusUnauthorized, response.StatusCode)
+	})
+
+	t.Run("should reject the kubeconfig with a token when disabled", func(t *testing.T) {
+		// given
+		db, _ := fixProvisionedInstance(t)
+		server := fixKubeconfigServer(db, nil, &fakeTokenIssuer{}, Config{})
+
+		// when
+		response := getKubeconfig(t, server.URL, tokenKubeconfigType, fixVerifiedPayload(t, map[string]interface{}{"email": "admin@example.com"}))
+
+		// then
+		assert.Equal(t, http.StatusBadRequest, response.StatusCode)
+	})
+
+	t.Run("should limit the number of downloads", func(t *testing.T) {
+		// given
+		db, instance := fixProvisionedInstance(t)
+		builder := &automock.KcBuilder{}
+		builder.On("Build", mock.Anything).Return("--kubeconfig file", nil)
+		server := fixKubeconfigServer(db, builder, nil, Config{RateLimit: 2, RateLimitInterval: time.Hour})
+
+		// when
+		first := getKubeconfig(t, server.URL, "", "")
+		second := getKubeconfig(t, server.URL, "", "")
+		third := getKubeconfig(t, server.URL, "", "")
+
+		// then
+		assert.Equal(t, http.StatusOK, first.StatusCode)
+		assert.Equal(t, http.StatusOK, second.StatusCode)
+		assert.Equal(t, http.StatusTooManyRequests, third.StatusCode)
+		assert.NotEmpty(t, third.Header.Get("Retry-After"))
+
+		actions, err := db.Actions().ListActionsByInstanceID(instance.InstanceID)
+		require.NoError(t, err)
+		assert.Len(t, actions, 2)
+	})
+}
+
+func TestRateLimiter(t *testing.T) {
+	// given
+	now := time.Now()
+	limiter := newRateLimiter(1, time.Minute)
+	limiter.now = func() time.Time { return now }
+
+	// when
+	allowed, _ := limiter.allow("instance-1")
+	rejected, retryAfter := limiter.allow("instance-1")
+	other, _ := limiter.allow("instance-2")
+
+	// then
+	assert.True(t, allowed)
+	assert.False(t, rejected)
+	assert.Equal(t, time.Minute, retryAfter)
+	assert.True(t, other)
+
+	// when
+	now = now.Add(time.Minute)
+	allowed, _ = limiter.allow("instance-1")
+
+	// then
+	assert.True(t, allowed)
+}
+
+type fakeTokenIssuer struct {
+	kubeconfig string
+	expiresAt  time.Time
+	expiration time.Duration
+	caller     string
+}
+
+func (f *fakeTokenIssuer) Issue(_ context.Context, _ *internal.Instance, caller string, expiration time.Duration) (string, time.Time, error) {
+	f.expiration = expiration
+	f.caller = caller
+	return f.kubeconfig, f.expiresAt, nil
+}
+
+func fixProvisionedInstance(t *testing.T) (storage.BrokerStorage, internal.Instance) {
+	db := storage.NewMemoryStorage()
+	instance := internal.Instance{
+		InstanceID:      instanceID,
+		RuntimeID:       instanceRuntimeID,
+		GlobalAccountID: globalAccountID,
+		SubAccountID:    instanceSubAccountID,
+		Parameters: internal.ProvisioningParameters{
+			Parameters: runtime.ProvisioningParametersDTO{RuntimeAdministrators: []string{"admin@example.com"}},
+		},
+	}
+	require.NoError(t, db.Instances().Insert(instance))
+	require.NoError(t, db.Operations().InsertProvisioningOperation(internal.ProvisioningOperation{
+		Operation: internal.Operation{
+			ID:         operationID,
+			InstanceID: instanceID,
+			State:      domain.Succeeded,
+			Type:       internal.OperationTypeProvision,
+		},
+	}))
+	return db, instance
+}
+
+func fixKubeconfigServer(db storage.BrokerStorage, builder KcBuilder, issuer TokenIssuer, cfg Config) *httptest.Server {
+	router := httputil.NewRouter()
+	NewHandler(db, builder, issuer, cfg, slog.New(slog.NewTextHandler(os.Stdout, nil))).AttachRoutes(router)
+	return httptest.NewServer(router)
+}
+
+func getKubeconfig(t *testing.T, serverURL, kubeconfigType, verifiedPayload string) *http.Response {
+	url := fmt.Sprintf("%s/kubeconfig/%s", serverURL, instanceID)
+	if kubeconfigType == tokenKubeconfigType {
+		url += "/token"
+	}
+	request, err := http.NewRequest(http.MethodGet, url, nil)
+	require.NoError(t, err)
+	if verifiedPayload != "" {
+		request.Header.Set(jwtPayloadHeader, verifiedPayload)
+	}
+	response, err := http.DefaultClient.Do(request)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = response.Body.Close() })
+	return response
+}
+
+// fixVerifiedPayload returns the token payload as set by Istio in the header
+func fixVerifiedPayload(t *testing.T, claims map[string]interface{}) string {
+	payload, err := json.Marshal(claims)
+	require.NoError(t, err)
+	return base64.RawURLEncoding.EncodeToString(payload)
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/kyma-project/kyma-environment-broker/internal/tracing"
+
 	authv1 "k8s.io/api/authentication/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	machineryv1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -67,7 +69,7 @@ func (p *SecretProvider) K8sClientForRuntimeID(runtimeID string) (client.Client,
 		return nil, fmt.Errorf("while creating rest config from kubeconfig")
 	}
 
-	k8sCli, err := client.New(restCfg, client.Options{
+	k8sCli, err := client.New(tracing.WrapRestConfig(restCfg), client.Options{
 		Scheme: scheme.Scheme,
 	})
 	if err != nil {
@@ -88,7 +90,7 @@ func (p *SecretProvider) K8sClientSetForRuntimeID(runtimeID string) (kubernetes.
 		return nil, fmt.Errorf("while creating k8s client set - rest config from kubeconfig")
 	}
 
-	clientset, err := kubernetes.NewForConfig(restCfg)
+	clientset, err := kubernetes.NewForConfig(tracing.WrapRestConfig(restCfg))
 	if err != nil {
 		return nil, fmt.Errorf("while creating k8s client set")
 	}
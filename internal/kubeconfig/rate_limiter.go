@@ -0,0 +1,63 @@
+package kubeconfig
+
+import (
+	"sync"
+	"time"
+)
+
+// rateLimiter counts kubeconfig downloads of each instance in fixed windows
+type rateLimiter struct {
+	limit    int
+	interval time.Duration
+	now      func() time.Time
+
+	mu      sync.Mutex
+	windows map[string]*downloadWindow
+}
+
+type downloadWindow struct {
+	start     time.Time
+	downloads int
+}
+
+func newRateLimiter(limit int, interval time.Duration) *rateLimiter {
+	return &rateLimiter{
+		limit:    limit,
+		interval: interval,
+		now:      time.Now,
+		windows:  map[string]*downloadWindow{},
+	}
+}
+
+// allow registers a download of the instance kubeconfig. When the limit is reached, it returns false and the time after which
+// the next download is allowed.
+func (l *rateLimiter) allow(instanceID string) (bool, time.Duration) {
+	if l.limit <= 0 {
+		return true, 0
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.removeExpiredWindows(now)
+
+	window, found := l.windows[instanceID]
+	if !found {
+		window = &downloadWindow{start: now}
+		l.windows[instanceID] = window
+	}
+	if window.downloads >= l.limit {
+		return false, window.start.Add(l.interval).Sub(now)
+	}
+	window.downloads++
+	return true, 0
+}
+
+func (l *rateLimiter) removeExpiredWindows(now time.Time) {
+	for instanceID, window := range l.windows {
+		if !now.Before(window.start.Add(l.interval)) {
+			delete(l.windows, instanceID)
+		}
+	}
+}
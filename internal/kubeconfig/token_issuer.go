@@ -0,0 +1,157 @@
+package kubeconfig
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/kyma-project/kyma-environment-broker/internal"
+
+	authv1 "k8s.io/api/authentication/v1"
+	v1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	mv1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	tokenServiceAccountPrefix    = "kyma-kubeconfig-"
+	tokenServiceAccountNamespace = "kyma-system"
+	callerAnnotationKey          = "kyma-project.io/kubeconfig-caller"
+	managedByLabelKey            = "app.kubernetes.io/managed-by"
+	managedByLabelValue          = "kcp-kyma-environment-broker"
+)
+
+// TokenIssuer builds a kubeconfig with a short-lived token of the caller for the runtime of the instance
+type TokenIssuer interface {
+	Issue(ctx context.Context, instance *internal.Instance, caller string, expiration time.Duration) (string, time.Time, error)
+}
+
+type clientSetProvider interface {
+	K8sClientSetForRuntimeID(runtimeID string) (kubernetes.Interface, error)
+}
+
+// ServiceAccountTokenIssuer requests tokens of a service account of the caller bound to the configured cluster role on the runtime.
+// Every caller has its own service account, so the runtime audit log shows who used the token.
+type ServiceAccountTokenIssuer struct {
+	clientProvider clientSetProvider
+	builder        *Builder
+	clusterRole    string
+}
+
+func NewServiceAccountTokenIssuer(clientProvider clientSetProvider, builder *Builder, clusterRole string) *ServiceAccountTokenIssuer {
+	return &ServiceAccountTokenIssuer{
+		clientProvider: clientProvider,
+		builder:        builder,
+		clusterRole:    clusterRole,
+	}
+}
+
+func (i *ServiceAccountTokenIssuer) Issue(ctx context.Context, instance *internal.Instance, caller string, expiration time.Duration) (string, time.Time, error) {
+	clientset, err := i.clientProvider.K8sClientSetForRuntimeID(instance.RuntimeID)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("while creating a runtime client: %w", err)
+	}
+
+	name := tokenServiceAccountName(caller)
+	err = i.ensureServiceAccount(ctx, clientset, name, caller)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	err = i.ensureClusterRoleBinding(ctx, clientset, name, caller)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	expirationSeconds := int64(expiration.Seconds())
+	tokenRequest, err := clientset.CoreV1().ServiceAccounts(tokenServiceAccountNamespace).CreateToken(ctx, name,
+		&authv1.TokenRequest{
+			ObjectMeta: mv1.ObjectMeta{
+				Name:      name,
+				Namespace: tokenServiceAccountNamespace,
+			},
+			Spec: authv1.TokenRequestSpec{
+				ExpirationSeconds: &expirationSeconds,
+			},
+		}, mv1.CreateOptions{})
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("while creating a service account token: %w", err)
+	}
+
+	kubeconfig, err := i.builder.BuildFromAdminKubeconfigForBinding(instance.RuntimeID, tokenRequest.Status.Token, instance.Parameters.Parameters.Name)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("while building a kubeconfig: %w", err)
+	}
+
+	expiresAt := tokenRequest.Status.ExpirationTimestamp.Time
+	if expiresAt.IsZero() {
+		expiresAt = time.Now().Add(expiration)
+	}
+	return kubeconfig, expiresAt, nil
+}
+
+// tokenServiceAccountName returns the name of the service account of the caller, the caller identity is hashed
+// because e-mails are not valid resource names
+func tokenServiceAccountName(caller string) string {
+	hash := sha256.Sum256([]byte(caller))
+	return tokenServiceAccountPrefix + hex.EncodeToString(hash[:8])
+}
+
+func (i *ServiceAccountTokenIssuer) ensureServiceAccount(ctx context.Context, clientset kubernetes.Interface, name, caller string) error {
+	_, err := clientset.CoreV1().ServiceAccounts(tokenServiceAccountNamespace).Create(ctx, &v1.ServiceAccount{
+		ObjectMeta: mv1.ObjectMeta{
+			Name:        name,
+			Namespace:   tokenServiceAccountNamespace,
+			Labels:      map[string]string{managedByLabelKey: managedByLabelValue},
+			Annotations: map[string]string{callerAnnotationKey: caller},
+		},
+	}, mv1.CreateOptions{})
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("while creating a service account: %w", err)
+	}
+	return nil
+}
+
+// ensureClusterRoleBinding creates the cluster role binding of the service account, the binding is recreated when the
+// configured cluster role changed because the role reference is immutable
+func (i *ServiceAccountTokenIssuer) ensureClusterRoleBinding(ctx context.Context, clientset kubernetes.Interface, name, caller string) error {
+	existing, err := clientset.RbacV1().ClusterRoleBindings().Get(ctx, name, mv1.GetOptions{})
+	switch {
+	case err == nil && existing.RoleRef.Name == i.clusterRole:
+		return nil
+	case err == nil:
+		err = clientset.RbacV1().ClusterRoleBindings().Delete(ctx, name, mv1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("while removing an outdated cluster role binding: %w", err)
+		}
+	case !apierrors.IsNotFound(err):
+		return fmt.Errorf("while getting a cluster role binding: %w", err)
+	}
+
+	_, err = clientset.RbacV1().ClusterRoleBindings().Create(ctx, &rbacv1.ClusterRoleBinding{
+		ObjectMeta: mv1.ObjectMeta{
+			Name:        name,
+			Labels:      map[string]string{managedByLabelKey: managedByLabelValue},
+			Annotations: map[string]string{callerAnnotationKey: caller},
+		},
+		RoleRef: rbacv1.RoleRef{
+			APIGroup: rbacv1.GroupName,
+			Kind:     "ClusterRole",
+			Name:     i.clusterRole,
+		},
+		Subjects: []rbacv1.Subject{
+			{
+				Kind:      rbacv1.ServiceAccountKind,
+				Name:      name,
+				Namespace: tokenServiceAccountNamespace,
+			},
+		},
+	}, mv1.CreateOptions{})
+	if err != nil && !apierrors.IsAlreadyExists(err) {
+		return fmt.Errorf("while creating a cluster role binding: %w", err)
+	}
+	return nil
+}
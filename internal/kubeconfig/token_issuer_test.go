@@ -0,0 +1,56 @@
+package kubeconfig
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/kyma-project/kyma-environment-broker/internal/fixture"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	mv1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestServiceAccountTokenIssuer_Issue(t *testing.T) {
+	// given
+	provider := NewFakeK8sClientProvider(nil)
+	builder := NewBuilder(nil, provider)
+	instance := fixture.FixInstance(instanceID)
+	instance.RuntimeID = instanceRuntimeID
+
+	// when
+	kubeconfig, expiresAt, err := NewServiceAccountTokenIssuer(provider, builder, "view").Issue(context.Background(), &instance, "john.doe@example.com", time.Hour)
+
+	// then
+	require.NoError(t, err)
+	assert.Contains(t, kubeconfig, "server: https://my.cluster")
+	assert.WithinDuration(t, time.Now().Add(time.Hour), expiresAt, time.Minute)
+
+	clientset, _ := provider.K8sClientSetForRuntimeID(instanceRuntimeID)
+	name := tokenServiceAccountName("john.doe@example.com")
+	crb, err := clientset.RbacV1().ClusterRoleBindings().Get(context.Background(), name, mv1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "view", crb.RoleRef.Name)
+	assert.Equal(t, "john.doe@example.com", crb.Annotations[callerAnnotationKey])
+	assert.Equal(t, name, crb.Subjects[0].Name)
+	assert.Equal(t, managedByLabelValue, crb.Labels[managedByLabelKey])
+
+	// when
+	_, _, err = NewServiceAccountTokenIssuer(provider, builder, "view").Issue(context.Background(), &instance, "jane.doe@example.com", time.Hour)
+
+	// then
+	require.NoError(t, err)
+	assert.NotEqual(t, name, tokenServiceAccountName("jane.doe@example.com"), "every caller has its own service account")
+	_, err = clientset.RbacV1().ClusterRoleBindings().Get(context.Background(), tokenServiceAccountName("jane.doe@example.com"), mv1.GetOptions{})
+	require.NoError(t, err)
+
+	// when
+	_, _, err = NewServiceAccountTokenIssuer(provider, builder, "edit").Issue(context.Background(), &instance, "john.doe@example.com", time.Hour)
+
+	// then
+	require.NoError(t, err)
+	crb, err = clientset.RbacV1().ClusterRoleBindings().Get(context.Background(), name, mv1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "edit", crb.RoleRef.Name)
+}
@@ -63,10 +63,12 @@ func (c *BindDurationCollector) OnUnbindingExecuted(ctx context.Context, ev inte
 
 type BindingCreationCollector struct {
 	bindingCreated *prometheus.CounterVec
+	bindingRenewed *prometheus.CounterVec
 }
 
-// NewBindingCreationCollector provides a counter which shows the total number of created bindings:
+// NewBindingCreationCollector provides counters which show the total number of created and renewed bindings:
 // - kcp_keb_v2_binding_created_total{plan_id}
+// - kcp_keb_v2_binding_renewed_total{plan_id}
 func NewBindingCreationCollector() *BindingCreationCollector {
 	return &BindingCreationCollector{
 		bindingCreated: prometheus.NewCounterVec(prometheus.CounterOpts{
@@ -75,15 +77,23 @@ func NewBindingCreationCollector() *BindingCreationCollector {
 			Name:      "binding_created_total",
 			Help:      "The total number of created bindings",
 		}, []string{"plan_id"}),
+		bindingRenewed: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: prometheusNamespaceV2,
+			Subsystem: prometheusSubsystemV2,
+			Name:      "binding_renewed_total",
+			Help:      "The total number of renewed binding tokens",
+		}, []string{"plan_id"}),
 	}
 }
 
 func (c *BindingCreationCollector) Describe(ch chan<- *prometheus.Desc) {
 	c.bindingCreated.Describe(ch)
+	c.bindingRenewed.Describe(ch)
 }
 
 func (c *BindingCreationCollector) Collect(ch chan<- prometheus.Metric) {
 	c.bindingCreated.Collect(ch)
+	c.bindingRenewed.Collect(ch)
 }
 
 func (c *BindingCreationCollector) OnBindingCreated(ctx context.Context, ev interface{}) error {
@@ -92,6 +102,12 @@ func (c *BindingCreationCollector) OnBindingCreated(ctx context.Context, ev inte
 	return nil
 }
 
+func (c *BindingCreationCollector) OnBindingRenewed(ctx context.Context, ev interface{}) error {
+	obj := ev.(broker.BindingRenewed)
+	c.bindingRenewed.WithLabelValues(obj.PlanID).Inc()
+	return nil
+}
+
 type BindingStatitics struct {
 	db     storage.Bindings
 	logger *slog.Logger
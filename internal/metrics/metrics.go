@@ -88,6 +88,7 @@ func Register(ctx context.Context, sub event.Subscriber, db storage.BrokerStorag
 	sub.Subscribe(broker.BindRequestProcessed{}, bindDurationCollector.OnBindingExecuted)
 	sub.Subscribe(broker.UnbindRequestProcessed{}, bindDurationCollector.OnUnbindingExecuted)
 	sub.Subscribe(broker.BindingCreated{}, bindCrestedCollector.OnBindingCreated)
+	sub.Subscribe(broker.BindingRenewed{}, bindCrestedCollector.OnBindingRenewed)
 
 	credentialsBindingsCollector := NewCredentialsBindingsCollector(db.Instances(), gardenerClient, cfg.CredentialsBindingsPollingInterval, cfg.AvailableCredentialsBindingsPollingInterval, logger)
 	credentialsBindingsCollector.StartCollector(ctx)
@@ -47,6 +47,8 @@ type Instance struct {
 	Provider     pkg.CloudProvider
 	Reconcilable bool
 	EmptyUpdates int
+	// ExpirationExtensions is the number of times the expiration of the trial or free instance was extended
+	ExpirationExtensions int
 }
 
 type InstanceWithSubaccountState struct {
@@ -59,6 +61,11 @@ func (i *Instance) IsExpired() bool {
 	return i.ExpiredAt != nil
 }
 
+// ExpirationTime returns the time when the trial or free instance expires, including the extensions
+func (i *Instance) ExpirationTime(expirationPeriod, extensionPeriod time.Duration) time.Time {
+	return i.CreatedAt.Add(expirationPeriod + time.Duration(i.ExpirationExtensions)*extensionPeriod)
+}
+
 func (i *Instance) GetSubscriptionGlobalAccoundID() string {
 	if i.SubscriptionGlobalAccountID != "" {
 		return i.SubscriptionGlobalAccountID
@@ -164,6 +171,14 @@ type Operation struct {
 	// RuntimeResourceCreatedAt stores when the broker started tracking the Runtime CR provisioning readiness retries.
 	// Used for timeout calculation that starts from the first runtime provisioning check, not OSB request arrival.
 	RuntimeResourceCreatedAt *time.Time `json:"runtimeResourceCreatedAt,omitempty"`
+
+	// RetriedAt stores when a failed operation was resumed by an administrator.
+	// Used as the start of the operation timeout and of the step retry timeouts instead of the operation creation time.
+	RetriedAt *time.Time `json:"retriedAt,omitempty"`
+
+	// DeferredUntil stores when the maintenance window opens for an update operation deferred to the instance maintenance window.
+	// Used as the start of the operation timeout instead of the operation creation time.
+	DeferredUntil *time.Time `json:"deferredUntil,omitempty"`
 }
 
 // ProviderValues contains values which are specific to particular plans (and provisioning parameters)
@@ -192,6 +207,18 @@ func (o *Operation) IsFinished() bool {
 	return o.State != OperationStateInProgress && o.State != OperationStatePending && o.State != OperationStateCanceling && o.State != OperationStateRetrying
 }
 
+// ProcessingStartedAt returns the time from which the operation processing timeout is calculated
+func (o *Operation) ProcessingStartedAt() time.Time {
+	startedAt := o.CreatedAt
+	if o.RetriedAt != nil && o.RetriedAt.After(startedAt) {
+		startedAt = *o.RetriedAt
+	}
+	if o.DeferredUntil != nil && o.DeferredUntil.After(startedAt) {
+		startedAt = *o.DeferredUntil
+	}
+	return startedAt
+}
+
 func (o *Operation) EventInfof(fmt string, args ...any) {
 	events.Infof(o.InstanceID, o.ID, fmt, args...)
 }
@@ -437,6 +464,10 @@ func NewUpdateOperation(operationID string, instance *Instance, updatingParams U
 		op.ProvisioningParameters.Parameters.AdditionalWorkerNodePools = updatingParams.AdditionalWorkerNodePools
 	}
 
+	if updatingParams.MaintenanceWindow != nil {
+		op.ProvisioningParameters.Parameters.MaintenanceWindow = updatingParams.MaintenanceWindow
+	}
+
 	return op
 }
 
@@ -506,6 +537,24 @@ type SubaccountState struct {
 	BetaEnabled       string `json:"betaEnabled"`
 	UsedForProduction string `json:"usedForProduction"`
 	ModifiedAt        int64  `json:"modifiedAt"`
+	// Attributes are the values of the additional subaccount attributes synchronized with labels
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+// SubaccountSyncCursor is the progress of the subaccount sync, all times are in milliseconds since the epoch
+type SubaccountSyncCursor struct {
+	LastEventID         int64 `json:"lastEventId"`
+	LastEventActionTime int64 `json:"lastEventActionTime"`
+	EventsSyncedAt      int64 `json:"eventsSyncedAt"`
+	AccountsSyncedAt    int64 `json:"accountsSyncedAt"`
+}
+
+// SubaccountSyncEvent is a CIS event applied by the subaccount sync
+type SubaccountSyncEvent struct {
+	ID           int64
+	SubaccountID string
+	ActionTime   int64
+	ProcessedAt  int64
 }
 
 type DeletedStats struct {
@@ -524,6 +573,100 @@ type Binding struct {
 	Kubeconfig        string
 	ExpirationSeconds int64
 	CreatedBy         string
+	Scope             BindingScope
+
+	// State and Description report the progress of the binding creation to the last_binding_operation endpoint,
+	// bindings created before the asynchronous creation was introduced have an empty state
+	State       domain.LastOperationState
+	Description string
+}
+
+type BindingProfile string
+
+const (
+	// BindingProfileClusterAdmin grants all verbs on all resources in the cluster
+	BindingProfileClusterAdmin BindingProfile = "cluster-admin"
+	// BindingProfileReadOnly grants read verbs on all resources in the cluster
+	BindingProfileReadOnly BindingProfile = "read-only"
+	// BindingProfileNamespaceAdmin grants all verbs on all resources in the listed namespaces
+	BindingProfileNamespaceAdmin BindingProfile = "namespace-admin"
+	// BindingProfileClusterRole binds an existing ClusterRole in the cluster or in the listed namespaces
+	BindingProfileClusterRole BindingProfile = "cluster-role"
+)
+
+type BindingCredentialType string
+
+const (
+	// BindingCredentialTypeServiceAccount authenticates with a token of a service account created for the binding
+	BindingCredentialTypeServiceAccount BindingCredentialType = "service_account"
+	// BindingCredentialTypeOIDC authenticates with the OIDC exec plugin as the subject of the binding
+	BindingCredentialTypeOIDC BindingCredentialType = "oidc"
+)
+
+// BindingSubject is the OIDC user or group which is granted the permissions of an OIDC binding
+type BindingSubject struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+}
+
+// BindingScope describes the permissions granted to the service account or the OIDC subject of a binding.
+// An empty credential type means the service account credentials.
+type BindingScope struct {
+	Profile        BindingProfile        `json:"profile"`
+	Namespaces     []string              `json:"namespaces,omitempty"`
+	ClusterRole    string                `json:"cluster_role,omitempty"`
+	CredentialType BindingCredentialType `json:"credential_type,omitempty"`
+	Subject        *BindingSubject       `json:"subject,omitempty"`
+}
+
+type WebhookDeliveryState string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryState = "pending"
+	WebhookDeliveryDelivered WebhookDeliveryState = "delivered"
+	WebhookDeliveryFailed    WebhookDeliveryState = "failed"
+)
+
+// WebhookDelivery is a notification about an operation lifecycle transition which must be sent to the configured webhook
+type WebhookDelivery struct {
+	ID          string
+	Webhook     string
+	Event       string
+	InstanceID  string
+	OperationID string
+	Payload     string
+
+	State         WebhookDeliveryState
+	Attempts      int
+	LastError     string
+	NextAttemptAt time.Time
+
+	// ClaimedBy is the KEB instance which sends the delivery, other instances skip the delivery until ClaimedUntil
+	ClaimedBy    string
+	ClaimedUntil *time.Time
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type QuotaReservationState string
+
+const (
+	QuotaReservationReserved  QuotaReservationState = "reserved"
+	QuotaReservationConfirmed QuotaReservationState = "confirmed"
+)
+
+// QuotaReservation holds one unit of the Kyma instances quota of the plan in the subaccount for the instance. The quota is reserved
+// when the provisioning or plan upgrade request is accepted and confirmed when the operation succeeds.
+type QuotaReservation struct {
+	InstanceID   string
+	SubAccountID string
+	PlanID       string
+	OperationID  string
+	State        QuotaReservationState
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
 }
 
 type RetryTuple struct {
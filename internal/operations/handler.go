@@ -0,0 +1,328 @@
+package operations
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	pkg "github.com/kyma-project/kyma-environment-broker/common/runtime"
+	"github.com/kyma-project/kyma-environment-broker/internal"
+	kebError "github.com/kyma-project/kyma-environment-broker/internal/error"
+	"github.com/kyma-project/kyma-environment-broker/internal/event"
+	"github.com/kyma-project/kyma-environment-broker/internal/httputil"
+	"github.com/kyma-project/kyma-environment-broker/internal/process"
+	"github.com/kyma-project/kyma-environment-broker/internal/ptr"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage/dberr"
+	"github.com/pivotal-cf/brokerapi/v12/domain"
+)
+
+const (
+	maxUpdateAttempts = 3
+
+	canceledByAdminReason kebError.Reason = "err_canceled_by_admin"
+	failedByAdminReason   kebError.Reason = "err_failed_by_admin"
+)
+
+type router interface {
+	HandleFunc(pattern string, handler func(http.ResponseWriter, *http.Request))
+}
+
+// Queue is a process queue of the operation type, the operation is removed from it when its processing is stopped
+// and added back when it is retried
+type Queue interface {
+	Add(operationID string)
+	Remove(operationID string)
+}
+
+type Handler interface {
+	AttachRoutes(r router)
+}
+
+type stateChangeRequest struct {
+	Reason string `json:"reason,omitempty"`
+}
+
+type stateChangeResponse struct {
+	OperationID string                    `json:"operation"`
+	InstanceID  string                    `json:"instanceID"`
+	State       domain.LastOperationState `json:"state"`
+}
+
+type handler struct {
+	operations storage.Operations
+	actions    storage.Actions
+	queues     map[internal.OperationType]Queue
+	publisher  event.Publisher
+	log        *slog.Logger
+}
+
+func NewHandler(operationsStorage storage.Operations, actionsStorage storage.Actions, queues map[internal.OperationType]Queue, publisher event.Publisher, log *slog.Logger) Handler {
+	return &handler{
+		operations: operationsStorage,
+		actions:    actionsStorage,
+		queues:     queues,
+		publisher:  publisher,
+		log:        log.With("service", "OperationsEndpoint"),
+	}
+}
+
+func (h *handler) AttachRoutes(r router) {
+	r.HandleFunc("PUT /operations/{operation_id}/cancel", h.cancelOperation)
+	r.HandleFunc("PUT /operations/{operation_id}/fail", h.failOperation)
+	r.HandleFunc("PUT /operations/{operation_id}/retry", h.retryOperation)
+}
+
+func (h *handler) cancelOperation(w http.ResponseWriter, req *http.Request) {
+	h.stopOperation(w, req, internal.OperationStateCanceled)
+}
+
+func (h *handler) failOperation(w http.ResponseWriter, req *http.Request) {
+	h.stopOperation(w, req, domain.Failed)
+}
+
+func (h *handler) stopOperation(w http.ResponseWriter, req *http.Request, state domain.LastOperationState) {
+	operationID := req.PathValue("operation_id")
+	logger := h.log.With("operationID", operationID)
+	logger.Info(fmt.Sprintf("Moving the operation to the %s state requested", state))
+
+	reason, err := h.readReason(req)
+	if err != nil {
+		logger.Warn(fmt.Sprintf("unable to read the request body: %s", err.Error()))
+		httputil.WriteErrorResponse(w, http.StatusBadRequest, err)
+		return
+	}
+
+	operation, err := h.operations.GetOperationByID(operationID)
+	if err != nil {
+		logger.Error(fmt.Sprintf("unable to get operation: %s", err.Error()))
+		switch {
+		case dberr.IsNotFound(err):
+			httputil.WriteErrorResponse(w, http.StatusNotFound, err)
+		default:
+			httputil.WriteErrorResponse(w, http.StatusInternalServerError, err)
+		}
+		return
+	}
+	logger = logger.With("instanceID", operation.InstanceID, "operationType", operation.Type)
+
+	if operation.IsFinished() {
+		msg := fmt.Sprintf("operation is already finished with state %s", operation.State)
+		logger.Warn(msg)
+		httputil.WriteErrorResponse(w, http.StatusConflict, errors.New(msg))
+		return
+	}
+
+	oldState := operation.State
+	operation, err = h.updateState(*operation, state, reason)
+	if err != nil {
+		logger.Error(fmt.Sprintf("unable to update the operation: %s", err.Error()))
+		httputil.WriteErrorResponse(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	if queue, found := h.queues[operation.Type]; found {
+		queue.Remove(operation.ID)
+	} else {
+		logger.Warn("no queue defined for the operation type")
+	}
+
+	h.insertAction(*operation, oldState, reason, logger)
+	operation.EventInfof("operation moved from %s to %s state by an administrator", oldState, operation.State)
+
+	// operation counters support only failed and succeeded operations, canceled operations are collected from the storage
+	if operation.State == domain.Failed {
+		h.publisher.Publish(context.TODO(), process.OperationFinished{
+			Operation: *operation,
+			PlanID:    operation.ProvisioningParameters.PlanID,
+		})
+	}
+
+	logger.Info(fmt.Sprintf("Operation moved from %s to %s state", oldState, operation.State))
+	httputil.WriteResponse(w, http.StatusOK, stateChangeResponse{
+		OperationID: operation.ID,
+		InstanceID:  operation.InstanceID,
+		State:       operation.State,
+	})
+}
+
+func (h *handler) retryOperation(w http.ResponseWriter, req *http.Request) {
+	operationID := req.PathValue("operation_id")
+	logger := h.log.With("operationID", operationID)
+	logger.Info("Retry of the operation requested")
+
+	reason, err := h.readReason(req)
+	if err != nil {
+		logger.Warn(fmt.Sprintf("unable to read the request body: %s", err.Error()))
+		httputil.WriteErrorResponse(w, http.StatusBadRequest, err)
+		return
+	}
+
+	operation, err := h.operations.GetOperationByID(operationID)
+	if err != nil {
+		logger.Error(fmt.Sprintf("unable to get operation: %s", err.Error()))
+		switch {
+		case dberr.IsNotFound(err):
+			httputil.WriteErrorResponse(w, http.StatusNotFound, err)
+		default:
+			httputil.WriteErrorResponse(w, http.StatusInternalServerError, err)
+		}
+		return
+	}
+	logger = logger.With("instanceID", operation.InstanceID, "operationType", operation.Type)
+
+	if operation.State != domain.Failed {
+		msg := fmt.Sprintf("only failed operations can be retried, the operation state is %s", operation.State)
+		logger.Warn(msg)
+		httputil.WriteErrorResponse(w, http.StatusConflict, errors.New(msg))
+		return
+	}
+
+	queue, found := h.queues[operation.Type]
+	if !found {
+		msg := fmt.Sprintf("operations of type %s cannot be retried", operation.Type)
+		logger.Warn(msg)
+		httputil.WriteErrorResponse(w, http.StatusBadRequest, errors.New(msg))
+		return
+	}
+
+	lastOperation, err := h.operations.GetLastOperationWithAllStates(operation.InstanceID)
+	if err != nil {
+		logger.Error(fmt.Sprintf("unable to get the last operation of the instance: %s", err.Error()))
+		httputil.WriteErrorResponse(w, http.StatusInternalServerError, err)
+		return
+	}
+	if lastOperation.ID != operation.ID {
+		msg := fmt.Sprintf("only the last operation of the instance can be retried, the last operation is %s", lastOperation.ID)
+		logger.Warn(msg)
+		httputil.WriteErrorResponse(w, http.StatusConflict, errors.New(msg))
+		return
+	}
+
+	operation.State = domain.InProgress
+	operation.Description = "Operation retried by an administrator"
+	if reason != "" {
+		operation.Description = fmt.Sprintf("%s: %s", operation.Description, reason)
+	}
+	operation.LastError = kebError.LastError{}
+	operation.RuntimeResourceCreatedAt = nil
+	operation.RetriedAt = ptr.Time(time.Now())
+	operation, err = h.operations.UpdateOperation(*operation)
+	if err != nil {
+		logger.Error(fmt.Sprintf("unable to update the operation: %s", err.Error()))
+		switch {
+		case dberr.IsConflict(err):
+			httputil.WriteErrorResponse(w, http.StatusConflict, err)
+		default:
+			httputil.WriteErrorResponse(w, http.StatusInternalServerError, err)
+		}
+		return
+	}
+
+	queue.Add(operation.ID)
+
+	h.insertAction(*operation, domain.Failed, reason, logger)
+	operation.EventInfof("operation retried by an administrator, finished stages: %v", operation.FinishedStages)
+	h.publisher.Publish(context.TODO(), process.OperationRetried{
+		Operation: *operation,
+	})
+
+	logger.Info(fmt.Sprintf("Operation retried, finished stages: %v", operation.FinishedStages))
+	httputil.WriteResponse(w, http.StatusAccepted, stateChangeResponse{
+		OperationID: operation.ID,
+		InstanceID:  operation.InstanceID,
+		State:       operation.State,
+	})
+}
+
+func (h *handler) readReason(req *http.Request) (string, error) {
+	if req.Body == nil {
+		return "", nil
+	}
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return "", err
+	}
+	if len(strings.TrimSpace(string(body))) == 0 {
+		return "", nil
+	}
+	var request stateChangeRequest
+	if err := json.Unmarshal(body, &request); err != nil {
+		return "", fmt.Errorf("while decoding request body: %w", err)
+	}
+	return request.Reason, nil
+}
+
+// updateState stores the new state of the operation. Workers may update the operation at the same time, so the update is repeated on a conflict.
+func (h *handler) updateState(operation internal.Operation, state domain.LastOperationState, reason string) (*internal.Operation, error) {
+	for attempt := 1; ; attempt++ {
+		setState(&operation, state, reason)
+		updated, err := h.operations.UpdateOperation(operation)
+		if err == nil {
+			return updated, nil
+		}
+		if !dberr.IsConflict(err) || attempt == maxUpdateAttempts {
+			return nil, err
+		}
+		current, err := h.operations.GetOperationByID(operation.ID)
+		if err != nil {
+			return nil, err
+		}
+		if current.IsFinished() {
+			return nil, fmt.Errorf("operation has been finished in the meantime with state %s", current.State)
+		}
+		operation = *current
+	}
+}
+
+func setState(operation *internal.Operation, state domain.LastOperationState, reason string) {
+	description := fmt.Sprintf("Operation %s by an administrator", stateVerb(state))
+	if reason != "" {
+		description = fmt.Sprintf("%s: %s", description, reason)
+	}
+	operation.State = state
+	operation.Description = description
+
+	lastErrorReason := canceledByAdminReason
+	if state == domain.Failed {
+		lastErrorReason = failedByAdminReason
+	}
+	operation.LastError = kebError.LastError{
+		Message:   description,
+		Reason:    lastErrorReason,
+		Component: kebError.KEBDependency,
+	}
+}
+
+func (h *handler) insertAction(operation internal.Operation, oldState domain.LastOperationState, reason string, logger *slog.Logger) {
+	message := fmt.Sprintf("Operation %s (type: %s) %s by an administrator.", operation.ID, operation.Type, stateVerb(operation.State))
+	if reason != "" {
+		message = fmt.Sprintf("%s Reason: %s", message, reason)
+	}
+	if err := h.actions.InsertAction(
+		pkg.OperationStateChangeActionType,
+		operation.InstanceID,
+		message,
+		string(oldState),
+		string(operation.State),
+	); err != nil {
+		logger.Error(fmt.Sprintf("while inserting action %q with message %s for instance ID %s: %v", pkg.OperationStateChangeActionType, message, operation.InstanceID, err))
+	}
+}
+
+func stateVerb(state domain.LastOperationState) string {
+	switch state {
+	case domain.Failed:
+		return "failed"
+	case domain.InProgress:
+		return "retried"
+	default:
+		return "canceled"
+	}
+}
@@ -0,0 +1,237 @@
+package operations_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	pkg "github.com/kyma-project/kyma-environment-broker/common/runtime"
+	"github.com/kyma-project/kyma-environment-broker/internal"
+	kebError "github.com/kyma-project/kyma-environment-broker/internal/error"
+	"github.com/kyma-project/kyma-environment-broker/internal/event"
+	"github.com/kyma-project/kyma-environment-broker/internal/fixture"
+	"github.com/kyma-project/kyma-environment-broker/internal/httputil"
+	"github.com/kyma-project/kyma-environment-broker/internal/operations"
+	"github.com/kyma-project/kyma-environment-broker/internal/process"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage"
+	"github.com/pivotal-cf/brokerapi/v12/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	cancelPathFormat = "/operations/%s/cancel"
+	failPathFormat   = "/operations/%s/fail"
+	retryPathFormat  = "/operations/%s/retry"
+)
+
+func TestOperationsHandler(t *testing.T) {
+	router := httputil.NewRouter()
+	provisioningQueue := process.NewFakeQueue()
+	updateQueue := process.NewFakeQueue()
+	db := storage.NewMemoryStorage()
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
+		Level: slog.LevelInfo,
+	}))
+	handler := operations.NewHandler(db.Operations(), db.Actions(), map[internal.OperationType]operations.Queue{
+		internal.OperationTypeProvision: provisioningQueue,
+		internal.OperationTypeUpdate:    updateQueue,
+	}, event.NewPubSub(logger), logger)
+	handler.AttachRoutes(router)
+
+	t.Run("should receive 404 Not Found response", func(t *testing.T) {
+		// given
+		req := httptest.NewRequest(http.MethodPut, fmt.Sprintf(cancelPathFormat, "op-404-not-found"), nil)
+		w := httptest.NewRecorder()
+
+		// when
+		router.ServeHTTP(w, req)
+
+		// then
+		assert.Equal(t, http.StatusNotFound, w.Result().StatusCode)
+	})
+
+	t.Run("should receive 409 Conflict response when operation is finished", func(t *testing.T) {
+		// given
+		operation := fixture.FixProvisioningOperation("op-succeeded", "inst-succeeded")
+		require.NoError(t, db.Operations().InsertOperation(operation))
+
+		req := httptest.NewRequest(http.MethodPut, fmt.Sprintf(cancelPathFormat, operation.ID), nil)
+		w := httptest.NewRecorder()
+
+		// when
+		router.ServeHTTP(w, req)
+
+		// then
+		assert.Equal(t, http.StatusConflict, w.Result().StatusCode)
+		actual, err := db.Operations().GetOperationByID(operation.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.Succeeded, actual.State)
+	})
+
+	t.Run("should receive 400 Bad Request response when body is invalid", func(t *testing.T) {
+		// given
+		operation := fixture.FixProvisioningOperation("op-invalid-body", "inst-invalid-body")
+		operation.State = domain.InProgress
+		require.NoError(t, db.Operations().InsertOperation(operation))
+
+		req := httptest.NewRequest(http.MethodPut, fmt.Sprintf(cancelPathFormat, operation.ID), bytes.NewBufferString("{"))
+		w := httptest.NewRecorder()
+
+		// when
+		router.ServeHTTP(w, req)
+
+		// then
+		assert.Equal(t, http.StatusBadRequest, w.Result().StatusCode)
+		actual, err := db.Operations().GetOperationByID(operation.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.InProgress, actual.State)
+	})
+
+	t.Run("should cancel the in progress operation", func(t *testing.T) {
+		// given
+		instanceID := "inst-cancel"
+		operation := fixture.FixProvisioningOperation("op-cancel", instanceID)
+		operation.State = domain.InProgress
+		require.NoError(t, db.Operations().InsertOperation(operation))
+		provisioningQueue.Add(operation.ID)
+
+		body := bytes.NewBufferString(`{"reason": "stuck in the Gardener step"}`)
+		req := httptest.NewRequest(http.MethodPut, fmt.Sprintf(cancelPathFormat, operation.ID), body)
+		w := httptest.NewRecorder()
+
+		// when
+		router.ServeHTTP(w, req)
+
+		// then
+		resp := w.Result()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		var response map[string]string
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&response))
+		assert.Equal(t, internal.OperationStateCanceled, response["state"])
+
+		actual, err := db.Operations().GetOperationByID(operation.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.LastOperationState(internal.OperationStateCanceled), actual.State)
+		assert.Contains(t, actual.Description, "stuck in the Gardener step")
+		assert.False(t, provisioningQueue.Contains(operation.ID))
+
+		actions, err := db.Actions().ListActionsByInstanceID(instanceID)
+		require.NoError(t, err)
+		require.Len(t, actions, 1)
+		assert.Equal(t, pkg.OperationStateChangeActionType, actions[0].Type)
+		assert.Equal(t, string(domain.InProgress), actions[0].OldValue)
+		assert.Equal(t, internal.OperationStateCanceled, actions[0].NewValue)
+	})
+
+	t.Run("should fail the in progress operation", func(t *testing.T) {
+		// given
+		instanceID := "inst-fail"
+		operation := fixture.FixUpdatingOperation("op-fail", instanceID)
+		operation.State = domain.InProgress
+		require.NoError(t, db.Operations().InsertOperation(operation))
+		updateQueue.Add(operation.ID)
+
+		req := httptest.NewRequest(http.MethodPut, fmt.Sprintf(failPathFormat, operation.ID), nil)
+		w := httptest.NewRecorder()
+
+		// when
+		router.ServeHTTP(w, req)
+
+		// then
+		assert.Equal(t, http.StatusOK, w.Result().StatusCode)
+
+		actual, err := db.Operations().GetOperationByID(operation.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.Failed, actual.State)
+		assert.NotEmpty(t, actual.LastError.Reason)
+		assert.False(t, updateQueue.Contains(operation.ID))
+
+		actions, err := db.Actions().ListActionsByInstanceID(instanceID)
+		require.NoError(t, err)
+		require.Len(t, actions, 1)
+		assert.Equal(t, string(domain.Failed), actions[0].NewValue)
+	})
+
+	t.Run("should retry the failed operation", func(t *testing.T) {
+		// given
+		instanceID := "inst-retry"
+		operation := fixture.FixUpdatingOperation("op-retry", instanceID)
+		operation.State = domain.Failed
+		operation.FinishedStages = []string{"start", "runtime_resource"}
+		operation.LastError = kebError.LastError{Message: "step failed", Reason: "err_step_failed", Step: "Update_Runtime_Resource"}
+		require.NoError(t, db.Operations().InsertOperation(operation))
+
+		req := httptest.NewRequest(http.MethodPut, fmt.Sprintf(retryPathFormat, operation.ID), bytes.NewBufferString(`{"reason": "dependency fixed"}`))
+		w := httptest.NewRecorder()
+
+		// when
+		router.ServeHTTP(w, req)
+
+		// then
+		assert.Equal(t, http.StatusAccepted, w.Result().StatusCode)
+
+		actual, err := db.Operations().GetOperationByID(operation.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.InProgress, actual.State)
+		assert.Equal(t, []string{"start", "runtime_resource"}, actual.FinishedStages)
+		assert.Empty(t, actual.LastError.Reason)
+		assert.NotNil(t, actual.RetriedAt)
+		assert.Contains(t, actual.Description, "dependency fixed")
+		assert.True(t, updateQueue.Contains(operation.ID))
+
+		actions, err := db.Actions().ListActionsByInstanceID(instanceID)
+		require.NoError(t, err)
+		require.Len(t, actions, 1)
+		assert.Equal(t, string(domain.Failed), actions[0].OldValue)
+		assert.Equal(t, string(domain.InProgress), actions[0].NewValue)
+	})
+
+	t.Run("should receive 409 Conflict response when retried operation is not failed", func(t *testing.T) {
+		// given
+		operation := fixture.FixProvisioningOperation("op-retry-succeeded", "inst-retry-succeeded")
+		require.NoError(t, db.Operations().InsertOperation(operation))
+
+		req := httptest.NewRequest(http.MethodPut, fmt.Sprintf(retryPathFormat, operation.ID), nil)
+		w := httptest.NewRecorder()
+
+		// when
+		router.ServeHTTP(w, req)
+
+		// then
+		assert.Equal(t, http.StatusConflict, w.Result().StatusCode)
+		assert.False(t, provisioningQueue.Contains(operation.ID))
+	})
+
+	t.Run("should receive 409 Conflict response when retried operation is not the last one", func(t *testing.T) {
+		// given
+		instanceID := "inst-retry-not-last"
+		failed := fixture.FixProvisioningOperation("op-retry-not-last", instanceID)
+		failed.State = domain.Failed
+		failed.CreatedAt = time.Now().Add(-time.Hour)
+		require.NoError(t, db.Operations().InsertOperation(failed))
+		newer := fixture.FixUpdatingOperation("op-retry-newer", instanceID)
+		newer.CreatedAt = time.Now()
+		require.NoError(t, db.Operations().InsertOperation(newer))
+
+		req := httptest.NewRequest(http.MethodPut, fmt.Sprintf(retryPathFormat, failed.ID), nil)
+		w := httptest.NewRecorder()
+
+		// when
+		router.ServeHTTP(w, req)
+
+		// then
+		assert.Equal(t, http.StatusConflict, w.Result().StatusCode)
+		actual, err := db.Operations().GetOperationByID(failed.ID)
+		require.NoError(t, err)
+		assert.Equal(t, domain.Failed, actual.State)
+		assert.False(t, provisioningQueue.Contains(failed.ID))
+	})
+}
@@ -52,41 +52,45 @@ func (s *BTPOperatorCleanupStep) Name() string {
 	return "BTPOperator_Cleanup"
 }
 
-func (s *BTPOperatorCleanupStep) softDelete(operation internal.Operation, k8sClient client.Client, log *slog.Logger) (internal.Operation, time.Duration, error) {
+func (s *BTPOperatorCleanupStep) softDelete(ctx context.Context, operation internal.Operation, k8sClient client.Client, log *slog.Logger) (internal.Operation, time.Duration, error) {
 	namespaces := corev1.NamespaceList{}
-	if err := k8sClient.List(context.Background(), &namespaces); err != nil {
+	if err := k8sClient.List(ctx, &namespaces); err != nil {
 		err = kebError.AsTemporaryError(err, "failed to list namespaces")
-		return s.retryOnError(operation, k8sClient, err, log, "failed to list namespaces")
+		return s.retryOnError(ctx, operation, k8sClient, err, log, "failed to list namespaces")
 	}
 
 	var errors []string
 	gvk := schema.GroupVersionKind{Group: btpOperatorGroup, Version: btpOperatorApiVer, Kind: btpOperatorBinding}
-	SBCrdExists, err := s.checkCRDExistence(k8sClient, gvk)
+	SBCrdExists, err := s.checkCRDExistence(ctx, k8sClient, gvk)
 	if err != nil {
 		return operation, 0, err
 	}
 	if SBCrdExists {
-		errors = s.removeResources(k8sClient, gvk, namespaces, errors)
+		errors = s.removeResources(ctx, k8sClient, gvk, namespaces, errors)
 	}
 
 	gvk.Kind = btpOperatorServiceInstance
-	SICrdExists, err := s.checkCRDExistence(k8sClient, gvk)
+	SICrdExists, err := s.checkCRDExistence(ctx, k8sClient, gvk)
 	if err != nil {
 		return operation, 0, err
 	}
 	if SICrdExists {
-		errors = s.removeResources(k8sClient, gvk, namespaces, errors)
+		errors = s.removeResources(ctx, k8sClient, gvk, namespaces, errors)
 	}
 
 	if len(errors) != 0 {
 		err := fmt.Errorf("%s", strings.Join(errors, ";"))
 		err = kebError.AsTemporaryError(err, "failed to cleanup BTP operator resources")
-		return s.retryOnError(operation, k8sClient, err, log, "failed to cleanup")
+		return s.retryOnError(ctx, operation, k8sClient, err, log, "failed to cleanup")
 	}
 	return operation, 0, nil
 }
 
 func (s *BTPOperatorCleanupStep) Run(operation internal.Operation, log *slog.Logger) (internal.Operation, time.Duration, error) {
+	return s.RunWithContext(context.Background(), operation, log)
+}
+
+func (s *BTPOperatorCleanupStep) RunWithContext(ctx context.Context, operation internal.Operation, log *slog.Logger) (internal.Operation, time.Duration, error) {
 	if operation.RuntimeID == "" {
 		log.Info("RuntimeID is empty, skipping")
 		return operation, 0, nil
@@ -111,7 +115,7 @@ func (s *BTPOperatorCleanupStep) Run(operation internal.Operation, log *slog.Log
 	}
 	if operation.UserAgent == broker.AccountCleanupJob {
 		log.Info("executing soft delete cleanup for accountcleanup-job")
-		return s.softDelete(operation, kclient, log)
+		return s.softDelete(ctx, operation, kclient, log)
 	}
 	if operation.RuntimeID == "" {
 		log.Info("instance has been deprovisioned already")
@@ -121,39 +125,39 @@ func (s *BTPOperatorCleanupStep) Run(operation internal.Operation, log *slog.Log
 		log.Info("Skipping service instance and binding deletion")
 		return operation, 0, nil
 	}
-	if err := s.deleteServiceBindingsAndInstances(kclient, log); err != nil {
+	if err := s.deleteServiceBindingsAndInstances(ctx, kclient, log); err != nil {
 		err = kebError.AsTemporaryError(err, "failed BTP operator resource cleanup")
-		return s.retryOnError(operation, kclient, err, log, "could not delete bindings and service instances")
+		return s.retryOnError(ctx, operation, kclient, err, log, "could not delete bindings and service instances")
 	}
 	return operation, 0, nil
 }
 
-func (s *BTPOperatorCleanupStep) deleteServiceBindingsAndInstances(k8sClient client.Client, log *slog.Logger) error {
+func (s *BTPOperatorCleanupStep) deleteServiceBindingsAndInstances(ctx context.Context, k8sClient client.Client, log *slog.Logger) error {
 	namespaces := corev1.NamespaceList{}
-	if err := k8sClient.List(context.Background(), &namespaces); err != nil {
+	if err := k8sClient.List(ctx, &namespaces); err != nil {
 		return err
 	}
-	requeue := s.deleteResource(k8sClient, namespaces, schema.GroupVersionKind{Group: btpOperatorGroup, Version: btpOperatorApiVer, Kind: btpOperatorBinding}, log)
-	requeue = requeue || s.deleteResource(k8sClient, namespaces, schema.GroupVersionKind{Group: btpOperatorGroup, Version: btpOperatorApiVer, Kind: btpOperatorServiceInstance}, log)
+	requeue := s.deleteResource(ctx, k8sClient, namespaces, schema.GroupVersionKind{Group: btpOperatorGroup, Version: btpOperatorApiVer, Kind: btpOperatorBinding}, log)
+	requeue = requeue || s.deleteResource(ctx, k8sClient, namespaces, schema.GroupVersionKind{Group: btpOperatorGroup, Version: btpOperatorApiVer, Kind: btpOperatorServiceInstance}, log)
 	if requeue {
 		return fmt.Errorf("waiting for resources to be deleted")
 	}
 	return nil
 }
 
-func (s *BTPOperatorCleanupStep) removeFinalizers(k8sClient client.Client, namespaces corev1.NamespaceList, gvk schema.GroupVersionKind) error {
+func (s *BTPOperatorCleanupStep) removeFinalizers(ctx context.Context, k8sClient client.Client, namespaces corev1.NamespaceList, gvk schema.GroupVersionKind) error {
 	listGvk := gvk
 	listGvk.Kind = gvk.Kind + "List"
 	var errors []string
 	for _, ns := range namespaces.Items {
 		list := &unstructured.UnstructuredList{}
 		list.SetGroupVersionKind(listGvk)
-		if err := k8sClient.List(context.Background(), list, client.InNamespace(ns.Name)); err != nil {
+		if err := k8sClient.List(ctx, list, client.InNamespace(ns.Name)); err != nil {
 			errors = append(errors, fmt.Sprintf("failed listing resource %v in namespace %v: %v", gvk, ns.Name, err))
 		}
 		for _, r := range list.Items {
 			r.SetFinalizers([]string{})
-			if err := k8sClient.Update(context.Background(), &r); err != nil {
+			if err := k8sClient.Update(ctx, &r); err != nil {
 				errors = append(errors, fmt.Sprintf("failed remove finalizer for resource %v %v/%v: %v", gvk, r.GetNamespace(), r.GetName(), err))
 			}
 		}
@@ -164,14 +168,14 @@ func (s *BTPOperatorCleanupStep) removeFinalizers(k8sClient client.Client, names
 	return nil
 }
 
-func (s *BTPOperatorCleanupStep) deleteResource(k8sClient client.Client, namespaces corev1.NamespaceList, gvk schema.GroupVersionKind, log *slog.Logger) (requeue bool) {
+func (s *BTPOperatorCleanupStep) deleteResource(ctx context.Context, k8sClient client.Client, namespaces corev1.NamespaceList, gvk schema.GroupVersionKind, log *slog.Logger) (requeue bool) {
 	listGvk := gvk
 	listGvk.Kind = gvk.Kind + "List"
 	stillExistingCount := 0
 	for _, ns := range namespaces.Items {
 		list := &unstructured.UnstructuredList{}
 		list.SetGroupVersionKind(listGvk)
-		if err := k8sClient.List(context.Background(), list, client.InNamespace(ns.Name)); err != nil {
+		if err := k8sClient.List(ctx, list, client.InNamespace(ns.Name)); err != nil {
 			log.Error(fmt.Sprintf("failed listing resource %v in namespace %v", gvk, ns.Name))
 			if k8serrors2.IsNoMatchError(err) {
 				// CRD doesn't exist anymore
@@ -188,14 +192,14 @@ func (s *BTPOperatorCleanupStep) deleteResource(k8sClient client.Client, namespa
 	for _, ns := range namespaces.Items {
 		obj := &unstructured.Unstructured{}
 		obj.SetGroupVersionKind(gvk)
-		if err := k8sClient.DeleteAllOf(context.Background(), obj, client.InNamespace(ns.Name)); err != nil {
+		if err := k8sClient.DeleteAllOf(ctx, obj, client.InNamespace(ns.Name)); err != nil {
 			log.Error(fmt.Sprintf("failed deleting resources %v in namespace %v", gvk, ns.Name))
 		}
 	}
 	return
 }
 
-func (s *BTPOperatorCleanupStep) retryOnError(op internal.Operation, kclient client.Client, err error, log *slog.Logger, msg string) (internal.Operation, time.Duration, error) {
+func (s *BTPOperatorCleanupStep) retryOnError(ctx context.Context, op internal.Operation, kclient client.Client, err error, log *slog.Logger, msg string) (internal.Operation, time.Duration, error) {
 	if err != nil {
 		// handleError returns retry period if it's retriable error and it's within timeout
 		op, retry, err2 := handleError(s.Name(), op, err, log, msg)
@@ -204,31 +208,31 @@ func (s *BTPOperatorCleanupStep) retryOnError(op internal.Operation, kclient cli
 		}
 		// when retry is 0, that means error has been retried defined number of times and as a fallback routine
 		// it was decided that KEB should try to remove finalizers once
-		s.attemptToRemoveFinalizers(op, kclient, log)
+		s.attemptToRemoveFinalizers(ctx, op, kclient, log)
 		return op, retry, err2
 	}
 	return op, 0, nil
 }
 
-func (s *BTPOperatorCleanupStep) attemptToRemoveFinalizers(op internal.Operation, k8sClient client.Client, log *slog.Logger) {
+func (s *BTPOperatorCleanupStep) attemptToRemoveFinalizers(ctx context.Context, op internal.Operation, k8sClient client.Client, log *slog.Logger) {
 	namespaces := corev1.NamespaceList{}
-	if err := k8sClient.List(context.Background(), &namespaces); err != nil {
+	if err := k8sClient.List(ctx, &namespaces); err != nil {
 		log.Error(fmt.Sprintf("failed to list namespaces to remove finalizers: %v", err))
 		return
 	}
-	if err := s.removeFinalizers(k8sClient, namespaces, schema.GroupVersionKind{Group: btpOperatorGroup, Version: btpOperatorApiVer, Kind: btpOperatorBinding}); err != nil {
+	if err := s.removeFinalizers(ctx, k8sClient, namespaces, schema.GroupVersionKind{Group: btpOperatorGroup, Version: btpOperatorApiVer, Kind: btpOperatorBinding}); err != nil {
 		log.Error(fmt.Sprintf("failed to remove finalizers for bindings: %v", err))
 	}
-	if err := s.removeFinalizers(k8sClient, namespaces, schema.GroupVersionKind{Group: btpOperatorGroup, Version: btpOperatorApiVer, Kind: btpOperatorServiceInstance}); err != nil {
+	if err := s.removeFinalizers(ctx, k8sClient, namespaces, schema.GroupVersionKind{Group: btpOperatorGroup, Version: btpOperatorApiVer, Kind: btpOperatorServiceInstance}); err != nil {
 		log.Error(fmt.Sprintf("failed to remove finalizers for instances: %v", err))
 	}
 }
 
-func (s *BTPOperatorCleanupStep) checkCRDExistence(k8sClient client.Client, gvk schema.GroupVersionKind) (bool, error) {
+func (s *BTPOperatorCleanupStep) checkCRDExistence(ctx context.Context, k8sClient client.Client, gvk schema.GroupVersionKind) (bool, error) {
 	crdName := fmt.Sprintf("%ss.%s", strings.ToLower(gvk.Kind), gvk.Group)
 	crd := &apiextensionsv1.CustomResourceDefinition{}
 
-	if err := k8sClient.Get(context.Background(), client.ObjectKey{Name: crdName}, crd); err != nil {
+	if err := k8sClient.Get(ctx, client.ObjectKey{Name: crdName}, crd); err != nil {
 		if k8serrors.IsNotFound(err) || k8serrors2.IsNoMatchError(err) {
 			return false, nil
 		} else {
@@ -238,15 +242,15 @@ func (s *BTPOperatorCleanupStep) checkCRDExistence(k8sClient client.Client, gvk
 	return true, nil
 }
 
-func (s *BTPOperatorCleanupStep) removeResources(k8sClient client.Client, gvk schema.GroupVersionKind, namespaces corev1.NamespaceList, errors []string) []string {
+func (s *BTPOperatorCleanupStep) removeResources(ctx context.Context, k8sClient client.Client, gvk schema.GroupVersionKind, namespaces corev1.NamespaceList, errors []string) []string {
 	for _, ns := range namespaces.Items {
 		obj := &unstructured.Unstructured{}
 		obj.SetGroupVersionKind(gvk)
-		if err := k8sClient.DeleteAllOf(context.Background(), obj, client.InNamespace(ns.Name)); err != nil {
+		if err := k8sClient.DeleteAllOf(ctx, obj, client.InNamespace(ns.Name)); err != nil {
 			errors = append(errors, err.Error())
 		}
 	}
-	if err := s.removeFinalizers(k8sClient, namespaces, gvk); err != nil {
+	if err := s.removeFinalizers(ctx, k8sClient, namespaces, gvk); err != nil {
 		errors = append(errors, err.Error())
 	}
 	return errors
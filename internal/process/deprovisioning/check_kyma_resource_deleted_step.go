@@ -37,6 +37,10 @@ func (step *CheckKymaResourceDeletedStep) Name() string {
 }
 
 func (step *CheckKymaResourceDeletedStep) Run(operation internal.Operation, logger *slog.Logger) (internal.Operation, time.Duration, error) {
+	return step.RunWithContext(context.Background(), operation, logger)
+}
+
+func (step *CheckKymaResourceDeletedStep) RunWithContext(ctx context.Context, operation internal.Operation, logger *slog.Logger) (internal.Operation, time.Duration, error) {
 	if operation.KymaResourceNamespace == "" {
 		logger.Warn("namespace for Kyma resource not specified")
 		return operation, 0, nil
@@ -57,7 +61,7 @@ func (step *CheckKymaResourceDeletedStep) Run(operation internal.Operation, logg
 
 	kymaUnstructured := &unstructured.Unstructured{}
 	kymaUnstructured.SetGroupVersionKind(obj.GroupVersionKind())
-	err = step.kcpClient.Get(context.Background(), client.ObjectKey{
+	err = step.kcpClient.Get(ctx, client.ObjectKey{
 		Namespace: operation.KymaResourceNamespace,
 		Name:      kymaResourceName,
 	}, kymaUnstructured)
@@ -38,6 +38,10 @@ func (step *CheckRuntimeResourceDeletionStep) Name() string {
 }
 
 func (step *CheckRuntimeResourceDeletionStep) Run(operation internal.Operation, logger *slog.Logger) (internal.Operation, time.Duration, error) {
+	return step.RunWithContext(context.Background(), operation, logger)
+}
+
+func (step *CheckRuntimeResourceDeletionStep) RunWithContext(ctx context.Context, operation internal.Operation, logger *slog.Logger) (internal.Operation, time.Duration, error) {
 	namespace := operation.KymaResourceNamespace
 	if namespace == "" {
 		logger.Warn("namespace for Kyma resource not specified, setting 'kcp-system'")
@@ -60,7 +64,7 @@ func (step *CheckRuntimeResourceDeletionStep) Run(operation internal.Operation,
 		},
 	}
 
-	err := step.kcpClient.Get(context.Background(), client.ObjectKey{
+	err := step.kcpClient.Get(ctx, client.ObjectKey{
 		Namespace: namespace,
 		Name:      resourceName,
 	}, runtime)
@@ -49,6 +49,10 @@ func (step *DeleteKymaResourceStep) Name() string {
 }
 
 func (step *DeleteKymaResourceStep) Run(operation internal.Operation, logger *slog.Logger) (internal.Operation, time.Duration, error) {
+	return step.RunWithContext(context.Background(), operation, logger)
+}
+
+func (step *DeleteKymaResourceStep) RunWithContext(ctx context.Context, operation internal.Operation, logger *slog.Logger) (internal.Operation, time.Duration, error) {
 	// read the KymaTemplate from the config if needed
 	if operation.KymaTemplate == "" {
 		cfg := &internal.ConfigForPlan{}
@@ -106,7 +110,7 @@ func (step *DeleteKymaResourceStep) Run(operation internal.Operation, logger *sl
 	kymaUnstructured.SetNamespace(operation.KymaResourceNamespace)
 	kymaUnstructured.SetGroupVersionKind(obj.GroupVersionKind())
 
-	err = step.kcpClient.Delete(context.Background(), kymaUnstructured)
+	err = step.kcpClient.Delete(ctx, kymaUnstructured)
 	if err != nil {
 		if errors.IsNotFound(err) {
 			logger.Info("no Kyma resource to delete - ignoring")
@@ -38,6 +38,10 @@ func (step *DeleteRuntimeResourceStep) Name() string {
 }
 
 func (step *DeleteRuntimeResourceStep) Run(operation internal.Operation, logger *slog.Logger) (internal.Operation, time.Duration, error) {
+	return step.RunWithContext(context.Background(), operation, logger)
+}
+
+func (step *DeleteRuntimeResourceStep) RunWithContext(ctx context.Context, operation internal.Operation, logger *slog.Logger) (internal.Operation, time.Duration, error) {
 	resourceName := operation.RuntimeResourceName
 	resourceNamespace := operation.KymaResourceNamespace
 
@@ -55,7 +59,7 @@ func (step *DeleteRuntimeResourceStep) Run(operation internal.Operation, logger
 	}
 
 	var runtime = imv1.Runtime{}
-	err := step.kcpClient.Get(context.Background(), client.ObjectKey{Name: resourceName, Namespace: resourceNamespace}, &runtime)
+	err := step.kcpClient.Get(ctx, client.ObjectKey{Name: resourceName, Namespace: resourceNamespace}, &runtime)
 	if err != nil {
 		if !errors.IsNotFound(err) {
 			logger.Warn(fmt.Sprintf("Unable to read runtime: %s", err))
@@ -66,7 +70,7 @@ func (step *DeleteRuntimeResourceStep) Run(operation internal.Operation, logger
 		}
 	}
 
-	err = step.kcpClient.Delete(context.Background(), &runtime)
+	err = step.kcpClient.Delete(ctx, &runtime)
 
 	// check the error
 	if err != nil {
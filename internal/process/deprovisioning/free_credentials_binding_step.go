@@ -54,6 +54,10 @@ func (s *FreeCredentialsBindingStep) Name() string {
 }
 
 func (s *FreeCredentialsBindingStep) Run(operation internal.Operation, logger *slog.Logger) (internal.Operation, time.Duration, error) {
+	return s.RunWithContext(context.Background(), operation, logger)
+}
+
+func (s *FreeCredentialsBindingStep) RunWithContext(ctx context.Context, operation internal.Operation, logger *slog.Logger) (internal.Operation, time.Duration, error) {
 	// The flow is:
 	// - find the credentials binding
 	// - check if the subscription is shared or not - if yes - do nothing
@@ -70,7 +74,7 @@ func (s *FreeCredentialsBindingStep) Run(operation internal.Operation, logger *s
 		logger.Info("Subscription not assigned, nothing to release")
 		return operation, 0, nil
 	}
-	credentialsBinding, err := s.gardenerClient.Resource(gardener.CredentialsBindingResource).Namespace(s.gardenerNS).Get(context.Background(), credentialsBindingName, metav1.GetOptions{})
+	credentialsBinding, err := s.gardenerClient.Resource(gardener.CredentialsBindingResource).Namespace(s.gardenerNS).Get(ctx, credentialsBindingName, metav1.GetOptions{})
 	if err != nil {
 		msg := fmt.Sprintf("getting secret binding %s in namespace %s", credentialsBindingName, s.gardenerNS)
 		return s.operationManager.RetryOperation(operation, msg, err, 10*time.Second, time.Minute, logger)
@@ -97,14 +101,14 @@ func (s *FreeCredentialsBindingStep) Run(operation internal.Operation, logger *s
 	// Wait for the current instance's own shoot to be deleted before checking other references.
 	// This handles the race where KIM is still asynchronously deleting the shoot.
 	if operation.ShootName != "" {
-		_, err = s.gardenerClient.Resource(gardener.ShootResource).Namespace(s.gardenerNS).Get(context.Background(), operation.ShootName, metav1.GetOptions{})
+		_, err = s.gardenerClient.Resource(gardener.ShootResource).Namespace(s.gardenerNS).Get(ctx, operation.ShootName, metav1.GetOptions{})
 		if err == nil {
 			logger.Info(fmt.Sprintf("Shoot %s for this instance still exists, waiting for deletion before releasing credentials binding %s", operation.ShootName, credentialsBindingName))
 			result, backoff, retryErr := s.operationManager.RetryOperation(operation, fmt.Sprintf("shoot %s still exists, waiting for deletion", operation.ShootName), nil, 10*time.Second, s.shootWaitTimeout, logger)
 			if result.State == domain.Failed {
 				// Timed out waiting — mark dirty only if no other shoot references this CB.
-				if s.isLastShootReferencingCB(credentialsBindingName, operation.ShootName, logger) {
-					s.markCredentialsBindingDirty(credentialsBindingName, logger)
+				if s.isLastShootReferencingCB(ctx, credentialsBindingName, operation.ShootName, logger) {
+					s.markCredentialsBindingDirty(ctx, credentialsBindingName, logger)
 				}
 			}
 			return result, backoff, retryErr
@@ -112,7 +116,7 @@ func (s *FreeCredentialsBindingStep) Run(operation internal.Operation, logger *s
 	}
 
 	// Own shoot is gone — check if any other shoot still references the CB.
-	shootlist, err := s.gardenerClient.Resource(gardener.ShootResource).Namespace(s.gardenerNS).List(context.Background(), metav1.ListOptions{})
+	shootlist, err := s.gardenerClient.Resource(gardener.ShootResource).Namespace(s.gardenerNS).List(ctx, metav1.ListOptions{})
 	if err != nil {
 		msg := fmt.Sprintf("listing Gardener shoots in namespace %s", s.gardenerNS)
 		return s.operationManager.RetryOperation(operation, msg, err, 10*time.Second, time.Minute, logger)
@@ -133,7 +137,7 @@ func (s *FreeCredentialsBindingStep) Run(operation internal.Operation, logger *s
 	labels["dirty"] = "true"
 	credentialsBinding.SetLabels(labels)
 
-	_, err = s.gardenerClient.Resource(gardener.CredentialsBindingResource).Namespace(s.gardenerNS).Update(context.Background(), credentialsBinding, metav1.UpdateOptions{})
+	_, err = s.gardenerClient.Resource(gardener.CredentialsBindingResource).Namespace(s.gardenerNS).Update(ctx, credentialsBinding, metav1.UpdateOptions{})
 	if err != nil {
 		msg := fmt.Sprintf("marking secret binding %s as dirty failed: %s", credentialsBinding.GetName(), err.Error())
 		return s.operationManager.RetryOperation(operation, msg, err, 10*time.Second, time.Minute, logger)
@@ -168,8 +172,8 @@ func (s *FreeCredentialsBindingStep) findCredentialsBindingName(operation intern
 	return *provisioningOp.ProvisioningParameters.Parameters.TargetSecret, nil
 }
 
-func (s *FreeCredentialsBindingStep) isLastShootReferencingCB(credentialsBindingName, ownShootName string, logger *slog.Logger) bool {
-	shootlist, err := s.gardenerClient.Resource(gardener.ShootResource).Namespace(s.gardenerNS).List(context.Background(), metav1.ListOptions{})
+func (s *FreeCredentialsBindingStep) isLastShootReferencingCB(ctx context.Context, credentialsBindingName, ownShootName string, logger *slog.Logger) bool {
+	shootlist, err := s.gardenerClient.Resource(gardener.ShootResource).Namespace(s.gardenerNS).List(ctx, metav1.ListOptions{})
 	if err != nil {
 		logger.Warn(fmt.Sprintf("failed to list shoots to check CB references: %s", err))
 		return false
@@ -186,8 +190,8 @@ func (s *FreeCredentialsBindingStep) isLastShootReferencingCB(credentialsBinding
 	return true
 }
 
-func (s *FreeCredentialsBindingStep) markCredentialsBindingDirty(credentialsBindingName string, logger *slog.Logger) {
-	cb, err := s.gardenerClient.Resource(gardener.CredentialsBindingResource).Namespace(s.gardenerNS).Get(context.Background(), credentialsBindingName, metav1.GetOptions{})
+func (s *FreeCredentialsBindingStep) markCredentialsBindingDirty(ctx context.Context, credentialsBindingName string, logger *slog.Logger) {
+	cb, err := s.gardenerClient.Resource(gardener.CredentialsBindingResource).Namespace(s.gardenerNS).Get(ctx, credentialsBindingName, metav1.GetOptions{})
 	if err != nil {
 		logger.Warn(fmt.Sprintf("failed to get credentials binding %s for dirty marking: %s", credentialsBindingName, err))
 		return
@@ -198,7 +202,7 @@ func (s *FreeCredentialsBindingStep) markCredentialsBindingDirty(credentialsBind
 	}
 	labels["dirty"] = "true"
 	cb.SetLabels(labels)
-	if _, err = s.gardenerClient.Resource(gardener.CredentialsBindingResource).Namespace(s.gardenerNS).Update(context.Background(), cb, metav1.UpdateOptions{}); err != nil {
+	if _, err = s.gardenerClient.Resource(gardener.CredentialsBindingResource).Namespace(s.gardenerNS).Update(ctx, cb, metav1.UpdateOptions{}); err != nil {
 		logger.Warn(fmt.Sprintf("failed to mark credentials binding %s as dirty: %s", credentialsBindingName, err))
 	}
 }
@@ -28,6 +28,12 @@ type OperationStepProcessed struct {
 	Operation    internal.Operation
 }
 
+// OperationStageFinished is published when all steps of a stage are processed and the stage is stored as finished
+type OperationStageFinished struct {
+	Stage     string
+	Operation internal.Operation
+}
+
 type OperationSucceeded struct {
 	Operation internal.Operation
 }
@@ -36,6 +42,11 @@ type OperationFailed struct {
 	Operation internal.Operation
 }
 
+// OperationRetried is published when a failed operation is resumed
+type OperationRetried struct {
+	Operation internal.Operation
+}
+
 type OperationFinished struct {
 	Operation internal.Operation
 	PlanID    string
@@ -11,3 +11,21 @@ func NewFakeQueue() *fakeQueue {
 func (q *fakeQueue) Add(opID string) {
 	q.operationIDs = append(q.operationIDs, opID)
 }
+
+func (q *fakeQueue) Remove(opID string) {
+	for i, id := range q.operationIDs {
+		if id == opID {
+			q.operationIDs = append(q.operationIDs[:i], q.operationIDs[i+1:]...)
+			return
+		}
+	}
+}
+
+func (q *fakeQueue) Contains(opID string) bool {
+	for _, id := range q.operationIDs {
+		if id == opID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,51 @@
+package process
+
+import (
+	"github.com/kyma-project/kyma-environment-broker/internal"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage"
+)
+
+// OperationClassifier classifies operations by the global account of the instance and the kind of the operation:
+// provisioning of paid plans has the high priority, suspensions and deprovisioning of trial and free instances have the low priority.
+type OperationClassifier struct {
+	operations  storage.Operations
+	lowCostPlan func(planID string) bool
+}
+
+// NewOperationClassifier creates the classifier, lowCostPlan returns true for plans of trial and free instances
+func NewOperationClassifier(operations storage.Operations, lowCostPlan func(planID string) bool) *OperationClassifier {
+	return &OperationClassifier{operations: operations, lowCostPlan: lowCostPlan}
+}
+
+func (c *OperationClassifier) Classify(operationID string) (Classification, error) {
+	operation, err := c.operations.GetOperationByID(operationID)
+	if err != nil {
+		return Classification{}, err
+	}
+
+	globalAccountID := operation.ProvisioningParameters.ErsContext.GlobalAccountID
+	if globalAccountID == "" {
+		globalAccountID = operation.GlobalAccountID
+	}
+	return Classification{
+		GlobalAccountID: globalAccountID,
+		Priority:        operationPriority(*operation, c.lowCostPlan(operation.ProvisioningParameters.PlanID)),
+	}, nil
+}
+
+func operationPriority(operation internal.Operation, lowCostPlan bool) Priority {
+	switch operation.Type {
+	case internal.OperationTypeProvision:
+		if lowCostPlan {
+			return PriorityNormal
+		}
+		return PriorityHigh
+	case internal.OperationTypeDeprovision:
+		if operation.Temporary || lowCostPlan {
+			return PriorityLow
+		}
+		return PriorityNormal
+	default:
+		return PriorityNormal
+	}
+}
@@ -0,0 +1,66 @@
+package process
+
+import (
+	"testing"
+
+	"github.com/kyma-project/kyma-environment-broker/internal"
+	"github.com/kyma-project/kyma-environment-broker/internal/fixture"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOperationClassifier(t *testing.T) {
+	for tn, tc := range map[string]struct {
+		operation        internal.Operation
+		expectedPriority Priority
+	}{
+		"provisioning of a paid plan": {
+			operation:        fixture.FixProvisioningOperation("op-id", "iid"),
+			expectedPriority: PriorityHigh,
+		},
+		"provisioning of a trial": {
+			operation:        fixture.FixProvisioningOperation("op-id", "iid", fixture.WithPlanID(fixture.TrialPlan)),
+			expectedPriority: PriorityNormal,
+		},
+		"update": {
+			operation:        fixture.FixUpdatingOperation("op-id", "iid"),
+			expectedPriority: PriorityNormal,
+		},
+		"deprovisioning of a paid plan": {
+			operation:        fixture.FixDeprovisioningOperationAsOperation("op-id", "iid"),
+			expectedPriority: PriorityNormal,
+		},
+		"deprovisioning of a trial": {
+			operation:        fixture.FixOperation("op-id", "iid", internal.OperationTypeDeprovision, fixture.WithPlanID(fixture.TrialPlan)),
+			expectedPriority: PriorityLow,
+		},
+		"suspension": {
+			operation:        fixture.FixSuspensionOperationAsOperation("op-id", "iid"),
+			expectedPriority: PriorityLow,
+		},
+	} {
+		t.Run(tn, func(t *testing.T) {
+			// given
+			db := storage.NewMemoryStorage()
+			tc.operation.ProvisioningParameters.ErsContext.GlobalAccountID = "ga-id"
+			require.NoError(t, db.Operations().InsertOperation(tc.operation))
+
+			// when
+			class, err := NewOperationClassifier(db.Operations(), isTrialPlan).Classify("op-id")
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, Classification{GlobalAccountID: "ga-id", Priority: tc.expectedPriority}, class)
+		})
+	}
+
+	t.Run("missing operation", func(t *testing.T) {
+		_, err := NewOperationClassifier(storage.NewMemoryStorage().Operations(), isTrialPlan).Classify("missing")
+		assert.Error(t, err)
+	})
+}
+
+func isTrialPlan(planID string) bool {
+	return planID == fixture.TrialPlan
+}
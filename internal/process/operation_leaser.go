@@ -0,0 +1,217 @@
+package process
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/kyma-project/kyma-environment-broker/internal"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage"
+
+	"github.com/google/uuid"
+)
+
+const leaseRetryInterval = 10 * time.Second
+
+type LeasingConfig struct {
+	// Enabled allows several KEB instances to share the operation queues, every operation is processed by the instance which holds its lease
+	Enabled bool `envconfig:"default=false"`
+	// Owner identifies the KEB instance, the host name is used if it is empty
+	Owner string `envconfig:"optional"`
+	// Duration is the time after which the lease of a not responding instance expires and its operations are taken over
+	Duration          time.Duration `envconfig:"default=2m"`
+	HeartbeatInterval time.Duration `envconfig:"default=30s"`
+	// RecoveryInterval is the interval of scans for operations which are not leased or their lease expired
+	RecoveryInterval time.Duration `envconfig:"default=1m"`
+}
+
+// OperationLeaser claims operations in the storage before they are processed, so an operation is processed by one KEB instance at a time.
+// Leases are renewed with heartbeats until the operation is finished. Operations of an instance which stopped sending heartbeats
+// are taken over by other instances once their leases expire.
+type OperationLeaser struct {
+	leases storage.OperationLeases
+	owner  string
+	cfg    LeasingConfig
+	log    *slog.Logger
+	now    func() time.Time
+
+	mu   sync.Mutex
+	held map[string]struct{}
+}
+
+func NewOperationLeaser(leases storage.OperationLeases, cfg LeasingConfig, log *slog.Logger) *OperationLeaser {
+	owner := cfg.Owner
+	if owner == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			hostname = uuid.NewString()
+		}
+		owner = hostname
+	}
+	return &OperationLeaser{
+		leases: leases,
+		owner:  owner,
+		cfg:    cfg,
+		log:    log.With("leaseOwner", owner),
+		now:    time.Now,
+		held:   make(map[string]struct{}),
+	}
+}
+
+// Executor returns the executor which processes only operations leased by this instance.
+// The executor is returned unchanged if leasing is not used.
+func (l *OperationLeaser) Executor(executor Executor) Executor {
+	if l == nil {
+		return executor
+	}
+	return &leasingExecutor{leaser: l, executor: executor}
+}
+
+// Run renews the held leases and, if queues are given, adds operations which can be claimed to the queue of their type
+func (l *OperationLeaser) Run(ctx context.Context, queues map[internal.OperationType]*Queue) {
+	heartbeat := time.NewTicker(l.cfg.HeartbeatInterval)
+	defer heartbeat.Stop()
+	recovery := time.NewTicker(l.cfg.RecoveryInterval)
+	defer recovery.Stop()
+
+	l.recover(queues)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-heartbeat.C:
+			l.renew()
+		case <-recovery.C:
+			l.recover(queues)
+		}
+	}
+}
+
+// AcquireJob claims the periodic job for the given duration and returns true if this instance should run it.
+// Every instance runs the job if leasing is not used.
+func (l *OperationLeaser) AcquireJob(name string, duration time.Duration) bool {
+	if l == nil {
+		return true
+	}
+	acquired, err := l.leases.AcquireJob(name, l.owner, l.now(), duration)
+	if err != nil {
+		l.log.Error(fmt.Sprintf("unable to acquire the lease of job %s: %s", name, err))
+		return false
+	}
+	return acquired
+}
+
+func (l *OperationLeaser) acquire(operationID string) (bool, error) {
+	acquired, err := l.leases.Acquire(operationID, l.owner, l.now(), l.cfg.Duration)
+	if err != nil || !acquired {
+		return false, err
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.held[operationID] = struct{}{}
+	return true, nil
+}
+
+func (l *OperationLeaser) release(operationID string) {
+	l.mu.Lock()
+	delete(l.held, operationID)
+	l.mu.Unlock()
+
+	if err := l.leases.Release(operationID, l.owner); err != nil {
+		// the lease expires if it is not renewed
+		l.log.Warn(fmt.Sprintf("unable to release the lease of operation %s: %s", operationID, err))
+	}
+}
+
+func (l *OperationLeaser) heldOperations() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	ids := make([]string, 0, len(l.held))
+	for id := range l.held {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+func (l *OperationLeaser) isHeld(operationID string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, found := l.held[operationID]
+	return found
+}
+
+func (l *OperationLeaser) renew() {
+	held := l.heldOperations()
+	if len(held) == 0 {
+		return
+	}
+	renewed, err := l.leases.Renew(l.owner, held, l.now(), l.cfg.Duration)
+	if err != nil {
+		l.log.Error(fmt.Sprintf("unable to renew operation leases: %s", err))
+		return
+	}
+
+	// leases of finished operations and leases taken over by other instances are not renewed
+	stillHeld := make(map[string]struct{}, len(renewed))
+	for _, id := range renewed {
+		stillHeld[id] = struct{}{}
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, id := range held {
+		if _, found := stillHeld[id]; !found {
+			delete(l.held, id)
+		}
+	}
+}
+
+func (l *OperationLeaser) recover(queues map[internal.OperationType]*Queue) {
+	for operationType, queue := range queues {
+		ids, err := l.leases.ListClaimable(operationType, l.owner, l.now())
+		if err != nil {
+			l.log.Error(fmt.Sprintf("unable to list %s operations which can be claimed: %s", operationType, err))
+			continue
+		}
+		for _, id := range ids {
+			if l.isHeld(id) {
+				continue
+			}
+			l.log.Info(fmt.Sprintf("Resuming the processing of %s operation ID: %s", operationType, id))
+			queue.Add(id)
+		}
+	}
+}
+
+type leasingExecutor struct {
+	leaser   *OperationLeaser
+	executor Executor
+}
+
+func (e *leasingExecutor) Execute(operationID string) (time.Duration, error) {
+	acquired, err := e.leaser.acquire(operationID)
+	if err != nil {
+		e.leaser.log.Error(fmt.Sprintf("unable to acquire the lease of operation %s, retrying: %s", operationID, err))
+		return leaseRetryInterval, nil
+	}
+	if !acquired {
+		e.leaser.log.Info(fmt.Sprintf("operation %s is leased by another instance, skipping", operationID))
+		return 0, nil
+	}
+
+	defer func() {
+		// the worker recovers the panic and drops the operation, so the lease must not be renewed
+		if r := recover(); r != nil {
+			e.leaser.release(operationID)
+			panic(r)
+		}
+	}()
+
+	when, err := e.executor.Execute(operationID)
+	if err != nil || when == 0 {
+		e.leaser.release(operationID)
+	}
+	return when, err
+}
@@ -0,0 +1,168 @@
+package process
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kyma-project/kyma-environment-broker/internal"
+	"github.com/kyma-project/kyma-environment-broker/internal/fixture"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage"
+
+	"github.com/pivotal-cf/brokerapi/v12/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeExecutor struct {
+	executed []string
+	when     time.Duration
+}
+
+func (e *fakeExecutor) Execute(operationID string) (time.Duration, error) {
+	e.executed = append(e.executed, operationID)
+	return e.when, nil
+}
+
+func fixLeaser(db storage.BrokerStorage, owner string, now time.Time) *OperationLeaser {
+	leaser := NewOperationLeaser(db.OperationLeases(), LeasingConfig{Owner: owner, Duration: time.Minute}, fixLogger())
+	leaser.now = func() time.Time { return now }
+	return leaser
+}
+
+func TestOperationLeaser_Executor(t *testing.T) {
+	// given
+	now := time.Now()
+	db := storage.NewMemoryStorage()
+	operation := fixture.FixProvisioningOperation("op-id", "instance-id")
+	operation.State = domain.InProgress
+	require.NoError(t, db.Operations().InsertOperation(operation))
+
+	first := &fakeExecutor{when: time.Second}
+	second := &fakeExecutor{}
+	firstLeaser := fixLeaser(db, "keb-1", now)
+	secondLeaser := fixLeaser(db, "keb-2", now)
+
+	// when
+	when, err := firstLeaser.Executor(first).Execute("op-id")
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, time.Second, when)
+	assert.True(t, firstLeaser.isHeld("op-id"))
+
+	// when
+	when, err = secondLeaser.Executor(second).Execute("op-id")
+
+	// then
+	require.NoError(t, err)
+	assert.Zero(t, when)
+	assert.Empty(t, second.executed, "operation leased by another instance must not be processed")
+
+	// when
+	first.when = 0
+	_, err = firstLeaser.Executor(first).Execute("op-id")
+	require.NoError(t, err)
+	_, err = secondLeaser.Executor(second).Execute("op-id")
+	require.NoError(t, err)
+
+	// then
+	assert.False(t, firstLeaser.isHeld("op-id"))
+	assert.Equal(t, []string{"op-id", "op-id"}, first.executed)
+	assert.Equal(t, []string{"op-id"}, second.executed, "released operation can be processed by another instance")
+}
+
+func TestOperationLeaser_NilLeaser(t *testing.T) {
+	// given
+	var leaser *OperationLeaser
+	executor := &fakeExecutor{}
+
+	// then
+	assert.Same(t, executor, leaser.Executor(executor))
+	assert.True(t, leaser.AcquireJob("job", time.Hour), "every instance runs the jobs without leasing")
+}
+
+func TestOperationLeaser_AcquireJob(t *testing.T) {
+	// given
+	now := time.Now()
+	db := storage.NewMemoryStorage()
+	firstLeaser := fixLeaser(db, "keb-1", now)
+	secondLeaser := fixLeaser(db, "keb-2", now)
+
+	// then
+	assert.True(t, firstLeaser.AcquireJob("job", time.Hour))
+	assert.False(t, secondLeaser.AcquireJob("job", time.Hour), "job leased by another instance")
+	assert.True(t, firstLeaser.AcquireJob("job", time.Hour), "the owner keeps the job")
+
+	// when
+	secondLeaser.now = func() time.Time { return now.Add(2 * time.Hour) }
+
+	// then
+	assert.True(t, secondLeaser.AcquireJob("job", time.Hour), "expired job lease is taken over")
+}
+
+func TestOperationLeaser_Renew(t *testing.T) {
+	// given
+	now := time.Now()
+	db := storage.NewMemoryStorage()
+	for _, id := range []string{"op-1", "op-2"} {
+		operation := fixture.FixProvisioningOperation(id, "instance-"+id)
+		operation.State = domain.InProgress
+		require.NoError(t, db.Operations().InsertOperation(operation))
+	}
+	leaser := fixLeaser(db, "keb-1", now)
+	executor := leaser.Executor(&fakeExecutor{when: time.Minute})
+	_, err := executor.Execute("op-1")
+	require.NoError(t, err)
+	_, err = executor.Execute("op-2")
+	require.NoError(t, err)
+
+	operation, err := db.Operations().GetOperationByID("op-2")
+	require.NoError(t, err)
+	operation.State = domain.Succeeded
+	_, err = db.Operations().UpdateOperation(*operation)
+	require.NoError(t, err)
+
+	// when
+	leaser.now = func() time.Time { return now.Add(50 * time.Second) }
+	leaser.renew()
+
+	// then
+	assert.True(t, leaser.isHeld("op-1"))
+	assert.False(t, leaser.isHeld("op-2"), "finished operation is not renewed")
+
+	acquired, err := db.OperationLeases().Acquire("op-1", "keb-2", now.Add(90*time.Second), time.Minute)
+	require.NoError(t, err)
+	assert.False(t, acquired, "renewed lease must not expire")
+}
+
+func TestOperationLeaser_RecoversExpiredLeases(t *testing.T) {
+	// given
+	now := time.Now()
+	db := storage.NewMemoryStorage()
+	for _, id := range []string{"op-1", "op-2"} {
+		operation := fixture.FixProvisioningOperation(id, "instance-"+id)
+		operation.State = domain.InProgress
+		require.NoError(t, db.Operations().InsertOperation(operation))
+	}
+	crashed := fixLeaser(db, "keb-1", now)
+	_, err := crashed.Executor(&fakeExecutor{when: time.Minute}).Execute("op-1")
+	require.NoError(t, err)
+
+	queue := NewQueue(&fakeExecutor{}, fixLogger(), "leasing-test")
+	defer queue.ShutDown()
+	queues := map[internal.OperationType]*Queue{internal.OperationTypeProvision: queue}
+	leaser := fixLeaser(db, "keb-2", now)
+
+	// when
+	leaser.recover(queues)
+
+	// then
+	assert.Equal(t, 1, queue.queue.Len(), "only the operation which is not leased is added")
+
+	// when
+	leaser.now = func() time.Time { return now.Add(2 * time.Minute) }
+	leaser.recover(queues)
+
+	// then
+	assert.Equal(t, 2, queue.queue.Len(), "operation with the expired lease is added")
+}
@@ -110,7 +110,7 @@ func (om *OperationManager) RetryOperation(operation internal.Operation, errorMe
 	log.Debug("Retry Operation was called", "message", errorMessage)
 
 	log.Debug("Retry Operation map size", "size", len(om.retryTimestamps))
-	om.storeTimestampIfMissing(operation.ID)
+	om.storeTimestampIfMissing(operation)
 	if !om.isTimeoutOccurred(operation.ID, maxTime) {
 		remainingTime := om.getRemainingTime(operation.ID, maxTime)
 		log.Debug("Retrying operation", "maxTime", maxTime, "retryInterval", retryInterval, "minutesLeft", int(remainingTime.Round(time.Second).Minutes()))
@@ -175,7 +175,7 @@ func (om *OperationManager) RetryOperationWithoutFail(operation internal.Operati
 	}
 
 	log.Debug("retrying operation", "maxTime", maxTime, "retryInterval", retryInterval)
-	om.storeTimestampIfMissing(operation.ID)
+	om.storeTimestampIfMissing(operation)
 	if !om.isTimeoutOccurred(operation.ID, maxTime) {
 		return operation, retryInterval, nil
 	}
@@ -216,6 +216,11 @@ func (om *OperationManager) UpdateOperation(operation internal.Operation, update
 				log.Error(fmt.Sprintf("while getting operation: %v", err))
 				return operation, 1 * time.Minute, err
 			}
+			// the operation has been terminated in the meantime (e.g. canceled by an administrator), it must not be overwritten
+			if isTerminatedExternally(op.State) && op.State != operation.State {
+				log.Info(fmt.Sprintf("operation is in state %s, skipping the update", op.State))
+				return *op, 0, nil
+			}
 			// do not optimize the flow by skipping the update call - it's required to update the `UpdatedAt` field
 			op.Merge(&operation)
 			update(op)
@@ -253,11 +258,13 @@ func (om *OperationManager) update(operation internal.Operation, state domain.La
 	}, log)
 }
 
-func (om *OperationManager) storeTimestampIfMissing(id string) {
+func (om *OperationManager) storeTimestampIfMissing(operation internal.Operation) {
 	om.mu.Lock()
 	defer om.mu.Unlock()
-	if om.retryTimestamps[id].IsZero() {
-		om.retryTimestamps[id] = time.Now()
+	ts := om.retryTimestamps[operation.ID]
+	// the timestamp stored before the operation was retried must not be used to calculate the timeout
+	if ts.IsZero() || (operation.RetriedAt != nil && ts.Before(*operation.RetriedAt)) {
+		om.retryTimestamps[operation.ID] = time.Now()
 	}
 }
 
@@ -37,6 +37,8 @@ const (
 	kcpRetryTimeout  = 20 * time.Second
 	dbRetryInterval  = 10 * time.Second
 	dbRetryTimeout   = 1 * time.Minute
+
+	placeholderValue = "resolved-during-provisioning"
 )
 
 type CreateRuntimeResourceStep struct {
@@ -76,6 +78,10 @@ func (s *CreateRuntimeResourceStep) Name() string {
 }
 
 func (s *CreateRuntimeResourceStep) Run(operation internal.Operation, log *slog.Logger) (internal.Operation, time.Duration, error) {
+	return s.RunWithContext(context.Background(), operation, log)
+}
+
+func (s *CreateRuntimeResourceStep) RunWithContext(ctx context.Context, operation internal.Operation, log *slog.Logger) (internal.Operation, time.Duration, error) {
 
 	kymaResourceName := operation.KymaResourceName
 	kymaResourceNamespace := operation.KymaResourceNamespace
@@ -84,7 +90,7 @@ func (s *CreateRuntimeResourceStep) Run(operation internal.Operation, log *slog.
 
 	operation.CloudProvider = string(provider.ProviderToCloudProvider(operation.ProviderValues.ProviderType))
 
-	runtimeCR, err := s.getEmptyOrExistingRuntimeResource(runtimeResourceName, kymaResourceNamespace)
+	runtimeCR, err := s.getEmptyOrExistingRuntimeResource(ctx, runtimeResourceName, kymaResourceNamespace)
 	if err != nil {
 		log.Error(fmt.Sprintf("unable to get Runtime resource %s/%s", operation.KymaResourceNamespace, runtimeResourceName))
 		return s.operationManager.RetryOperation(operation, "unable to get Runtime resource", err, kcpRetryInterval, kcpRetryTimeout, log)
@@ -95,14 +101,14 @@ func (s *CreateRuntimeResourceStep) Run(operation internal.Operation, log *slog.
 		return operation, 0, nil
 	} else {
 		var backoff time.Duration
-		err = s.updateRuntimeResourceObject(log, *operation.ProviderValues, runtimeCR, operation, runtimeResourceName, operation.CloudProvider)
+		err = s.updateRuntimeResourceObject(ctx, log, *operation.ProviderValues, runtimeCR, operation, runtimeResourceName, operation.CloudProvider)
 		if err != nil {
 			if kebError.IsTemporaryError(err) {
 				return s.operationManager.RetryOperation(operation, fmt.Sprintf("while creating Runtime CR object: %s", err), err, kcpRetryInterval, kcpRetryTimeout, log)
 			}
 			return s.operationManager.OperationFailed(operation, fmt.Sprintf("while creating Runtime CR object: %s", err), err, log)
 		}
-		err = s.k8sClient.Create(context.Background(), runtimeCR)
+		err = s.k8sClient.Create(ctx, runtimeCR)
 		if err != nil {
 			log.Error(fmt.Sprintf("unable to create Runtime resource: %s/%s: %s", operation.KymaResourceNamespace, runtimeResourceName, err.Error()))
 			return s.operationManager.RetryOperation(operation, "unable to create Runtime resource", err, kcpRetryInterval, kcpRetryTimeout, log)
@@ -135,14 +141,59 @@ func (s *CreateRuntimeResourceStep) Run(operation internal.Operation, log *slog.
 	}
 }
 
-func (s *CreateRuntimeResourceStep) updateRuntimeResourceObject(log *slog.Logger, values internal.ProviderValues, runtime *imv1.Runtime, operation internal.Operation, runtimeName, cloudProvider string) error {
+// RenderRuntimeResource builds the Runtime resource for the operation without creating it. Values resolved by the preceding
+// steps (runtime ID, credentials binding, discovered zones) are replaced with placeholders when they are not set yet.
+func (s *CreateRuntimeResourceStep) RenderRuntimeResource(ctx context.Context, operation internal.Operation, log *slog.Logger) (*imv1.Runtime, error) {
+	if operation.ProviderValues == nil {
+		return nil, fmt.Errorf("provider values are not set")
+	}
+	if operation.ProvisioningParameters.Parameters.TargetSecret == nil {
+		operation.ProvisioningParameters.Parameters.TargetSecret = ptr.String(placeholderValue)
+	}
+	if s.providerSpec.ZonesDiscovery(pkg.CloudProviderFromString(operation.ProviderValues.ProviderType)) {
+		operation.DiscoveredZones = placeholderDiscoveredZones(operation)
+	}
+
+	runtimeCR := &imv1.Runtime{}
+	cloudProvider := string(provider.ProviderToCloudProvider(operation.ProviderValues.ProviderType))
+	if err := s.updateRuntimeResourceObject(ctx, log, *operation.ProviderValues, runtimeCR, operation, steps.KymaRuntimeResourceName(operation), cloudProvider); err != nil {
+		return nil, err
+	}
+	return runtimeCR, nil
+}
+
+func placeholderDiscoveredZones(operation internal.Operation) map[string][]string {
+	values := operation.ProviderValues
+	zonesCount := max(values.ZonesCount, 3)
+	zones := make([]string, 0, zonesCount)
+	for i := 1; i <= zonesCount; i++ {
+		zones = append(zones, fmt.Sprintf("%s-%d", placeholderValue, i))
+	}
+
+	discoveredZones := make(map[string][]string, len(operation.DiscoveredZones))
+	for machineType, machineZones := range operation.DiscoveredZones {
+		discoveredZones[machineType] = machineZones
+	}
+	machineTypes := []string{DefaultIfParamNotSet(values.DefaultMachineType, operation.ProvisioningParameters.Parameters.MachineType)}
+	for _, pool := range operation.ProvisioningParameters.Parameters.AdditionalWorkerNodePools {
+		machineTypes = append(machineTypes, pool.MachineType)
+	}
+	for _, machineType := range machineTypes {
+		if len(discoveredZones[machineType]) < values.ZonesCount {
+			discoveredZones[machineType] = zones
+		}
+	}
+	return discoveredZones
+}
+
+func (s *CreateRuntimeResourceStep) updateRuntimeResourceObject(ctx context.Context, log *slog.Logger, values internal.ProviderValues, runtime *imv1.Runtime, operation internal.Operation, runtimeName, cloudProvider string) error {
 
 	runtime.ObjectMeta.Name = runtimeName
 	runtime.ObjectMeta.Namespace = operation.KymaResourceNamespace
 
 	runtime.ObjectMeta.Labels = s.createLabelsForRuntime(operation, values.Region, cloudProvider)
 
-	providerObj, err := s.createShootProvider(log, &operation, values)
+	providerObj, err := s.createShootProvider(ctx, log, &operation, values)
 	if err != nil {
 		return err
 	}
@@ -205,7 +256,7 @@ func (s *CreateRuntimeResourceStep) createSecurityConfiguration(operation intern
 	return security
 }
 
-func (s *CreateRuntimeResourceStep) createShootProvider(log *slog.Logger, operation *internal.Operation, values internal.ProviderValues) (imv1.Provider, error) {
+func (s *CreateRuntimeResourceStep) createShootProvider(ctx context.Context, log *slog.Logger, operation *internal.Operation, values internal.ProviderValues) (imv1.Provider, error) {
 
 	maxSurge := intstr.FromInt32(int32(DefaultIfParamNotSet(values.ZonesCount, operation.ProvisioningParameters.Parameters.MaxSurge)))
 	maxUnavailable := intstr.FromInt32(int32(DefaultIfParamNotSet(0, operation.ProvisioningParameters.Parameters.MaxUnavailable)))
@@ -246,7 +297,7 @@ func (s *CreateRuntimeResourceStep) createShootProvider(log *slog.Logger, operat
 
 	volGb := values.VolumeSizeGb
 	if s.kcrVolumeProvider != nil {
-		looked, err := s.kcrVolumeProvider.DefaultVolumeSizeGb(context.Background(),
+		looked, err := s.kcrVolumeProvider.DefaultVolumeSizeGb(ctx,
 			pkg.CloudProviderFromString(values.ProviderType),
 			provider.Workers[0].Machine.Type)
 		if err != nil {
@@ -274,7 +325,7 @@ func (s *CreateRuntimeResourceStep) createShootProvider(log *slog.Logger, operat
 				continue
 			}
 			resolvedType := s.providerSpec.ResolveMachineType(cp, pool.MachineType)
-			volGb, err := s.kcrVolumeProvider.DefaultVolumeSizeGb(context.Background(), cp, resolvedType)
+			volGb, err := s.kcrVolumeProvider.DefaultVolumeSizeGb(ctx, cp, resolvedType)
 			if err != nil {
 				return imv1.Provider{}, err
 			}
@@ -342,9 +393,9 @@ func (s *CreateRuntimeResourceStep) createNetworkingConfiguration(operation inte
 	}
 }
 
-func (s *CreateRuntimeResourceStep) getEmptyOrExistingRuntimeResource(name, namespace string) (*imv1.Runtime, error) {
+func (s *CreateRuntimeResourceStep) getEmptyOrExistingRuntimeResource(ctx context.Context, name, namespace string) (*imv1.Runtime, error) {
 	runtime := imv1.Runtime{}
-	err := s.k8sClient.Get(context.Background(), client.ObjectKey{
+	err := s.k8sClient.Get(ctx, client.ObjectKey{
 		Namespace: namespace,
 		Name:      name,
 	}, &runtime)
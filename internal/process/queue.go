@@ -18,15 +18,22 @@ type Executor interface {
 }
 
 type Queue struct {
-	queue     workqueue.TypedRateLimitingInterface[string]
-	executor  Executor
-	waitGroup sync.WaitGroup
-	log       *slog.Logger
-	name      string
+	queue      workqueue.TypedRateLimitingInterface[string]
+	storage    *fairShareQueue
+	classifier Classifier
+	executor   Executor
+	waitGroup  sync.WaitGroup
+	log        *slog.Logger
+	name       string
 
 	speedFactor       int64
 	workersInUseGauge prometheus.Gauge
-	queueDepthGauge   prometheus.Gauge
+
+	// pending holds IDs of items added to the queue and not yet taken by a worker,
+	// removed holds IDs of pending items which must be dropped instead of processed when a worker gets them
+	pending   map[string]struct{}
+	removed   map[string]struct{}
+	removedMu sync.Mutex
 }
 
 var queueWorkersInUseMetric = promauto.NewGaugeVec(prometheus.GaugeOpts{
@@ -40,37 +47,124 @@ var queueDepthMetric = promauto.NewGaugeVec(prometheus.GaugeOpts{
 	Namespace: "kcp",
 	Subsystem: "keb_v2",
 	Name:      "queue_depth",
-	Help:      "Number of items currently waiting in the queue",
-}, []string{"queue_name"})
+	Help:      "Number of items currently waiting in the queue per global account",
+}, []string{"queue_name", "global_account_id"})
 
+var queuePriorityDepthMetric = promauto.NewGaugeVec(prometheus.GaugeOpts{
+	Namespace: "kcp",
+	Subsystem: "keb_v2",
+	Name:      "queue_priority_depth",
+	Help:      "Number of items currently waiting in the queue per priority",
+}, []string{"queue_name", "priority"})
+
+// NewQueue creates a queue which processes all items with the normal priority, in FIFO order
 func NewQueue(executor Executor, log *slog.Logger, name string) *Queue {
+	return NewQueueWithClassifier(executor, log, name, nil)
+}
+
+// NewQueueWithClassifier creates a queue which schedules items according to the classifier:
+// priorities are served with the weighted round robin and global accounts get a fair share of the workers inside a priority
+func NewQueueWithClassifier(executor Executor, log *slog.Logger, name string, classifier Classifier) *Queue {
+	storage := newFairShareQueue(func(class Classification, priorityDepth, accountDepth int) {
+		queuePriorityDepthMetric.WithLabelValues(name, class.Priority.String()).Set(float64(priorityDepth))
+		// series of global accounts without queued items are deleted to keep the number of series bounded
+		if accountDepth == 0 {
+			queueDepthMetric.DeleteLabelValues(name, class.GlobalAccountID)
+			return
+		}
+		queueDepthMetric.WithLabelValues(name, class.GlobalAccountID).Set(float64(accountDepth))
+	})
+	delayingQueue := workqueue.NewTypedDelayingQueueWithConfig(workqueue.TypedDelayingQueueConfig[string]{
+		Name:  "operations",
+		Queue: workqueue.NewTypedWithConfig(workqueue.TypedQueueConfig[string]{Name: "operations", Queue: workqueue.Queue[string](storage)}),
+	})
 	// add queue name field that could be logged later on
 	return &Queue{
-		queue:             workqueue.NewTypedRateLimitingQueueWithConfig(workqueue.DefaultTypedControllerRateLimiter[string](), workqueue.TypedRateLimitingQueueConfig[string]{Name: "operations"}),
+		queue: workqueue.NewTypedRateLimitingQueueWithConfig(workqueue.DefaultTypedControllerRateLimiter[string](), workqueue.TypedRateLimitingQueueConfig[string]{
+			Name:          "operations",
+			DelayingQueue: delayingQueue,
+		}),
+		storage:           storage,
+		classifier:        classifier,
 		executor:          executor,
 		waitGroup:         sync.WaitGroup{},
 		log:               log.With("queueName", name),
 		speedFactor:       1,
 		name:              name,
 		workersInUseGauge: queueWorkersInUseMetric.WithLabelValues(name),
-		queueDepthGauge:   queueDepthMetric.WithLabelValues(name),
+		pending:           make(map[string]struct{}),
+		removed:           make(map[string]struct{}),
 	}
 }
 
 func (q *Queue) Add(processId string) {
+	q.markPending(processId)
+	q.classify(processId)
 	q.queue.Add(processId)
 	queueLen := q.queue.Len()
-	q.queueDepthGauge.Set(float64(queueLen))
 	q.log.Info(fmt.Sprintf("added item %s to the queue %s, queue length is %d", processId, q.name, queueLen))
 }
 
 func (q *Queue) AddAfter(processId string, duration time.Duration) {
+	q.markPending(processId)
+	q.classify(processId)
 	q.queue.AddAfter(processId, duration)
 	queueLen := q.queue.Len()
-	q.queueDepthGauge.Set(float64(queueLen))
 	q.log.Info(fmt.Sprintf("item %s will be added to the queue %s after duration of %s, queue length is %d", processId, q.name, duration, queueLen))
 }
 
+// Remove drops the item from the queue. The underlying workqueue does not support removing items,
+// so the item is marked as removed and skipped by the worker which gets it next, including items scheduled with AddAfter.
+// Items which are not pending (never added, being processed or already processed) are not marked.
+func (q *Queue) Remove(processId string) {
+	q.removedMu.Lock()
+	_, pending := q.pending[processId]
+	if pending {
+		q.removed[processId] = struct{}{}
+	}
+	q.removedMu.Unlock()
+	q.queue.Forget(processId)
+	if !pending {
+		q.log.Info(fmt.Sprintf("item %s is not pending in the queue %s, nothing to remove", processId, q.name))
+		return
+	}
+	q.log.Info(fmt.Sprintf("item %s marked as removed from the queue %s", processId, q.name))
+}
+
+// classify stores the classification of the item before it is added, the workqueue calls the storage under its lock, so the classifier is not called there.
+// The classification of an operation does not change, so it is kept until the item is processed and the classifier is called only for new items.
+func (q *Queue) classify(processId string) {
+	if q.classifier == nil || q.storage.isClassified(processId) {
+		return
+	}
+	class, err := q.classifier.Classify(processId)
+	if err != nil {
+		q.log.Warn(fmt.Sprintf("unable to classify item %s, using the normal priority: %s", processId, err))
+		class = defaultClassification
+	}
+	q.storage.setClassification(processId, class)
+}
+
+// markPending marks the item as pending and clears the removed mark, the item added again must be processed
+func (q *Queue) markPending(processId string) {
+	q.removedMu.Lock()
+	defer q.removedMu.Unlock()
+	q.pending[processId] = struct{}{}
+	delete(q.removed, processId)
+}
+
+// takeRemoved clears the pending mark of the item taken by a worker and returns true if the item was marked as removed
+func (q *Queue) takeRemoved(processId string) bool {
+	q.removedMu.Lock()
+	defer q.removedMu.Unlock()
+	delete(q.pending, processId)
+	if _, found := q.removed[processId]; !found {
+		return false
+	}
+	delete(q.removed, processId)
+	return true
+}
+
 func (q *Queue) ShutDown() {
 	q.log.Info(fmt.Sprintf("shutting down the queue, queue length is %d", q.queue.Len()))
 	q.queue.ShutDown()
@@ -112,20 +206,30 @@ func (q *Queue) worker(queue workqueue.TypedRateLimitingInterface[string], proce
 					return true
 				}
 
+				if q.takeRemoved(key) {
+					queue.Forget(key)
+					queue.Done(key)
+					q.storage.forget(key)
+					log.Info(fmt.Sprintf("item %s has been removed from the queue, skipping", key))
+					return false
+				}
+
 				q.workersInUseGauge.Inc()
 				queueLen := queue.Len()
-				q.queueDepthGauge.Set(float64(queueLen))
 				id := key
 				workerLogger := log.With("operationID", id)
 				workerLogger.Info(fmt.Sprintf("about to process item %s, queue length is %d", id, queueLen))
 
+				finished := false
 				defer func() {
 					q.workersInUseGauge.Dec()
 					if err := recover(); err != nil {
 						workerLogger.Error(fmt.Sprintf("panic error from process: %v. Stacktrace: %s", err, debug.Stack()))
 					}
 					queue.Done(key)
-					q.queueDepthGauge.Set(float64(queue.Len()))
+					if finished {
+						q.storage.forget(key)
+					}
 					workerLogger.Info("queue done processing")
 				}()
 
@@ -133,6 +237,7 @@ func (q *Queue) worker(queue workqueue.TypedRateLimitingInterface[string], proce
 				if err == nil && when != 0 {
 					workerLogger.Info(fmt.Sprintf("Adding %q item after %s, queue length %d", id, when, queue.Len()))
 					afterDuration := time.Duration(int64(when) / q.speedFactor)
+					q.markPending(key)
 					queue.AddAfter(key, afterDuration)
 					return false
 				}
@@ -141,6 +246,7 @@ func (q *Queue) worker(queue workqueue.TypedRateLimitingInterface[string], proce
 				}
 
 				queue.Forget(key)
+				finished = true
 				workerLogger.Info(fmt.Sprintf("item for %s has been processed, no retry, element forgotten", id))
 
 				return false
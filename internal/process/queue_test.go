@@ -9,6 +9,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
 	dto "github.com/prometheus/client_model/go"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -23,10 +24,33 @@ func (e *StdExecutor) Execute(operationID string) (time.Duration, error) {
 	return 0, nil
 }
 
-func gaugeValue(t *testing.T, queueName string) float64 {
+// depthSeries returns the queue depth series of the queue by the global account
+func depthSeries(t *testing.T, queueName string) map[string]float64 {
+	t.Helper()
+	metrics := make(chan prometheus.Metric)
+	go func() {
+		queueDepthMetric.Collect(metrics)
+		close(metrics)
+	}()
+	series := map[string]float64{}
+	for metric := range metrics {
+		var m dto.Metric
+		require.NoError(t, metric.Write(&m))
+		labels := map[string]string{}
+		for _, label := range m.GetLabel() {
+			labels[label.GetName()] = label.GetValue()
+		}
+		if labels["queue_name"] == queueName {
+			series[labels["global_account_id"]] = m.GetGauge().GetValue()
+		}
+	}
+	return series
+}
+
+func priorityGaugeValue(t *testing.T, queueName string, priority Priority) float64 {
 	t.Helper()
 	var m dto.Metric
-	require.NoError(t, queueDepthMetric.WithLabelValues(queueName).Write(&m))
+	require.NoError(t, queuePriorityDepthMetric.WithLabelValues(queueName, priority.String()).Write(&m))
 	return m.GetGauge().GetValue()
 }
 
@@ -42,17 +66,17 @@ func TestQueueDepthMetric(t *testing.T) {
 		<-release
 	}}, logger, name)
 
-	assert.Equal(t, 0.0, gaugeValue(t, name), "depth should be 0 before any items added")
+	assert.Empty(t, depthSeries(t, name), "no series before any items added")
 
 	q.Add("op-1")
-	assert.Equal(t, 1.0, gaugeValue(t, name), "depth should be 1 after Add")
+	assert.Equal(t, map[string]float64{"": 1}, depthSeries(t, name), "depth should be 1 after Add")
 
 	ctx, cancel := context.WithCancel(context.Background())
 	q.Run(ctx.Done(), 1)
 
 	// wait until the worker picks up the item
 	<-processing
-	assert.Equal(t, 0.0, gaugeValue(t, name), "depth should be 0 after Get")
+	assert.Empty(t, depthSeries(t, name), "series should be deleted after Get")
 
 	close(release)
 	cancel()
@@ -96,6 +120,55 @@ func TestWorkerLogging(t *testing.T) {
 
 }
 
+func TestQueueRemove(t *testing.T) {
+	// given
+	logger := slog.New(slog.NewTextHandler(&bytes.Buffer{}, nil))
+	var mu sync.Mutex
+	var executed []string
+	processed := make(chan struct{}, 2)
+
+	queue := NewQueue(&StdExecutor{logger: func(msg string) {
+		mu.Lock()
+		executed = append(executed, msg)
+		mu.Unlock()
+		processed <- struct{}{}
+	}}, logger, "remove-test")
+
+	queue.Add("removed-op")
+	queue.Remove("removed-op")
+	queue.Add("processed-op")
+	queue.Remove("processed-op")
+	queue.Add("processed-op")
+
+	// when
+	cancelContext, cancel := context.WithCancel(context.Background())
+	queue.Run(cancelContext.Done(), 1)
+	<-processed
+
+	queue.ShutDown()
+	cancel()
+	queue.waitGroup.Wait()
+
+	// then
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{"executing operation processed-op"}, executed)
+	assert.Empty(t, queue.pending)
+	assert.Empty(t, queue.removed)
+}
+
+func TestQueueRemoveNotPending(t *testing.T) {
+	// given
+	logger := slog.New(slog.NewTextHandler(&bytes.Buffer{}, nil))
+	queue := NewQueue(&StdExecutor{logger: func(string) {}}, logger, "remove-not-pending-test")
+
+	// when
+	queue.Remove("unknown-op")
+
+	// then
+	assert.Empty(t, queue.removed)
+}
+
 type captureWriter struct {
 	buf *bytes.Buffer
 }
@@ -0,0 +1,296 @@
+package process
+
+import (
+	"container/heap"
+	"sync"
+)
+
+// Priority is the scheduling class of an item in the queue. Items of a higher priority are processed more often,
+// but lower priorities are never starved.
+type Priority int
+
+const (
+	PriorityLow Priority = iota
+	PriorityNormal
+	PriorityHigh
+)
+
+func (p Priority) String() string {
+	switch p {
+	case PriorityLow:
+		return "low"
+	case PriorityHigh:
+		return "high"
+	default:
+		return "normal"
+	}
+}
+
+// Classification describes how an item is scheduled: the tenant (global account) which owns it and its priority
+type Classification struct {
+	GlobalAccountID string
+	Priority        Priority
+}
+
+var defaultClassification = Classification{Priority: PriorityNormal}
+
+// Classifier provides the classification of an operation processed by the queue
+type Classifier interface {
+	Classify(operationID string) (Classification, error)
+}
+
+// priorityWeights defines how many items of the given priority are taken in one round of the weighted round robin
+var priorityWeights = [...]int{
+	PriorityLow:    1,
+	PriorityNormal: 2,
+	PriorityHigh:   4,
+}
+
+// fairShareQueue implements the storage of the workqueue.
+// Priorities are served with the weighted round robin, inside a priority the global account which was served least recently goes first,
+// and items of one global account are processed in FIFO order.
+// Push, Pop, Touch and Len are called by the workqueue under its lock, the classifications are set by the Queue before the item is added.
+type fairShareQueue struct {
+	mu sync.Mutex
+
+	// classifications holds the classification which is used when the item is pushed
+	classifications map[string]Classification
+	// queued holds the classification of the items which are currently in the queue
+	queued map[string]Classification
+
+	priorities [len(priorityWeights)]*tenantHeap
+	credits    [len(priorityWeights)]int
+	depths     [len(priorityWeights)]int
+	size       int
+	sequence   uint64
+
+	// accountDepths holds the number of queued items per global account, global accounts without queued items are dropped
+	accountDepths map[string]int
+
+	onDepthChange func(class Classification, priorityDepth, accountDepth int)
+}
+
+func newFairShareQueue(onDepthChange func(class Classification, priorityDepth, accountDepth int)) *fairShareQueue {
+	q := &fairShareQueue{
+		classifications: make(map[string]Classification),
+		queued:          make(map[string]Classification),
+		accountDepths:   make(map[string]int),
+		onDepthChange:   onDepthChange,
+	}
+	for i := range q.priorities {
+		q.priorities[i] = &tenantHeap{tenants: make(map[string]*tenant)}
+	}
+	return q
+}
+
+// setClassification stores the classification used for the item from now on
+func (q *fairShareQueue) setClassification(item string, class Classification) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.classifications[item] = class
+}
+
+// forget drops the stored classification of the item which is processed, unless the item was queued again in the meantime
+func (q *fairShareQueue) forget(item string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if _, found := q.queued[item]; found {
+		return
+	}
+	delete(q.classifications, item)
+}
+
+// isClassified returns true if the item has a classification which is not forgotten yet
+func (q *fairShareQueue) isClassified(item string) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	_, found := q.classifications[item]
+	return found
+}
+
+func (q *fairShareQueue) classificationOf(item string) Classification {
+	if class, found := q.classifications[item]; found {
+		return class
+	}
+	return defaultClassification
+}
+
+func (q *fairShareQueue) Push(item string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.push(item, q.classificationOf(item))
+}
+
+// Touch is called when an item which is already queued is added again, the item is moved if its classification changed
+func (q *fairShareQueue) Touch(item string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	current, found := q.queued[item]
+	if !found {
+		return
+	}
+	class := q.classificationOf(item)
+	if class == current {
+		return
+	}
+	q.priorities[current.Priority].remove(current.GlobalAccountID, item)
+	delete(q.queued, item)
+	q.dequeued(current)
+	q.push(item, class)
+}
+
+func (q *fairShareQueue) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.size
+}
+
+func (q *fairShareQueue) Pop() string {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.size == 0 {
+		return ""
+	}
+	priority := q.nextPriority()
+	q.sequence++
+	item := q.priorities[priority].pop(q.sequence)
+	class := q.queued[item]
+	delete(q.queued, item)
+	q.dequeued(class)
+	return item
+}
+
+func (q *fairShareQueue) push(item string, class Classification) {
+	q.sequence++
+	q.priorities[class.Priority].push(class.GlobalAccountID, item, q.sequence)
+	q.queued[item] = class
+	q.size++
+	q.depths[class.Priority]++
+	q.accountDepths[class.GlobalAccountID]++
+	q.notify(class)
+}
+
+func (q *fairShareQueue) dequeued(class Classification) {
+	q.size--
+	q.depths[class.Priority]--
+	q.accountDepths[class.GlobalAccountID]--
+	q.notify(class)
+	if q.accountDepths[class.GlobalAccountID] == 0 {
+		delete(q.accountDepths, class.GlobalAccountID)
+	}
+}
+
+// nextPriority returns the highest non-empty priority with credits left in the current round, a new round starts when there is none
+func (q *fairShareQueue) nextPriority() Priority {
+	for {
+		for p := len(q.priorities) - 1; p >= 0; p-- {
+			if q.credits[p] > 0 && q.priorities[p].Len() > 0 {
+				q.credits[p]--
+				return Priority(p)
+			}
+		}
+		q.credits = priorityWeights
+	}
+}
+
+func (q *fairShareQueue) notify(class Classification) {
+	if q.onDepthChange == nil {
+		return
+	}
+	q.onDepthChange(class, q.depths[class.Priority], q.accountDepths[class.GlobalAccountID])
+}
+
+type queuedItem struct {
+	id       string
+	sequence uint64
+}
+
+type tenant struct {
+	globalAccountID string
+	items           []queuedItem
+	lastServed      uint64
+	index           int
+}
+
+// tenantHeap orders global accounts by the time they were served last, ties are resolved by the age of the oldest item.
+// A global account without queued items is dropped, when it adds an item again it is treated as served at that moment,
+// so it does not go ahead of global accounts which are already waiting.
+type tenantHeap struct {
+	heap    []*tenant
+	tenants map[string]*tenant
+}
+
+func (h *tenantHeap) push(globalAccountID, item string, sequence uint64) {
+	t, found := h.tenants[globalAccountID]
+	if !found {
+		t = &tenant{globalAccountID: globalAccountID, lastServed: sequence}
+		h.tenants[globalAccountID] = t
+		t.items = append(t.items, queuedItem{id: item, sequence: sequence})
+		heap.Push(h, t)
+		return
+	}
+	t.items = append(t.items, queuedItem{id: item, sequence: sequence})
+}
+
+func (h *tenantHeap) pop(sequence uint64) string {
+	t := h.heap[0]
+	item := t.items[0].id
+	t.items = t.items[1:]
+	if len(t.items) == 0 {
+		heap.Pop(h)
+		delete(h.tenants, t.globalAccountID)
+		return item
+	}
+	t.lastServed = sequence
+	heap.Fix(h, t.index)
+	return item
+}
+
+func (h *tenantHeap) remove(globalAccountID, item string) {
+	t, found := h.tenants[globalAccountID]
+	if !found {
+		return
+	}
+	for i, queued := range t.items {
+		if queued.id == item {
+			t.items = append(t.items[:i], t.items[i+1:]...)
+			break
+		}
+	}
+	if len(t.items) == 0 {
+		heap.Remove(h, t.index)
+		delete(h.tenants, globalAccountID)
+		return
+	}
+	heap.Fix(h, t.index)
+}
+
+func (h *tenantHeap) Len() int { return len(h.heap) }
+
+func (h *tenantHeap) Less(i, j int) bool {
+	if h.heap[i].lastServed != h.heap[j].lastServed {
+		return h.heap[i].lastServed < h.heap[j].lastServed
+	}
+	return h.heap[i].items[0].sequence < h.heap[j].items[0].sequence
+}
+
+func (h *tenantHeap) Swap(i, j int) {
+	h.heap[i], h.heap[j] = h.heap[j], h.heap[i]
+	h.heap[i].index = i
+	h.heap[j].index = j
+}
+
+func (h *tenantHeap) Push(x any) {
+	t := x.(*tenant)
+	t.index = len(h.heap)
+	h.heap = append(h.heap, t)
+}
+
+func (h *tenantHeap) Pop() any {
+	old := h.heap
+	n := len(old)
+	t := old[n-1]
+	old[n-1] = nil
+	h.heap = old[:n-1]
+	return t
+}
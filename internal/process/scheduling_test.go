@@ -0,0 +1,232 @@
+package process
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/kyma-project/kyma-environment-broker/internal/fixture"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func pushClassified(q *fairShareQueue, item string, class Classification) {
+	q.setClassification(item, class)
+	q.Push(item)
+}
+
+func popAll(q *fairShareQueue) []string {
+	var items []string
+	for q.Len() > 0 {
+		items = append(items, q.Pop())
+	}
+	return items
+}
+
+func TestFairShareQueue_GlobalAccountFairness(t *testing.T) {
+	// given
+	q := newFairShareQueue(nil)
+	for i := 0; i < 5; i++ {
+		pushClassified(q, fmt.Sprintf("ga1-%d", i), Classification{GlobalAccountID: "ga1", Priority: PriorityNormal})
+	}
+	pushClassified(q, "ga2-0", Classification{GlobalAccountID: "ga2", Priority: PriorityNormal})
+	pushClassified(q, "ga2-1", Classification{GlobalAccountID: "ga2", Priority: PriorityNormal})
+
+	// when
+	first := q.Pop()
+	pushClassified(q, "ga3-0", Classification{GlobalAccountID: "ga3", Priority: PriorityNormal})
+	rest := popAll(q)
+
+	// then
+	assert.Equal(t, "ga1-0", first)
+	assert.Equal(t, []string{"ga2-0", "ga1-1", "ga3-0", "ga2-1", "ga1-2", "ga1-3", "ga1-4"}, rest)
+}
+
+func TestFairShareQueue_ReturningGlobalAccountDoesNotJumpTheQueue(t *testing.T) {
+	// given
+	q := newFairShareQueue(nil)
+	pushClassified(q, "busy-0", Classification{GlobalAccountID: "busy", Priority: PriorityNormal})
+	pushClassified(q, "ga1-0", Classification{GlobalAccountID: "ga1", Priority: PriorityNormal})
+	pushClassified(q, "ga2-0", Classification{GlobalAccountID: "ga2", Priority: PriorityNormal})
+	pushClassified(q, "ga2-1", Classification{GlobalAccountID: "ga2", Priority: PriorityNormal})
+
+	// when
+	var items []string
+	for i := 1; i <= 3; i++ {
+		items = append(items, q.Pop())
+		// the busy global account adds its next operation right after the previous one is taken
+		pushClassified(q, fmt.Sprintf("busy-%d", i), Classification{GlobalAccountID: "busy", Priority: PriorityNormal})
+	}
+	items = append(items, popAll(q)...)
+
+	// then
+	assert.Equal(t, []string{"busy-0", "ga1-0", "ga2-0", "busy-1", "ga2-1", "busy-2", "busy-3"}, items)
+}
+
+func TestFairShareQueue_PriorityWeights(t *testing.T) {
+	// given
+	q := newFairShareQueue(nil)
+	for i := 0; i < 6; i++ {
+		pushClassified(q, fmt.Sprintf("low-%d", i), Classification{GlobalAccountID: "ga", Priority: PriorityLow})
+		pushClassified(q, fmt.Sprintf("normal-%d", i), Classification{GlobalAccountID: "ga", Priority: PriorityNormal})
+		pushClassified(q, fmt.Sprintf("high-%d", i), Classification{GlobalAccountID: "ga", Priority: PriorityHigh})
+	}
+
+	// when
+	items := popAll(q)
+
+	// then
+	assert.Equal(t, []string{
+		"high-0", "high-1", "high-2", "high-3", "normal-0", "normal-1", "low-0",
+		"high-4", "high-5", "normal-2", "normal-3", "low-1",
+		"normal-4", "normal-5", "low-2",
+		"low-3", "low-4", "low-5",
+	}, items)
+}
+
+func TestFairShareQueue_UnclassifiedItems(t *testing.T) {
+	// given
+	q := newFairShareQueue(nil)
+	q.Push("op-1")
+	q.Push("op-2")
+	pushClassified(q, "op-3", Classification{Priority: PriorityLow})
+
+	// then
+	assert.Equal(t, []string{"op-1", "op-2", "op-3"}, popAll(q))
+}
+
+func TestFairShareQueue_TouchChangesClassification(t *testing.T) {
+	// given
+	q := newFairShareQueue(nil)
+	pushClassified(q, "op-1", Classification{GlobalAccountID: "ga", Priority: PriorityNormal})
+	pushClassified(q, "op-2", Classification{GlobalAccountID: "ga", Priority: PriorityLow})
+
+	// when
+	q.setClassification("op-2", Classification{GlobalAccountID: "ga", Priority: PriorityHigh})
+	q.Touch("op-2")
+
+	// then
+	assert.Equal(t, 2, q.Len())
+	assert.Equal(t, []string{"op-2", "op-1"}, popAll(q))
+}
+
+func TestFairShareQueue_Forget(t *testing.T) {
+	// given
+	q := newFairShareQueue(nil)
+	class := Classification{GlobalAccountID: "ga", Priority: PriorityHigh}
+	pushClassified(q, "op-1", class)
+
+	// when
+	q.forget("op-1")
+
+	// then
+	assert.Equal(t, class, q.classificationOf("op-1"), "queued item keeps its classification")
+
+	// when
+	q.Pop()
+	q.forget("op-1")
+
+	// then
+	assert.Equal(t, defaultClassification, q.classificationOf("op-1"))
+}
+
+func TestFairShareQueue_DepthChanges(t *testing.T) {
+	// given
+	depths := map[Priority]int{}
+	accountDepths := map[string]int{}
+	q := newFairShareQueue(func(class Classification, priorityDepth, accountDepth int) {
+		depths[class.Priority] = priorityDepth
+		accountDepths[class.GlobalAccountID] = accountDepth
+	})
+
+	// when
+	pushClassified(q, "op-1", Classification{GlobalAccountID: "ga1", Priority: PriorityHigh})
+	pushClassified(q, "op-2", Classification{GlobalAccountID: "ga2", Priority: PriorityHigh})
+	pushClassified(q, "op-3", Classification{GlobalAccountID: "ga1", Priority: PriorityLow})
+
+	// then
+	assert.Equal(t, map[Priority]int{PriorityHigh: 2, PriorityLow: 1}, depths)
+	assert.Equal(t, map[string]int{"ga1": 2, "ga2": 1}, accountDepths)
+
+	// when
+	popAll(q)
+
+	// then
+	assert.Equal(t, map[Priority]int{PriorityHigh: 0, PriorityLow: 0}, depths)
+	assert.Equal(t, map[string]int{"ga1": 0, "ga2": 0}, accountDepths)
+	assert.Empty(t, q.accountDepths)
+}
+
+func TestQueueDepthMetricPerPriority(t *testing.T) {
+	// given
+	name := fmt.Sprintf("tenant-depth-test-%d", time.Now().UnixNano())
+	db := storage.NewMemoryStorage()
+	provisioning := fixture.FixProvisioningOperation("provisioning-id", "instance-id")
+	provisioning.ProvisioningParameters.ErsContext.GlobalAccountID = "ga-1"
+	require.NoError(t, db.Operations().InsertOperation(provisioning))
+	suspension := fixture.FixSuspensionOperationAsOperation("suspension-id", "instance-id-2")
+	suspension.ProvisioningParameters.ErsContext.GlobalAccountID = "ga-2"
+	require.NoError(t, db.Operations().InsertOperation(suspension))
+
+	q := NewQueueWithClassifier(&StdExecutor{logger: func(string) {}}, fixLogger(), name, NewOperationClassifier(db.Operations(), isTrialPlan))
+
+	// when
+	q.Add("provisioning-id")
+	q.Add("suspension-id")
+
+	// then
+	assert.Equal(t, 1.0, priorityGaugeValue(t, name, PriorityHigh))
+	assert.Equal(t, 1.0, priorityGaugeValue(t, name, PriorityLow))
+	assert.Equal(t, map[string]float64{"ga-1": 1, "ga-2": 1}, depthSeries(t, name))
+	q.ShutDown()
+}
+
+func TestQueueDepthMetricDeletesEmptyGlobalAccounts(t *testing.T) {
+	// given
+	name := fmt.Sprintf("tenant-cleanup-test-%d", time.Now().UnixNano())
+	classifier := &countingClassifier{}
+	q := NewQueueWithClassifier(&StdExecutor{logger: func(string) {}}, fixLogger(), name, classifier)
+	q.Add("op-1")
+	q.Add("op-2")
+	require.Equal(t, map[string]float64{"ga": 2}, depthSeries(t, name))
+
+	// when
+	item, _ := q.queue.Get()
+	q.queue.Done(item)
+
+	// then
+	assert.Equal(t, map[string]float64{"ga": 1}, depthSeries(t, name))
+
+	// when
+	item, _ = q.queue.Get()
+	q.queue.Done(item)
+
+	// then
+	assert.Empty(t, depthSeries(t, name))
+	q.ShutDown()
+}
+
+type countingClassifier struct {
+	calls int
+}
+
+func (c *countingClassifier) Classify(string) (Classification, error) {
+	c.calls++
+	return Classification{GlobalAccountID: "ga", Priority: PriorityHigh}, nil
+}
+
+func TestQueueClassifiesItemOnce(t *testing.T) {
+	// given
+	classifier := &countingClassifier{}
+	q := NewQueueWithClassifier(&StdExecutor{logger: func(string) {}}, fixLogger(), fmt.Sprintf("classify-once-test-%d", time.Now().UnixNano()), classifier)
+
+	// when
+	q.Add("op-1")
+	q.Add("op-1")
+	q.AddAfter("op-1", time.Hour)
+
+	// then
+	assert.Equal(t, 1, classifier.calls)
+	q.ShutDown()
+}
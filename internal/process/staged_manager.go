@@ -12,8 +12,12 @@ import (
 	kebError "github.com/kyma-project/kyma-environment-broker/internal/error"
 	"github.com/kyma-project/kyma-environment-broker/internal/event"
 	"github.com/kyma-project/kyma-environment-broker/internal/storage"
+	"github.com/kyma-project/kyma-environment-broker/internal/tracing"
 
 	"github.com/pivotal-cf/brokerapi/v12/domain"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
 type StagedManager struct {
@@ -43,6 +47,13 @@ type Step interface {
 	Run(operation internal.Operation, logger *slog.Logger) (internal.Operation, time.Duration, error)
 }
 
+// StepWithContext is a step which gets the context of the span which processes it. The manager runs it with RunWithContext
+// instead of Run, calls made by the step to external services must use the context, so they belong to the trace of the operation.
+type StepWithContext interface {
+	Step
+	RunWithContext(ctx context.Context, operation internal.Operation, logger *slog.Logger) (internal.Operation, time.Duration, error)
+}
+
 type StepCondition func(operation internal.Operation) bool
 
 type StepWithCondition struct {
@@ -113,14 +124,25 @@ func (m *StagedManager) Execute(operationID string) (time.Duration, error) {
 	}
 
 	logOperation := m.log.With("operationID", operationID, "instanceID", operation.InstanceID, "planID", operation.ProvisioningParameters.PlanID)
+	if isTerminatedExternally(operation.State) {
+		logOperation.Info(fmt.Sprintf("Operation is in state %s, processing skipped", operation.State))
+		return 0, nil
+	}
+
+	// every processing of the operation is a child of the root span of the operation, recorded when the operation finishes
+	ctx, span := tracing.Tracer().Start(tracing.OperationContext(context.Background(), operationID), fmt.Sprintf("process %s", operation.Type),
+		trace.WithAttributes(operationAttributes(*operation)...))
+	defer span.End()
+
 	logOperation.Info(fmt.Sprintf("Start process operation steps for GlobalAccount=%s, ", operation.ProvisioningParameters.ErsContext.GlobalAccountID))
-	if time.Since(operation.CreatedAt) > m.operationTimeout {
+	if time.Since(operation.ProcessingStartedAt()) > m.operationTimeout {
 		timeoutErr := kebError.TimeoutError("operation has reached the time limit", string(kebError.KEBDependency))
 		operation.LastError = timeoutErr
 		defer m.publishEventOnFail(operation, err)
-		logOperation.Info(fmt.Sprintf("operation has reached the time limit: operation was created at: %s, timeout: %s elapsed %s",
-			operation.CreatedAt.Format(time.RFC3339Nano), m.operationTimeout.String(), time.Since(operation.CreatedAt).String()))
+		logOperation.Info(fmt.Sprintf("operation has reached the time limit: operation was created at: %s, processing started at: %s, timeout: %s elapsed %s",
+			operation.CreatedAt.Format(time.RFC3339Nano), operation.ProcessingStartedAt().Format(time.RFC3339Nano), m.operationTimeout.String(), time.Since(operation.ProcessingStartedAt()).String()))
 		operation.State = domain.Failed
+		span.SetStatus(codes.Error, timeoutErr.Error())
 		_, err = m.operationStorage.UpdateOperation(*operation)
 		if err != nil {
 			logOperation.Info("Unable to save operation with finished the provisioning process")
@@ -135,10 +157,19 @@ func (m *StagedManager) Execute(operationID string) (time.Duration, error) {
 	var when time.Duration
 	processedOperation := *operation
 
+	var stageSpan trace.Span
+	defer func() {
+		if stageSpan != nil {
+			stageSpan.End()
+		}
+	}()
+
 	for _, stage := range m.stages {
 		if processedOperation.IsStageFinished(stage.name) {
 			continue
 		}
+		var stageCtx context.Context
+		stageCtx, stageSpan = tracing.Tracer().Start(ctx, fmt.Sprintf("stage %s", stage.name), trace.WithAttributes(tracing.AttributeStage.String(stage.name)))
 
 		for _, step := range stage.steps {
 			logStep := logOperation.With("step", step.Name()).
@@ -149,12 +180,18 @@ func (m *StagedManager) Execute(operationID string) (time.Duration, error) {
 			}
 			operation.EventInfof("processing step: %v", step.Name())
 
-			processedOperation, when, err = m.runStep(step, processedOperation, logStep)
+			processedOperation, when, err = m.runStep(stageCtx, step.Step, processedOperation, logStep)
 			if err != nil {
+				span.SetStatus(codes.Error, err.Error())
 				logStep.Error(fmt.Sprintf("Process operation failed: %s", err))
 				operation.EventErrorf(err, "step %v processing returned error", step.Name())
 				return 0, err
 			}
+			if processedOperation.State == internal.OperationStateCanceled {
+				logStep.Info(fmt.Sprintf("Operation %q has been canceled. Process finished.", operation.ID))
+				operation.EventInfof("operation processing canceled")
+				return 0, nil
+			}
 			if processedOperation.State == domain.Failed || processedOperation.State == domain.Succeeded {
 				logStep.Info(fmt.Sprintf("Operation %q got status %s. Process finished.", operation.ID, processedOperation.State))
 				operation.EventInfof("operation processing %v", processedOperation.State)
@@ -171,6 +208,7 @@ func (m *StagedManager) Execute(operationID string) (time.Duration, error) {
 		}
 
 		processedOperation, err = m.saveFinishedStage(processedOperation, stage, logOperation)
+		stageSpan.End()
 
 		// it is ok, when operation does not exist in the DB - it can happen at the end of a deprovisioning process
 		if err != nil && !dberr.IsNotFound(err) {
@@ -195,6 +233,12 @@ func (m *StagedManager) Execute(operationID string) (time.Duration, error) {
 	return 0, nil
 }
 
+// isTerminatedExternally returns true for states which can be set outside of the staged manager (e.g. by an administrator)
+// and which must stop the processing of the operation
+func isTerminatedExternally(state domain.LastOperationState) bool {
+	return state == internal.OperationStateCanceled || state == internal.OperationStateCanceling || state == domain.Failed
+}
+
 func (m *StagedManager) saveFinishedStage(operation internal.Operation, s *stage, log *slog.Logger) (internal.Operation, error) {
 	operation.FinishStage(s.name)
 	op, err := m.operationStorage.UpdateOperation(operation)
@@ -204,11 +248,22 @@ func (m *StagedManager) saveFinishedStage(operation internal.Operation, s *stage
 		return operation, err
 	}
 	log.Info(fmt.Sprintf("Finished stage %s", s.name))
+	m.publisher.Publish(context.TODO(), OperationStageFinished{
+		Stage:     s.name,
+		Operation: operation,
+	})
 	return *op, nil
 }
 
-func (m *StagedManager) runStep(step Step, operation internal.Operation, logger *slog.Logger) (processedOperation internal.Operation, backoff time.Duration, err error) {
+func (m *StagedManager) runStep(ctx context.Context, step Step, operation internal.Operation, logger *slog.Logger) (processedOperation internal.Operation, backoff time.Duration, err error) {
 	var start time.Time
+	ctx, span := tracing.Tracer().Start(ctx, step.Name(), trace.WithAttributes(tracing.AttributeStep.String(step.Name())))
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}()
 	defer func() {
 		if pErr := recover(); pErr != nil {
 			logger.Info(fmt.Sprintf("panic in RunStep in staged manager: %v", pErr))
@@ -220,11 +275,11 @@ func (m *StagedManager) runStep(step Step, operation internal.Operation, logger
 
 	processedOperation = operation
 	begin := time.Now()
-	for {
+	for iteration := 1; ; iteration++ {
 		start = time.Now()
 		logger.Info("Start step")
 		stepLogger := logger.With("step", step.Name(), "operationID", processedOperation.ID)
-		processedOperation, backoff, err = step.Run(processedOperation, stepLogger)
+		processedOperation, backoff, err = m.runStepIteration(ctx, step, processedOperation, stepLogger, iteration)
 		if err != nil {
 			logOperation := stepLogger.With("error_component", processedOperation.LastError.GetComponent(), "error_reason", processedOperation.LastError.GetReason())
 			logOperation.Warn(fmt.Sprintf("Last error from step: %s", processedOperation.LastError.Error()))
@@ -249,8 +304,10 @@ func (m *StagedManager) runStep(step Step, operation internal.Operation, logger
 		// break the loop if:
 		// - the step does not need a retry
 		// - step returns an error
-		// - the loop takes too much time (to not block the worker too long)
-		if backoff == 0 || err != nil || time.Since(begin) > m.cfg.MaxStepProcessingTime {
+		// - the loop takes too much time or the backoff would exceed the limit (to not block the worker too long),
+		//   the operation is added to the queue again after the backoff
+		sleep := backoff / time.Duration(m.speedFactor)
+		if backoff == 0 || err != nil || time.Since(begin)+sleep > m.cfg.MaxStepProcessingTime {
 			if err != nil {
 				logOperation := m.log.With("step", step.Name(), "operationID", processedOperation.ID, "error_component", processedOperation.LastError.GetComponent(), "error_reason", processedOperation.LastError.GetReason())
 				logOperation.Error(fmt.Sprintf("Last Error that terminated the step: %s", processedOperation.LastError.Error()))
@@ -258,10 +315,33 @@ func (m *StagedManager) runStep(step Step, operation internal.Operation, logger
 			return processedOperation, backoff, err
 		}
 		operation.EventInfof("step %v sleeping for %v", step.Name(), backoff)
-		time.Sleep(backoff / time.Duration(m.speedFactor))
+		time.Sleep(sleep)
 	}
 }
 
+// runStepIteration runs the step once in its own span
+func (m *StagedManager) runStepIteration(ctx context.Context, step Step, operation internal.Operation, logger *slog.Logger, iteration int) (internal.Operation, time.Duration, error) {
+	ctx, span := tracing.Tracer().Start(ctx, fmt.Sprintf("%s #%d", step.Name(), iteration), trace.WithAttributes(
+		tracing.AttributeStep.String(step.Name()),
+		tracing.AttributeIteration.Int(iteration),
+	))
+	defer span.End()
+
+	var processedOperation internal.Operation
+	var backoff time.Duration
+	var err error
+	if stepWithContext, ok := step.(StepWithContext); ok {
+		processedOperation, backoff, err = stepWithContext.RunWithContext(ctx, operation, logger)
+	} else {
+		processedOperation, backoff, err = step.Run(operation, logger)
+	}
+	span.SetAttributes(tracing.AttributeBackoff.String(backoff.String()))
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return processedOperation, backoff, err
+}
+
 func (m *StagedManager) publishEventOnFail(operation *internal.Operation, err error) {
 	logOperation := m.log.With("operationID", operation.ID, "error_component", operation.LastError.GetComponent(), "error_reason", operation.LastError.GetReason())
 	logOperation.Error(fmt.Sprintf("Last error: %s", operation.LastError.Error()))
@@ -287,8 +367,24 @@ func (m *StagedManager) publishEventOnSuccess(operation *internal.Operation) {
 }
 
 func (m *StagedManager) publishOperationFinishedEvent(operation internal.Operation) {
+	var err error
+	if operation.State == domain.Failed {
+		err = operation.LastError
+	}
+	tracing.RecordOperation(context.Background(), operation.ID, fmt.Sprintf("%s operation", operation.Type), operation.CreatedAt, err, operationAttributes(operation)...)
+
 	m.publisher.Publish(context.TODO(), OperationFinished{
 		Operation: operation,
 		PlanID:    operation.ProvisioningParameters.PlanID,
 	})
 }
+
+func operationAttributes(operation internal.Operation) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		tracing.AttributeOperationID.String(operation.ID),
+		tracing.AttributeOperationType.String(string(operation.Type)),
+		tracing.AttributeInstanceID.String(operation.InstanceID),
+		tracing.AttributeRuntimeID.String(operation.RuntimeID),
+		tracing.AttributePlanID.String(operation.ProvisioningParameters.PlanID),
+	}
+}
@@ -22,7 +22,13 @@ import (
 	"github.com/kyma-project/kyma-environment-broker/internal"
 	"github.com/kyma-project/kyma-environment-broker/internal/event"
 	"github.com/kyma-project/kyma-environment-broker/internal/storage"
+	"github.com/kyma-project/kyma-environment-broker/internal/tracing"
 	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
 )
 
 const (
@@ -49,6 +55,7 @@ func TestHappyPath(t *testing.T) {
 
 	// then
 	eventCollector.AssertProcessedSteps(t, []string{"first", "second", "third", "first-2"})
+	assert.Equal(t, []string{"stage-1", "stage-2"}, eventCollector.StagesFinished)
 	op, _ := operationStorage.GetOperationByID(operation.ID)
 	assert.True(t, op.IsStageFinished("stage-1"))
 	assert.True(t, op.IsStageFinished("stage-2"))
@@ -160,6 +167,52 @@ func TestSkipFinishedStage(t *testing.T) {
 	assert.True(t, op.IsStageFinished("stage-2"))
 }
 
+func TestSkipCanceledOperation(t *testing.T) {
+	// given
+	operation := FixOperation("op-0001234")
+	operation.State = internal.OperationStateCanceled
+
+	mgr, operationStorage, eventCollector := SetupStagedManager(t, operation)
+	err := mgr.AddStep("stage-1", &testingStep{name: "first", eventPublisher: eventCollector}, nil)
+	assert.NoError(t, err)
+
+	// when
+	retry, err := mgr.Execute(operation.ID)
+
+	// then
+	assert.NoError(t, err)
+	assert.Zero(t, retry)
+	assert.Empty(t, eventCollector.StepsProcessed)
+	op, _ := operationStorage.GetOperationByID(operation.ID)
+	assert.Equal(t, domain.LastOperationState(internal.OperationStateCanceled), op.State)
+	assert.False(t, op.IsStageFinished("stage-1"))
+}
+
+func TestResumeRetriedOperation(t *testing.T) {
+	// given
+	operation := FixOperation("op-0001234")
+	operation.CreatedAt = time.Now().Add(-time.Hour)
+	operation.RetriedAt = ptr.Time(time.Now())
+	operation.FinishedStages = []string{"stage-1"}
+
+	mgr, operationStorage, eventCollector := SetupStagedManager(t, operation)
+	err := mgr.AddStep("stage-1", &testingStep{name: "first", eventPublisher: eventCollector}, nil)
+	assert.NoError(t, err)
+	err = mgr.AddStep("stage-2", &testingStep{name: "second", eventPublisher: eventCollector}, nil)
+	assert.NoError(t, err)
+
+	// when
+	retry, err := mgr.Execute(operation.ID)
+
+	// then
+	assert.NoError(t, err)
+	assert.Zero(t, retry)
+	eventCollector.AssertProcessedSteps(t, []string{"second"})
+	op, _ := operationStorage.GetOperationByID(operation.ID)
+	assert.True(t, op.IsStageFinished("stage-2"))
+	assert.NotEqual(t, domain.Failed, op.State)
+}
+
 func SetupStagedManager(t *testing.T, op internal.Operation) (*process.StagedManager, storage.Operations, *CollectingEventHandler) {
 	memoryStorage := storage.NewMemoryStorage()
 	err := memoryStorage.Operations().InsertOperation(op)
@@ -194,13 +247,19 @@ type onceRetryingStep struct {
 	name           string
 	processed      bool
 	eventPublisher event.Publisher
+	spanContexts   []trace.SpanContext
 }
 
 func (s *onceRetryingStep) Name() string {
 	return s.name
 }
 func (s *onceRetryingStep) Run(operation internal.Operation, logger *slog.Logger) (internal.Operation, time.Duration, error) {
-	s.eventPublisher.Publish(context.Background(), s.name)
+	return s.RunWithContext(context.Background(), operation, logger)
+}
+
+func (s *onceRetryingStep) RunWithContext(ctx context.Context, operation internal.Operation, logger *slog.Logger) (internal.Operation, time.Duration, error) {
+	s.eventPublisher.Publish(ctx, s.name)
+	s.spanContexts = append(s.spanContexts, trace.SpanContextFromContext(ctx))
 	if !s.processed {
 		s.processed = true
 		return operation, time.Millisecond, nil
@@ -253,6 +312,7 @@ type CollectingEventHandler struct {
 	mu             sync.Mutex
 	StepsProcessed []string // collects events from the Manager
 	stepsExecuted  []string // collects events from testing steps
+	StagesFinished []string // collects stage transitions from the Manager
 }
 
 func (h *CollectingEventHandler) OnStepExecuted(_ context.Context, ev interface{}) {
@@ -271,6 +331,10 @@ func (h *CollectingEventHandler) Publish(ctx context.Context, ev interface{}) {
 	switch ev.(type) {
 	case process.OperationStepProcessed:
 		h.OnStepProcessed(ctx, ev)
+	case process.OperationStageFinished:
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		h.StagesFinished = append(h.StagesFinished, ev.(process.OperationStageFinished).Stage)
 	case string:
 		h.OnStepExecuted(ctx, ev)
 	}
@@ -364,3 +428,73 @@ func TestOperationSucceededEvent(t *testing.T) {
 	rc.WaitForState(t, domain.Succeeded)
 	rc.AssertDurationGreaterThanZero(t)
 }
+
+func TestTracing(t *testing.T) {
+	// given
+	recorder := tracetest.NewSpanRecorder()
+	provider, _, err := tracing.NewTracerProvider(context.Background(), tracing.Config{Exporter: tracing.ExporterNone, SampleRatio: 1}, nil, sdktrace.WithSpanProcessor(recorder))
+	assert.NoError(t, err)
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() {
+		otel.SetTracerProvider(noop.NewTracerProvider())
+	})
+
+	operation := FixOperation("op-0001234")
+	mgr, _, eventCollector := SetupStagedManager(t, operation)
+	err = mgr.AddStep("stage-1", &testingStep{name: "first", eventPublisher: eventCollector}, nil)
+	assert.NoError(t, err)
+	retryingStep := &onceRetryingStep{name: "first-2", eventPublisher: eventCollector}
+	err = mgr.AddStep("stage-2", retryingStep, nil)
+	assert.NoError(t, err)
+
+	// when
+	_, err = mgr.Execute(operation.ID)
+	assert.NoError(t, err)
+
+	// then
+	spans := map[string]sdktrace.ReadOnlySpan{}
+	for _, span := range recorder.Ended() {
+		assert.Equal(t, tracing.OperationTraceID(operation.ID), span.SpanContext().TraceID())
+		spans[span.Name()] = span
+	}
+	assert.Len(t, spans, 9)
+	assertParent := func(child, parent string) {
+		assert.Equal(t, spans[parent].SpanContext().SpanID(), spans[child].Parent().SpanID(), "parent of %s", child)
+	}
+	assertParent("process provision", "provision operation")
+	assertParent("stage stage-1", "process provision")
+	assertParent("first", "stage stage-1")
+	assertParent("first #1", "first")
+	assertParent("stage stage-2", "process provision")
+	assertParent("first-2", "stage stage-2")
+	assertParent("first-2 #1", "first-2")
+	assertParent("first-2 #2", "first-2")
+	assert.Equal(t, spans["first-2 #1"].SpanContext(), retryingStep.spanContexts[0])
+	assert.Equal(t, spans["first-2 #2"].SpanContext(), retryingStep.spanContexts[1])
+}
+
+func TestTracing_EndsSpanOfPanickingStep(t *testing.T) {
+	// given
+	recorder := tracetest.NewSpanRecorder()
+	provider, _, err := tracing.NewTracerProvider(context.Background(), tracing.Config{Exporter: tracing.ExporterNone, SampleRatio: 1}, nil, sdktrace.WithSpanProcessor(recorder))
+	assert.NoError(t, err)
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() {
+		otel.SetTracerProvider(noop.NewTracerProvider())
+	})
+
+	operation := FixOperation("op-0001234")
+	mgr, _, eventCollector := SetupStagedManager(t, operation)
+	err = mgr.AddStep("stage-1", &panicStep{name: "first-panic", eventPublisher: eventCollector}, nil)
+	assert.NoError(t, err)
+
+	// when
+	_, _ = mgr.Execute(operation.ID)
+
+	// then
+	ended := map[string]bool{}
+	for _, span := range recorder.Ended() {
+		ended[span.Name()] = true
+	}
+	assert.True(t, ended["first-panic #1"], "the span of the panicking iteration is ended")
+}
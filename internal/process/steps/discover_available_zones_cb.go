@@ -42,6 +42,10 @@ func (s *DiscoverAvailableZonesCBStep) Name() string {
 }
 
 func (s *DiscoverAvailableZonesCBStep) Run(operation internal.Operation, log *slog.Logger) (internal.Operation, time.Duration, error) {
+	return s.RunWithContext(context.Background(), operation, log)
+}
+
+func (s *DiscoverAvailableZonesCBStep) RunWithContext(ctx context.Context, operation internal.Operation, log *slog.Logger) (internal.Operation, time.Duration, error) {
 	provider := runtime.CloudProviderFromString(operation.ProviderValues.ProviderType)
 	zonesDiscoveryEnabled := s.providerSpec.ZonesDiscovery(provider)
 
@@ -84,7 +88,7 @@ func (s *DiscoverAvailableZonesCBStep) Run(operation internal.Operation, log *sl
 
 	// Always use a per-call client with the exact Kyma-specific secret to ensure
 	// zone discovery reflects the actual subscription restrictions for this instance.
-	client, err := s.factory.NewPerCallFromSecret(context.Background(), provider, secret, operation.ProviderValues.Region)
+	client, err := s.factory.NewPerCallFromSecret(ctx, provider, secret, operation.ProviderValues.Region)
 	if err != nil {
 		return s.operationManager.RetryOperation(operation, fmt.Sprintf("unable to create %s client", provider), err, 10*time.Second, time.Minute, log)
 	}
@@ -107,7 +111,7 @@ func (s *DiscoverAvailableZonesCBStep) Run(operation internal.Operation, log *sl
 
 	discoveredZones := make(map[string][]string)
 	for machineType := range machineTypes {
-		zones, err := client.AvailableZones(context.Background(), machineType)
+		zones, err := client.AvailableZones(ctx, machineType)
 		if err != nil {
 			return s.operationManager.RetryOperation(operation, fmt.Sprintf("unable to get available zones for machine type %s", machineType), err, 10*time.Second, time.Minute, log)
 		}
@@ -59,7 +59,11 @@ func (*checkRuntimeResource) Name() string {
 }
 
 func (s *checkRuntimeResource) Run(operation internal.Operation, log *slog.Logger) (internal.Operation, time.Duration, error) {
-	runtime, err := s.GetRuntimeResource(operation.RuntimeID, operation.KymaResourceNamespace)
+	return s.RunWithContext(context.Background(), operation, log)
+}
+
+func (s *checkRuntimeResource) RunWithContext(ctx context.Context, operation internal.Operation, log *slog.Logger) (internal.Operation, time.Duration, error) {
+	runtime, err := s.GetRuntimeResource(ctx, operation.RuntimeID, operation.KymaResourceNamespace)
 	if err != nil {
 		log.Error(fmt.Sprintf("unable to get Runtime resource %s/%s", operation.KymaResourceNamespace, operation.RuntimeID))
 		return s.operationManager.RetryOperation(operation, "unable to get Runtime resource", err, kcpRetryInterval, kcpRetryTimeout, log)
@@ -85,7 +89,11 @@ func (*checkRuntimeResourceProvisioning) Name() string {
 }
 
 func (s *checkRuntimeResourceProvisioning) Run(operation internal.Operation, log *slog.Logger) (internal.Operation, time.Duration, error) {
-	runtime, err := s.GetRuntimeResource(operation.RuntimeID, operation.KymaResourceNamespace)
+	return s.RunWithContext(context.Background(), operation, log)
+}
+
+func (s *checkRuntimeResourceProvisioning) RunWithContext(ctx context.Context, operation internal.Operation, log *slog.Logger) (internal.Operation, time.Duration, error) {
+	runtime, err := s.GetRuntimeResource(ctx, operation.RuntimeID, operation.KymaResourceNamespace)
 	if err != nil {
 		log.Error(fmt.Sprintf("unable to get Runtime resource %s/%s", operation.KymaResourceNamespace, operation.RuntimeID))
 		return s.operationManager.RetryOperation(operation, "unable to get Runtime resource", err, kcpRetryInterval, kcpRetryTimeout, log)
@@ -107,11 +115,11 @@ func (s *checkRuntimeResourceProvisioning) Run(operation internal.Operation, log
 				return operation, 5 * time.Second, nil
 			}
 		}
-		return s.RetryOrFail(operation, log, runtime)
+		return s.RetryOrFail(ctx, operation, log, runtime)
 	}
 }
 
-func (s *checkRuntimeResourceProvisioning) RetryOrFail(operation internal.Operation, log *slog.Logger, runtime *imv1.Runtime) (internal.Operation, time.Duration, error) {
+func (s *checkRuntimeResourceProvisioning) RetryOrFail(ctx context.Context, operation internal.Operation, log *slog.Logger, runtime *imv1.Runtime) (internal.Operation, time.Duration, error) {
 	retryOperation, retry, err := s.operationManager.RetryOperationForRuntimeResourceProvisioning(operation, fmt.Sprintf("Runtime resource not in %s state", imv1.RuntimeStateReady), nil, s.runtimeResourceStateRetry.Interval, s.runtimeResourceStateRetry.Timeout, log)
 	if retryOperation.State == domain.Failed {
 		log.Error(fmt.Sprintf("runtime resource state: %s", runtime.Status.State))
@@ -128,7 +136,7 @@ func (s *checkRuntimeResourceProvisioning) RetryOrFail(operation internal.Operat
 			))
 		}
 		log.Error("failing operation and removing Runtime CR")
-		err = s.k8sClient.Delete(context.Background(), runtime)
+		err = s.k8sClient.Delete(ctx, runtime)
 		if err != nil {
 			log.Warn(fmt.Sprintf("unable to delete Runtime resource %s/%s: %s", runtime.Name, runtime.Namespace, err))
 		}
@@ -136,17 +144,17 @@ func (s *checkRuntimeResourceProvisioning) RetryOrFail(operation internal.Operat
 	return retryOperation, retry, err
 }
 
-func (s *checkRuntimeResource) GetRuntimeResource(name string, namespace string) (*imv1.Runtime, error) {
-	return GetRuntimeResource(name, namespace, s.k8sClient)
+func (s *checkRuntimeResource) GetRuntimeResource(ctx context.Context, name string, namespace string) (*imv1.Runtime, error) {
+	return GetRuntimeResource(ctx, name, namespace, s.k8sClient)
 }
 
-func (s *checkRuntimeResourceProvisioning) GetRuntimeResource(name string, namespace string) (*imv1.Runtime, error) {
-	return GetRuntimeResource(name, namespace, s.k8sClient)
+func (s *checkRuntimeResourceProvisioning) GetRuntimeResource(ctx context.Context, name string, namespace string) (*imv1.Runtime, error) {
+	return GetRuntimeResource(ctx, name, namespace, s.k8sClient)
 }
 
-func GetRuntimeResource(name string, namespace string, c client.Client) (*imv1.Runtime, error) {
+func GetRuntimeResource(ctx context.Context, name string, namespace string, c client.Client) (*imv1.Runtime, error) {
 	runtime := imv1.Runtime{}
-	err := c.Get(context.Background(), client.ObjectKey{
+	err := c.Get(ctx, client.ObjectKey{
 		Namespace: namespace,
 		Name:      name,
 	}, &runtime)
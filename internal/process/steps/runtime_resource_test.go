@@ -1,6 +1,7 @@
 package steps
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -150,7 +151,7 @@ func TestCheckRuntimeResourceProvisioningStep(t *testing.T) {
 		assert.Zero(t, backoff)
 		assert.Equal(t, domain.Failed, op.State)
 		// in the real life this resource could be still there, in the step we just trigger deletion (fire-and-forget)
-		_, err = GetRuntimeResource(existingRuntime.Name, existingRuntime.Namespace, k8sClient)
+		_, err = GetRuntimeResource(context.Background(), existingRuntime.Name, existingRuntime.Namespace, k8sClient)
 		assert.Error(t, err)
 		assert.ErrorContains(t, err, "not found")
 	})
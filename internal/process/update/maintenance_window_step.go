@@ -0,0 +1,154 @@
+package update
+
+import (
+	"fmt"
+	"log/slog"
+	"reflect"
+	"time"
+
+	"github.com/kyma-project/kyma-environment-broker/internal"
+	kebError "github.com/kyma-project/kyma-environment-broker/internal/error"
+	"github.com/kyma-project/kyma-environment-broker/internal/process"
+	"github.com/kyma-project/kyma-environment-broker/internal/ptr"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage/dberr"
+)
+
+// maxDeferralCheckInterval limits the delay between checks of a deferred operation, so changes of the maintenance window are noticed
+const maxDeferralCheckInterval = 10 * time.Minute
+
+// MaintenanceWindowStep keeps update operations with disruptive changes (plan, machine type or additional worker node pools)
+// in the pending state until the maintenance window of the instance opens.
+// Update operations requested after a deferred one wait for it, so the updates are applied in order.
+type MaintenanceWindowStep struct {
+	operationManager *process.OperationManager
+	operationStorage storage.Operations
+	instanceStorage  storage.Instances
+	now              func() time.Time
+}
+
+func NewMaintenanceWindowStep(db storage.BrokerStorage) *MaintenanceWindowStep {
+	step := &MaintenanceWindowStep{
+		operationStorage: db.Operations(),
+		instanceStorage:  db.Instances(),
+		now:              time.Now,
+	}
+	step.operationManager = process.NewOperationManager(step.operationStorage, step.Name(), kebError.KEBDependency)
+	return step
+}
+
+func (s *MaintenanceWindowStep) Name() string {
+	return "Update_Kyma_Maintenance_Window"
+}
+
+func (s *MaintenanceWindowStep) Run(operation internal.Operation, log *slog.Logger) (internal.Operation, time.Duration, error) {
+	if operation.State != internal.OperationStatePending {
+		return operation, 0, nil
+	}
+	now := s.now()
+
+	deferred, err := s.olderDeferredOperation(operation)
+	if err != nil {
+		log.Error(fmt.Sprintf("unable to list operations of the instance: %s", err))
+		return operation, 10 * time.Second, nil
+	}
+	if deferred != nil {
+		log.Info(fmt.Sprintf("waiting for deferred update operation %s to be processed", deferred.ID))
+		return operation, untilNextCheck(now, *deferred.DeferredUntil), nil
+	}
+
+	if !hasDisruptiveChanges(operation) {
+		return operation, 0, nil
+	}
+
+	instance, err := s.instanceStorage.GetByID(operation.InstanceID)
+	if err != nil {
+		if dberr.IsNotFound(err) {
+			// the initialisation step fails the operation
+			return operation, 0, nil
+		}
+		return operation, time.Second, nil
+	}
+
+	window := instance.Parameters.Parameters.MaintenanceWindow
+	if window == nil || window.Contains(now) {
+		if operation.DeferredUntil == nil {
+			return operation, 0, nil
+		}
+		log.Info("maintenance window is open, processing the deferred operation")
+		if !operation.DeferredUntil.After(now) {
+			return operation, 0, nil
+		}
+		// the maintenance window was changed while the operation was deferred
+		op, delay, _ := s.operationManager.UpdateOperation(operation, func(op *internal.Operation) {
+			op.DeferredUntil = ptr.Time(now)
+		}, log)
+		if delay != 0 {
+			log.Error("unable to update the operation, retrying")
+			return operation, delay, nil
+		}
+		return op, 0, nil
+	}
+
+	begin := window.NextBegin(now)
+	if operation.DeferredUntil == nil || !operation.DeferredUntil.Equal(begin) {
+		op, delay, _ := s.operationManager.UpdateOperation(operation, func(op *internal.Operation) {
+			op.DeferredUntil = &begin
+			op.Description = fmt.Sprintf("Operation deferred until the maintenance window opens at %s", begin.Format(time.RFC3339))
+		}, log)
+		if delay != 0 {
+			log.Error("unable to update the operation, retrying")
+			return operation, delay, nil
+		}
+		operation = op
+		log.Info(fmt.Sprintf("operation deferred until the maintenance window opens at %s", begin.Format(time.RFC3339)))
+		operation.EventInfof("operation deferred until the maintenance window opens at %s", begin.Format(time.RFC3339))
+	}
+
+	return operation, untilNextCheck(now, begin), nil
+}
+
+func (s *MaintenanceWindowStep) olderDeferredOperation(operation internal.Operation) (*internal.Operation, error) {
+	operations, err := s.operationStorage.ListOperationsByInstanceID(operation.InstanceID)
+	if err != nil {
+		return nil, err
+	}
+	for _, op := range operations {
+		if op.ID != operation.ID && op.Type == internal.OperationTypeUpdate && op.State == internal.OperationStatePending &&
+			op.DeferredUntil != nil && op.CreatedAt.Before(operation.CreatedAt) {
+			return &op, nil
+		}
+	}
+	return nil, nil
+}
+
+func hasDisruptiveChanges(operation internal.Operation) bool {
+	if operation.UpdatedPlanID != "" {
+		return true
+	}
+
+	params := operation.UpdatingParameters
+	previous := operation.PreviousParameters.Parameters
+	if params.MachineType != nil && *params.MachineType != "" {
+		oldMachineType := ""
+		if operation.ProviderValues != nil {
+			oldMachineType = operation.ProviderValues.DefaultMachineType
+		}
+		if previous.MachineType != nil {
+			oldMachineType = *previous.MachineType
+		}
+		if *params.MachineType != oldMachineType {
+			return true
+		}
+	}
+
+	// empty list and nil does not make any difference
+	if params.AdditionalWorkerNodePools != nil && (len(params.AdditionalWorkerNodePools) != 0 || len(previous.AdditionalWorkerNodePools) != 0) {
+		return !reflect.DeepEqual(params.AdditionalWorkerNodePools, previous.AdditionalWorkerNodePools)
+	}
+	return false
+}
+
+func untilNextCheck(now, until time.Time) time.Duration {
+	return max(min(until.Sub(now), maxDeferralCheckInterval), time.Second)
+}
@@ -0,0 +1,216 @@
+package update
+
+import (
+	"testing"
+	"time"
+
+	pkg "github.com/kyma-project/kyma-environment-broker/common/runtime"
+	"github.com/kyma-project/kyma-environment-broker/internal"
+	"github.com/kyma-project/kyma-environment-broker/internal/event"
+	"github.com/kyma-project/kyma-environment-broker/internal/fixture"
+	"github.com/kyma-project/kyma-environment-broker/internal/process"
+	"github.com/kyma-project/kyma-environment-broker/internal/ptr"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage"
+	"github.com/pivotal-cf/brokerapi/v12/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var maintenanceWindow = &pkg.MaintenanceWindowDTO{Begin: "220000+0000", End: "230000+0000"}
+
+func TestMaintenanceWindowStep(t *testing.T) {
+	outsideWindow := time.Date(2026, 10, 17, 10, 0, 0, 0, time.UTC)
+	insideWindow := time.Date(2026, 10, 17, 22, 30, 0, 0, time.UTC)
+	windowBegin := time.Date(2026, 10, 17, 22, 0, 0, 0, time.UTC)
+
+	for tn, tc := range map[string]struct {
+		window                *pkg.MaintenanceWindowDTO
+		now                   time.Time
+		modifyOperation       func(op *internal.Operation)
+		expectedDelay         time.Duration
+		expectedDeferredUntil *time.Time
+	}{
+		"machine type change outside the window": {
+			window: maintenanceWindow,
+			now:    outsideWindow,
+			modifyOperation: func(op *internal.Operation) {
+				op.UpdatingParameters.MachineType = ptr.String("m6i.2xlarge")
+			},
+			expectedDelay:         maxDeferralCheckInterval,
+			expectedDeferredUntil: &windowBegin,
+		},
+		"plan change outside the window": {
+			window: maintenanceWindow,
+			now:    windowBegin.Add(-time.Minute),
+			modifyOperation: func(op *internal.Operation) {
+				op.UpdatedPlanID = "updated-plan-id"
+			},
+			expectedDelay:         time.Minute,
+			expectedDeferredUntil: &windowBegin,
+		},
+		"additional worker node pools change outside the window": {
+			window: maintenanceWindow,
+			now:    outsideWindow,
+			modifyOperation: func(op *internal.Operation) {
+				op.UpdatingParameters.AdditionalWorkerNodePools = []pkg.AdditionalWorkerNodePool{{Name: "worker-1", MachineType: "m6i.large", AutoScalerMin: 3, AutoScalerMax: 20}}
+			},
+			expectedDelay:         maxDeferralCheckInterval,
+			expectedDeferredUntil: &windowBegin,
+		},
+		"machine type change inside the window": {
+			window: maintenanceWindow,
+			now:    insideWindow,
+			modifyOperation: func(op *internal.Operation) {
+				op.UpdatingParameters.MachineType = ptr.String("m6i.2xlarge")
+			},
+		},
+		"machine type change without the window": {
+			now: outsideWindow,
+			modifyOperation: func(op *internal.Operation) {
+				op.UpdatingParameters.MachineType = ptr.String("m6i.2xlarge")
+			},
+		},
+		"unchanged machine type outside the window": {
+			window: maintenanceWindow,
+			now:    outsideWindow,
+			modifyOperation: func(op *internal.Operation) {
+				op.PreviousParameters.Parameters.MachineType = ptr.String("m6i.2xlarge")
+				op.UpdatingParameters.MachineType = ptr.String("m6i.2xlarge")
+				op.UpdatingParameters.AdditionalWorkerNodePools = []pkg.AdditionalWorkerNodePool{}
+			},
+		},
+		"OIDC change outside the window": {
+			window: maintenanceWindow,
+			now:    outsideWindow,
+		},
+		"deferred operation when the window opens": {
+			window: maintenanceWindow,
+			now:    insideWindow,
+			modifyOperation: func(op *internal.Operation) {
+				op.UpdatingParameters.MachineType = ptr.String("m6i.2xlarge")
+				op.DeferredUntil = &windowBegin
+			},
+			expectedDeferredUntil: &windowBegin,
+		},
+		"deferred operation when the window is removed": {
+			now: outsideWindow,
+			modifyOperation: func(op *internal.Operation) {
+				op.UpdatingParameters.MachineType = ptr.String("m6i.2xlarge")
+				op.DeferredUntil = &windowBegin
+			},
+			expectedDeferredUntil: &outsideWindow,
+		},
+	} {
+		t.Run(tn, func(t *testing.T) {
+			// given
+			db := storage.NewMemoryStorage()
+			instance := fixture.FixInstance("iid")
+			instance.Parameters.Parameters.MaintenanceWindow = tc.window
+			require.NoError(t, db.Instances().Insert(instance))
+
+			operation := fixture.FixUpdatingOperation("up-id", "iid")
+			operation.State = internal.OperationStatePending
+			operation.CreatedAt = tc.now.Add(-time.Hour)
+			if tc.modifyOperation != nil {
+				tc.modifyOperation(&operation)
+			}
+			require.NoError(t, db.Operations().InsertOperation(operation))
+
+			step := NewMaintenanceWindowStep(db)
+			step.now = func() time.Time { return tc.now }
+
+			// when
+			op, delay, err := step.Run(operation, fixLogger())
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedDelay, delay)
+			assert.Equal(t, internal.OperationStatePending, string(op.State))
+
+			stored, err := db.Operations().GetOperationByID(operation.ID)
+			require.NoError(t, err)
+			if tc.expectedDeferredUntil == nil {
+				assert.Nil(t, stored.DeferredUntil)
+				return
+			}
+			require.NotNil(t, stored.DeferredUntil)
+			assert.True(t, tc.expectedDeferredUntil.Equal(*stored.DeferredUntil))
+			assert.True(t, tc.expectedDeferredUntil.Equal(stored.ProcessingStartedAt()))
+		})
+	}
+}
+
+func TestMaintenanceWindowStep_WaitsForDeferredOperation(t *testing.T) {
+	// given
+	now := time.Date(2026, 10, 17, 21, 0, 0, 0, time.UTC)
+	db := storage.NewMemoryStorage()
+	instance := fixture.FixInstance("iid")
+	instance.Parameters.Parameters.MaintenanceWindow = maintenanceWindow
+	require.NoError(t, db.Instances().Insert(instance))
+
+	deferred := fixture.FixUpdatingOperation("deferred-id", "iid")
+	deferred.State = internal.OperationStatePending
+	deferred.CreatedAt = now.Add(-time.Hour)
+	deferred.DeferredUntil = ptr.Time(now.Add(time.Hour))
+	require.NoError(t, db.Operations().InsertOperation(deferred))
+
+	operation := fixture.FixUpdatingOperation("up-id", "iid")
+	operation.State = internal.OperationStatePending
+	operation.CreatedAt = now
+	require.NoError(t, db.Operations().InsertOperation(operation))
+
+	step := NewMaintenanceWindowStep(db)
+	step.now = func() time.Time { return now }
+
+	// when
+	_, delay, err := step.Run(operation, fixLogger())
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, maxDeferralCheckInterval, delay)
+
+	// when
+	deferred.State = domain.InProgress
+	_, err = db.Operations().UpdateOperation(deferred)
+	require.NoError(t, err)
+	_, delay, err = step.Run(operation, fixLogger())
+
+	// then
+	require.NoError(t, err)
+	assert.Zero(t, delay)
+}
+
+func TestMaintenanceWindowStep_ReleasesWorker(t *testing.T) {
+	// given
+	db := storage.NewMemoryStorage()
+	instance := fixture.FixInstance("iid")
+	instance.Parameters.Parameters.MaintenanceWindow = maintenanceWindow
+	require.NoError(t, db.Instances().Insert(instance))
+
+	operation := fixture.FixUpdatingOperation("up-id", "iid")
+	operation.State = internal.OperationStatePending
+	operation.CreatedAt = time.Now()
+	operation.UpdatingParameters.MachineType = ptr.String("m6i.2xlarge")
+	require.NoError(t, db.Operations().InsertOperation(operation))
+
+	step := NewMaintenanceWindowStep(db)
+	step.now = func() time.Time { return time.Date(2026, 10, 17, 10, 0, 0, 0, time.UTC) }
+
+	manager := process.NewStagedManager(db.Operations(), event.NewPubSub(fixLogger()), time.Hour, process.StagedManagerConfiguration{MaxStepProcessingTime: time.Minute}, fixLogger())
+	manager.DefineStages([]string{"maintenance"})
+	require.NoError(t, manager.AddStep("maintenance", step, nil))
+
+	// when
+	start := time.Now()
+	when, err := manager.Execute(operation.ID)
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, maxDeferralCheckInterval, when)
+	assert.Less(t, time.Since(start), 10*time.Second)
+
+	stored, err := db.Operations().GetOperationByID(operation.ID)
+	require.NoError(t, err)
+	assert.Equal(t, internal.OperationStatePending, string(stored.State))
+	assert.NotNil(t, stored.DeferredUntil)
+}
@@ -40,6 +40,10 @@ func (s *UpdateKymaStep) Name() string {
 }
 
 func (s *UpdateKymaStep) Run(operation internal.Operation, log *slog.Logger) (internal.Operation, time.Duration, error) {
+	return s.RunWithContext(context.Background(), operation, log)
+}
+
+func (s *UpdateKymaStep) RunWithContext(ctx context.Context, operation internal.Operation, log *slog.Logger) (internal.Operation, time.Duration, error) {
 	if operation.UpdatedPlanID == "" {
 		log.Info("Plan did not change, skipping update Kyma resource step")
 		return operation, 0, nil
@@ -97,7 +101,7 @@ func (s *UpdateKymaStep) Run(operation internal.Operation, log *slog.Logger) (in
 
 	kymaUnstructured := &unstructured.Unstructured{}
 	kymaUnstructured.SetGroupVersionKind(obj.GroupVersionKind())
-	err = s.kcpClient.Get(context.Background(), client.ObjectKey{
+	err = s.kcpClient.Get(ctx, client.ObjectKey{
 		Namespace: operation.KymaResourceNamespace,
 		Name:      kymaResourceName,
 	}, kymaUnstructured)
@@ -108,7 +112,7 @@ func (s *UpdateKymaStep) Run(operation internal.Operation, log *slog.Logger) (in
 	log.Info(fmt.Sprintf("Updating Kyma resource: %s in namespace:%s", kymaResourceName, operation.KymaResourceNamespace))
 
 	kymaUnstructured.SetLabels(steps.UpdatePlanLabels(kymaUnstructured.GetLabels(), operation.UpdatedPlanID))
-	err = s.kcpClient.Update(context.Background(), kymaUnstructured)
+	err = s.kcpClient.Update(ctx, kymaUnstructured)
 	if err != nil {
 		return s.operationManager.RetryOperationWithoutFail(operation, s.Name(), fmt.Sprintf("unable to update Kyma Resource %s", kymaResourceName), 10*time.Second, 1*time.Minute, log, err)
 	}
@@ -64,8 +64,12 @@ func (s *UpdateRuntimeStep) Name() string {
 }
 
 func (s *UpdateRuntimeStep) Run(operation internal.Operation, log *slog.Logger) (internal.Operation, time.Duration, error) {
+	return s.RunWithContext(context.Background(), operation, log)
+}
+
+func (s *UpdateRuntimeStep) RunWithContext(ctx context.Context, operation internal.Operation, log *slog.Logger) (internal.Operation, time.Duration, error) {
 	var runtime = imv1.Runtime{}
-	err := s.k8sClient.Get(context.Background(), client.ObjectKey{Name: operation.GetRuntimeResourceName(), Namespace: operation.GetRuntimeResourceNamespace()}, &runtime)
+	err := s.k8sClient.Get(ctx, client.ObjectKey{Name: operation.GetRuntimeResourceName(), Namespace: operation.GetRuntimeResourceNamespace()}, &runtime)
 	if err != nil {
 		if errors.IsNotFound(err) {
 			return s.operationManager.OperationFailed(operation, fmt.Sprintf("Runtime Resource  %s not found", operation.GetRuntimeResourceName()), err, log)
@@ -73,11 +77,11 @@ func (s *UpdateRuntimeStep) Run(operation internal.Operation, log *slog.Logger)
 		return s.operationManager.RetryOperation(operation, fmt.Sprintf("unable to get Runtime Resource %s", operation.GetRuntimeResourceName()), err, 10*time.Second, 1*time.Minute, log)
 	}
 
-	if op, backoff, err := s.updateKymaWorker(operation, &runtime, log); backoff > 0 || err != nil {
+	if op, backoff, err := s.updateKymaWorker(ctx, operation, &runtime, log); backoff > 0 || err != nil {
 		return op, backoff, err
 	}
 
-	if op, backoff, err := s.updateAdditionalWorkerPools(operation, &runtime, log); backoff > 0 || err != nil {
+	if op, backoff, err := s.updateAdditionalWorkerPools(ctx, operation, &runtime, log); backoff > 0 || err != nil {
 		return op, backoff, err
 	}
 
@@ -105,7 +109,7 @@ func (s *UpdateRuntimeStep) Run(operation internal.Operation, log *slog.Logger)
 
 	s.applyMaxPodsConfig(operation, &runtime)
 
-	err = s.k8sClient.Update(context.Background(), &runtime)
+	err = s.k8sClient.Update(ctx, &runtime)
 	if err != nil {
 		return s.operationManager.RetryOperation(operation, fmt.Sprintf("unable to update Runtime Resource %s", operation.GetRuntimeResourceName()), err, 10*time.Second, 1*time.Minute, log)
 	}
@@ -117,7 +121,7 @@ func (s *UpdateRuntimeStep) Run(operation internal.Operation, log *slog.Logger)
 	return operation, 0, nil
 }
 
-func (s *UpdateRuntimeStep) updateKymaWorker(operation internal.Operation, runtime *imv1.Runtime, log *slog.Logger) (internal.Operation, time.Duration, error) {
+func (s *UpdateRuntimeStep) updateKymaWorker(ctx context.Context, operation internal.Operation, runtime *imv1.Runtime, log *slog.Logger) (internal.Operation, time.Duration, error) {
 	if operation.ProviderValues == nil {
 		return s.operationManager.OperationFailed(operation, "ProviderValues is nil, cannot update Kyma worker", nil, log)
 	}
@@ -147,7 +151,7 @@ func (s *UpdateRuntimeStep) updateKymaWorker(operation internal.Operation, runti
 
 	if machineTypeChanged || additionalVolumeSizeChanged {
 		resolvedMachineType := runtime.Spec.Shoot.Provider.Workers[0].Machine.Type
-		volGb, err := s.computeMainWorkerBaseVolumeGb(operation, runtime)
+		volGb, err := s.computeMainWorkerBaseVolumeGb(ctx, operation, runtime)
 		if err != nil {
 			if kebError.IsTemporaryError(err) {
 				return s.operationManager.RetryOperation(operation, fmt.Sprintf("reading KCR ConfigMap for machine %s", resolvedMachineType), err, 10*time.Second, 1*time.Minute, log)
@@ -181,10 +185,10 @@ func (s *UpdateRuntimeStep) updateKymaWorker(operation internal.Operation, runti
 	return operation, 0, nil
 }
 
-func (s *UpdateRuntimeStep) computeMainWorkerBaseVolumeGb(operation internal.Operation, runtime *imv1.Runtime) (int, error) {
+func (s *UpdateRuntimeStep) computeMainWorkerBaseVolumeGb(ctx context.Context, operation internal.Operation, runtime *imv1.Runtime) (int, error) {
 	if s.kcrVolumeProvider != nil {
 		resolvedMachineType := runtime.Spec.Shoot.Provider.Workers[0].Machine.Type
-		return s.kcrVolumeProvider.DefaultVolumeSizeGb(context.Background(),
+		return s.kcrVolumeProvider.DefaultVolumeSizeGb(ctx,
 			pkg.CloudProviderFromString(operation.ProviderValues.ProviderType),
 			resolvedMachineType)
 	}
@@ -195,7 +199,7 @@ func (s *UpdateRuntimeStep) computeMainWorkerBaseVolumeGb(operation internal.Ope
 	return values.VolumeSizeGb, nil
 }
 
-func (s *UpdateRuntimeStep) updateAdditionalWorkerPools(operation internal.Operation, runtime *imv1.Runtime, log *slog.Logger) (internal.Operation, time.Duration, error) {
+func (s *UpdateRuntimeStep) updateAdditionalWorkerPools(ctx context.Context, operation internal.Operation, runtime *imv1.Runtime, log *slog.Logger) (internal.Operation, time.Duration, error) {
 	if operation.UpdatingParameters.AdditionalWorkerNodePools == nil {
 		return operation, 0, nil
 	}
@@ -230,7 +234,7 @@ func (s *UpdateRuntimeStep) updateAdditionalWorkerPools(operation internal.Opera
 				continue
 			}
 			resolvedType := s.providerSpec.ResolveMachineType(pkg.CloudProviderFromString(operation.ProviderValues.ProviderType), pool.MachineType)
-			volGb, err := s.kcrVolumeProvider.DefaultVolumeSizeGb(context.Background(), cp, resolvedType)
+			volGb, err := s.kcrVolumeProvider.DefaultVolumeSizeGb(ctx, cp, resolvedType)
 			if err != nil {
 				if kebError.IsTemporaryError(err) {
 					return s.operationManager.RetryOperation(operation, fmt.Sprintf("reading KCR ConfigMap for machine %s", resolvedType), err, 10*time.Second, 1*time.Minute, log)
@@ -6,14 +6,23 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	"math"
 	"net/http"
+	"sync"
 	"time"
 
+	"github.com/kyma-project/kyma-environment-broker/internal/broker"
+	"github.com/kyma-project/kyma-environment-broker/internal/tracing"
+
+	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/oauth2/clientcredentials"
 )
 
 const (
 	entitlementsServicePath = "%s/entitlements/v1/services/kymaruntime/plans/%s/subaccounts/%s/entitlements"
+
+	// FailOpenQuota is returned for fail-open plans when the entitlements service is unavailable and no quota is cached
+	FailOpenQuota = math.MaxInt32
 )
 
 type Config struct {
@@ -23,6 +32,14 @@ type Config struct {
 	ServiceURL   string
 	Retries      int           `envconfig:"default=5"`
 	Interval     time.Duration `envconfig:"default=1s"`
+	// CacheTTL is the time the assigned quota is served from the cache without asking the entitlements service, 0 disables the cache
+	CacheTTL time.Duration `envconfig:"default=0"`
+	// CacheStaleTTL is the time after CacheTTL when the cached quota is still served while it is refreshed in the background,
+	// after a failed refresh the cached quota is served only to fail-open plans
+	CacheStaleTTL time.Duration `envconfig:"default=1h"`
+	// FailOpenPlans are the plan names for which the quota check passes when the entitlements service is unavailable,
+	// the quota check of other plans fails
+	FailOpenPlans broker.StringList `envconfig:"optional"`
 }
 
 type Client struct {
@@ -30,6 +47,32 @@ type Client struct {
 	httpClient *http.Client
 	config     Config
 	log        *slog.Logger
+	metrics    *Metrics
+	now        func() time.Time
+
+	mu       sync.Mutex
+	cache    map[cacheKey]cacheEntry
+	inFlight map[cacheKey]*call
+}
+
+type cacheKey struct {
+	subAccountID string
+	planName     string
+}
+
+type cacheEntry struct {
+	quota     int
+	fetchedAt time.Time
+	// refreshFailed is set when the entitlements service was unavailable during the last refresh
+	refreshFailed bool
+}
+
+// call is a request to the entitlements service shared by all callers asking for the same subaccount and plan
+type call struct {
+	done        chan struct{}
+	quota       int
+	err         error
+	unavailable bool
 }
 
 type Response struct {
@@ -46,35 +89,121 @@ func NewClient(ctx context.Context, config Config, log *slog.Logger) *Client {
 
 	return &Client{
 		ctx:        ctx,
-		httpClient: httpClientOAuth,
+		httpClient: tracing.NewHTTPClient(httpClientOAuth),
 		config:     config,
 		log:        log,
+		now:        time.Now,
+		cache:      make(map[cacheKey]cacheEntry),
+		inFlight:   make(map[cacheKey]*call),
+	}
+}
+
+// WithMetrics makes the client report cache and fail-open metrics
+func (c *Client) WithMetrics(metrics *Metrics) *Client {
+	c.metrics = metrics
+	return c
+}
+
+// GetQuota returns the quota assigned to the subaccount. A fresh cached quota is returned without a request,
+// a stale cached quota is returned and refreshed in the background, otherwise the entitlements service is asked.
+// Once a refresh failed, the stale quota is returned only for fail-open plans until the entitlements service responds again.
+// Concurrent requests for the same subaccount and plan share one request to the entitlements service.
+// The request to the entitlements service is traced as a part of the trace in ctx.
+func (c *Client) GetQuota(ctx context.Context, subAccountID, planName string) (int, error) {
+	key := cacheKey{subAccountID: subAccountID, planName: planName}
+
+	c.mu.Lock()
+	entry, cached := c.cache[key]
+	age := c.now().Sub(entry.fetchedAt)
+	switch {
+	case cached && age < c.config.CacheTTL:
+		c.mu.Unlock()
+		c.metrics.cacheHit(age)
+		return entry.quota, nil
+	case cached && age < c.config.CacheTTL+c.config.CacheStaleTTL && (!entry.refreshFailed || c.config.FailOpenPlans.Contains(planName)):
+		c.startCall(ctx, key)
+		c.mu.Unlock()
+		c.metrics.cacheStale(age)
+		return entry.quota, nil
+	}
+	cl := c.startCall(ctx, key)
+	c.mu.Unlock()
+	c.metrics.cacheMiss()
+
+	<-cl.done
+	if cl.err == nil {
+		return cl.quota, nil
+	}
+	if !cl.unavailable || !c.config.FailOpenPlans.Contains(planName) {
+		return 0, cl.err
+	}
+
+	c.metrics.failOpen(planName)
+	if cached {
+		c.log.Warn(fmt.Sprintf("Entitlements service is unavailable, using quota of plan %s in subaccount %s cached %s ago: %v", planName, subAccountID, age, cl.err))
+		return entry.quota, nil
+	}
+	c.log.Warn(fmt.Sprintf("Entitlements service is unavailable, skipping the quota check of plan %s in subaccount %s: %v", planName, subAccountID, cl.err))
+	return FailOpenQuota, nil
+}
+
+// startCall returns the request in flight for the key or starts a new one, it must be called with the mutex held.
+// The request is shared by many callers, so it is not canceled with ctx, only the trace of ctx is continued.
+func (c *Client) startCall(ctx context.Context, key cacheKey) *call {
+	if cl, ok := c.inFlight[key]; ok {
+		return cl
 	}
+	cl := &call{done: make(chan struct{})}
+	c.inFlight[key] = cl
+
+	reqCtx := trace.ContextWithSpanContext(c.ctx, trace.SpanContextFromContext(ctx))
+	go func() {
+		cl.quota, cl.err, cl.unavailable = c.fetch(reqCtx, key.subAccountID, key.planName)
+
+		c.mu.Lock()
+		switch {
+		case cl.err == nil && c.config.CacheTTL > 0:
+			c.cache[key] = cacheEntry{quota: cl.quota, fetchedAt: c.now()}
+		case cl.err != nil && !cl.unavailable:
+			delete(c.cache, key)
+		case cl.err != nil:
+			if entry, ok := c.cache[key]; ok {
+				entry.refreshFailed = true
+				c.cache[key] = entry
+			}
+			c.log.Warn(fmt.Sprintf("unable to refresh quota of plan %s in subaccount %s: %v", key.planName, key.subAccountID, cl.err))
+		}
+		delete(c.inFlight, key)
+		c.mu.Unlock()
+		close(cl.done)
+	}()
+	return cl
 }
 
-func (c *Client) GetQuota(subAccountID, planName string) (int, error) {
+// fetch asks the entitlements service for the quota with retries, it returns true if the service is unavailable
+func (c *Client) fetch(ctx context.Context, subAccountID, planName string) (int, error, bool) {
 	var lastErr error
 
 	for i := 0; i < c.config.Retries; i++ {
-		quota, err, retry := c.do(subAccountID, planName)
+		quota, err, retry := c.do(ctx, subAccountID, planName)
 		if err == nil {
-			return quota, nil
+			return quota, nil, false
 		}
 
 		lastErr = err
 		if !retry {
-			return 0, lastErr
+			return 0, lastErr, false
 		}
 
 		c.log.Warn(fmt.Sprintf("Error fetching quota, retrying in %s: %v", c.config.Interval, err))
 		time.Sleep(c.config.Interval)
 	}
 
-	return 0, lastErr
+	return 0, lastErr, true
 }
 
-func (c *Client) do(subAccountID, planName string) (int, error, bool) {
-	req, err := http.NewRequestWithContext(c.ctx, http.MethodGet, fmt.Sprintf(entitlementsServicePath, c.config.ServiceURL, planName, subAccountID), nil)
+func (c *Client) do(ctx context.Context, subAccountID, planName string) (int, error, bool) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf(entitlementsServicePath, c.config.ServiceURL, planName, subAccountID), nil)
 	if err != nil {
 		return 0, fmt.Errorf("while creating request: %w", err), false
 	}
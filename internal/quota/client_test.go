@@ -6,9 +6,13 @@ import (
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -47,7 +51,7 @@ func TestGetQuota_Success(t *testing.T) {
 			client, cleanup := fixClient(t, http.StatusOK, tc.response)
 			defer cleanup()
 
-			quota, err := client.GetQuota("test-subaccount", "aws")
+			quota, err := client.GetQuota(context.Background(), "test-subaccount", "aws")
 
 			assert.NoError(t, err)
 			assert.Equal(t, tc.expected, quota)
@@ -64,7 +68,7 @@ func TestGetQuota_SubaccountNotExits(t *testing.T) {
 	defer cleanup()
 
 	// when
-	quota, err := client.GetQuota("test-subaccount", "aws")
+	quota, err := client.GetQuota(context.Background(), "test-subaccount", "aws")
 
 	// then
 	assert.EqualError(t, err, "Subaccount test-subaccount does not exist")
@@ -77,7 +81,7 @@ func TestGetQuota_ProvisioningServiceNotAvailable(t *testing.T) {
 	defer cleanup()
 
 	// when
-	quota, err := client.GetQuota("test-subaccount", "aws")
+	quota, err := client.GetQuota(context.Background(), "test-subaccount", "aws")
 
 	// then
 	assert.EqualError(t, err, "The entitlements service is currently unavailable. Please try again later")
@@ -119,7 +123,7 @@ func TestGetQuota_SuccessAfterProvisioningServiceRetry(t *testing.T) {
 	client := NewClient(context.Background(), fixConfig(authServer.URL, serviceServer.URL), slog.Default())
 
 	// when
-	quota, err := client.GetQuota("test-subaccount", "aws")
+	quota, err := client.GetQuota(context.Background(), "test-subaccount", "aws")
 
 	// then
 	assert.NoError(t, err)
@@ -143,7 +147,7 @@ func TestGetQuota_AuthenticationServiceNotAvailable(t *testing.T) {
 	client := NewClient(context.Background(), fixConfig(authServer.URL, serviceServer.URL), slog.Default())
 
 	// when
-	quota, err := client.GetQuota("test-subaccount", "aws")
+	quota, err := client.GetQuota(context.Background(), "test-subaccount", "aws")
 
 	// then
 	assert.EqualError(t, err, "The authentication service is currently unavailable. Please try again later")
@@ -185,13 +189,241 @@ func TestGetQuota_SuccessAfterAuthenticationServiceRetry(t *testing.T) {
 	client := NewClient(context.Background(), fixConfig(authServer.URL, serviceServer.URL), slog.Default())
 
 	// when
-	quota, err := client.GetQuota("test-subaccount", "aws")
+	quota, err := client.GetQuota(context.Background(), "test-subaccount", "aws")
 
 	// then
 	assert.NoError(t, err)
 	assert.Equal(t, 2, quota)
 }
 
+func TestGetQuota_Cache(t *testing.T) {
+	t.Run("should serve fresh quota from the cache", func(t *testing.T) {
+		// given
+		server := newFakeEntitlementsServer(t, 2)
+		client := server.client(withCache(time.Minute, time.Hour))
+		metrics := NewMetrics(prometheus.NewRegistry(), "test")
+		client.WithMetrics(metrics)
+
+		// when
+		first, err := client.GetQuota(context.Background(), "test-subaccount", "aws")
+		require.NoError(t, err)
+		second, err := client.GetQuota(context.Background(), "test-subaccount", "aws")
+		require.NoError(t, err)
+
+		// then
+		assert.Equal(t, 2, first)
+		assert.Equal(t, 2, second)
+		assert.Equal(t, int32(1), server.calls.Load())
+		assert.Equal(t, 1.0, testutil.ToFloat64(metrics.cacheRequests.WithLabelValues(cacheResultMiss)))
+		assert.Equal(t, 1.0, testutil.ToFloat64(metrics.cacheRequests.WithLabelValues(cacheResultHit)))
+	})
+
+	t.Run("should serve stale quota and refresh it in the background", func(t *testing.T) {
+		// given
+		server := newFakeEntitlementsServer(t, 2)
+		client := server.client(withCache(time.Minute, time.Hour))
+		_, err := client.GetQuota(context.Background(), "test-subaccount", "aws")
+		require.NoError(t, err)
+		server.quota.Store(3)
+		client.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+
+		// when
+		quota, err := client.GetQuota(context.Background(), "test-subaccount", "aws")
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 2, quota)
+		assert.Eventually(t, func() bool {
+			quota, err := client.GetQuota(context.Background(), "test-subaccount", "aws")
+			return err == nil && quota == 3
+		}, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("should ask the entitlements service once for concurrent requests", func(t *testing.T) {
+		// given
+		server := newFakeEntitlementsServer(t, 2)
+		server.delay = 50 * time.Millisecond
+		client := server.client(withCache(time.Minute, time.Hour))
+
+		// when
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				quota, err := client.GetQuota(context.Background(), "test-subaccount", "aws")
+				assert.NoError(t, err)
+				assert.Equal(t, 2, quota)
+			}()
+		}
+		wg.Wait()
+
+		// then
+		assert.Equal(t, int32(1), server.calls.Load())
+	})
+
+	t.Run("should use cached quota of fail-open plan when the entitlements service is unavailable", func(t *testing.T) {
+		// given
+		server := newFakeEntitlementsServer(t, 2)
+		client := server.client(withCache(time.Minute, time.Hour), withFailOpenPlans("aws"))
+		_, err := client.GetQuota(context.Background(), "test-subaccount", "aws")
+		require.NoError(t, err)
+		server.status.Store(http.StatusServiceUnavailable)
+		client.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+
+		// when
+		quota, err := client.GetQuota(context.Background(), "test-subaccount", "aws")
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 2, quota)
+	})
+
+	t.Run("should pass quota check of fail-open plan without cached quota", func(t *testing.T) {
+		// given
+		server := newFakeEntitlementsServer(t, 2)
+		server.status.Store(http.StatusServiceUnavailable)
+		client := server.client(withFailOpenPlans("aws"))
+		metrics := NewMetrics(prometheus.NewRegistry(), "test")
+		client.WithMetrics(metrics)
+
+		// when
+		quota, err := client.GetQuota(context.Background(), "test-subaccount", "aws")
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, FailOpenQuota, quota)
+		assert.Equal(t, 1.0, testutil.ToFloat64(metrics.failOpenChecks.WithLabelValues("aws")))
+	})
+
+	t.Run("should fail quota check of fail-closed plan when the entitlements service is unavailable", func(t *testing.T) {
+		// given
+		server := newFakeEntitlementsServer(t, 2)
+		client := server.client(withCache(time.Minute, time.Hour), withFailOpenPlans("aws"))
+		_, err := client.GetQuota(context.Background(), "test-subaccount", "azure")
+		require.NoError(t, err)
+		server.status.Store(http.StatusServiceUnavailable)
+		client.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+
+		// when
+		_, err = client.GetQuota(context.Background(), "test-subaccount", "azure")
+
+		// then
+		assert.EqualError(t, err, "The entitlements service is currently unavailable. Please try again later")
+	})
+
+	t.Run("should serve stale quota only to fail-open plans after a failed refresh", func(t *testing.T) {
+		// given
+		server := newFakeEntitlementsServer(t, 2)
+		client := server.client(withCache(time.Minute, time.Hour), withFailOpenPlans("aws"))
+		for _, plan := range []string{"aws", "azure"} {
+			_, err := client.GetQuota(context.Background(), "test-subaccount", plan)
+			require.NoError(t, err)
+		}
+		server.status.Store(http.StatusServiceUnavailable)
+		client.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+
+		// when
+		quota, err := client.GetQuota(context.Background(), "test-subaccount", "azure")
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 2, quota, "the first stale request is served while the quota is refreshed")
+		assert.Eventually(t, func() bool {
+			_, err := client.GetQuota(context.Background(), "test-subaccount", "azure")
+			return err != nil
+		}, 5*time.Second, 10*time.Millisecond)
+
+		// when
+		_, err = client.GetQuota(context.Background(), "test-subaccount", "aws")
+		require.NoError(t, err)
+		require.Eventually(t, func() bool {
+			client.mu.Lock()
+			defer client.mu.Unlock()
+			return client.cache[cacheKey{subAccountID: "test-subaccount", planName: "aws"}].refreshFailed
+		}, 5*time.Second, 10*time.Millisecond)
+		quota, err = client.GetQuota(context.Background(), "test-subaccount", "aws")
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 2, quota)
+	})
+
+	t.Run("should not fail open when the subaccount does not exist", func(t *testing.T) {
+		// given
+		server := newFakeEntitlementsServer(t, 2)
+		server.status.Store(http.StatusNotFound)
+		client := server.client(withFailOpenPlans("aws"))
+
+		// when
+		_, err := client.GetQuota(context.Background(), "test-subaccount", "aws")
+
+		// then
+		assert.EqualError(t, err, "Subaccount test-subaccount does not exist")
+	})
+}
+
+type fakeEntitlementsServer struct {
+	authURL    string
+	serviceURL string
+	quota      atomic.Int32
+	status     atomic.Int32
+	calls      atomic.Int32
+	delay      time.Duration
+}
+
+func newFakeEntitlementsServer(t *testing.T, quota int32) *fakeEntitlementsServer {
+	s := &fakeEntitlementsServer{}
+	s.quota.Store(quota)
+	s.status.Store(http.StatusOK)
+	authServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		err := json.NewEncoder(w).Encode(map[string]interface{}{
+			"access_token": "mock-access-token",
+			"token_type":   "Bearer",
+			"expires_in":   3600,
+		})
+		assert.NoError(t, err)
+	}))
+	serviceServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.calls.Add(1)
+		time.Sleep(s.delay)
+		w.WriteHeader(int(s.status.Load()))
+		err := json.NewEncoder(w).Encode(Response{Amount: float64(s.quota.Load())})
+		assert.NoError(t, err)
+	}))
+	t.Cleanup(func() {
+		authServer.Close()
+		serviceServer.Close()
+	})
+	s.authURL = authServer.URL
+	s.serviceURL = serviceServer.URL
+	return s
+}
+
+func (s *fakeEntitlementsServer) client(opts ...func(*Config)) *Client {
+	cfg := fixConfig(s.authURL, s.serviceURL)
+	cfg.Retries = 2
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return NewClient(context.Background(), cfg, slog.Default())
+}
+
+func withCache(ttl, staleTTL time.Duration) func(*Config) {
+	return func(cfg *Config) {
+		cfg.CacheTTL = ttl
+		cfg.CacheStaleTTL = staleTTL
+	}
+}
+
+func withFailOpenPlans(plans ...string) func(*Config) {
+	return func(cfg *Config) {
+		cfg.FailOpenPlans = plans
+	}
+}
+
 func fixClient(t *testing.T, statusCode int, response any) (*Client, func()) {
 	authServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
@@ -0,0 +1,86 @@
+package quota
+
+import (
+	"errors"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	cacheResultHit   = "hit"
+	cacheResultStale = "stale"
+	cacheResultMiss  = "miss"
+)
+
+type Metrics struct {
+	cacheRequests  *prometheus.CounterVec
+	servedAge      prometheus.Gauge
+	failOpenChecks *prometheus.CounterVec
+}
+
+func NewMetrics(reg prometheus.Registerer, namespace string) *Metrics {
+	m := &Metrics{
+		cacheRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "quota_cache_requests_total",
+			Help:      "Requests for the assigned Kyma instances quota by the cache result: hit, stale or miss.",
+		}, []string{"result"}),
+		servedAge: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "quota_cache_served_age_seconds",
+			Help:      "Age of the assigned Kyma instances quota last served from the cache.",
+		}),
+		failOpenChecks: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "quota_fail_open_total",
+			Help:      "Quota checks passed without the entitlements service because it was unavailable.",
+		}, []string{"plan"}),
+	}
+	m.cacheRequests = register(reg, m.cacheRequests)
+	m.servedAge = register(reg, m.servedAge)
+	m.failOpenChecks = register(reg, m.failOpenChecks)
+	return m
+}
+
+// register returns the already registered collector if the metrics were registered by another client
+func register[T prometheus.Collector](reg prometheus.Registerer, collector T) T {
+	if err := reg.Register(collector); err != nil {
+		var alreadyRegistered prometheus.AlreadyRegisteredError
+		if errors.As(err, &alreadyRegistered) {
+			return alreadyRegistered.ExistingCollector.(T)
+		}
+		panic(err)
+	}
+	return collector
+}
+
+func (m *Metrics) cacheHit(age time.Duration) {
+	if m == nil {
+		return
+	}
+	m.cacheRequests.WithLabelValues(cacheResultHit).Inc()
+	m.servedAge.Set(age.Seconds())
+}
+
+func (m *Metrics) cacheStale(age time.Duration) {
+	if m == nil {
+		return
+	}
+	m.cacheRequests.WithLabelValues(cacheResultStale).Inc()
+	m.servedAge.Set(age.Seconds())
+}
+
+func (m *Metrics) cacheMiss() {
+	if m == nil {
+		return
+	}
+	m.cacheRequests.WithLabelValues(cacheResultMiss).Inc()
+}
+
+func (m *Metrics) failOpen(planName string) {
+	if m == nil {
+		return
+	}
+	m.failOpenChecks.WithLabelValues(planName).Inc()
+}
@@ -0,0 +1,249 @@
+package quota
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/kyma-project/kyma-environment-broker/internal"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage/dberr"
+	"github.com/kyma-project/kyma-environment-broker/internal/whitelist"
+
+	"github.com/pivotal-cf/brokerapi/v12/domain"
+)
+
+type ReservationsConfig struct {
+	// ReconciliationInterval is the interval of re-syncing the reservations with operations, instances and the entitlements service, 0 disables the reconciliation
+	ReconciliationInterval time.Duration `envconfig:"default=1h"`
+	// ReservedTimeout is the time after which a reservation without an operation is released, it happens when the request fails before the operation is stored
+	ReservedTimeout time.Duration `envconfig:"default=1h"`
+}
+
+// reconciliationJobName is the name of the reconciliation lease, so only one KEB instance reconciles the reservations
+const reconciliationJobName = "quota-reservations-reconciliation"
+
+// PlanNameFunc returns the name of the plan with the given ID
+type PlanNameFunc func(planID string) string
+
+// JobLeaser claims a periodic job for one KEB instance
+type JobLeaser interface {
+	AcquireJob(name string, duration time.Duration) bool
+}
+
+// EntitlementsClient returns the Kyma instances quota assigned to the subaccount
+type EntitlementsClient interface {
+	GetQuota(ctx context.Context, subAccountID, planName string) (int, error)
+}
+
+// ReservationKeeper confirms the quota reservations of succeeded provisioning and plan upgrade operations,
+// releases the reservations of failed operations and the reservations of deprovisioned instances
+type ReservationKeeper struct {
+	reservations storage.QuotaReservations
+	log          *slog.Logger
+}
+
+func NewReservationKeeper(reservations storage.QuotaReservations, log *slog.Logger) *ReservationKeeper {
+	return &ReservationKeeper{
+		reservations: reservations,
+		log:          log.With("service", "QuotaReservationKeeper"),
+	}
+}
+
+// OnOperationFinished updates the reservation of the finished operation, it is subscribed to the operation finished events
+func (k *ReservationKeeper) OnOperationFinished(operation internal.Operation) error {
+	switch operation.Type {
+	case internal.OperationTypeProvision, internal.OperationTypeUpdate:
+		switch operation.State {
+		case domain.Succeeded:
+			if err := k.reservations.Confirm(operation.ID); err != nil {
+				return fmt.Errorf("while confirming quota reservation of operation %s: %w", operation.ID, err)
+			}
+		case domain.Failed:
+			if err := k.reservations.Release(operation.ID); err != nil {
+				return fmt.Errorf("while releasing quota reservation of operation %s: %w", operation.ID, err)
+			}
+		}
+	case internal.OperationTypeDeprovision:
+		if operation.State == domain.Succeeded {
+			if err := k.reservations.ReleaseByInstanceID(operation.InstanceID); err != nil {
+				return fmt.Errorf("while releasing quota reservations of instance %s: %w", operation.InstanceID, err)
+			}
+		}
+	}
+	return nil
+}
+
+type ReconciliationResult struct {
+	Confirmed     int
+	Released      int
+	OverCommitted int
+}
+
+// Reconciler re-syncs the quota reservations, it repairs reservations which were not confirmed or released because the broker
+// missed the operation result, and compares the used quota with the quota assigned by the entitlements service
+type Reconciler struct {
+	cfg          ReservationsConfig
+	reservations storage.QuotaReservations
+	instances    storage.Instances
+	operations   storage.Operations
+	entitlements EntitlementsClient
+	planName     PlanNameFunc
+	whitelist    whitelist.Set
+	leaser       JobLeaser
+	log          *slog.Logger
+	now          func() time.Time
+}
+
+func NewReconciler(cfg ReservationsConfig, db storage.BrokerStorage, entitlements EntitlementsClient, planName PlanNameFunc, quotaWhitelist whitelist.Set,
+	leaser JobLeaser, log *slog.Logger) *Reconciler {
+	return &Reconciler{
+		cfg:          cfg,
+		reservations: db.QuotaReservations(),
+		instances:    db.Instances(),
+		operations:   db.Operations(),
+		entitlements: entitlements,
+		planName:     planName,
+		whitelist:    quotaWhitelist,
+		leaser:       leaser,
+		log:          log.With("service", "QuotaReservationReconciler"),
+		now:          time.Now,
+	}
+}
+
+// Run reconciles the reservations periodically until the context is done. With several KEB instances,
+// the reservations are reconciled by the instance which holds the reconciliation lease.
+func (r *Reconciler) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.cfg.ReconciliationInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		if !r.leaser.AcquireJob(reconciliationJobName, r.cfg.ReconciliationInterval) {
+			r.log.Debug("Quota reservations are reconciled by another instance")
+			continue
+		}
+		result, err := r.Reconcile()
+		if err != nil {
+			r.log.Error(fmt.Sprintf("unable to reconcile quota reservations: %s", err))
+			continue
+		}
+		r.log.Info(fmt.Sprintf("Quota reservations reconciled: confirmed=%d released=%d overCommitted=%d", result.Confirmed, result.Released, result.OverCommitted))
+	}
+}
+
+func (r *Reconciler) Reconcile() (ReconciliationResult, error) {
+	result := ReconciliationResult{}
+	reservations, err := r.reservations.List()
+	if err != nil {
+		return result, fmt.Errorf("while listing quota reservations: %w", err)
+	}
+
+	type subAccountPlan struct {
+		subAccountID string
+		planID       string
+	}
+	inUse := make(map[subAccountPlan]struct{})
+	for _, reservation := range reservations {
+		logger := r.log.With("instanceID", reservation.InstanceID, "planID", reservation.PlanID, "operationID", reservation.OperationID)
+		kept, err := r.reconcileReservation(reservation, &result, logger)
+		if err != nil {
+			logger.Error(fmt.Sprintf("unable to reconcile quota reservation: %s", err))
+			continue
+		}
+		if kept {
+			inUse[subAccountPlan{subAccountID: reservation.SubAccountID, planID: reservation.PlanID}] = struct{}{}
+		}
+	}
+
+	for key := range inUse {
+		if whitelist.IsWhitelisted(key.subAccountID, r.whitelist) {
+			continue
+		}
+		overCommitted, err := r.checkAssignedQuota(key.subAccountID, key.planID)
+		if err != nil {
+			r.log.Error(fmt.Sprintf("unable to check assigned quota of plan %s in subaccount %s: %s", key.planID, key.subAccountID, err))
+			continue
+		}
+		if overCommitted {
+			result.OverCommitted++
+		}
+	}
+	return result, nil
+}
+
+// reconcileReservation confirms or releases the reservation and returns true if the reservation is kept
+func (r *Reconciler) reconcileReservation(reservation internal.QuotaReservation, result *ReconciliationResult, logger *slog.Logger) (bool, error) {
+	if reservation.State == internal.QuotaReservationReserved && reservation.OperationID != "" {
+		operation, err := r.operations.GetOperationByID(reservation.OperationID)
+		switch {
+		case dberr.IsNotFound(err):
+			if r.now().Sub(reservation.CreatedAt) < r.cfg.ReservedTimeout {
+				return true, nil
+			}
+			logger.Info("Releasing quota reservation without an operation")
+			return false, r.release(reservation, result)
+		case err != nil:
+			return false, fmt.Errorf("while getting operation: %w", err)
+		case operation.State == domain.Succeeded:
+			logger.Info("Confirming quota reservation of succeeded operation")
+			if err := r.reservations.Confirm(reservation.OperationID); err != nil {
+				return false, err
+			}
+			result.Confirmed++
+			return true, nil
+		case operation.State == domain.Failed:
+			logger.Info("Releasing quota reservation of failed operation")
+			return false, r.release(reservation, result)
+		default:
+			return true, nil
+		}
+	}
+
+	instance, err := r.instances.GetByID(reservation.InstanceID)
+	switch {
+	case dberr.IsNotFound(err):
+		if reservation.State == internal.QuotaReservationReserved && r.now().Sub(reservation.CreatedAt) < r.cfg.ReservedTimeout {
+			return true, nil
+		}
+		logger.Info("Releasing quota reservation of not existing instance")
+		return false, r.release(reservation, result)
+	case err != nil:
+		return false, fmt.Errorf("while getting instance: %w", err)
+	case reservation.State == internal.QuotaReservationConfirmed && instance.ServicePlanID != reservation.PlanID:
+		logger.Info(fmt.Sprintf("Releasing quota reservation of the previous plan, the instance plan is %s", instance.ServicePlanID))
+		return false, r.release(reservation, result)
+	}
+	return true, nil
+}
+
+func (r *Reconciler) release(reservation internal.QuotaReservation, result *ReconciliationResult) error {
+	if err := r.reservations.ReleasePlan(reservation.InstanceID, reservation.PlanID); err != nil {
+		return err
+	}
+	result.Released++
+	return nil
+}
+
+// checkAssignedQuota returns true if the used quota is higher than the quota assigned by the entitlements service,
+// it happens when the entitlement was decreased after the instances were provisioned
+func (r *Reconciler) checkAssignedQuota(subAccountID, planID string) (bool, error) {
+	planName := r.planName(planID)
+	assignedQuota, err := r.entitlements.GetQuota(context.Background(), subAccountID, planName)
+	if err != nil {
+		return false, fmt.Errorf("while getting assigned quota: %w", err)
+	}
+	used, err := r.reservations.CountUsed(subAccountID, planID)
+	if err != nil {
+		return false, fmt.Errorf("while counting used quota: %w", err)
+	}
+	if used > assignedQuota {
+		r.log.Warn(fmt.Sprintf("Kyma instances quota of plan %s in subaccount %s is over-committed: assignedQuota: %d, usedQuota: %d", planName, subAccountID, assignedQuota, used))
+		return true, nil
+	}
+	return false, nil
+}
@@ -0,0 +1,266 @@
+package quota
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kyma-project/kyma-environment-broker/internal"
+	"github.com/kyma-project/kyma-environment-broker/internal/broker"
+	"github.com/kyma-project/kyma-environment-broker/internal/fixture"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage"
+	"github.com/kyma-project/kyma-environment-broker/internal/whitelist"
+
+	"github.com/pivotal-cf/brokerapi/v12/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	reservationSubAccountID = "subaccount-1"
+)
+
+func TestReservationKeeper(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	t.Run("should confirm the reservation of succeeded provisioning", func(t *testing.T) {
+		// given
+		db := storage.NewMemoryStorage()
+		keeper := NewReservationKeeper(db.QuotaReservations(), log)
+		fixReservation(t, db, "instance-1", broker.AWSPlanID, "op-1")
+		operation := fixture.FixProvisioningOperation("op-1", "instance-1")
+		operation.State = domain.Succeeded
+
+		// when
+		err := keeper.OnOperationFinished(operation)
+
+		// then
+		require.NoError(t, err)
+		reservations, err := db.QuotaReservations().List()
+		require.NoError(t, err)
+		require.Len(t, reservations, 1)
+		assert.Equal(t, internal.QuotaReservationConfirmed, reservations[0].State)
+	})
+
+	t.Run("should release the reservation of failed plan upgrade", func(t *testing.T) {
+		// given
+		db := storage.NewMemoryStorage()
+		keeper := NewReservationKeeper(db.QuotaReservations(), log)
+		fixReservation(t, db, "instance-1", broker.BuildRuntimeAWSPlanID, "op-update")
+		operation := fixture.FixUpdatingOperation("op-update", "instance-1")
+		operation.State = domain.Failed
+
+		// when
+		err := keeper.OnOperationFinished(operation)
+
+		// then
+		require.NoError(t, err)
+		reservations, err := db.QuotaReservations().List()
+		require.NoError(t, err)
+		assert.Empty(t, reservations)
+	})
+
+	t.Run("should release reservations of deprovisioned instance", func(t *testing.T) {
+		// given
+		db := storage.NewMemoryStorage()
+		keeper := NewReservationKeeper(db.QuotaReservations(), log)
+		fixReservation(t, db, "instance-1", broker.AWSPlanID, "op-1")
+		require.NoError(t, db.QuotaReservations().Confirm("op-1"))
+		operation := fixture.FixOperation("op-deprovision", "instance-1", internal.OperationTypeDeprovision)
+		operation.State = domain.Succeeded
+
+		// when
+		err := keeper.OnOperationFinished(operation)
+
+		// then
+		require.NoError(t, err)
+		reservations, err := db.QuotaReservations().List()
+		require.NoError(t, err)
+		assert.Empty(t, reservations)
+	})
+}
+
+func TestReconciler_Reconcile(t *testing.T) {
+	log := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	cfg := ReservationsConfig{ReservedTimeout: time.Hour}
+
+	t.Run("should confirm and release reservations of finished operations", func(t *testing.T) {
+		// given
+		db := storage.NewMemoryStorage()
+		fixReservation(t, db, "instance-succeeded", broker.AWSPlanID, "op-succeeded")
+		fixReservation(t, db, "instance-failed", broker.AWSPlanID, "op-failed")
+		fixReservation(t, db, "instance-in-progress", broker.AWSPlanID, "op-in-progress")
+		fixOperation(t, db, "op-succeeded", "instance-succeeded", domain.Succeeded)
+		fixOperation(t, db, "op-failed", "instance-failed", domain.Failed)
+		fixOperation(t, db, "op-in-progress", "instance-in-progress", domain.InProgress)
+		fixInstance(t, db, "instance-succeeded", broker.AWSPlanID)
+		reconciler := NewReconciler(cfg, db, &fakeEntitlements{quota: 10}, planName, whitelist.Set{}, nil, log)
+
+		// when
+		result, err := reconciler.Reconcile()
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, ReconciliationResult{Confirmed: 1, Released: 1}, result)
+		assertReservations(t, db, map[string]internal.QuotaReservationState{
+			"instance-succeeded":   internal.QuotaReservationConfirmed,
+			"instance-in-progress": internal.QuotaReservationReserved,
+		})
+	})
+
+	t.Run("should release reservations without operation after the timeout", func(t *testing.T) {
+		// given
+		db := storage.NewMemoryStorage()
+		fixReservation(t, db, "instance-1", broker.AWSPlanID, "op-not-stored")
+		reconciler := NewReconciler(cfg, db, &fakeEntitlements{quota: 10}, planName, whitelist.Set{}, nil, log)
+
+		// when
+		result, err := reconciler.Reconcile()
+
+		// then
+		require.NoError(t, err)
+		assert.Zero(t, result.Released)
+
+		// when
+		reconciler.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+		result, err = reconciler.Reconcile()
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.Released)
+		assertReservations(t, db, map[string]internal.QuotaReservationState{})
+	})
+
+	t.Run("should release confirmed reservations of removed instances and previous plans", func(t *testing.T) {
+		// given
+		db := storage.NewMemoryStorage()
+		fixReservation(t, db, "instance-removed", broker.AWSPlanID, "op-removed")
+		require.NoError(t, db.QuotaReservations().Confirm("op-removed"))
+		fixReservation(t, db, "instance-upgraded", broker.AWSPlanID, "op-upgraded")
+		require.NoError(t, db.QuotaReservations().Confirm("op-upgraded"))
+		fixInstance(t, db, "instance-upgraded", broker.BuildRuntimeAWSPlanID)
+		reconciler := NewReconciler(cfg, db, &fakeEntitlements{quota: 10}, planName, whitelist.Set{}, nil, log)
+
+		// when
+		result, err := reconciler.Reconcile()
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 2, result.Released)
+		assertReservations(t, db, map[string]internal.QuotaReservationState{})
+	})
+
+	t.Run("should report over-committed quota", func(t *testing.T) {
+		// given
+		db := storage.NewMemoryStorage()
+		fixReservation(t, db, "instance-1", broker.AWSPlanID, "op-1")
+		fixReservation(t, db, "instance-2", broker.AWSPlanID, "op-2")
+		fixOperation(t, db, "op-1", "instance-1", domain.InProgress)
+		fixOperation(t, db, "op-2", "instance-2", domain.InProgress)
+		entitlements := &fakeEntitlements{quota: 1}
+		reconciler := NewReconciler(cfg, db, entitlements, planName, whitelist.Set{}, nil, log)
+
+		// when
+		result, err := reconciler.Reconcile()
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.OverCommitted)
+		assert.Equal(t, []string{reservationSubAccountID + "/" + broker.AWSPlanName}, entitlements.requests)
+
+		// when
+		reconciler = NewReconciler(cfg, db, entitlements, planName, whitelist.Set{reservationSubAccountID: struct{}{}}, nil, log)
+		result, err = reconciler.Reconcile()
+
+		// then
+		require.NoError(t, err)
+		assert.Zero(t, result.OverCommitted)
+	})
+
+	t.Run("should not reconcile without the reconciliation lease", func(t *testing.T) {
+		// given
+		db := storage.NewMemoryStorage()
+		fixReservation(t, db, "instance-1", broker.AWSPlanID, "op-1")
+		fixOperation(t, db, "op-1", "instance-1", domain.Failed)
+		leaser := &fakeJobLeaser{}
+		reconciler := NewReconciler(ReservationsConfig{ReconciliationInterval: 10 * time.Millisecond}, db, &fakeEntitlements{quota: 10}, planName, whitelist.Set{}, leaser, log)
+		ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancel()
+
+		// when
+		reconciler.Run(ctx)
+
+		// then
+		assert.NotZero(t, leaser.calls())
+		assertReservations(t, db, map[string]internal.QuotaReservationState{"instance-1": internal.QuotaReservationReserved})
+	})
+}
+
+func fixReservation(t *testing.T, db storage.BrokerStorage, instanceID, planID, operationID string) {
+	reserved, err := db.QuotaReservations().Reserve(internal.QuotaReservation{
+		InstanceID:   instanceID,
+		SubAccountID: reservationSubAccountID,
+		PlanID:       planID,
+		OperationID:  operationID,
+	}, 10)
+	require.NoError(t, err)
+	require.True(t, reserved)
+}
+
+func fixOperation(t *testing.T, db storage.BrokerStorage, operationID, instanceID string, state domain.LastOperationState) {
+	operation := fixture.FixProvisioningOperation(operationID, instanceID)
+	operation.State = state
+	require.NoError(t, db.Operations().InsertOperation(operation))
+}
+
+func fixInstance(t *testing.T, db storage.BrokerStorage, instanceID, planID string) {
+	instance := fixture.FixInstance(instanceID)
+	instance.SubAccountID = reservationSubAccountID
+	instance.ServicePlanID = planID
+	require.NoError(t, db.Instances().Insert(instance))
+}
+
+func assertReservations(t *testing.T, db storage.BrokerStorage, expected map[string]internal.QuotaReservationState) {
+	reservations, err := db.QuotaReservations().List()
+	require.NoError(t, err)
+	actual := make(map[string]internal.QuotaReservationState, len(reservations))
+	for _, reservation := range reservations {
+		actual[reservation.InstanceID] = reservation.State
+	}
+	assert.Equal(t, expected, actual)
+}
+
+func planName(planID string) string {
+	return broker.AvailablePlans.GetPlanNameOrEmpty(broker.PlanIDType(planID))
+}
+
+type fakeJobLeaser struct {
+	mu       sync.Mutex
+	acquired int
+}
+
+func (f *fakeJobLeaser) AcquireJob(_ string, _ time.Duration) bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.acquired++
+	return false
+}
+
+func (f *fakeJobLeaser) calls() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.acquired
+}
+
+type fakeEntitlements struct {
+	quota    int
+	requests []string
+}
+
+func (f *fakeEntitlements) GetQuota(_ context.Context, subAccountID, planName string) (int, error) {
+	f.requests = append(f.requests, subAccountID+"/"+planName)
+	return f.quota, nil
+}
@@ -14,6 +14,9 @@ type BindingDTO struct {
 	Kubeconfig        string
 	ExpirationSeconds int64
 	CreatedBy         string
+	Scope             string
+	State             string
+	Description       string
 }
 
 type BindingStatsDTO struct {
@@ -63,6 +63,8 @@ type InstanceDTO struct {
 	Version int
 
 	EmptyUpdates int
+
+	ExpirationExtensions int
 }
 
 type InstanceWithExtendedOperationDTO struct {
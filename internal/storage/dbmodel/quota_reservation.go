@@ -0,0 +1,16 @@
+package dbmodel
+
+import (
+	"time"
+)
+
+type QuotaReservationDTO struct {
+	InstanceID   string
+	SubAccountID string
+	PlanID       string
+	OperationID  string
+	State        string
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
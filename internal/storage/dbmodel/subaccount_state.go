@@ -7,4 +7,22 @@ type SubaccountStateDTO struct {
 	UsedForProduction string `json:"used_for_production"`
 
 	ModifiedAt int64 `json:"modified_at"`
+
+	Attributes string `json:"attributes"`
+}
+
+type SubaccountSyncCursorDTO struct {
+	ID string `json:"id"`
+
+	LastEventID         int64 `json:"last_event_id"`
+	LastEventActionTime int64 `json:"last_event_action_time"`
+	EventsSyncedAt      int64 `json:"events_synced_at"`
+	AccountsSyncedAt    int64 `json:"accounts_synced_at"`
+}
+
+type SubaccountSyncEventDTO struct {
+	EventID      int64  `json:"event_id"`
+	SubAccountID string `json:"sub_account_id"`
+	ActionTime   int64  `json:"action_time"`
+	ProcessedAt  int64  `json:"processed_at"`
 }
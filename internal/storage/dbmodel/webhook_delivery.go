@@ -0,0 +1,25 @@
+package dbmodel
+
+import (
+	"time"
+)
+
+type WebhookDeliveryDTO struct {
+	ID          string
+	Webhook     string
+	Event       string
+	InstanceID  string
+	OperationID string
+	Payload     string
+
+	State         string
+	Attempts      int
+	LastError     string
+	NextAttemptAt time.Time
+
+	ClaimedBy    *string
+	ClaimedUntil *time.Time
+
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
@@ -0,0 +1,129 @@
+package memory
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/kyma-project/kyma-environment-broker/internal"
+
+	"github.com/pivotal-cf/brokerapi/v12/domain"
+)
+
+type operationLease struct {
+	owner     string
+	expiresAt time.Time
+}
+
+type OperationLease struct {
+	mu         sync.Mutex
+	leases     map[string]operationLease
+	jobLeases  map[string]operationLease
+	operations *operations
+}
+
+func NewOperationLease(operations *operations) *OperationLease {
+	return &OperationLease{
+		leases:     make(map[string]operationLease),
+		jobLeases:  make(map[string]operationLease),
+		operations: operations,
+	}
+}
+
+func (s *OperationLease) Acquire(operationID, owner string, now time.Time, duration time.Duration) (bool, error) {
+	if _, found := s.operation(operationID); !found {
+		return false, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.claimable(operationID, owner, now) {
+		return false, nil
+	}
+	s.leases[operationID] = operationLease{owner: owner, expiresAt: now.Add(duration)}
+	return true, nil
+}
+
+func (s *OperationLease) Renew(owner string, operationIDs []string, now time.Time, duration time.Duration) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	renewed := make([]string, 0)
+	for _, id := range operationIDs {
+		lease, found := s.leases[id]
+		if !found || lease.owner != owner {
+			continue
+		}
+		operation, found := s.operation(id)
+		if !found || !notFinished(operation) {
+			continue
+		}
+		s.leases[id] = operationLease{owner: owner, expiresAt: now.Add(duration)}
+		renewed = append(renewed, id)
+	}
+	return renewed, nil
+}
+
+func (s *OperationLease) Release(operationID, owner string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if lease, found := s.leases[operationID]; found && lease.owner == owner {
+		delete(s.leases, operationID)
+	}
+	return nil
+}
+
+func (s *OperationLease) AcquireJob(name, owner string, now time.Time, duration time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	lease, found := s.jobLeases[name]
+	if found && lease.owner != owner && !lease.expiresAt.Before(now) {
+		return false, nil
+	}
+	s.jobLeases[name] = operationLease{owner: owner, expiresAt: now.Add(duration)}
+	return true, nil
+}
+
+func (s *OperationLease) ListClaimable(operationType internal.OperationType, owner string, now time.Time) ([]string, error) {
+	s.operations.mu.Lock()
+	candidates := make([]internal.Operation, 0)
+	for _, op := range s.operations.operations {
+		if op.Type == operationType && notFinished(op) {
+			candidates = append(candidates, op)
+		}
+	}
+	s.operations.mu.Unlock()
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].CreatedAt.Before(candidates[j].CreatedAt)
+	})
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ids := make([]string, 0)
+	for _, op := range candidates {
+		if s.claimable(op.ID, owner, now) {
+			ids = append(ids, op.ID)
+		}
+	}
+	return ids, nil
+}
+
+func (s *OperationLease) claimable(operationID, owner string, now time.Time) bool {
+	lease, found := s.leases[operationID]
+	return !found || lease.owner == owner || lease.expiresAt.Before(now)
+}
+
+func (s *OperationLease) operation(operationID string) (internal.Operation, bool) {
+	s.operations.mu.Lock()
+	defer s.operations.mu.Unlock()
+	op, found := s.operations.operations[operationID]
+	return op, found
+}
+
+func notFinished(operation internal.Operation) bool {
+	return operation.State == internal.OperationStatePending || operation.State == domain.InProgress
+}
@@ -0,0 +1,144 @@
+package memory
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/kyma-project/kyma-environment-broker/internal"
+)
+
+type quotaReservationKey struct {
+	instanceID string
+	planID     string
+}
+
+type QuotaReservation struct {
+	mu           sync.Mutex
+	reservations map[quotaReservationKey]internal.QuotaReservation
+	instances    *instances
+}
+
+func NewQuotaReservation(instances *instances) *QuotaReservation {
+	return &QuotaReservation{
+		reservations: make(map[quotaReservationKey]internal.QuotaReservation),
+		instances:    instances,
+	}
+}
+
+func (s *QuotaReservation) Reserve(reservation internal.QuotaReservation, assignedQuota int) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := quotaReservationKey{instanceID: reservation.InstanceID, planID: reservation.PlanID}
+	if _, found := s.reservations[key]; found {
+		return true, nil
+	}
+	if s.countUsed(reservation.SubAccountID, reservation.PlanID) >= assignedQuota {
+		return false, nil
+	}
+
+	now := time.Now()
+	reservation.State = internal.QuotaReservationReserved
+	reservation.CreatedAt = now
+	reservation.UpdatedAt = now
+	s.reservations[key] = reservation
+	return true, nil
+}
+
+func (s *QuotaReservation) Confirm(operationID string) error {
+	if operationID == "" {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key, reservation := range s.reservations {
+		if reservation.OperationID != operationID {
+			continue
+		}
+		reservation.State = internal.QuotaReservationConfirmed
+		reservation.UpdatedAt = time.Now()
+		s.reservations[key] = reservation
+		for otherKey, other := range s.reservations {
+			if other.InstanceID == reservation.InstanceID && other.OperationID != operationID {
+				delete(s.reservations, otherKey)
+			}
+		}
+	}
+	return nil
+}
+
+func (s *QuotaReservation) Release(operationID string) error {
+	if operationID == "" {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key, reservation := range s.reservations {
+		if reservation.OperationID == operationID {
+			delete(s.reservations, key)
+		}
+	}
+	return nil
+}
+
+func (s *QuotaReservation) ReleasePlan(instanceID, planID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.reservations, quotaReservationKey{instanceID: instanceID, planID: planID})
+	return nil
+}
+
+func (s *QuotaReservation) ReleaseByInstanceID(instanceID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key, reservation := range s.reservations {
+		if reservation.InstanceID == instanceID {
+			delete(s.reservations, key)
+		}
+	}
+	return nil
+}
+
+func (s *QuotaReservation) CountUsed(subAccountID, planID string) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.countUsed(subAccountID, planID), nil
+}
+
+func (s *QuotaReservation) List() ([]internal.QuotaReservation, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	reservations := make([]internal.QuotaReservation, 0, len(s.reservations))
+	for _, reservation := range s.reservations {
+		reservations = append(reservations, reservation)
+	}
+	sort.Slice(reservations, func(i, j int) bool {
+		return reservations[i].CreatedAt.Before(reservations[j].CreatedAt)
+	})
+	return reservations, nil
+}
+
+func (s *QuotaReservation) countUsed(subAccountID, planID string) int {
+	used := make(map[string]struct{})
+	for _, reservation := range s.reservations {
+		if reservation.SubAccountID == subAccountID && reservation.PlanID == planID {
+			used[reservation.InstanceID] = struct{}{}
+		}
+	}
+
+	s.instances.mu.Lock()
+	defer s.instances.mu.Unlock()
+	for _, instance := range s.instances.instances {
+		if instance.SubAccountID == subAccountID && instance.ServicePlanID == planID {
+			used[instance.InstanceID] = struct{}{}
+		}
+	}
+	return len(used)
+}
@@ -4,17 +4,21 @@ import (
 	"sync"
 
 	"github.com/kyma-project/kyma-environment-broker/internal"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage/dberr"
 )
 
 type SubaccountStates struct {
 	mutex sync.Mutex
 
 	subaccountStates map[string]internal.SubaccountState
+	cursor           *internal.SubaccountSyncCursor
+	processedEvents  map[int64]internal.SubaccountSyncEvent
 }
 
 func NewSubaccountStates() *SubaccountStates {
 	return &SubaccountStates{
 		subaccountStates: make(map[string]internal.SubaccountState, 0),
+		processedEvents:  make(map[int64]internal.SubaccountSyncEvent),
 	}
 }
 
@@ -48,3 +52,56 @@ func (s *SubaccountStates) ListStates() ([]internal.SubaccountState, error) {
 
 	return states, nil
 }
+
+func (s *SubaccountStates) GetSyncCursor() (internal.SubaccountSyncCursor, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.cursor == nil {
+		return internal.SubaccountSyncCursor{}, dberr.NotFound("subaccount sync cursor not found")
+	}
+	return *s.cursor, nil
+}
+
+func (s *SubaccountStates) UpsertSyncCursor(cursor internal.SubaccountSyncCursor) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.cursor = &cursor
+	return nil
+}
+
+func (s *SubaccountStates) ListProcessedEventIDs(fromActionTime int64) ([]int64, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	ids := make([]int64, 0)
+	for id, event := range s.processedEvents {
+		if event.ActionTime >= fromActionTime {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+func (s *SubaccountStates) MarkEventProcessed(event internal.SubaccountSyncEvent) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, ok := s.processedEvents[event.ID]; !ok {
+		s.processedEvents[event.ID] = event
+	}
+	return nil
+}
+
+func (s *SubaccountStates) DeleteProcessedEventsBefore(actionTime int64) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for id, event := range s.processedEvents {
+		if event.ActionTime < actionTime {
+			delete(s.processedEvents, id)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package memory
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/kyma-project/kyma-environment-broker/internal"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage/dberr"
+)
+
+type WebhookDelivery struct {
+	mu   sync.Mutex
+	data map[string]internal.WebhookDelivery
+}
+
+func NewWebhookDelivery() *WebhookDelivery {
+	return &WebhookDelivery{
+		data: make(map[string]internal.WebhookDelivery),
+	}
+}
+
+func (s *WebhookDelivery) Insert(delivery internal.WebhookDelivery) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, found := s.data[delivery.ID]; found {
+		return dberr.AlreadyExists("webhook delivery with id %s already exists", delivery.ID)
+	}
+	s.data[delivery.ID] = delivery
+
+	return nil
+}
+
+func (s *WebhookDelivery) Update(delivery internal.WebhookDelivery) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, found := s.data[delivery.ID]
+	if !found || delivery.ClaimedBy == "" || stored.ClaimedBy != delivery.ClaimedBy {
+		return dberr.Conflict("webhook delivery %s does not exist or is claimed by another owner than %s", delivery.ID, delivery.ClaimedBy)
+	}
+	delivery.ClaimedBy = ""
+	delivery.ClaimedUntil = nil
+	delivery.UpdatedAt = time.Now()
+	s.data[delivery.ID] = delivery
+
+	return nil
+}
+
+func (s *WebhookDelivery) ClaimPending(owner string, now, claimedUntil time.Time, limit int) ([]internal.WebhookDelivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := make([]internal.WebhookDelivery, 0)
+	for _, delivery := range s.data {
+		if delivery.State != internal.WebhookDeliveryPending || delivery.NextAttemptAt.After(now) {
+			continue
+		}
+		if delivery.ClaimedUntil != nil && !delivery.ClaimedUntil.Before(now) {
+			continue
+		}
+		result = append(result, delivery)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].NextAttemptAt.Before(result[j].NextAttemptAt)
+	})
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+	for i := range result {
+		result[i].ClaimedBy = owner
+		result[i].ClaimedUntil = &claimedUntil
+		s.data[result[i].ID] = result[i]
+	}
+
+	return result, nil
+}
+
+func (s *WebhookDelivery) ListByOperationID(operationID string) ([]internal.WebhookDelivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := make([]internal.WebhookDelivery, 0)
+	for _, delivery := range s.data {
+		if delivery.OperationID == operationID {
+			result = append(result, delivery)
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CreatedAt.Before(result[j].CreatedAt)
+	})
+
+	return result, nil
+}
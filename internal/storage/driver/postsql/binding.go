@@ -1,12 +1,15 @@
 package postsql
 
 import (
+	"encoding/json"
 	"fmt"
 
 	"github.com/kyma-project/kyma-environment-broker/internal"
 	"github.com/kyma-project/kyma-environment-broker/internal/storage/dberr"
 	"github.com/kyma-project/kyma-environment-broker/internal/storage/dbmodel"
 	"github.com/kyma-project/kyma-environment-broker/internal/storage/postsql"
+
+	"github.com/pivotal-cf/brokerapi/v12/domain"
 )
 
 type Binding struct {
@@ -129,6 +132,11 @@ func (s *Binding) toBindingDTO(binding *internal.Binding) (dbmodel.BindingDTO, e
 		return dbmodel.BindingDTO{}, fmt.Errorf("while encrypting kubeconfig: %w", err)
 	}
 
+	scope, err := json.Marshal(binding.Scope)
+	if err != nil {
+		return dbmodel.BindingDTO{}, fmt.Errorf("while marshalling binding scope: %w", err)
+	}
+
 	return dbmodel.BindingDTO{
 		Kubeconfig:        string(encrypted),
 		ID:                binding.ID,
@@ -137,6 +145,9 @@ func (s *Binding) toBindingDTO(binding *internal.Binding) (dbmodel.BindingDTO, e
 		ExpirationSeconds: binding.ExpirationSeconds,
 		CreatedBy:         binding.CreatedBy,
 		ExpiresAt:         binding.ExpiresAt,
+		Scope:             string(scope),
+		State:             string(binding.State),
+		Description:       binding.Description,
 	}, nil
 }
 
@@ -146,6 +157,14 @@ func (s *Binding) toBinding(dto dbmodel.BindingDTO) (internal.Binding, error) {
 		return internal.Binding{}, fmt.Errorf("while decrypting kubeconfig: %w", err)
 	}
 
+	// bindings created before the scopes were introduced have the cluster-admin permissions
+	scope := internal.BindingScope{Profile: internal.BindingProfileClusterAdmin}
+	if dto.Scope != "" {
+		if err := json.Unmarshal([]byte(dto.Scope), &scope); err != nil {
+			return internal.Binding{}, fmt.Errorf("while unmarshalling binding scope: %w", err)
+		}
+	}
+
 	return internal.Binding{
 		Kubeconfig:        string(decrypted),
 		ID:                dto.ID,
@@ -154,6 +173,9 @@ func (s *Binding) toBinding(dto dbmodel.BindingDTO) (internal.Binding, error) {
 		ExpirationSeconds: dto.ExpirationSeconds,
 		CreatedBy:         dto.CreatedBy,
 		ExpiresAt:         dto.ExpiresAt,
+		Scope:             scope,
+		State:             domain.LastOperationState(dto.State),
+		Description:       dto.Description,
 	}, nil
 }
 
@@ -45,6 +45,8 @@ func TestBinding(t *testing.T) {
 		assert.NotNil(t, createdBinding.Kubeconfig)
 		assert.Equal(t, fixedBinding.Kubeconfig, createdBinding.Kubeconfig)
 		assert.Equal(t, fixedBinding.CreatedBy, createdBinding.CreatedBy)
+		assert.Equal(t, fixedBinding.Scope, createdBinding.Scope)
+		assert.Equal(t, fixedBinding.State, createdBinding.State)
 
 		// when
 		err = brokerStorage.Bindings().Delete(testInstanceID, testBindingId)
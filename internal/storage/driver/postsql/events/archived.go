@@ -0,0 +1,18 @@
+package events
+
+import (
+	eventsapi "github.com/kyma-project/kyma-environment-broker/common/events"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage/postsql"
+)
+
+type ArchivedEvents struct {
+	postsql.Factory
+}
+
+func NewArchivedEvents(fac postsql.Factory) *ArchivedEvents {
+	return &ArchivedEvents{Factory: fac}
+}
+
+func (a *ArchivedEvents) ListWithArchivedByInstanceID(instanceID string) ([]eventsapi.EventDTO, error) {
+	return a.Factory.NewReadSession().ListEventsWithArchivedByInstanceID(instanceID)
+}
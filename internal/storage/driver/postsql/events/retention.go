@@ -0,0 +1,64 @@
+package events
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/kyma-project/kyma-environment-broker/internal/storage/postsql"
+)
+
+type Retention struct {
+	postsql.Factory
+}
+
+func NewRetention(fac postsql.Factory) *Retention {
+	return &Retention{Factory: fac}
+}
+
+func (r *Retention) ListDeprovisionedInstanceIDs(lastEventBefore time.Time, limit int) ([]string, error) {
+	return r.Factory.NewReadSession().ListDeprovisionedEventInstanceIDs(lastEventBefore, limit)
+}
+
+func (r *Retention) CountByInstanceIDs(instanceIDs []string) (int, error) {
+	return r.Factory.NewReadSession().CountEventsByInstanceIDs(instanceIDs)
+}
+
+func (r *Retention) ArchiveByInstanceIDs(instanceIDs []string) (int, error) {
+	sess, err := r.Factory.NewSessionWithinTransaction()
+	if err != nil {
+		return 0, err
+	}
+	defer sess.RollbackUnlessCommitted()
+
+	if _, err := sess.ArchiveEventsByInstanceIDs(instanceIDs, time.Now()); err != nil {
+		return 0, err
+	}
+	deleted, err := sess.DeleteEventsByInstanceIDs(instanceIDs)
+	if err != nil {
+		return 0, err
+	}
+	if err := sess.Commit(); err != nil {
+		return 0, fmt.Errorf("while committing archived events: %w", err)
+	}
+	return deleted, nil
+}
+
+func (r *Retention) DeleteByInstanceIDs(instanceIDs []string) (int, error) {
+	deleted, err := r.Factory.NewWriteSession().DeleteEventsByInstanceIDs(instanceIDs)
+	if err != nil {
+		return 0, err
+	}
+	return deleted, nil
+}
+
+func (r *Retention) CountExpired(createdBefore time.Time) (int, error) {
+	return r.Factory.NewReadSession().CountExpiredEvents(createdBefore)
+}
+
+func (r *Retention) DeleteExpired(createdBefore time.Time, limit int) (int, error) {
+	deleted, err := r.Factory.NewWriteSession().DeleteExpiredEvents(createdBefore, limit)
+	if err != nil {
+		return 0, err
+	}
+	return deleted, nil
+}
@@ -0,0 +1,94 @@
+package postsql_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kyma-project/kyma-environment-broker/internal/fixture"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventsRetention(t *testing.T) {
+	storageCleanup, brokerStorage, conn, err := storage.GetTestStorageWithConn(brokerStorageDatabaseTestConfig())
+	require.NoError(t, err)
+	require.NotNil(t, brokerStorage)
+	defer func() {
+		err := storageCleanup()
+		assert.NoError(t, err)
+	}()
+
+	now := time.Now().UTC()
+	require.NoError(t, brokerStorage.Instances().Insert(fixture.FixInstance("existing")))
+	insertEvent := func(instanceID string, createdAt time.Time) {
+		_, err := conn.NewSession(nil).InsertInto("events").
+			Pair("id", uuid.NewString()).
+			Pair("level", "info").
+			Pair("instance_id", instanceID).
+			Pair("operation_id", "op-"+instanceID).
+			Pair("message", "event").
+			Pair("created_at", createdAt).
+			Exec()
+		require.NoError(t, err)
+	}
+	insertEvent("existing", now.Add(-48*time.Hour))
+	insertEvent("existing", now.Add(-30*time.Hour))
+	insertEvent("existing", now)
+	insertEvent("", now.Add(-48*time.Hour))
+	insertEvent("deprovisioned-1", now.Add(-48*time.Hour))
+	insertEvent("deprovisioned-1", now.Add(-47*time.Hour))
+	insertEvent("deprovisioned-2", now.Add(-48*time.Hour))
+	insertEvent("deprovisioned-3", now.Add(-time.Hour))
+	retention := brokerStorage.EventsRetention()
+
+	ids, err := retention.ListDeprovisionedInstanceIDs(now.Add(-24*time.Hour), 10)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"deprovisioned-1", "deprovisioned-2"}, ids, "instance with recent events is not listed")
+
+	ids, err = retention.ListDeprovisionedInstanceIDs(now.Add(-24*time.Hour), 1)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"deprovisioned-1"}, ids)
+
+	count, err := retention.CountByInstanceIDs([]string{"deprovisioned-1", "deprovisioned-2"})
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+
+	count, err = retention.CountExpired(now.Add(-24 * time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 3, count, "events of deprovisioned instances are not expired")
+
+	archived, err := retention.ArchiveByInstanceIDs([]string{"deprovisioned-1"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, archived)
+
+	var archivedCount int
+	require.NoError(t, conn.QueryRow("SELECT count(*) FROM events_archived WHERE instance_id = 'deprovisioned-1'").Scan(&archivedCount))
+	assert.Equal(t, 2, archivedCount)
+
+	insertEvent("deprovisioned-1", now.Add(-46*time.Hour))
+	instanceEvents, err := brokerStorage.ArchivedEvents().ListWithArchivedByInstanceID("deprovisioned-1")
+	require.NoError(t, err)
+	require.Len(t, instanceEvents, 3, "archived and not archived events are listed")
+	assert.True(t, instanceEvents[0].CreatedAt.Before(instanceEvents[1].CreatedAt))
+	assert.True(t, instanceEvents[1].CreatedAt.Before(instanceEvents[2].CreatedAt))
+	_, err = retention.DeleteByInstanceIDs([]string{"deprovisioned-1"})
+	require.NoError(t, err)
+
+	deleted, err := retention.DeleteByInstanceIDs([]string{"deprovisioned-2"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, deleted)
+
+	deleted, err = retention.DeleteExpired(now.Add(-24*time.Hour), 2)
+	require.NoError(t, err)
+	assert.Equal(t, 2, deleted)
+	deleted, err = retention.DeleteExpired(now.Add(-24*time.Hour), 2)
+	require.NoError(t, err)
+	assert.Equal(t, 1, deleted)
+
+	var remaining int
+	require.NoError(t, conn.QueryRow("SELECT count(*) FROM events").Scan(&remaining))
+	assert.Equal(t, 2, remaining, "recent events of the existing instance and events of the recently deprovisioned instance are kept")
+}
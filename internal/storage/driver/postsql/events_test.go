@@ -0,0 +1,73 @@
+package postsql_test
+
+import (
+	"testing"
+	"time"
+
+	eventsapi "github.com/kyma-project/kyma-environment-broker/common/events"
+	"github.com/kyma-project/kyma-environment-broker/internal"
+	"github.com/kyma-project/kyma-environment-broker/internal/fixture"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage"
+	eventstorage "github.com/kyma-project/kyma-environment-broker/internal/storage/driver/postsql/events"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage/postsql"
+
+	"github.com/pivotal-cf/brokerapi/v12/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvents_ListEventsByRegion(t *testing.T) {
+	storageCleanup, brokerStorage, conn, err := storage.GetTestStorageWithConn(brokerStorageDatabaseTestConfig())
+	require.NoError(t, err)
+	require.NotNil(t, brokerStorage)
+	defer func() {
+		err := storageCleanup()
+		assert.NoError(t, err)
+	}()
+
+	// given
+	euInstance := fixture.FixInstance("eu-instance")
+	euInstance.ProviderRegion = "eu-west-1"
+	require.NoError(t, brokerStorage.Instances().Insert(euInstance))
+	usInstance := fixture.FixInstance("us-instance")
+	usInstance.ProviderRegion = "us-east-1"
+	require.NoError(t, brokerStorage.Instances().Insert(usInstance))
+	require.NoError(t, brokerStorage.InstancesArchived().Insert(internal.InstanceArchived{
+		InstanceID:                   "archived-eu-instance",
+		GlobalAccountID:              "ga",
+		SubaccountID:                 "sa",
+		PlanID:                       "plan-id",
+		PlanName:                     "aws",
+		SubaccountRegion:             "cf-eu10",
+		Region:                       "eu-west-1",
+		Provider:                     "aws",
+		ProvisioningStartedAt:        time.Now(),
+		ProvisioningState:            domain.Succeeded,
+		FirstDeprovisioningStartedAt: time.Now(),
+	}))
+
+	events := eventstorage.New(postsql.NewFactory(conn))
+	events.InsertEvent(eventsapi.InfoEventLevel, "eu event", "eu-instance", "op-1")
+	events.InsertEvent(eventsapi.InfoEventLevel, "us event", "us-instance", "op-2")
+	events.InsertEvent(eventsapi.InfoEventLevel, "archived eu event", "archived-eu-instance", "op-3")
+	events.InsertEvent(eventsapi.InfoEventLevel, "event without instance", "", "op-4")
+
+	// when
+	found, err := events.ListEvents(eventsapi.EventFilter{Regions: []string{"eu-west-1"}})
+
+	// then
+	require.NoError(t, err)
+	var messages []string
+	for _, event := range found {
+		messages = append(messages, event.Message)
+	}
+	assert.ElementsMatch(t, []string{"eu event", "archived eu event"}, messages)
+
+	// when
+	found, err = events.ListEvents(eventsapi.EventFilter{Regions: []string{"eu-west-1", "us-east-1"}, SubAccountIDs: []string{usInstance.SubAccountID}})
+
+	// then
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	assert.Equal(t, "us event", found[0].Message)
+}
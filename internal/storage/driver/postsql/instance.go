@@ -205,6 +205,7 @@ func (s *Instance) toInstance(dto dbmodel.InstanceDTO) (internal.Instance, error
 		Version:                     dto.Version,
 		Provider:                    pkg.CloudProvider(dto.Provider),
 		EmptyUpdates:                dto.EmptyUpdates,
+		ExpirationExtensions:        dto.ExpirationExtensions,
 	}, nil
 }
 
@@ -256,6 +257,7 @@ func (s *Instance) toInstanceWithSubaccountState(dto dbmodel.InstanceWithSubacco
 			ExpiredAt:                   dto.ExpiredAt,
 			Version:                     dto.InstanceDTO.Version,
 			EmptyUpdates:                dto.EmptyUpdates,
+			ExpirationExtensions:        dto.ExpirationExtensions,
 			Provider:                    pkg.CloudProvider(dto.Provider)},
 		BetaEnabled:       betaEnabled,
 		UsedForProduction: usedForProduction,
@@ -353,6 +355,7 @@ func (s *Instance) toInstanceDTO(instance internal.Instance) (dbmodel.InstanceDT
 		Version:                     instance.Version,
 		Provider:                    string(instance.Provider),
 		EmptyUpdates:                instance.EmptyUpdates,
+		ExpirationExtensions:        instance.ExpirationExtensions,
 	}, nil
 }
 
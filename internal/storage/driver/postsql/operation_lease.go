@@ -0,0 +1,50 @@
+package postsql
+
+import (
+	"time"
+
+	"github.com/kyma-project/kyma-environment-broker/internal"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage/postsql"
+)
+
+type OperationLease struct {
+	postsql.Factory
+}
+
+func NewOperationLease(sess postsql.Factory) *OperationLease {
+	return &OperationLease{
+		Factory: sess,
+	}
+}
+
+func (s *OperationLease) Acquire(operationID, owner string, now time.Time, duration time.Duration) (bool, error) {
+	acquired, err := s.Factory.NewWriteSession().AcquireOperationLease(operationID, owner, now, now.Add(duration))
+	if err != nil {
+		return false, err
+	}
+	return acquired, nil
+}
+
+func (s *OperationLease) Renew(owner string, operationIDs []string, now time.Time, duration time.Duration) ([]string, error) {
+	renewed, err := s.Factory.NewWriteSession().RenewOperationLeases(owner, operationIDs, now, now.Add(duration))
+	if err != nil {
+		return nil, err
+	}
+	return renewed, nil
+}
+
+func (s *OperationLease) Release(operationID, owner string) error {
+	return s.Factory.NewWriteSession().ReleaseOperationLease(operationID, owner)
+}
+
+func (s *OperationLease) AcquireJob(name, owner string, now time.Time, duration time.Duration) (bool, error) {
+	acquired, err := s.Factory.NewWriteSession().AcquireJobLease(name, owner, now, now.Add(duration))
+	if err != nil {
+		return false, err
+	}
+	return acquired, nil
+}
+
+func (s *OperationLease) ListClaimable(operationType internal.OperationType, owner string, now time.Time) ([]string, error) {
+	return s.Factory.NewReadSession().ListClaimableOperationIDs(operationType, owner, now)
+}
@@ -0,0 +1,105 @@
+package postsql_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kyma-project/kyma-environment-broker/internal"
+	"github.com/kyma-project/kyma-environment-broker/internal/fixture"
+
+	"github.com/pivotal-cf/brokerapi/v12/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOperationLease(t *testing.T) {
+	storageCleanup, brokerStorage, err := GetStorageForDatabaseTests()
+	require.NoError(t, err)
+	require.NotNil(t, brokerStorage)
+	defer func() {
+		err := storageCleanup()
+		assert.NoError(t, err)
+	}()
+
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	inProgress := fixture.FixProvisioningOperation("op-in-progress", "instance-1")
+	inProgress.State = domain.InProgress
+	pending := fixture.FixProvisioningOperation("op-pending", "instance-2")
+	pending.State = internal.OperationStatePending
+	succeeded := fixture.FixProvisioningOperation("op-succeeded", "instance-3")
+	for _, op := range []internal.Operation{inProgress, pending, succeeded} {
+		require.NoError(t, brokerStorage.Operations().InsertOperation(op))
+	}
+	leases := brokerStorage.OperationLeases()
+
+	claimable, err := leases.ListClaimable(internal.OperationTypeProvision, "keb-1", now)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"op-in-progress", "op-pending"}, claimable)
+
+	acquired, err := leases.Acquire("op-in-progress", "keb-1", now, time.Minute)
+	require.NoError(t, err)
+	assert.True(t, acquired)
+
+	acquired, err = leases.Acquire("op-in-progress", "keb-2", now, time.Minute)
+	require.NoError(t, err)
+	assert.False(t, acquired, "lease held by another owner")
+
+	claimable, err = leases.ListClaimable(internal.OperationTypeProvision, "keb-2", now)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"op-pending"}, claimable)
+
+	renewed, err := leases.Renew("keb-1", []string{"op-in-progress", "op-pending"}, now.Add(30*time.Second), time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"op-in-progress"}, renewed)
+
+	acquired, err = leases.Acquire("op-in-progress", "keb-2", now.Add(time.Minute), time.Minute)
+	require.NoError(t, err)
+	assert.False(t, acquired, "renewed lease is still valid")
+
+	acquired, err = leases.Acquire("op-in-progress", "keb-2", now.Add(2*time.Minute), time.Minute)
+	require.NoError(t, err)
+	assert.True(t, acquired, "expired lease is taken over")
+
+	require.NoError(t, leases.Release("op-in-progress", "keb-1"))
+	acquired, err = leases.Acquire("op-in-progress", "keb-1", now.Add(2*time.Minute), time.Minute)
+	require.NoError(t, err)
+	assert.False(t, acquired, "lease is released only by its owner")
+
+	require.NoError(t, leases.Release("op-in-progress", "keb-2"))
+	acquired, err = leases.Acquire("op-in-progress", "keb-1", now.Add(2*time.Minute), time.Minute)
+	require.NoError(t, err)
+	assert.True(t, acquired)
+}
+
+func TestJobLease(t *testing.T) {
+	storageCleanup, brokerStorage, err := GetStorageForDatabaseTests()
+	require.NoError(t, err)
+	require.NotNil(t, brokerStorage)
+	defer func() {
+		err := storageCleanup()
+		assert.NoError(t, err)
+	}()
+
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	leases := brokerStorage.OperationLeases()
+
+	acquired, err := leases.AcquireJob("quota-reconciliation", "keb-1", now, time.Hour)
+	require.NoError(t, err)
+	assert.True(t, acquired)
+
+	acquired, err = leases.AcquireJob("quota-reconciliation", "keb-2", now.Add(time.Minute), time.Hour)
+	require.NoError(t, err)
+	assert.False(t, acquired, "lease held by another owner")
+
+	acquired, err = leases.AcquireJob("other-job", "keb-2", now, time.Hour)
+	require.NoError(t, err)
+	assert.True(t, acquired, "jobs are leased independently")
+
+	acquired, err = leases.AcquireJob("quota-reconciliation", "keb-1", now.Add(time.Hour), time.Hour)
+	require.NoError(t, err)
+	assert.True(t, acquired, "the owner renews its lease")
+
+	acquired, err = leases.AcquireJob("quota-reconciliation", "keb-2", now.Add(3*time.Hour), time.Hour)
+	require.NoError(t, err)
+	assert.True(t, acquired, "expired lease is taken over")
+}
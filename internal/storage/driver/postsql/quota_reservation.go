@@ -0,0 +1,114 @@
+package postsql
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/kyma-project/kyma-environment-broker/internal"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage/dbmodel"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage/postsql"
+)
+
+type QuotaReservation struct {
+	postsql.Factory
+}
+
+func NewQuotaReservation(sess postsql.Factory) *QuotaReservation {
+	return &QuotaReservation{
+		Factory: sess,
+	}
+}
+
+func (s *QuotaReservation) Reserve(reservation internal.QuotaReservation, assignedQuota int) (bool, error) {
+	sess, err := s.Factory.NewSessionWithinTransaction()
+	if err != nil {
+		return false, err
+	}
+	defer sess.RollbackUnlessCommitted()
+
+	if err := sess.LockQuotaReservations(reservation.SubAccountID, reservation.PlanID); err != nil {
+		return false, err
+	}
+	exists, err := sess.QuotaReservationExists(reservation.InstanceID, reservation.PlanID)
+	if err != nil {
+		return false, err
+	}
+	if exists {
+		return true, nil
+	}
+	used, err := sess.CountUsedQuota(reservation.SubAccountID, reservation.PlanID)
+	if err != nil {
+		return false, err
+	}
+	if used >= assignedQuota {
+		return false, nil
+	}
+
+	now := time.Now()
+	reservation.State = internal.QuotaReservationReserved
+	reservation.CreatedAt = now
+	reservation.UpdatedAt = now
+	if err := sess.InsertQuotaReservation(toQuotaReservationDTO(reservation)); err != nil {
+		return false, err
+	}
+	if err := sess.Commit(); err != nil {
+		return false, fmt.Errorf("while committing quota reservation: %w", err)
+	}
+	return true, nil
+}
+
+func (s *QuotaReservation) Confirm(operationID string) error {
+	return s.Factory.NewWriteSession().ConfirmQuotaReservation(operationID, time.Now())
+}
+
+func (s *QuotaReservation) Release(operationID string) error {
+	return s.Factory.NewWriteSession().DeleteQuotaReservationsByOperationID(operationID)
+}
+
+func (s *QuotaReservation) ReleasePlan(instanceID, planID string) error {
+	return s.Factory.NewWriteSession().DeleteQuotaReservation(instanceID, planID)
+}
+
+func (s *QuotaReservation) ReleaseByInstanceID(instanceID string) error {
+	return s.Factory.NewWriteSession().DeleteQuotaReservationsByInstanceID(instanceID)
+}
+
+func (s *QuotaReservation) CountUsed(subAccountID, planID string) (int, error) {
+	count, err := s.Factory.NewReadSession().CountUsedQuota(subAccountID, planID)
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (s *QuotaReservation) List() ([]internal.QuotaReservation, error) {
+	dtos, err := s.Factory.NewReadSession().ListQuotaReservations()
+	if err != nil {
+		return nil, err
+	}
+	reservations := make([]internal.QuotaReservation, 0, len(dtos))
+	for _, dto := range dtos {
+		reservations = append(reservations, internal.QuotaReservation{
+			InstanceID:   dto.InstanceID,
+			SubAccountID: dto.SubAccountID,
+			PlanID:       dto.PlanID,
+			OperationID:  dto.OperationID,
+			State:        internal.QuotaReservationState(dto.State),
+			CreatedAt:    dto.CreatedAt,
+			UpdatedAt:    dto.UpdatedAt,
+		})
+	}
+	return reservations, nil
+}
+
+func toQuotaReservationDTO(reservation internal.QuotaReservation) dbmodel.QuotaReservationDTO {
+	return dbmodel.QuotaReservationDTO{
+		InstanceID:   reservation.InstanceID,
+		SubAccountID: reservation.SubAccountID,
+		PlanID:       reservation.PlanID,
+		OperationID:  reservation.OperationID,
+		State:        string(reservation.State),
+		CreatedAt:    reservation.CreatedAt,
+		UpdatedAt:    reservation.UpdatedAt,
+	}
+}
@@ -0,0 +1,79 @@
+package postsql_test
+
+import (
+	"testing"
+
+	"github.com/kyma-project/kyma-environment-broker/internal"
+	"github.com/kyma-project/kyma-environment-broker/internal/fixture"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuotaReservation(t *testing.T) {
+	storageCleanup, brokerStorage, err := GetStorageForDatabaseTests()
+	require.NoError(t, err)
+	require.NotNil(t, brokerStorage)
+	defer func() {
+		err := storageCleanup()
+		assert.NoError(t, err)
+	}()
+
+	existing := fixture.FixInstance("instance-1")
+	existing.SubAccountID = "subaccount-1"
+	existing.ServicePlanID = "plan-a"
+	require.NoError(t, brokerStorage.Instances().Insert(existing))
+	reservations := brokerStorage.QuotaReservations()
+
+	fixReservation := func(instanceID, planID, operationID string) internal.QuotaReservation {
+		return internal.QuotaReservation{InstanceID: instanceID, SubAccountID: "subaccount-1", PlanID: planID, OperationID: operationID}
+	}
+
+	used, err := reservations.CountUsed("subaccount-1", "plan-a")
+	require.NoError(t, err)
+	assert.Equal(t, 1, used, "existing instances use the quota")
+
+	reserved, err := reservations.Reserve(fixReservation("instance-2", "plan-a", "op-2"), 2)
+	require.NoError(t, err)
+	assert.True(t, reserved)
+
+	reserved, err = reservations.Reserve(fixReservation("instance-3", "plan-a", "op-3"), 2)
+	require.NoError(t, err)
+	assert.False(t, reserved, "quota exhausted")
+
+	reserved, err = reservations.Reserve(fixReservation("instance-2", "plan-a", "op-2"), 2)
+	require.NoError(t, err)
+	assert.True(t, reserved, "reservation of the instance already exists")
+
+	used, err = reservations.CountUsed("subaccount-1", "plan-a")
+	require.NoError(t, err)
+	assert.Equal(t, 2, used)
+
+	reserved, err = reservations.Reserve(fixReservation("instance-1", "plan-b", "op-upgrade"), 1)
+	require.NoError(t, err)
+	assert.True(t, reserved)
+
+	require.NoError(t, reservations.Confirm("op-upgrade"))
+	all, err := reservations.List()
+	require.NoError(t, err)
+	require.Len(t, all, 2)
+	for _, reservation := range all {
+		if reservation.InstanceID == "instance-1" {
+			assert.Equal(t, "plan-b", reservation.PlanID, "previous reservation of the instance is released")
+			assert.Equal(t, internal.QuotaReservationConfirmed, reservation.State)
+		} else {
+			assert.Equal(t, internal.QuotaReservationReserved, reservation.State)
+		}
+	}
+
+	require.NoError(t, reservations.Release("op-2"))
+	require.NoError(t, reservations.ReleasePlan("instance-1", "plan-a"))
+	all, err = reservations.List()
+	require.NoError(t, err)
+	assert.Len(t, all, 1, "the instance has no reservation of the plan")
+
+	require.NoError(t, reservations.ReleaseByInstanceID("instance-1"))
+	all, err = reservations.List()
+	require.NoError(t, err)
+	assert.Empty(t, all)
+}
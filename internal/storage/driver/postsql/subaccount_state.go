@@ -2,6 +2,7 @@ package postsql
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 
 	"github.com/kyma-project/kyma-environment-broker/internal"
@@ -11,6 +12,9 @@ import (
 	"k8s.io/apimachinery/pkg/util/wait"
 )
 
+// subaccountSyncCursorID identifies the only cursor row, there is a single subaccount sync per database
+const subaccountSyncCursorID = "subaccount-sync"
+
 type SubaccountState struct {
 	postsql.Factory
 }
@@ -65,21 +69,89 @@ func (s *SubaccountState) ListStates() ([]internal.SubaccountState, error) {
 	return result, nil
 }
 
+func (s *SubaccountState) GetSyncCursor() (internal.SubaccountSyncCursor, error) {
+	sess := s.Factory.NewReadSession()
+	dto, err := sess.GetSubaccountSyncCursor(subaccountSyncCursorID)
+	if err != nil {
+		return internal.SubaccountSyncCursor{}, err
+	}
+	return internal.SubaccountSyncCursor{
+		LastEventID:         dto.LastEventID,
+		LastEventActionTime: dto.LastEventActionTime,
+		EventsSyncedAt:      dto.EventsSyncedAt,
+		AccountsSyncedAt:    dto.AccountsSyncedAt,
+	}, nil
+}
+
+func (s *SubaccountState) UpsertSyncCursor(cursor internal.SubaccountSyncCursor) error {
+	sess := s.Factory.NewWriteSession()
+	return sess.UpsertSubaccountSyncCursor(dbmodel.SubaccountSyncCursorDTO{
+		ID:                  subaccountSyncCursorID,
+		LastEventID:         cursor.LastEventID,
+		LastEventActionTime: cursor.LastEventActionTime,
+		EventsSyncedAt:      cursor.EventsSyncedAt,
+		AccountsSyncedAt:    cursor.AccountsSyncedAt,
+	})
+}
+
+func (s *SubaccountState) ListProcessedEventIDs(fromActionTime int64) ([]int64, error) {
+	sess := s.Factory.NewReadSession()
+	ids, err := sess.ListSubaccountSyncEventIDs(fromActionTime)
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func (s *SubaccountState) MarkEventProcessed(event internal.SubaccountSyncEvent) error {
+	sess := s.Factory.NewWriteSession()
+	return sess.InsertSubaccountSyncEvent(dbmodel.SubaccountSyncEventDTO{
+		EventID:      event.ID,
+		SubAccountID: event.SubaccountID,
+		ActionTime:   event.ActionTime,
+		ProcessedAt:  event.ProcessedAt,
+	})
+}
+
+func (s *SubaccountState) DeleteProcessedEventsBefore(actionTime int64) error {
+	sess := s.Factory.NewWriteSession()
+	return sess.DeleteSubaccountSyncEventsBefore(actionTime)
+}
+
 func (s *SubaccountState) subaccountStateToDB(state internal.SubaccountState) (dbmodel.SubaccountStateDTO, error) {
+	attributes := []byte("{}")
+	if len(state.Attributes) > 0 {
+		var err error
+		attributes, err = json.Marshal(state.Attributes)
+		if err != nil {
+			return dbmodel.SubaccountStateDTO{}, fmt.Errorf("while marshalling subaccount attributes: %w", err)
+		}
+	}
 	return dbmodel.SubaccountStateDTO{
 		ID:                state.ID,
 		BetaEnabled:       state.BetaEnabled,
 		UsedForProduction: state.UsedForProduction,
 		ModifiedAt:        state.ModifiedAt,
+		Attributes:        string(attributes),
 	}, nil
 }
 
 func (s *SubaccountState) toSubaccountState(dto *dbmodel.SubaccountStateDTO) (internal.SubaccountState, error) {
+	var attributes map[string]string
+	if dto.Attributes != "" {
+		if err := json.Unmarshal([]byte(dto.Attributes), &attributes); err != nil {
+			return internal.SubaccountState{}, fmt.Errorf("while unmarshalling subaccount attributes: %w", err)
+		}
+	}
+	if len(attributes) == 0 {
+		attributes = nil
+	}
 	return internal.SubaccountState{
 		ID:                dto.ID,
 		BetaEnabled:       dto.BetaEnabled,
 		UsedForProduction: dto.UsedForProduction,
 		ModifiedAt:        dto.ModifiedAt,
+		Attributes:        attributes,
 	}, nil
 }
 
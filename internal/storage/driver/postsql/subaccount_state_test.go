@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/kyma-project/kyma-environment-broker/internal"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage/dberr"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -33,6 +34,7 @@ var (
 		BetaEnabled:       "true",
 		UsedForProduction: "USED_FOR_PRODUCTION",
 		ModifiedAt:        108,
+		Attributes:        map[string]string{"displayName": "subaccount-2", "region": "eu10"},
 	}
 )
 
@@ -98,4 +100,34 @@ func TestSubaccountState(t *testing.T) {
 		assert.Len(t, subaccountStates, 1)
 	})
 
+	t.Run("should upsert and fetch sync cursor", func(t *testing.T) {
+		_, err := svc.GetSyncCursor()
+		assert.True(t, dberr.IsNotFound(err))
+
+		require.NoError(t, svc.UpsertSyncCursor(internal.SubaccountSyncCursor{LastEventID: 1, LastEventActionTime: 100, EventsSyncedAt: 200}))
+		require.NoError(t, svc.UpsertSyncCursor(internal.SubaccountSyncCursor{LastEventID: 2, LastEventActionTime: 150, EventsSyncedAt: 300, AccountsSyncedAt: 250}))
+
+		cursor, err := svc.GetSyncCursor()
+		require.NoError(t, err)
+		assert.Equal(t, internal.SubaccountSyncCursor{LastEventID: 2, LastEventActionTime: 150, EventsSyncedAt: 300, AccountsSyncedAt: 250}, cursor)
+	})
+
+	t.Run("should mark events processed once and delete old ones", func(t *testing.T) {
+		require.NoError(t, svc.MarkEventProcessed(internal.SubaccountSyncEvent{ID: 1, SubaccountID: subaccountID1, ActionTime: 100, ProcessedAt: 110}))
+		require.NoError(t, svc.MarkEventProcessed(internal.SubaccountSyncEvent{ID: 1, SubaccountID: subaccountID1, ActionTime: 100, ProcessedAt: 120}))
+		require.NoError(t, svc.MarkEventProcessed(internal.SubaccountSyncEvent{ID: 2, SubaccountID: subaccountID2, ActionTime: 200, ProcessedAt: 210}))
+
+		processed, err := svc.ListProcessedEventIDs(0)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []int64{1, 2}, processed)
+		processed, err = svc.ListProcessedEventIDs(150)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []int64{2}, processed)
+
+		require.NoError(t, svc.DeleteProcessedEventsBefore(150))
+
+		processed, err = svc.ListProcessedEventIDs(0)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []int64{2}, processed)
+	})
 }
@@ -0,0 +1,98 @@
+package postsql
+
+import (
+	"sort"
+	"time"
+
+	"github.com/kyma-project/kyma-environment-broker/internal"
+	"github.com/kyma-project/kyma-environment-broker/internal/ptr"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage/dbmodel"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage/postsql"
+)
+
+type WebhookDelivery struct {
+	postsql.Factory
+}
+
+func NewWebhookDelivery(sess postsql.Factory) *WebhookDelivery {
+	return &WebhookDelivery{
+		Factory: sess,
+	}
+}
+
+func (s *WebhookDelivery) Insert(delivery internal.WebhookDelivery) error {
+	return s.Factory.NewWriteSession().InsertWebhookDelivery(toWebhookDeliveryDTO(delivery))
+}
+
+func (s *WebhookDelivery) Update(delivery internal.WebhookDelivery) error {
+	delivery.UpdatedAt = time.Now()
+	return s.Factory.NewWriteSession().UpdateWebhookDelivery(toWebhookDeliveryDTO(delivery))
+}
+
+func (s *WebhookDelivery) ClaimPending(owner string, now, claimedUntil time.Time, limit int) ([]internal.WebhookDelivery, error) {
+	dtos, err := s.Factory.NewWriteSession().ClaimPendingWebhookDeliveries(owner, now, claimedUntil, limit)
+	if err != nil {
+		return nil, err
+	}
+	deliveries := toWebhookDeliveries(dtos)
+	sort.Slice(deliveries, func(i, j int) bool {
+		return deliveries[i].NextAttemptAt.Before(deliveries[j].NextAttemptAt)
+	})
+	return deliveries, nil
+}
+
+func (s *WebhookDelivery) ListByOperationID(operationID string) ([]internal.WebhookDelivery, error) {
+	dtos, err := s.Factory.NewReadSession().ListWebhookDeliveriesByOperationID(operationID)
+	if err != nil {
+		return nil, err
+	}
+	return toWebhookDeliveries(dtos), nil
+}
+
+func toWebhookDeliveryDTO(delivery internal.WebhookDelivery) dbmodel.WebhookDeliveryDTO {
+	return dbmodel.WebhookDeliveryDTO{
+		ID:            delivery.ID,
+		Webhook:       delivery.Webhook,
+		Event:         delivery.Event,
+		InstanceID:    delivery.InstanceID,
+		OperationID:   delivery.OperationID,
+		Payload:       delivery.Payload,
+		State:         string(delivery.State),
+		Attempts:      delivery.Attempts,
+		LastError:     delivery.LastError,
+		NextAttemptAt: delivery.NextAttemptAt,
+		ClaimedBy:     claimedBy(delivery.ClaimedBy),
+		CreatedAt:     delivery.CreatedAt,
+		UpdatedAt:     delivery.UpdatedAt,
+	}
+}
+
+func claimedBy(owner string) *string {
+	if owner == "" {
+		return nil
+	}
+	return ptr.String(owner)
+}
+
+func toWebhookDeliveries(dtos []dbmodel.WebhookDeliveryDTO) []internal.WebhookDelivery {
+	deliveries := make([]internal.WebhookDelivery, 0, len(dtos))
+	for _, dto := range dtos {
+		deliveries = append(deliveries, internal.WebhookDelivery{
+			ID:            dto.ID,
+			Webhook:       dto.Webhook,
+			Event:         dto.Event,
+			InstanceID:    dto.InstanceID,
+			OperationID:   dto.OperationID,
+			Payload:       dto.Payload,
+			State:         internal.WebhookDeliveryState(dto.State),
+			Attempts:      dto.Attempts,
+			LastError:     dto.LastError,
+			NextAttemptAt: dto.NextAttemptAt,
+			ClaimedBy:     ptr.ToString(dto.ClaimedBy),
+			ClaimedUntil:  dto.ClaimedUntil,
+			CreatedAt:     dto.CreatedAt,
+			UpdatedAt:     dto.UpdatedAt,
+		})
+	}
+	return deliveries
+}
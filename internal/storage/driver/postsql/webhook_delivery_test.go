@@ -0,0 +1,99 @@
+package postsql_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/kyma-project/kyma-environment-broker/internal"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage/dberr"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookDelivery(t *testing.T) {
+	storageCleanup, brokerStorage, err := GetStorageForDatabaseTests()
+	require.NoError(t, err)
+	require.NotNil(t, brokerStorage)
+	defer func() {
+		err := storageCleanup()
+		assert.NoError(t, err)
+	}()
+
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	due := fixWebhookDelivery("delivery-1", "operation-1", now.Add(-time.Minute))
+	notDue := fixWebhookDelivery("delivery-2", "operation-1", now.Add(time.Hour))
+	other := fixWebhookDelivery("delivery-3", "operation-2", now.Add(-2*time.Minute))
+
+	for _, delivery := range []internal.WebhookDelivery{due, notDue, other} {
+		err = brokerStorage.WebhookDeliveries().Insert(delivery)
+		require.NoError(t, err)
+	}
+
+	pending, err := brokerStorage.WebhookDeliveries().ClaimPending("keb-1", now, now.Add(time.Minute), 1)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	assert.Equal(t, "delivery-3", pending[0].ID)
+	assert.Equal(t, "keb-1", pending[0].ClaimedBy)
+
+	pending, err = brokerStorage.WebhookDeliveries().ClaimPending("keb-2", now, now.Add(time.Minute), 10)
+	require.NoError(t, err)
+	require.Len(t, pending, 1)
+	assert.Equal(t, "delivery-1", pending[0].ID)
+
+	pending, err = brokerStorage.WebhookDeliveries().ClaimPending("keb-2", now, now.Add(time.Minute), 10)
+	require.NoError(t, err)
+	assert.Empty(t, pending)
+
+	pending, err = brokerStorage.WebhookDeliveries().ClaimPending("keb-2", now.Add(2*time.Minute), now.Add(3*time.Minute), 10)
+	require.NoError(t, err)
+	assert.Len(t, pending, 2, "expired claims must be claimable again")
+
+	// the claim of keb-1 on delivery-3 expired and keb-2 claimed it
+	staleClaim := other
+	staleClaim.ClaimedBy = "keb-1"
+	staleClaim.State = internal.WebhookDeliveryFailed
+	err = brokerStorage.WebhookDeliveries().Update(staleClaim)
+	assert.True(t, dberr.IsConflict(err))
+
+	due.ClaimedBy = "keb-2"
+	due.State = internal.WebhookDeliveryDelivered
+	due.Attempts = 1
+	err = brokerStorage.WebhookDeliveries().Update(due)
+	require.NoError(t, err)
+	err = brokerStorage.WebhookDeliveries().Update(due)
+	assert.True(t, dberr.IsConflict(err), "the claim is released by the update")
+
+	deliveries, err := brokerStorage.WebhookDeliveries().ListByOperationID("operation-1")
+	require.NoError(t, err)
+	require.Len(t, deliveries, 2)
+	assert.Equal(t, internal.WebhookDeliveryDelivered, deliveries[0].State)
+	assert.Equal(t, 1, deliveries[0].Attempts)
+	assert.Equal(t, `{"event":"failed"}`, deliveries[0].Payload)
+	assert.Equal(t, internal.WebhookDeliveryPending, deliveries[1].State)
+
+	deliveries, err = brokerStorage.WebhookDeliveries().ListByOperationID("operation-2")
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+	assert.Equal(t, internal.WebhookDeliveryPending, deliveries[0].State, "the stale claim owner does not overwrite the state")
+
+	notExisting := fixWebhookDelivery("not-existing", "operation-1", now)
+	notExisting.ClaimedBy = "keb-2"
+	err = brokerStorage.WebhookDeliveries().Update(notExisting)
+	assert.True(t, dberr.IsConflict(err))
+}
+
+func fixWebhookDelivery(id, operationID string, nextAttemptAt time.Time) internal.WebhookDelivery {
+	return internal.WebhookDelivery{
+		ID:            id,
+		Webhook:       "alerting",
+		Event:         "failed",
+		InstanceID:    "instance-id",
+		OperationID:   operationID,
+		Payload:       `{"event":"failed"}`,
+		State:         internal.WebhookDeliveryPending,
+		NextAttemptAt: nextAttemptAt,
+		CreatedAt:     nextAttemptAt,
+		UpdatedAt:     nextAttemptAt,
+	}
+}
@@ -86,6 +86,14 @@ type SubaccountStates interface {
 	UpsertState(state internal.SubaccountState) error
 	DeleteState(subaccountID string) error
 	ListStates() ([]internal.SubaccountState, error)
+	// GetSyncCursor returns dberr.NotFound if the sync has not stored its cursor yet
+	GetSyncCursor() (internal.SubaccountSyncCursor, error)
+	UpsertSyncCursor(cursor internal.SubaccountSyncCursor) error
+	// ListProcessedEventIDs returns the IDs of the processed events not older than the action time
+	ListProcessedEventIDs(fromActionTime int64) ([]int64, error)
+	// MarkEventProcessed is a no-op if the event is already marked
+	MarkEventProcessed(event internal.SubaccountSyncEvent) error
+	DeleteProcessedEventsBefore(actionTime int64) error
 }
 
 type Bindings interface {
@@ -103,6 +111,67 @@ type Actions interface {
 	ListActionsByInstanceID(instanceID string) ([]runtime.Action, error)
 }
 
+type WebhookDeliveries interface {
+	Insert(delivery internal.WebhookDelivery) error
+	// Update stores the result of sending the delivery and releases its claim. It returns dberr.Conflict
+	// if the delivery is no longer claimed by delivery.ClaimedBy, for example, because the claim expired and another owner claimed it
+	Update(delivery internal.WebhookDelivery) error
+	// ClaimPending claims at most limit pending deliveries which are due and not claimed by another owner until claimedUntil,
+	// a delivery is returned to a single owner even if several owners claim deliveries at the same time
+	ClaimPending(owner string, now, claimedUntil time.Time, limit int) ([]internal.WebhookDelivery, error)
+	ListByOperationID(operationID string) ([]internal.WebhookDelivery, error)
+}
+
+// OperationLeases stores the claims of KEB instances on the operations they process, so several instances can share the work
+type OperationLeases interface {
+	// Acquire claims the operation for the owner, it succeeds if the operation is not leased, the lease expired or the owner already holds it
+	Acquire(operationID, owner string, now time.Time, duration time.Duration) (bool, error)
+	// Renew extends the leases of not finished operations held by the owner and returns IDs of the renewed operations
+	Renew(owner string, operationIDs []string, now time.Time, duration time.Duration) ([]string, error)
+	Release(operationID, owner string) error
+	// ListClaimable returns IDs of not finished operations of the given type which can be acquired by the owner
+	ListClaimable(operationType internal.OperationType, owner string, now time.Time) ([]string, error)
+	// AcquireJob claims a periodic job, which must run on one instance only, in the same way as an operation
+	AcquireJob(name, owner string, now time.Time, duration time.Duration) (bool, error)
+}
+
+// QuotaReservations keeps the Kyma instances quota reserved for instances, so parallel requests cannot over-commit the entitlement.
+// The used quota of the plan in the subaccount is the number of distinct instances which have a reservation of the plan or exist with the plan.
+type QuotaReservations interface {
+	// Reserve stores the reservation if the used quota is lower than the assigned quota. Reserving the plan again for the same instance
+	// succeeds and does not change the existing reservation.
+	Reserve(reservation internal.QuotaReservation, assignedQuota int) (bool, error)
+	// Confirm marks the reservation made by the operation as confirmed and releases other reservations of the instance
+	Confirm(operationID string) error
+	// Release removes the reservation made by the operation
+	Release(operationID string) error
+	// ReleasePlan removes the reservation of the plan for the instance
+	ReleasePlan(instanceID, planID string) error
+	ReleaseByInstanceID(instanceID string) error
+	CountUsed(subAccountID, planID string) (int, error)
+	List() ([]internal.QuotaReservation, error)
+}
+
+// EventsRetention removes events which are no longer needed, so the events table does not grow without bound
+type EventsRetention interface {
+	// ListDeprovisionedInstanceIDs returns IDs of instances which no longer exist and have no events created after the given time
+	ListDeprovisionedInstanceIDs(lastEventBefore time.Time, limit int) ([]string, error)
+	CountByInstanceIDs(instanceIDs []string) (int, error)
+	// ArchiveByInstanceIDs moves events of the given instances to the archive and returns the number of moved events
+	ArchiveByInstanceIDs(instanceIDs []string) (int, error)
+	DeleteByInstanceIDs(instanceIDs []string) (int, error)
+	// CountExpired returns the number of events created before the given time which are not related to deprovisioned instances
+	CountExpired(createdBefore time.Time) (int, error)
+	// DeleteExpired deletes at most limit events counted by CountExpired and returns the number of deleted events
+	DeleteExpired(createdBefore time.Time, limit int) (int, error)
+}
+
+// ArchivedEvents reads events of instances together with the events moved to the archive by the events retention
+type ArchivedEvents interface {
+	// ListWithArchivedByInstanceID returns events of the instance, including the archived ones, ordered by creation time
+	ListWithArchivedByInstanceID(instanceID string) ([]events.EventDTO, error)
+}
+
 type TimeZones interface {
 	GetTimeZone() (string, error)
 }
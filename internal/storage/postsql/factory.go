@@ -50,6 +50,8 @@ type ReadSession interface {
 	ListEvents(filter events.EventFilter) ([]events.EventDTO, error)
 	GetDistinctSubAccounts() ([]string, dberr.Error)
 	ListSubaccountStates() ([]dbmodel.SubaccountStateDTO, dberr.Error)
+	GetSubaccountSyncCursor(id string) (dbmodel.SubaccountSyncCursorDTO, dberr.Error)
+	ListSubaccountSyncEventIDs(fromActionTime int64) ([]int64, dberr.Error)
 	GetInstanceArchivedByID(id string) (dbmodel.InstanceArchivedDTO, error)
 	GetOperationsStatsV2() ([]dbmodel.OperationStatEntryV2, error)
 	ListDeletedInstanceIDs(amount int) ([]string, error)
@@ -64,6 +66,14 @@ type ReadSession interface {
 	ListExpiredBindings() ([]dbmodel.BindingDTO, error)
 	GetBindingsStatistics() (dbmodel.BindingStatsDTO, error)
 	ListActions(instanceID string) ([]runtime.Action, error)
+	ListWebhookDeliveriesByOperationID(operationID string) ([]dbmodel.WebhookDeliveryDTO, error)
+	ListClaimableOperationIDs(operationType internal.OperationType, owner string, now time.Time) ([]string, error)
+	CountUsedQuota(subAccountID, planID string) (int, dberr.Error)
+	ListQuotaReservations() ([]dbmodel.QuotaReservationDTO, dberr.Error)
+	ListDeprovisionedEventInstanceIDs(lastEventBefore time.Time, limit int) ([]string, error)
+	CountEventsByInstanceIDs(instanceIDs []string) (int, error)
+	CountExpiredEvents(createdBefore time.Time) (int, error)
+	ListEventsWithArchivedByInstanceID(instanceID string) ([]events.EventDTO, error)
 	GetTimeZone() (string, dberr.Error)
 }
 
@@ -78,6 +88,9 @@ type WriteSession interface {
 	DeleteEvents(until time.Time) dberr.Error
 	UpsertSubaccountState(state dbmodel.SubaccountStateDTO) dberr.Error
 	DeleteState(id string) dberr.Error
+	UpsertSubaccountSyncCursor(cursor dbmodel.SubaccountSyncCursorDTO) dberr.Error
+	InsertSubaccountSyncEvent(event dbmodel.SubaccountSyncEventDTO) dberr.Error
+	DeleteSubaccountSyncEventsBefore(actionTime int64) dberr.Error
 	DeleteOperationByID(operationID string) dberr.Error
 	InsertInstanceArchived(instance dbmodel.InstanceArchivedDTO) dberr.Error
 	InsertBinding(binding dbmodel.BindingDTO) dberr.Error
@@ -85,6 +98,24 @@ type WriteSession interface {
 	DeleteBinding(instanceID, bindingID string) dberr.Error
 	UpdateInstanceLastOperation(instanceID, operationID string) error
 	InsertAction(actionType runtime.ActionType, instanceID, message, oldValue, newValue string) dberr.Error
+	InsertWebhookDelivery(delivery dbmodel.WebhookDeliveryDTO) dberr.Error
+	UpdateWebhookDelivery(delivery dbmodel.WebhookDeliveryDTO) dberr.Error
+	ClaimPendingWebhookDeliveries(owner string, now, claimedUntil time.Time, limit int) ([]dbmodel.WebhookDeliveryDTO, dberr.Error)
+	AcquireOperationLease(operationID, owner string, now, expiresAt time.Time) (bool, dberr.Error)
+	RenewOperationLeases(owner string, operationIDs []string, now, expiresAt time.Time) ([]string, dberr.Error)
+	ReleaseOperationLease(operationID, owner string) dberr.Error
+	AcquireJobLease(name, owner string, now, expiresAt time.Time) (bool, dberr.Error)
+	ArchiveEventsByInstanceIDs(instanceIDs []string, archivedAt time.Time) (int, dberr.Error)
+	DeleteEventsByInstanceIDs(instanceIDs []string) (int, dberr.Error)
+	DeleteExpiredEvents(createdBefore time.Time, limit int) (int, dberr.Error)
+	LockQuotaReservations(subAccountID, planID string) dberr.Error
+	CountUsedQuota(subAccountID, planID string) (int, dberr.Error)
+	QuotaReservationExists(instanceID, planID string) (bool, dberr.Error)
+	InsertQuotaReservation(reservation dbmodel.QuotaReservationDTO) dberr.Error
+	ConfirmQuotaReservation(operationID string, updatedAt time.Time) dberr.Error
+	DeleteQuotaReservationsByOperationID(operationID string) dberr.Error
+	DeleteQuotaReservation(instanceID, planID string) dberr.Error
+	DeleteQuotaReservationsByInstanceID(instanceID string) dberr.Error
 }
 
 type Transaction interface {
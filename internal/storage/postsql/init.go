@@ -20,6 +20,14 @@ const (
 	InstancesArchivedTableName = "instances_archived"
 	BindingsTableName          = "bindings"
 	ActionsTableName           = "actions"
+	WebhookDeliveriesTableName = "webhook_deliveries"
+	EventsTableName            = "events"
+	EventsArchivedTableName    = "events_archived"
+	QuotaReservationsTableName = "quota_reservations"
+	JobLeasesTableName         = "job_leases"
+
+	SubaccountSyncCursorTableName          = "subaccount_sync_cursor"
+	SubaccountSyncProcessedEventsTableName = "subaccount_sync_processed_events"
 )
 
 // InitializeDatabase opens database connection and initializes schema if it does not exist
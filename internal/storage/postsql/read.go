@@ -101,6 +101,37 @@ func (r readSession) ListSubaccountStates() ([]dbmodel.SubaccountStateDTO, dberr
 	return states, nil
 }
 
+func (r readSession) GetSubaccountSyncCursor(id string) (dbmodel.SubaccountSyncCursorDTO, dberr.Error) {
+	var cursor dbmodel.SubaccountSyncCursorDTO
+
+	err := r.session.
+		Select("*").
+		From(SubaccountSyncCursorTableName).
+		Where(dbr.Eq("id", id)).
+		LoadOne(&cursor)
+	if err != nil {
+		if errors.Is(err, dbr.ErrNotFound) {
+			return dbmodel.SubaccountSyncCursorDTO{}, dberr.NotFound("Cannot find the subaccount sync cursor %s", id)
+		}
+		return dbmodel.SubaccountSyncCursorDTO{}, dberr.Internal("Failed to get subaccount sync cursor: %s", err)
+	}
+	return cursor, nil
+}
+
+func (r readSession) ListSubaccountSyncEventIDs(fromActionTime int64) ([]int64, dberr.Error) {
+	var ids []int64
+
+	_, err := r.session.
+		Select("event_id").
+		From(SubaccountSyncProcessedEventsTableName).
+		Where(dbr.Gte("action_time", fromActionTime)).
+		Load(&ids)
+	if err != nil {
+		return nil, dberr.Internal("Failed to get processed subaccount sync events: %s", err)
+	}
+	return ids, nil
+}
+
 func (r readSession) GetDistinctSubAccounts() ([]string, dberr.Error) {
 	var subAccounts []string
 
@@ -740,11 +771,47 @@ func (r readSession) ListEvents(filter events.EventFilter) ([]events.EventDTO, e
 	if len(filter.OperationIDs) != 0 {
 		stmt.Where(dbr.Eq("operation_id", filter.OperationIDs))
 	}
+	if len(filter.GlobalAccountIDs) != 0 {
+		stmt.Where(fmt.Sprintf("instance_id IN (SELECT instance_id FROM %s WHERE global_account_id IN ? UNION SELECT instance_id FROM %s WHERE global_account_id IN ?)",
+			InstancesTableName, InstancesArchivedTableName), filter.GlobalAccountIDs, filter.GlobalAccountIDs)
+	}
+	if len(filter.SubAccountIDs) != 0 {
+		stmt.Where(fmt.Sprintf("instance_id IN (SELECT instance_id FROM %s WHERE sub_account_id IN ? UNION SELECT instance_id FROM %s WHERE subaccount_id IN ?)",
+			InstancesTableName, InstancesArchivedTableName), filter.SubAccountIDs, filter.SubAccountIDs)
+	}
+	if len(filter.Regions) != 0 {
+		stmt.Where(fmt.Sprintf("instance_id IN (SELECT instance_id FROM %s WHERE provider_region IN ? UNION SELECT instance_id FROM %s WHERE region IN ?)",
+			InstancesTableName, InstancesArchivedTableName), filter.Regions, filter.Regions)
+	}
+	if len(filter.Levels) != 0 {
+		stmt.Where(dbr.Eq("level", filter.Levels))
+	}
+	if !filter.From.IsZero() {
+		stmt.Where(dbr.Gte("created_at", filter.From))
+	}
+	if !filter.To.IsZero() {
+		stmt.Where(dbr.Lte("created_at", filter.To))
+	}
+	if filter.Message != "" {
+		stmt.Where("message ILIKE ?", "%"+escapeLike(filter.Message)+"%")
+	}
+	if filter.After != nil {
+		stmt.Where("(created_at, id) > (?, ?)", filter.After.CreatedAt, filter.After.ID)
+	}
 	stmt.OrderBy("created_at")
+	stmt.OrderBy("id")
+	if filter.PageSize > 0 {
+		stmt.Limit(uint64(filter.PageSize))
+	}
 	_, err := stmt.Load(&events)
 	return events, err
 }
 
+// escapeLike escapes characters with a special meaning in LIKE patterns
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
 func (r readSession) getInstanceCountByLastOperationID(filter dbmodel.InstanceFilter) (int, error) {
 	var res struct {
 		Total int
@@ -1034,6 +1101,128 @@ func (r readSession) ListActions(instanceID string) ([]runtime.Action, error) {
 	return actions, err
 }
 
+func (r readSession) ListWebhookDeliveriesByOperationID(operationID string) ([]dbmodel.WebhookDeliveryDTO, error) {
+	var deliveries []dbmodel.WebhookDeliveryDTO
+	stmt := r.session.Select("*").From(WebhookDeliveriesTableName)
+	stmt.Where(dbr.Eq("operation_id", operationID))
+	stmt.OrderAsc("created_at")
+	_, err := stmt.Load(&deliveries)
+	if err != nil {
+		return nil, fmt.Errorf("while getting webhook deliveries for operation %s: %w", operationID, err)
+	}
+	return deliveries, nil
+}
+
+// ListClaimableOperationIDs returns IDs of not finished operations which are not leased, their lease expired or the lease is held by the owner
+func (r readSession) ListClaimableOperationIDs(operationType internal.OperationType, owner string, now time.Time) ([]string, error) {
+	var ids []string
+	stmt := r.session.Select("id").From(OperationTableName)
+	stmt.Where(dbr.Eq("type", operationType))
+	stmt.Where(dbr.Or(dbr.Eq("state", internal.OperationStatePending), dbr.Eq("state", domain.InProgress)))
+	stmt.Where(dbr.Or(dbr.Eq("lease_owner", nil), dbr.Eq("lease_owner", owner), dbr.Lt("lease_expires_at", now)))
+	stmt.OrderAsc("created_at")
+	_, err := stmt.Load(&ids)
+	if err != nil {
+		return nil, fmt.Errorf("while getting claimable %s operations: %w", operationType, err)
+	}
+	return ids, nil
+}
+
+// ListDeprovisionedEventInstanceIDs returns IDs of instances which no longer exist and have no events created after the given time
+func (r readSession) ListDeprovisionedEventInstanceIDs(lastEventBefore time.Time, limit int) ([]string, error) {
+	var ids []string
+	_, err := r.session.Select("instance_id").
+		From(EventsTableName).
+		Where("instance_id IS NOT NULL AND instance_id <> ''").
+		Where(fmt.Sprintf("instance_id NOT IN (SELECT instance_id FROM %s)", InstancesTableName)).
+		GroupBy("instance_id").
+		Having("max(created_at) < ?", lastEventBefore).
+		OrderBy("instance_id").
+		Limit(uint64(limit)).
+		Load(&ids)
+	if err != nil {
+		return nil, fmt.Errorf("while getting instance IDs of events of deprovisioned instances: %w", err)
+	}
+	return ids, nil
+}
+
+func (r readSession) CountEventsByInstanceIDs(instanceIDs []string) (int, error) {
+	if len(instanceIDs) == 0 {
+		return 0, nil
+	}
+	var res struct {
+		Total int
+	}
+	err := r.session.Select("count(*) as total").
+		From(EventsTableName).
+		Where(dbr.Eq("instance_id", instanceIDs)).
+		LoadOne(&res)
+	if err != nil {
+		return 0, fmt.Errorf("while counting events of instances: %w", err)
+	}
+	return res.Total, nil
+}
+
+// CountExpiredEvents returns the number of events created before the given time which are not related to deprovisioned instances
+func (r readSession) CountExpiredEvents(createdBefore time.Time) (int, error) {
+	var res struct {
+		Total int
+	}
+	err := r.session.Select("count(*) as total").
+		From(EventsTableName).
+		Where(dbr.Lt("created_at", createdBefore)).
+		Where(notDeprovisionedEventCondition).
+		LoadOne(&res)
+	if err != nil {
+		return 0, fmt.Errorf("while counting expired events: %w", err)
+	}
+	return res.Total, nil
+}
+
+// ListEventsWithArchivedByInstanceID returns events of the instance from both the events table and the archive,
+// an event which is being archived at the moment is returned once
+func (r readSession) ListEventsWithArchivedByInstanceID(instanceID string) ([]events.EventDTO, error) {
+	var result []events.EventDTO
+	_, err := r.session.SelectBySql(fmt.Sprintf(`SELECT id, level, instance_id, operation_id, message, created_at FROM %s WHERE instance_id = ?
+		UNION SELECT id, level, instance_id, operation_id, message, created_at FROM %s WHERE instance_id = ?
+		ORDER BY created_at, id`, EventsTableName, EventsArchivedTableName), instanceID, instanceID).
+		Load(&result)
+	if err != nil {
+		return nil, fmt.Errorf("while getting events of instance %s: %w", instanceID, err)
+	}
+	return result, nil
+}
+
+// notDeprovisionedEventCondition matches events without an instance and events of existing instances
+var notDeprovisionedEventCondition = fmt.Sprintf("(instance_id IS NULL OR instance_id = '' OR instance_id IN (SELECT instance_id FROM %s))", InstancesTableName)
+
+func (r readSession) CountUsedQuota(subAccountID, planID string) (int, dberr.Error) {
+	return countUsedQuota(r.session, subAccountID, planID)
+}
+
+func (r readSession) ListQuotaReservations() ([]dbmodel.QuotaReservationDTO, dberr.Error) {
+	var reservations []dbmodel.QuotaReservationDTO
+	_, err := r.session.Select("*").From(QuotaReservationsTableName).OrderAsc("created_at").Load(&reservations)
+	if err != nil {
+		return nil, dberr.Internal("Failed to get quota reservations: %s", err)
+	}
+	return reservations, nil
+}
+
+// countUsedQuota returns the number of distinct instances which have a reservation of the plan in the subaccount or exist with the plan
+func countUsedQuota(runner dbr.SessionRunner, subAccountID, planID string) (int, dberr.Error) {
+	var count int
+	err := runner.SelectBySql(fmt.Sprintf(`SELECT count(*) FROM (
+		SELECT instance_id FROM %s WHERE sub_account_id = ? AND plan_id = ?
+		UNION
+		SELECT instance_id FROM %s WHERE sub_account_id = ? AND service_plan_id = ?
+	) AS used`, QuotaReservationsTableName, InstancesTableName), subAccountID, planID, subAccountID, planID).LoadOne(&count)
+	if err != nil {
+		return 0, dberr.Internal("Failed to count used quota of plan %s in subaccount %s: %s", planID, subAccountID, err)
+	}
+	return count, nil
+}
+
 func addInstanceArchivedFilter(stmt *dbr.SelectStmt, filter dbmodel.InstanceFilter) {
 	if len(filter.InstanceIDs) > 0 {
 		stmt.Where("instance_id IN ?", filter.InstanceIDs)
@@ -1,16 +1,19 @@
 package postsql
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/kyma-project/kyma-environment-broker/common/events"
 	"github.com/kyma-project/kyma-environment-broker/common/runtime"
+	"github.com/kyma-project/kyma-environment-broker/internal"
 	"github.com/kyma-project/kyma-environment-broker/internal/storage/dberr"
 	"github.com/kyma-project/kyma-environment-broker/internal/storage/dbmodel"
 
 	"github.com/gocraft/dbr"
 	"github.com/google/uuid"
 	"github.com/lib/pq"
+	"github.com/pivotal-cf/brokerapi/v12/domain"
 )
 
 const (
@@ -55,6 +58,9 @@ func (ws writeSession) InsertBinding(binding dbmodel.BindingDTO) dberr.Error {
 		Pair("kubeconfig", binding.Kubeconfig).
 		Pair("expiration_seconds", binding.ExpirationSeconds).
 		Pair("created_by", binding.CreatedBy).
+		Pair("scope", binding.Scope).
+		Pair("state", binding.State).
+		Pair("description", binding.Description).
 		Exec()
 
 	if err != nil {
@@ -73,6 +79,8 @@ func (ws writeSession) UpdateBinding(binding dbmodel.BindingDTO) dberr.Error {
 	_, err := ws.update(BindingsTableName).
 		Set("kubeconfig", binding.Kubeconfig).
 		Set("expires_at", binding.ExpiresAt).
+		Set("state", binding.State).
+		Set("description", binding.Description).
 		Where(dbr.Eq("id", binding.ID)).
 		Where(dbr.Eq("instance_id", binding.InstanceID)).
 		Exec()
@@ -138,6 +146,7 @@ func (ws writeSession) InsertInstance(instance dbmodel.InstanceDTO) dberr.Error
 		Pair("expired_at", instance.ExpiredAt).
 		Pair("version", instance.Version).
 		Pair("empty_updates", instance.EmptyUpdates).
+		Pair("expiration_extensions", instance.ExpirationExtensions).
 		Exec()
 
 	if err != nil {
@@ -184,6 +193,7 @@ func (ws writeSession) UpdateInstance(instance dbmodel.InstanceDTO) dberr.Error
 		Set("version", instance.Version+1).
 		Set("expired_at", instance.ExpiredAt).
 		Set("empty_updates", instance.EmptyUpdates).
+		Set("expiration_extensions", instance.ExpirationExtensions).
 		Exec()
 	if err != nil {
 		return dberr.Internal("Failed to update record to Instance table: %s", err)
@@ -234,6 +244,7 @@ func (ws writeSession) UpsertSubaccountState(state dbmodel.SubaccountStateDTO) d
 		Set("beta_enabled", state.BetaEnabled).
 		Set("used_for_production", state.UsedForProduction).
 		Set("modified_at", state.ModifiedAt).
+		Set("attributes", state.Attributes).
 		Exec()
 	if err != nil {
 		return dberr.Internal("Failed to update record to subaccount_states table: %s", err)
@@ -248,6 +259,7 @@ func (ws writeSession) UpsertSubaccountState(state dbmodel.SubaccountStateDTO) d
 			Pair("beta_enabled", state.BetaEnabled).
 			Pair("used_for_production", state.UsedForProduction).
 			Pair("modified_at", state.ModifiedAt).
+			Pair("attributes", state.Attributes).
 			Exec()
 		if err != nil {
 			return dberr.Internal("Failed to upsert record to subaccount_states table: %s", err)
@@ -266,6 +278,41 @@ func (ws writeSession) DeleteState(subaccountID string) dberr.Error {
 	return nil
 }
 
+func (ws writeSession) UpsertSubaccountSyncCursor(cursor dbmodel.SubaccountSyncCursorDTO) dberr.Error {
+	_, err := ws.insertBySql(fmt.Sprintf(`INSERT INTO %s (id, last_event_id, last_event_action_time, events_synced_at, accounts_synced_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET last_event_id = EXCLUDED.last_event_id, last_event_action_time = EXCLUDED.last_event_action_time,
+		events_synced_at = EXCLUDED.events_synced_at, accounts_synced_at = EXCLUDED.accounts_synced_at`, SubaccountSyncCursorTableName),
+		cursor.ID, cursor.LastEventID, cursor.LastEventActionTime, cursor.EventsSyncedAt, cursor.AccountsSyncedAt).
+		Exec()
+	if err != nil {
+		return dberr.Internal("Failed to upsert record to %s table: %s", SubaccountSyncCursorTableName, err)
+	}
+	return nil
+}
+
+func (ws writeSession) InsertSubaccountSyncEvent(event dbmodel.SubaccountSyncEventDTO) dberr.Error {
+	_, err := ws.insertBySql(fmt.Sprintf(`INSERT INTO %s (event_id, sub_account_id, action_time, processed_at)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT (event_id) DO NOTHING`, SubaccountSyncProcessedEventsTableName),
+		event.EventID, event.SubAccountID, event.ActionTime, event.ProcessedAt).
+		Exec()
+	if err != nil {
+		return dberr.Internal("Failed to insert record to %s table: %s", SubaccountSyncProcessedEventsTableName, err)
+	}
+	return nil
+}
+
+func (ws writeSession) DeleteSubaccountSyncEventsBefore(actionTime int64) dberr.Error {
+	_, err := ws.deleteFrom(SubaccountSyncProcessedEventsTableName).
+		Where(dbr.Lt("action_time", actionTime)).
+		Exec()
+	if err != nil {
+		return dberr.Internal("failed to delete processed subaccount sync events: %v", err)
+	}
+	return nil
+}
+
 func (ws writeSession) UpdateEncryptedDataInOperation(op dbmodel.OperationDTO) dberr.Error {
 	res, err := ws.update(OperationTableName).
 		Where(dbr.Eq("id", op.ID)).
@@ -428,6 +475,319 @@ func (ws writeSession) InsertAction(actionType runtime.ActionType, instanceID, m
 	return nil
 }
 
+func (ws writeSession) InsertWebhookDelivery(delivery dbmodel.WebhookDeliveryDTO) dberr.Error {
+	_, err := ws.insertInto(WebhookDeliveriesTableName).
+		Pair("id", delivery.ID).
+		Pair("webhook", delivery.Webhook).
+		Pair("event", delivery.Event).
+		Pair("instance_id", delivery.InstanceID).
+		Pair("operation_id", delivery.OperationID).
+		Pair("payload", delivery.Payload).
+		Pair("state", delivery.State).
+		Pair("attempts", delivery.Attempts).
+		Pair("last_error", delivery.LastError).
+		Pair("next_attempt_at", delivery.NextAttemptAt).
+		Pair("created_at", delivery.CreatedAt).
+		Pair("updated_at", delivery.UpdatedAt).
+		Exec()
+	if err != nil {
+		if err, ok := err.(*pq.Error); ok {
+			if err.Code == UniqueViolationErrorCode {
+				return dberr.AlreadyExists("webhook delivery with id %s already exists", delivery.ID)
+			}
+		}
+		return dberr.Internal("failed to insert webhook delivery: %s", err)
+	}
+	return nil
+}
+
+// UpdateWebhookDelivery stores the result of the delivery only if it is still claimed by the owner which sent it
+func (ws writeSession) UpdateWebhookDelivery(delivery dbmodel.WebhookDeliveryDTO) dberr.Error {
+	if delivery.ClaimedBy == nil {
+		return dberr.Conflict("webhook delivery %s is not claimed", delivery.ID)
+	}
+	res, err := ws.update(WebhookDeliveriesTableName).
+		Set("state", delivery.State).
+		Set("attempts", delivery.Attempts).
+		Set("last_error", delivery.LastError).
+		Set("next_attempt_at", delivery.NextAttemptAt).
+		Set("claimed_by", nil).
+		Set("claimed_until", nil).
+		Set("updated_at", delivery.UpdatedAt).
+		Where(dbr.Eq("id", delivery.ID)).
+		Where(dbr.Eq("claimed_by", *delivery.ClaimedBy)).
+		Exec()
+	if err != nil {
+		return dberr.Internal("failed to update webhook delivery %s: %s", delivery.ID, err)
+	}
+	rAffected, err := res.RowsAffected()
+	if err != nil {
+		return dberr.Internal("failed to get number of updated webhook deliveries: %s", err)
+	}
+	if rAffected == int64(0) {
+		return dberr.Conflict("webhook delivery %s does not exist or is claimed by another owner than %s", delivery.ID, *delivery.ClaimedBy)
+	}
+	return nil
+}
+
+// ClaimPendingWebhookDeliveries claims the pending deliveries which are due and not claimed, rows locked by a concurrent claim are skipped
+func (ws writeSession) ClaimPendingWebhookDeliveries(owner string, now, claimedUntil time.Time, limit int) ([]dbmodel.WebhookDeliveryDTO, dberr.Error) {
+	var deliveries []dbmodel.WebhookDeliveryDTO
+	err := ws.update(WebhookDeliveriesTableName).
+		Set("claimed_by", owner).
+		Set("claimed_until", claimedUntil).
+		Where(fmt.Sprintf(`id IN (SELECT id FROM %s WHERE state = ? AND next_attempt_at <= ? AND (claimed_until IS NULL OR claimed_until < ?)
+			ORDER BY next_attempt_at LIMIT ? FOR UPDATE SKIP LOCKED)`, WebhookDeliveriesTableName), internal.WebhookDeliveryPending, now, now, limit).
+		Returning("id", "webhook", "event", "instance_id", "operation_id", "payload", "state", "attempts", "last_error",
+			"next_attempt_at", "claimed_by", "claimed_until", "created_at", "updated_at").
+		Load(&deliveries)
+	if err != nil {
+		return nil, dberr.Internal("failed to claim pending webhook deliveries: %s", err)
+	}
+	return deliveries, nil
+}
+
+// AcquireOperationLease sets the lease of the operation if the operation is not leased, the lease expired or it is already held by the owner
+func (ws writeSession) AcquireOperationLease(operationID, owner string, now, expiresAt time.Time) (bool, dberr.Error) {
+	res, err := ws.update(OperationTableName).
+		Set("lease_owner", owner).
+		Set("lease_heartbeat_at", now).
+		Set("lease_expires_at", expiresAt).
+		Where(dbr.Eq("id", operationID)).
+		Where(dbr.Or(dbr.Eq("lease_owner", nil), dbr.Eq("lease_owner", owner), dbr.Lt("lease_expires_at", now))).
+		Exec()
+	if err != nil {
+		return false, dberr.Internal("failed to acquire the lease of operation %s: %s", operationID, err)
+	}
+	rAffected, err := res.RowsAffected()
+	if err != nil {
+		return false, dberr.Internal("failed to get number of leased operations: %s", err)
+	}
+	return rAffected == int64(1), nil
+}
+
+// RenewOperationLeases extends the leases of not finished operations held by the owner and returns IDs of the renewed operations
+func (ws writeSession) RenewOperationLeases(owner string, operationIDs []string, now, expiresAt time.Time) ([]string, dberr.Error) {
+	var renewed []string
+	if len(operationIDs) == 0 {
+		return renewed, nil
+	}
+	err := ws.update(OperationTableName).
+		Set("lease_heartbeat_at", now).
+		Set("lease_expires_at", expiresAt).
+		Where(dbr.Eq("lease_owner", owner)).
+		Where(dbr.Eq("id", operationIDs)).
+		Where(dbr.Or(dbr.Eq("state", internal.OperationStatePending), dbr.Eq("state", domain.InProgress))).
+		Returning("id").
+		Load(&renewed)
+	if err != nil {
+		return nil, dberr.Internal("failed to renew operation leases of %s: %s", owner, err)
+	}
+	return renewed, nil
+}
+
+func (ws writeSession) ReleaseOperationLease(operationID, owner string) dberr.Error {
+	_, err := ws.update(OperationTableName).
+		Set("lease_owner", nil).
+		Set("lease_heartbeat_at", nil).
+		Set("lease_expires_at", nil).
+		Where(dbr.Eq("id", operationID)).
+		Where(dbr.Eq("lease_owner", owner)).
+		Exec()
+	if err != nil {
+		return dberr.Internal("failed to release the lease of operation %s: %s", operationID, err)
+	}
+	return nil
+}
+
+// AcquireJobLease sets the lease of the job if the job is not leased, the lease expired or it is already held by the owner
+func (ws writeSession) AcquireJobLease(name, owner string, now, expiresAt time.Time) (bool, dberr.Error) {
+	res, err := ws.insertBySql(fmt.Sprintf(`INSERT INTO %[1]s (name, owner, expires_at)
+		VALUES (?, ?, ?)
+		ON CONFLICT (name) DO UPDATE SET owner = EXCLUDED.owner, expires_at = EXCLUDED.expires_at
+		WHERE %[1]s.owner = EXCLUDED.owner OR %[1]s.expires_at < ?`, JobLeasesTableName),
+		name, owner, expiresAt, now).
+		Exec()
+	if err != nil {
+		return false, dberr.Internal("failed to acquire the lease of job %s: %s", name, err)
+	}
+	rAffected, err := res.RowsAffected()
+	if err != nil {
+		return false, dberr.Internal("failed to get number of leased jobs: %s", err)
+	}
+	return rAffected == int64(1), nil
+}
+
+// ArchiveEventsByInstanceIDs copies events of the given instances to the archive, events which are already archived are skipped
+func (ws writeSession) ArchiveEventsByInstanceIDs(instanceIDs []string, archivedAt time.Time) (int, dberr.Error) {
+	if len(instanceIDs) == 0 {
+		return 0, nil
+	}
+	res, err := ws.insertBySql(fmt.Sprintf(`INSERT INTO %s (id, level, instance_id, operation_id, message, created_at, archived_at)
+		SELECT id, level, instance_id, operation_id, message, created_at, ? FROM %s WHERE instance_id IN ?
+		ON CONFLICT (id) DO NOTHING`, EventsArchivedTableName, EventsTableName), archivedAt, instanceIDs).
+		Exec()
+	if err != nil {
+		return 0, dberr.Internal("failed to archive events of instances: %s", err)
+	}
+	rAffected, err := res.RowsAffected()
+	if err != nil {
+		return 0, dberr.Internal("failed to get number of archived events: %s", err)
+	}
+	return int(rAffected), nil
+}
+
+func (ws writeSession) DeleteEventsByInstanceIDs(instanceIDs []string) (int, dberr.Error) {
+	if len(instanceIDs) == 0 {
+		return 0, nil
+	}
+	res, err := ws.deleteFrom(EventsTableName).
+		Where(dbr.Eq("instance_id", instanceIDs)).
+		Exec()
+	if err != nil {
+		return 0, dberr.Internal("failed to delete events of instances: %s", err)
+	}
+	rAffected, err := res.RowsAffected()
+	if err != nil {
+		return 0, dberr.Internal("failed to get number of deleted events: %s", err)
+	}
+	return int(rAffected), nil
+}
+
+// DeleteExpiredEvents deletes at most limit events created before the given time which are not related to deprovisioned instances
+func (ws writeSession) DeleteExpiredEvents(createdBefore time.Time, limit int) (int, dberr.Error) {
+	res, err := ws.deleteFrom(EventsTableName).
+		Where(fmt.Sprintf("id IN (SELECT id FROM %s WHERE created_at < ? AND %s LIMIT ?)", EventsTableName, notDeprovisionedEventCondition), createdBefore, limit).
+		Exec()
+	if err != nil {
+		return 0, dberr.Internal("failed to delete events created before %v: %s", createdBefore.Format(time.RFC1123Z), err)
+	}
+	rAffected, err := res.RowsAffected()
+	if err != nil {
+		return 0, dberr.Internal("failed to get number of deleted events: %s", err)
+	}
+	return int(rAffected), nil
+}
+
+// LockQuotaReservations locks the reservations of the plan in the subaccount until the transaction ends,
+// so the used quota does not change between counting it and inserting a new reservation
+func (ws writeSession) LockQuotaReservations(subAccountID, planID string) dberr.Error {
+	if ws.transaction == nil {
+		return dberr.Internal("quota reservations can be locked only within a transaction")
+	}
+	_, err := ws.transaction.Exec("SELECT pg_advisory_xact_lock(hashtext($1))", fmt.Sprintf("%s/%s/%s", QuotaReservationsTableName, subAccountID, planID))
+	if err != nil {
+		return dberr.Internal("failed to lock quota reservations of plan %s in subaccount %s: %s", planID, subAccountID, err)
+	}
+	return nil
+}
+
+func (ws writeSession) CountUsedQuota(subAccountID, planID string) (int, dberr.Error) {
+	if ws.transaction != nil {
+		return countUsedQuota(ws.transaction, subAccountID, planID)
+	}
+	return countUsedQuota(ws.session, subAccountID, planID)
+}
+
+func (ws writeSession) QuotaReservationExists(instanceID, planID string) (bool, dberr.Error) {
+	var runner dbr.SessionRunner = ws.session
+	if ws.transaction != nil {
+		runner = ws.transaction
+	}
+	var count int
+	err := runner.Select("count(*)").From(QuotaReservationsTableName).
+		Where(dbr.Eq("instance_id", instanceID)).
+		Where(dbr.Eq("plan_id", planID)).
+		LoadOne(&count)
+	if err != nil {
+		return false, dberr.Internal("failed to get quota reservation of plan %s for instance %s: %s", planID, instanceID, err)
+	}
+	return count > 0, nil
+}
+
+func (ws writeSession) InsertQuotaReservation(reservation dbmodel.QuotaReservationDTO) dberr.Error {
+	_, err := ws.insertInto(QuotaReservationsTableName).
+		Pair("instance_id", reservation.InstanceID).
+		Pair("sub_account_id", reservation.SubAccountID).
+		Pair("plan_id", reservation.PlanID).
+		Pair("operation_id", reservation.OperationID).
+		Pair("state", reservation.State).
+		Pair("created_at", reservation.CreatedAt).
+		Pair("updated_at", reservation.UpdatedAt).
+		Exec()
+	if err != nil {
+		if err, ok := err.(*pq.Error); ok {
+			if err.Code == UniqueViolationErrorCode {
+				return dberr.AlreadyExists("quota reservation of plan %s for instance %s already exists", reservation.PlanID, reservation.InstanceID)
+			}
+		}
+		return dberr.Internal("failed to insert quota reservation: %s", err)
+	}
+	return nil
+}
+
+// ConfirmQuotaReservation confirms the reservation made by the operation and removes other reservations of the same instance
+func (ws writeSession) ConfirmQuotaReservation(operationID string, updatedAt time.Time) dberr.Error {
+	if operationID == "" {
+		return nil
+	}
+	var instanceIDs []string
+	err := ws.update(QuotaReservationsTableName).
+		Set("state", internal.QuotaReservationConfirmed).
+		Set("updated_at", updatedAt).
+		Where(dbr.Eq("operation_id", operationID)).
+		Returning("instance_id").
+		Load(&instanceIDs)
+	if err != nil {
+		return dberr.Internal("failed to confirm quota reservation of operation %s: %s", operationID, err)
+	}
+	if len(instanceIDs) == 0 {
+		return nil
+	}
+	_, err = ws.deleteFrom(QuotaReservationsTableName).
+		Where(dbr.Eq("instance_id", instanceIDs)).
+		Where(dbr.Neq("operation_id", operationID)).
+		Exec()
+	if err != nil {
+		return dberr.Internal("failed to delete previous quota reservations of instance %s: %s", instanceIDs[0], err)
+	}
+	return nil
+}
+
+func (ws writeSession) DeleteQuotaReservationsByOperationID(operationID string) dberr.Error {
+	if operationID == "" {
+		return nil
+	}
+	_, err := ws.deleteFrom(QuotaReservationsTableName).
+		Where(dbr.Eq("operation_id", operationID)).
+		Exec()
+	if err != nil {
+		return dberr.Internal("failed to delete quota reservation of operation %s: %s", operationID, err)
+	}
+	return nil
+}
+
+func (ws writeSession) DeleteQuotaReservation(instanceID, planID string) dberr.Error {
+	_, err := ws.deleteFrom(QuotaReservationsTableName).
+		Where(dbr.Eq("instance_id", instanceID)).
+		Where(dbr.Eq("plan_id", planID)).
+		Exec()
+	if err != nil {
+		return dberr.Internal("failed to delete quota reservation of plan %s for instance %s: %s", planID, instanceID, err)
+	}
+	return nil
+}
+
+func (ws writeSession) DeleteQuotaReservationsByInstanceID(instanceID string) dberr.Error {
+	_, err := ws.deleteFrom(QuotaReservationsTableName).
+		Where(dbr.Eq("instance_id", instanceID)).
+		Exec()
+	if err != nil {
+		return dberr.Internal("failed to delete quota reservations of instance %s: %s", instanceID, err)
+	}
+	return nil
+}
+
 func (ws writeSession) Commit() dberr.Error {
 	err := ws.transaction.Commit()
 	if err != nil {
@@ -449,6 +809,14 @@ func (ws writeSession) insertInto(table string) *dbr.InsertStmt {
 	return ws.session.InsertInto(table)
 }
 
+func (ws writeSession) insertBySql(query string, value ...interface{}) *dbr.InsertStmt {
+	if ws.transaction != nil {
+		return ws.transaction.InsertBySql(query, value...)
+	}
+
+	return ws.session.InsertBySql(query, value...)
+}
+
 func (ws writeSession) deleteFrom(table string) *dbr.DeleteStmt {
 	if ws.transaction != nil {
 		return ws.transaction.DeleteFrom(table)
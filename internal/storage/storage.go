@@ -3,9 +3,13 @@ package storage
 import (
 	"fmt"
 	"log/slog"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/gocraft/dbr"
+	"github.com/google/uuid"
 	eventsapi "github.com/kyma-project/kyma-environment-broker/common/events"
 	"github.com/kyma-project/kyma-environment-broker/internal/events"
 	"github.com/kyma-project/kyma-environment-broker/internal/storage/driver/memory"
@@ -23,6 +27,11 @@ type BrokerStorage interface {
 	InstancesArchived() InstancesArchived
 	Bindings() Bindings
 	Actions() Actions
+	WebhookDeliveries() WebhookDeliveries
+	OperationLeases() OperationLeases
+	QuotaReservations() QuotaReservations
+	EventsRetention() EventsRetention
+	ArchivedEvents() ArchivedEvents
 	TimeZones() TimeZones
 }
 
@@ -58,6 +67,11 @@ func NewFromConfigAndConnectionURL(cfg Config, evcfg events.Config, cipher postg
 		instancesArchived: postgres.NewInstanceArchived(factory),
 		bindings:          postgres.NewBinding(factory, cipher),
 		actions:           postgres.NewAction(factory),
+		webhookDeliveries: postgres.NewWebhookDelivery(factory),
+		operationLeases:   postgres.NewOperationLease(factory),
+		quotaReservations: postgres.NewQuotaReservation(factory),
+		eventsRetention:   eventstorage.NewRetention(factory),
+		archivedEvents:    eventstorage.NewArchivedEvents(factory),
 		timezones:         postgres.NewTimeZones(factory),
 	}, connection, nil
 }
@@ -65,24 +79,35 @@ func NewFromConfigAndConnectionURL(cfg Config, evcfg events.Config, cipher postg
 func NewMemoryStorage() BrokerStorage {
 	op := memory.NewOperation()
 	ss := memory.NewSubaccountStates()
+	instances := memory.NewInstance(op, ss)
+	inMemoryEvents := newInMemoryEvents(instances)
 	return storage{
 		operation:         op,
 		subaccountStates:  ss,
-		instance:          memory.NewInstance(op, ss),
-		events:            events.New(events.Config{}, newInMemoryEvents()),
+		instance:          instances,
+		events:            events.New(events.Config{}, inMemoryEvents),
 		instancesArchived: memory.NewInstanceArchivedInMemoryStorage(),
 		bindings:          memory.NewBinding(),
 		actions:           memory.NewAction(),
+		webhookDeliveries: memory.NewWebhookDelivery(),
+		operationLeases:   memory.NewOperationLease(op),
+		quotaReservations: memory.NewQuotaReservation(instances),
+		eventsRetention:   inMemoryEvents,
+		archivedEvents:    inMemoryEvents,
 	}
 }
 
 type inMemoryEvents struct {
-	events []eventsapi.EventDTO
+	mu        sync.Mutex
+	events    []eventsapi.EventDTO
+	archived  []eventsapi.EventDTO
+	instances Instances
 }
 
-func newInMemoryEvents() *inMemoryEvents {
+func newInMemoryEvents(instances Instances) *inMemoryEvents {
 	return &inMemoryEvents{
-		events: make([]eventsapi.EventDTO, 0),
+		events:    make([]eventsapi.EventDTO, 0),
+		instances: instances,
 	}
 }
 
@@ -91,11 +116,15 @@ func (*inMemoryEvents) RunGarbageCollection(pollingPeriod, retention time.Durati
 }
 
 func (e *inMemoryEvents) InsertEvent(eventLevel eventsapi.EventLevel, message, instanceID, operationID string) {
-	e.events = append(e.events, eventsapi.EventDTO{Level: eventLevel, InstanceID: &instanceID, OperationID: &operationID, Message: message})
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.events = append(e.events, eventsapi.EventDTO{ID: uuid.NewString(), Level: eventLevel, InstanceID: &instanceID, OperationID: &operationID, Message: message, CreatedAt: time.Now()})
 	slog.Info(fmt.Sprintf("EVENT [instanceID=%v/operationID=%v] %v: %v", instanceID, operationID, eventLevel, message))
 }
 
 func (e *inMemoryEvents) ListEvents(filter eventsapi.EventFilter) ([]eventsapi.EventDTO, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
 	var events []eventsapi.EventDTO
 	for _, ev := range e.events {
 		if !requiredContains(ev.InstanceID, filter.InstanceIDs) {
@@ -104,11 +133,180 @@ func (e *inMemoryEvents) ListEvents(filter eventsapi.EventFilter) ([]eventsapi.E
 		if !requiredContains(ev.OperationID, filter.OperationIDs) {
 			continue
 		}
+		if !requiredContains(&ev.Level, filter.Levels) {
+			continue
+		}
+		if !filter.From.IsZero() && ev.CreatedAt.Before(filter.From) {
+			continue
+		}
+		if !filter.To.IsZero() && ev.CreatedAt.After(filter.To) {
+			continue
+		}
+		if filter.Message != "" && !strings.Contains(strings.ToLower(ev.Message), strings.ToLower(filter.Message)) {
+			continue
+		}
+		if filter.After != nil && !afterPosition(ev, *filter.After) {
+			continue
+		}
+		if !e.matchesInstance(ev, filter) {
+			continue
+		}
 		events = append(events, ev)
 	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return afterPosition(events[j], eventsapi.EventPosition{CreatedAt: events[i].CreatedAt, ID: events[i].ID})
+	})
+	if filter.PageSize > 0 && len(events) > filter.PageSize {
+		events = events[:filter.PageSize]
+	}
 	return events, nil
 }
 
+func (e *inMemoryEvents) matchesInstance(ev eventsapi.EventDTO, filter eventsapi.EventFilter) bool {
+	if len(filter.GlobalAccountIDs) == 0 && len(filter.SubAccountIDs) == 0 && len(filter.Regions) == 0 {
+		return true
+	}
+	if ev.InstanceID == nil {
+		return false
+	}
+	instance, err := e.instances.GetByID(*ev.InstanceID)
+	if err != nil {
+		return false
+	}
+	return requiredContains(&instance.GlobalAccountID, filter.GlobalAccountIDs) && requiredContains(&instance.SubAccountID, filter.SubAccountIDs) &&
+		requiredContains(&instance.ProviderRegion, filter.Regions)
+}
+
+func afterPosition(ev eventsapi.EventDTO, position eventsapi.EventPosition) bool {
+	if ev.CreatedAt.Equal(position.CreatedAt) {
+		return ev.ID > position.ID
+	}
+	return ev.CreatedAt.After(position.CreatedAt)
+}
+
+func (e *inMemoryEvents) ListDeprovisionedInstanceIDs(lastEventBefore time.Time, limit int) ([]string, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	lastEvents := make(map[string]time.Time)
+	for _, ev := range e.events {
+		if e.instanceExists(ev.InstanceID) || ev.InstanceID == nil || *ev.InstanceID == "" {
+			continue
+		}
+		if ev.CreatedAt.After(lastEvents[*ev.InstanceID]) {
+			lastEvents[*ev.InstanceID] = ev.CreatedAt
+		}
+	}
+	var ids []string
+	for id, lastEvent := range lastEvents {
+		if lastEvent.Before(lastEventBefore) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	if len(ids) > limit {
+		ids = ids[:limit]
+	}
+	return ids, nil
+}
+
+func (e *inMemoryEvents) CountByInstanceIDs(instanceIDs []string) (int, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	count := 0
+	for _, ev := range e.events {
+		if len(instanceIDs) > 0 && requiredContains(ev.InstanceID, instanceIDs) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (e *inMemoryEvents) ArchiveByInstanceIDs(instanceIDs []string) (int, error) {
+	return e.deleteWhere(func(ev eventsapi.EventDTO) bool {
+		return len(instanceIDs) > 0 && requiredContains(ev.InstanceID, instanceIDs)
+	}, -1, true), nil
+}
+
+func (e *inMemoryEvents) DeleteByInstanceIDs(instanceIDs []string) (int, error) {
+	return e.deleteWhere(func(ev eventsapi.EventDTO) bool {
+		return len(instanceIDs) > 0 && requiredContains(ev.InstanceID, instanceIDs)
+	}, -1, false), nil
+}
+
+func (e *inMemoryEvents) ListWithArchivedByInstanceID(instanceID string) ([]eventsapi.EventDTO, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	seen := make(map[string]struct{})
+	var events []eventsapi.EventDTO
+	for _, ev := range append(append([]eventsapi.EventDTO{}, e.events...), e.archived...) {
+		if ev.InstanceID == nil || *ev.InstanceID != instanceID {
+			continue
+		}
+		if _, found := seen[ev.ID]; found {
+			continue
+		}
+		seen[ev.ID] = struct{}{}
+		events = append(events, ev)
+	}
+	sort.SliceStable(events, func(i, j int) bool {
+		return afterPosition(events[j], eventsapi.EventPosition{CreatedAt: events[i].CreatedAt, ID: events[i].ID})
+	})
+	return events, nil
+}
+
+func (e *inMemoryEvents) CountExpired(createdBefore time.Time) (int, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	count := 0
+	for _, ev := range e.events {
+		if e.isExpired(ev, createdBefore) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (e *inMemoryEvents) DeleteExpired(createdBefore time.Time, limit int) (int, error) {
+	return e.deleteWhere(func(ev eventsapi.EventDTO) bool {
+		return e.isExpired(ev, createdBefore)
+	}, limit, false), nil
+}
+
+func (e *inMemoryEvents) isExpired(ev eventsapi.EventDTO, createdBefore time.Time) bool {
+	if !ev.CreatedAt.Before(createdBefore) {
+		return false
+	}
+	return ev.InstanceID == nil || *ev.InstanceID == "" || e.instanceExists(ev.InstanceID)
+}
+
+func (e *inMemoryEvents) instanceExists(instanceID *string) bool {
+	if instanceID == nil {
+		return false
+	}
+	_, err := e.instances.GetByID(*instanceID)
+	return err == nil
+}
+
+// deleteWhere removes at most limit matching events, all matching events are removed if limit is negative
+func (e *inMemoryEvents) deleteWhere(matches func(eventsapi.EventDTO) bool, limit int, archive bool) int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	kept := e.events[:0]
+	deleted := 0
+	for _, ev := range e.events {
+		if (limit < 0 || deleted < limit) && matches(ev) {
+			deleted++
+			if archive {
+				e.archived = append(e.archived, ev)
+			}
+			continue
+		}
+		kept = append(kept, ev)
+	}
+	e.events = kept
+	return deleted
+}
+
 func requiredContains[T comparable](el *T, sl []T) bool {
 	if len(sl) == 0 {
 		return true
@@ -132,6 +330,11 @@ type storage struct {
 	instancesArchived InstancesArchived
 	bindings          Bindings
 	actions           Actions
+	webhookDeliveries WebhookDeliveries
+	operationLeases   OperationLeases
+	quotaReservations QuotaReservations
+	eventsRetention   EventsRetention
+	archivedEvents    ArchivedEvents
 	timezones         TimeZones
 }
 
@@ -167,4 +370,24 @@ func (s storage) Actions() Actions {
 	return s.actions
 }
 
+func (s storage) WebhookDeliveries() WebhookDeliveries {
+	return s.webhookDeliveries
+}
+
+func (s storage) OperationLeases() OperationLeases {
+	return s.operationLeases
+}
+
+func (s storage) QuotaReservations() QuotaReservations {
+	return s.quotaReservations
+}
+
+func (s storage) EventsRetention() EventsRetention {
+	return s.eventsRetention
+}
+
+func (s storage) ArchivedEvents() ArchivedEvents {
+	return s.archivedEvents
+}
+
 func (s storage) TimeZones() TimeZones { return s.timezones }
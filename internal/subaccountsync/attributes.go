@@ -0,0 +1,137 @@
+package subaccountsync
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/kyma-project/kyma-environment-broker/internal/customresources"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+const (
+	AttributeDisplayName       = "displayName"
+	AttributeRegion            = "region"
+	AttributeParentDirectoryID = "parentDirectoryID"
+	// customPropertyPrefix selects a custom property of the subaccount by its key, e.g. customProperties.costCenter
+	customPropertyPrefix = "customProperties."
+
+	maxLabelValueLength = 63
+)
+
+var invalidLabelValueChars = regexp.MustCompile(`[^A-Za-z0-9._-]`)
+
+// AttributeLabels maps CIS subaccount attributes to the label keys of Kyma and Runtime CRs
+type AttributeLabels map[string]string
+
+// Unmarshal parses comma-separated attribute=labelKey pairs.
+// Implements envconfig.Unmarshal interface.
+func (a *AttributeLabels) Unmarshal(in string) error {
+	labels := make(AttributeLabels)
+	for _, pair := range strings.Split(in, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		attribute, labelKey, found := strings.Cut(pair, "=")
+		if !found {
+			return fmt.Errorf("invalid synced attribute %q, expected attribute=labelKey", pair)
+		}
+		attribute, labelKey = strings.TrimSpace(attribute), strings.TrimSpace(labelKey)
+		if !isSupportedAttribute(attribute) {
+			return fmt.Errorf("unsupported subaccount attribute %q", attribute)
+		}
+		if errs := validation.IsQualifiedName(labelKey); len(errs) > 0 {
+			return fmt.Errorf("invalid label key %q for attribute %s: %s", labelKey, attribute, strings.Join(errs, ", "))
+		}
+		if customresources.IsManagedLabel(labelKey) {
+			return fmt.Errorf("label key %q for attribute %s is managed by KEB or the Kyma operators", labelKey, attribute)
+		}
+		labels[attribute] = labelKey
+	}
+	*a = labels
+	return nil
+}
+
+func isSupportedAttribute(attribute string) bool {
+	switch attribute {
+	case AttributeDisplayName, AttributeRegion, AttributeParentDirectoryID:
+		return true
+	}
+	return strings.HasPrefix(attribute, customPropertyPrefix) && len(attribute) > len(customPropertyPrefix)
+}
+
+// selectAttributes returns only the synced attributes
+func (a AttributeLabels) selectAttributes(attributes map[string]string) map[string]string {
+	if len(a) == 0 {
+		return nil
+	}
+	selected := make(map[string]string, len(a))
+	for attribute := range a {
+		if value, ok := attributes[attribute]; ok && value != "" {
+			selected[attribute] = value
+		}
+	}
+	return selected
+}
+
+// desiredLabels returns the labels for the attribute values, an empty value means the label must be removed
+func (a AttributeLabels) desiredLabels(attributes map[string]string) map[string]string {
+	if len(a) == 0 {
+		return nil
+	}
+	labels := make(map[string]string, len(a))
+	for attribute, labelKey := range a {
+		labels[labelKey] = labelValue(attributes[attribute])
+	}
+	return labels
+}
+
+// currentLabels returns the values of the synced labels set on a resource
+func (a AttributeLabels) currentLabels(resourceLabels map[string]string) map[string]string {
+	if len(a) == 0 {
+		return nil
+	}
+	labels := make(map[string]string, len(a))
+	for _, labelKey := range a {
+		if value, ok := resourceLabels[labelKey]; ok {
+			labels[labelKey] = value
+		}
+	}
+	return labels
+}
+
+func labelsMatch(desired, current map[string]string) bool {
+	for key, value := range desired {
+		if current[key] != value {
+			return false
+		}
+	}
+	return true
+}
+
+// labelValue converts the attribute value to a valid label value, invalid characters are replaced with '-'
+// and the value is shortened to 63 characters
+func labelValue(value string) string {
+	value = invalidLabelValueChars.ReplaceAllString(value, "-")
+	if len(value) > maxLabelValueLength {
+		value = value[:maxLabelValueLength]
+	}
+	return strings.Trim(value, "-_.")
+}
+
+// subaccountAttributes returns the attributes of the subaccount, the parent directory ID is empty if the subaccount is not in a directory
+func subaccountAttributes(displayName, region, parentGUID, globalAccountGUID string, customProperties map[string]string) map[string]string {
+	attributes := map[string]string{
+		AttributeDisplayName: displayName,
+		AttributeRegion:      region,
+	}
+	if parentGUID != "" && parentGUID != globalAccountGUID {
+		attributes[AttributeParentDirectoryID] = parentGUID
+	}
+	for key, value := range customProperties {
+		attributes[customPropertyPrefix+key] = value
+	}
+	return attributes
+}
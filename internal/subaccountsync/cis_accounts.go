@@ -44,7 +44,7 @@ func (c *RateLimitedCisClient) GetSubaccountData(subaccountID string) (CisStateT
 		return CisStateType{}, fmt.Errorf("while processing response: %s", c.handleErrorStatusCode(response))
 	}
 
-	var cisResponse CisStateType
+	var cisResponse CisAccountResponse
 	err = json.NewDecoder(response.Body).Decode(&cisResponse)
 	if err != nil {
 		c.incRequest("failure")
@@ -52,5 +52,5 @@ func (c *RateLimitedCisClient) GetSubaccountData(subaccountID string) (CisStateT
 	}
 
 	c.incRequest("success")
-	return cisResponse, nil
+	return cisResponse.toCisState(), nil
 }
@@ -9,7 +9,9 @@ import (
 	"time"
 )
 
-func (c *RateLimitedCisClient) buildEventRequest(cursor string) (*http.Request, error) {
+// buildEventRequest builds the request for the first page of events of the window or for the next page if the cursor is set,
+// the window is not smaller than the configured events window size
+func (c *RateLimitedCisClient) buildEventRequest(cursor string, windowSize time.Duration) (*http.Request, error) {
 	request, err := http.NewRequest(http.MethodGet, fmt.Sprintf(eventServicePath, c.config.ServiceURL), nil)
 	if err != nil {
 		return nil, fmt.Errorf("while creating request: %v", err)
@@ -18,7 +20,7 @@ func (c *RateLimitedCisClient) buildEventRequest(cursor string) (*http.Request,
 	if cursor != "" {
 		q.Add("cursor", cursor)
 	} else {
-		q.Add("since", durationToSince(c.eventsWindowSize))
+		q.Add("since", durationToSince(max(windowSize, c.eventsWindowSize)))
 		q.Add("entityType", "Subaccount")
 		for _, et := range eventTypes {
 			q.Add("eventType", et)
@@ -31,12 +33,12 @@ func (c *RateLimitedCisClient) buildEventRequest(cursor string) (*http.Request,
 	return request, nil
 }
 
-func (c *RateLimitedCisClient) fetchEventsWindow() ([]Event, error) {
+func (c *RateLimitedCisClient) fetchEventsWindow(windowSize time.Duration) ([]Event, error) {
 	var events []Event
 	var cursor string
 	var page int
 	for {
-		cisResponse, err := c.fetchEventsPage(cursor)
+		cisResponse, err := c.fetchEventsPage(cursor, windowSize)
 		if err != nil {
 			c.log.Error(fmt.Sprintf("while getting subaccount events page %d: %v", page, err))
 			return events, err
@@ -52,8 +54,8 @@ func (c *RateLimitedCisClient) fetchEventsWindow() ([]Event, error) {
 	return events, nil
 }
 
-func (c *RateLimitedCisClient) fetchEventsPage(cursor string) (CisEventsResponse, error) {
-	request, err := c.buildEventRequest(cursor)
+func (c *RateLimitedCisClient) fetchEventsPage(cursor string, windowSize time.Duration) (CisEventsResponse, error) {
+	request, err := c.buildEventRequest(cursor, windowSize)
 	if err != nil {
 		return CisEventsResponse{}, fmt.Errorf("while building request for event service: %v", err)
 	}
@@ -80,17 +82,27 @@ func (c *RateLimitedCisClient) fetchEventsPage(cursor string) (CisEventsResponse
 	return cisResponse, nil
 }
 
-func (c *RateLimitedCisClient) getEventsForSubaccounts(logs slog.Logger, subaccountsMap subaccountsSetType) ([]Event, error) {
-	rawEvents, err := c.fetchEventsWindow()
+// getEventsForSubaccounts returns the events of the subaccounts and the action time of the most recent event in CIS
+func (c *RateLimitedCisClient) getEventsForSubaccounts(logs slog.Logger, subaccountsMap subaccountsSetType, windowSize time.Duration) ([]Event, int64, error) {
+	rawEvents, err := c.fetchEventsWindow(windowSize)
+	latestActionTime := latestEventActionTime(rawEvents)
 	if err != nil {
-		return filterEvents(rawEvents, subaccountsMap), err
+		return filterEvents(rawEvents, subaccountsMap), latestActionTime, err
 	}
 
 	// filter events to get only the ones in subaccounts map
 	filteredEventsFromCis := filterEvents(rawEvents, subaccountsMap)
 	logs.Info(fmt.Sprintf("Raw events: %d, filtered: %d", len(rawEvents), len(filteredEventsFromCis)))
 
-	return filteredEventsFromCis, nil
+	return filteredEventsFromCis, latestActionTime, nil
+}
+
+func latestEventActionTime(events []Event) int64 {
+	var latest int64
+	for _, event := range events {
+		latest = max(latest, event.ActionTime)
+	}
+	return latest
 }
 
 func filterEvents(rawEvents []Event, subaccounts subaccountsSetType) []Event {
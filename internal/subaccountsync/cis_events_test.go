@@ -37,7 +37,7 @@ func TestBuildEventRequest_FirstCall(t *testing.T) {
 		ctx:              context.Background(),
 		RateLimiter:      rate.NewLimiter(rate.Every(time.Millisecond), 1000),
 	}
-	req, err := c.buildEventRequest("")
+	req, err := c.buildEventRequest("", 0)
 	require.NoError(t, err)
 	q := req.URL.Query()
 	assert.Equal(t, "1H", q.Get("since"))
@@ -50,6 +50,18 @@ func TestBuildEventRequest_FirstCall(t *testing.T) {
 	assert.Contains(t, req.URL.Path, "events/v2/events/central")
 }
 
+func TestBuildEventRequest_ExtendedWindow(t *testing.T) {
+	c := &RateLimitedCisClient{
+		config:           CisEndpointConfig{ServiceURL: "http://example.com", PageSize: "10"},
+		eventsWindowSize: 20 * time.Minute,
+		ctx:              context.Background(),
+		RateLimiter:      rate.NewLimiter(rate.Every(time.Millisecond), 1000),
+	}
+	req, err := c.buildEventRequest("", 150*time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, "3H", req.URL.Query().Get("since"))
+}
+
 func TestBuildEventRequest_SubsequentCall(t *testing.T) {
 	c := &RateLimitedCisClient{
 		config:           CisEndpointConfig{ServiceURL: "http://example.com", PageSize: "10"},
@@ -57,7 +69,7 @@ func TestBuildEventRequest_SubsequentCall(t *testing.T) {
 		ctx:              context.Background(),
 		RateLimiter:      rate.NewLimiter(rate.Every(time.Millisecond), 1000),
 	}
-	req, err := c.buildEventRequest("cursor-abc")
+	req, err := c.buildEventRequest("cursor-abc", 0)
 	require.NoError(t, err)
 	q := req.URL.Query()
 	assert.Equal(t, "cursor-abc", q.Get("cursor"))
@@ -98,7 +110,7 @@ func TestFetchEventsWindow_CursorPagination(t *testing.T) {
 		ctx:              context.Background(),
 	}
 
-	events, err := c.fetchEventsWindow()
+	events, err := c.fetchEventsWindow(0)
 	require.NoError(t, err)
 	require.Len(t, events, 2)
 	assert.Equal(t, "sa1", events[0].SubaccountID)
@@ -1,16 +1,27 @@
 package subaccountsync
 
+import (
+	"encoding/json"
+	"strings"
+)
+
 type (
 	EventDetails struct {
-		BetaEnabled       bool   `json:"betaEnabled"`
-		UsedForProduction string `json:"usedForProduction"`
+		BetaEnabled       bool      `json:"betaEnabled"`
+		UsedForProduction string    `json:"usedForProduction"`
+		DisplayName       string    `json:"displayName"`
+		Region            string    `json:"region"`
+		ParentGUID        string    `json:"parentGuid"`
+		Labels            cisLabels `json:"labels"`
 	}
 
 	Event struct {
-		ActionTime   int64  `json:"actionTime"`
-		SubaccountID string `json:"entityId"`
-		Type         string `json:"eventType"`
-		Details      EventDetails
+		ID                int64  `json:"id"`
+		ActionTime        int64  `json:"actionTime"`
+		SubaccountID      string `json:"entityId"`
+		GlobalAccountGUID string `json:"globalAccountGUID"`
+		Type              string `json:"eventType"`
+		Details           EventDetails
 	}
 
 	CisEventsResponse struct {
@@ -22,6 +33,23 @@ type (
 		BetaEnabled       bool   `json:"betaEnabled"`
 		UsedForProduction string `json:"usedForProduction"`
 		ModifiedDate      int64  `json:"modifiedDate"`
+		// Attributes are the values of the synced subaccount attributes, see AttributeLabels
+		Attributes map[string]string `json:"-"`
+	}
+
+	CisAccountResponse struct {
+		CisStateType
+		DisplayName       string              `json:"displayName"`
+		Region            string              `json:"region"`
+		ParentGUID        string              `json:"parentGUID"`
+		GlobalAccountGUID string              `json:"globalAccountGUID"`
+		Labels            cisLabels           `json:"labels"`
+		CustomProperties  []CisCustomProperty `json:"customProperties"`
+	}
+
+	CisCustomProperty struct {
+		Key   string `json:"key"`
+		Value string `json:"value"`
 	}
 
 	CisErrorResponseType struct {
@@ -32,4 +60,61 @@ type (
 	CisAccountErrorResponseType struct {
 		Error CisErrorResponseType `json:"error"`
 	}
+
+	// cisLabels are the custom properties of the subaccount, a property can have multiple values
+	cisLabels map[string][]string
 )
+
+func (s CisStateType) isEmpty() bool {
+	return !s.BetaEnabled && s.UsedForProduction == "" && s.ModifiedDate == 0 && len(s.Attributes) == 0
+}
+
+// UnmarshalJSON accepts labels with a single value and ignores labels in an unknown format,
+// so the labels never break the synchronization of the other attributes
+func (l *cisLabels) UnmarshalJSON(data []byte) error {
+	var multiValue map[string][]string
+	if err := json.Unmarshal(data, &multiValue); err == nil {
+		*l = multiValue
+		return nil
+	}
+	var singleValue map[string]string
+	if err := json.Unmarshal(data, &singleValue); err == nil {
+		labels := make(cisLabels, len(singleValue))
+		for key, value := range singleValue {
+			labels[key] = []string{value}
+		}
+		*l = labels
+		return nil
+	}
+	*l = nil
+	return nil
+}
+
+func (l cisLabels) customProperties() map[string]string {
+	properties := make(map[string]string, len(l))
+	for key, values := range l {
+		properties[key] = strings.Join(values, ",")
+	}
+	return properties
+}
+
+func (r CisAccountResponse) toCisState() CisStateType {
+	properties := r.Labels.customProperties()
+	for _, property := range r.CustomProperties {
+		if _, ok := properties[property.Key]; !ok {
+			properties[property.Key] = property.Value
+		}
+	}
+	state := r.CisStateType
+	state.Attributes = subaccountAttributes(r.DisplayName, r.Region, r.ParentGUID, r.GlobalAccountGUID, properties)
+	return state
+}
+
+func (e Event) toCisState() CisStateType {
+	return CisStateType{
+		BetaEnabled:       e.Details.BetaEnabled,
+		UsedForProduction: e.Details.UsedForProduction,
+		ModifiedDate:      e.ActionTime,
+		Attributes:        subaccountAttributes(e.Details.DisplayName, e.Details.Region, e.Details.ParentGUID, e.GlobalAccountGUID, e.Details.Labels.customProperties()),
+	}
+}
@@ -23,6 +23,8 @@ type (
 		LogLevel                          string        `envconfig:"default=info"`
 		RuntimeConfigurationConfigMapName string
 		AlwaysSubaccountFromDatabase      bool `envconfig:"default=false"`
+		// SyncedAttributes maps additional subaccount attributes to label keys, e.g. displayName=kyma-project.io/subaccount-name,customProperties.costCenter=kyma-project.io/cost-center
+		SyncedAttributes AttributeLabels `envconfig:"optional"`
 	}
 
 	CisEndpointConfig struct {
@@ -0,0 +1,172 @@
+package subaccountsync
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/kyma-project/kyma-environment-broker/internal"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage/dberr"
+)
+
+// SyncStatus shows how far the sync is behind CIS, all times are in milliseconds since the epoch
+type SyncStatus struct {
+	Subaccounts              int   `json:"subaccounts"`
+	LastEventID              int64 `json:"lastEventId"`
+	LastEventActionTime      int64 `json:"lastEventActionTime"`
+	LatestCisEventActionTime int64 `json:"latestCisEventActionTime"`
+	EventsSyncedAt           int64 `json:"eventsSyncedAt"`
+	AccountsSyncedAt         int64 `json:"accountsSyncedAt"`
+	// EventsLagSeconds is the time between the latest event in CIS and the last applied event
+	EventsLagSeconds *int64 `json:"eventsLagSeconds"`
+}
+
+// restoreCursorFromDB restores the progress of the sync, so the first events window after a restart covers all events since the last processed one
+func (reconciler *stateReconcilerType) restoreCursorFromDB() {
+	logs := reconciler.logger
+	cursor, err := reconciler.db.SubaccountStates().GetSyncCursor()
+	switch {
+	case dberr.IsNotFound(err):
+		logs.Info("Subaccount sync cursor not found in the database, starting with the default events window")
+		return
+	case err != nil:
+		logs.Error(fmt.Sprintf("while getting subaccount sync cursor from db: %s", err))
+		return
+	}
+
+	reconciler.cursorMutex.Lock()
+	reconciler.cursor = cursor
+	reconciler.cursorMutex.Unlock()
+	reconciler.eventWindow.UpdateToTime(cursor.LastEventActionTime)
+	logs.Info(fmt.Sprintf("Subaccount sync cursor restored: last event ID: %d, last event time: %d, events synced at: %d", cursor.LastEventID, cursor.LastEventActionTime, cursor.EventsSyncedAt))
+}
+
+// processedEventIDs returns the IDs of the already processed events from the window of the given events, loaded with one query
+func (reconciler *stateReconcilerType) processedEventIDs(events []Event) map[int64]struct{} {
+	processed := make(map[int64]struct{})
+	if reconciler.db == nil || len(events) == 0 {
+		return processed
+	}
+	fromActionTime := events[0].ActionTime
+	for _, event := range events {
+		fromActionTime = min(fromActionTime, event.ActionTime)
+	}
+	ids, err := reconciler.db.SubaccountStates().ListProcessedEventIDs(fromActionTime)
+	if err != nil {
+		// applying the events again is safe, older events never override newer state
+		reconciler.logger.Warn(fmt.Sprintf("while getting processed events since %d: %s", fromActionTime, err))
+		return processed
+	}
+	for _, id := range ids {
+		processed[id] = struct{}{}
+	}
+	return processed
+}
+
+// persistEvent stores the subaccount state changed by the event and then marks the event processed,
+// so the event is not applied again after a restart
+func (reconciler *stateReconcilerType) persistEvent(event Event) {
+	if reconciler.db == nil || event.ID == 0 {
+		return
+	}
+	logs := reconciler.logger
+	subaccount := subaccountIDType(event.SubaccountID)
+
+	reconciler.mutex.Lock()
+	state, ok := reconciler.inMemoryState[subaccount]
+	reconciler.mutex.Unlock()
+	if ok && !state.pendingDelete {
+		if err := reconciler.db.SubaccountStates().UpsertState(toSubaccountState(subaccount, state)); err != nil {
+			logs.Error(fmt.Sprintf("while storing subaccount:%s state after event %d: %s", subaccount, event.ID, err))
+			return
+		}
+	}
+
+	err := reconciler.db.SubaccountStates().MarkEventProcessed(internal.SubaccountSyncEvent{
+		ID:           event.ID,
+		SubaccountID: event.SubaccountID,
+		ActionTime:   event.ActionTime,
+		ProcessedAt:  epochInMillis(),
+	})
+	if err != nil {
+		logs.Error(fmt.Sprintf("while marking event %d as processed: %s", event.ID, err))
+	}
+}
+
+func (reconciler *stateReconcilerType) updateEventsCursor(lastEvent *Event, latestCisEventTime int64, synced bool) {
+	reconciler.cursorMutex.Lock()
+	defer reconciler.cursorMutex.Unlock()
+
+	if lastEvent != nil && lastEvent.ActionTime >= reconciler.cursor.LastEventActionTime {
+		reconciler.cursor.LastEventID = lastEvent.ID
+		reconciler.cursor.LastEventActionTime = lastEvent.ActionTime
+	}
+	reconciler.latestCisEventTime = max(reconciler.latestCisEventTime, latestCisEventTime)
+	if synced {
+		reconciler.cursor.EventsSyncedAt = epochInMillis()
+		if reconciler.metrics != nil {
+			reconciler.metrics.eventsLastSync.Set(float64(reconciler.cursor.EventsSyncedAt) / 1000)
+		}
+	}
+	reconciler.storeCursorInDb()
+
+	if reconciler.db == nil {
+		return
+	}
+	// the events window is rounded up to full hours, so older events are never fetched again
+	processedBefore := reconciler.eventWindow.GetNextFromTime() - time.Hour.Milliseconds()
+	if err := reconciler.db.SubaccountStates().DeleteProcessedEventsBefore(processedBefore); err != nil {
+		reconciler.logger.Warn(fmt.Sprintf("while deleting processed events before %d: %s", processedBefore, err))
+	}
+}
+
+func (reconciler *stateReconcilerType) updateAccountsCursor() {
+	reconciler.cursorMutex.Lock()
+	defer reconciler.cursorMutex.Unlock()
+
+	reconciler.cursor.AccountsSyncedAt = epochInMillis()
+	reconciler.storeCursorInDb()
+}
+
+// storeCursorInDb must be called with the cursor mutex held
+func (reconciler *stateReconcilerType) storeCursorInDb() {
+	if reconciler.db == nil {
+		return
+	}
+	if err := reconciler.db.SubaccountStates().UpsertSyncCursor(reconciler.cursor); err != nil {
+		reconciler.logger.Error(fmt.Sprintf("while storing subaccount sync cursor: %s", err))
+	}
+}
+
+func (reconciler *stateReconcilerType) getStatus() SyncStatus {
+	reconciler.mutex.Lock()
+	subaccounts := len(reconciler.inMemoryState)
+	reconciler.mutex.Unlock()
+
+	reconciler.cursorMutex.Lock()
+	defer reconciler.cursorMutex.Unlock()
+
+	status := SyncStatus{
+		Subaccounts:              subaccounts,
+		LastEventID:              reconciler.cursor.LastEventID,
+		LastEventActionTime:      reconciler.cursor.LastEventActionTime,
+		LatestCisEventActionTime: reconciler.latestCisEventTime,
+		EventsSyncedAt:           reconciler.cursor.EventsSyncedAt,
+		AccountsSyncedAt:         reconciler.cursor.AccountsSyncedAt,
+	}
+	if status.LatestCisEventActionTime > 0 {
+		lag := max(status.LatestCisEventActionTime-status.LastEventActionTime, 0) / 1000
+		status.EventsLagSeconds = &lag
+	}
+	return status
+}
+
+func (reconciler *stateReconcilerType) statusHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(reconciler.getStatus()); err != nil {
+			reconciler.logger.Warn(fmt.Sprintf("while writing sync status: %s", err))
+		}
+	})
+}
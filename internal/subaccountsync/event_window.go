@@ -1,5 +1,7 @@
 package subaccountsync
 
+import "time"
+
 type EventWindow struct {
 	lastToTime    int64
 	windowSize    int64
@@ -29,3 +31,12 @@ func (ew *EventWindow) UpdateToTime(eventTime int64) {
 		ew.lastToTime = eventTime
 	}
 }
+
+// GetWindowSize returns the duration of the next window, the window is extended to cover all events since the last processed one,
+// e.g. after a restart when the time of the last processed event is restored from the storage
+func (ew *EventWindow) GetWindowSize() time.Duration {
+	if ew.lastToTime == 0 {
+		return time.Duration(ew.windowSize) * time.Millisecond
+	}
+	return time.Duration(ew.nowMillisFunc()-ew.GetNextFromTime()) * time.Millisecond
+}
@@ -2,6 +2,7 @@ package subaccountsync
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -30,3 +31,24 @@ func TestEventWindow(t *testing.T) {
 		assert.Equal(t, now-1000, ew.lastToTime)
 	})
 }
+
+func TestEventWindow_GetWindowSize(t *testing.T) {
+	now := int64(1709164800000)
+
+	t.Run("should return configured window size when no event was processed", func(t *testing.T) {
+		ew := NewEventWindow(windowSize, func() int64 { return now })
+		assert.Equal(t, 20*time.Minute, ew.GetWindowSize())
+	})
+
+	t.Run("should extend the window to the last processed event restored after restart", func(t *testing.T) {
+		ew := NewEventWindow(windowSize, func() int64 { return now })
+		ew.UpdateToTime(now - (3 * time.Hour).Milliseconds())
+		assert.Equal(t, 3*time.Hour, ew.GetWindowSize())
+	})
+
+	t.Run("should return configured window size when the last processed event is recent", func(t *testing.T) {
+		ew := NewEventWindow(windowSize, func() int64 { return now })
+		ew.UpdateToTime(now - 1000)
+		assert.Equal(t, 20*time.Minute, ew.GetWindowSize())
+	})
+}
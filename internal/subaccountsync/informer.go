@@ -47,7 +47,7 @@ func kymaCREventHandlers(stateReconciler *stateReconcilerType, logger *slog.Logg
 			if err != nil {
 				return
 			}
-			stateReconciler.reconcileResourceUpdate(subaccountIDType(subaccountID), runtimeIDType(runtimeID), resourceStateType{kymaState: kymaStateType{betaEnabled: betaEnabled, usedForProduction: usedForProduction}})
+			stateReconciler.reconcileResourceUpdate(subaccountIDType(subaccountID), runtimeIDType(runtimeID), resourceStateType{kymaState: kymaStateType{betaEnabled: betaEnabled, usedForProduction: usedForProduction, labels: stateReconciler.syncedAttributes.currentLabels(u.GetLabels())}})
 			data, err := stateReconciler.accountsClient.GetSubaccountData(subaccountID)
 			if err != nil {
 				logger.Warn(fmt.Sprintf("while getting data for subaccount:%s", err))
@@ -66,7 +66,7 @@ func kymaCREventHandlers(stateReconciler *stateReconcilerType, logger *slog.Logg
 				return
 			}
 			if !reflect.DeepEqual(oldObj.(*unstructured.Unstructured).GetLabels(), u.GetLabels()) {
-				stateReconciler.reconcileResourceUpdate(subaccountIDType(subaccountID), runtimeIDType(runtimeID), resourceStateType{kymaState: kymaStateType{betaEnabled: betaEnabled, usedForProduction: usedForProduction}})
+				stateReconciler.reconcileResourceUpdate(subaccountIDType(subaccountID), runtimeIDType(runtimeID), resourceStateType{kymaState: kymaStateType{betaEnabled: betaEnabled, usedForProduction: usedForProduction, labels: stateReconciler.syncedAttributes.currentLabels(u.GetLabels())}})
 			}
 		},
 		DeleteFunc: func(obj interface{}) {
@@ -100,7 +100,7 @@ func runtimeCREventHandlers(stateReconciler *stateReconcilerType, logger *slog.L
 				logger.Warn(fmt.Sprintf("Runtime CR %s has no subaccount or runtime label, skipping", u.GetName()))
 				return
 			}
-			stateReconciler.reconcileRuntimeResourceUpdate(subaccountIDType(subaccountID), runtimeIDType(runtimeID), labels[customresources.UsedForProductionLabelKey])
+			stateReconciler.reconcileRuntimeResourceUpdate(subaccountIDType(subaccountID), runtimeIDType(runtimeID), runtimeCRStateFromLabels(labels, stateReconciler.syncedAttributes))
 		},
 		UpdateFunc: func(oldObj, newObj interface{}) {
 			u, ok := newObj.(*unstructured.Unstructured)
@@ -116,8 +116,15 @@ func runtimeCREventHandlers(stateReconciler *stateReconcilerType, logger *slog.L
 					logger.Warn(fmt.Sprintf("Runtime CR %s has no subaccount or runtime label, skipping", u.GetName()))
 					return
 				}
-				stateReconciler.reconcileRuntimeResourceUpdate(subaccountIDType(subaccountID), runtimeIDType(runtimeID), labels[customresources.UsedForProductionLabelKey])
+				stateReconciler.reconcileRuntimeResourceUpdate(subaccountIDType(subaccountID), runtimeIDType(runtimeID), runtimeCRStateFromLabels(labels, stateReconciler.syncedAttributes))
 			}
 		},
 	}
 }
+
+func runtimeCRStateFromLabels(labels map[string]string, syncedAttributes AttributeLabels) runtimeCRStateType {
+	return runtimeCRStateType{
+		usedForProduction: labels[customresources.UsedForProductionLabelKey],
+		labels:            syncedAttributes.currentLabels(labels),
+	}
+}
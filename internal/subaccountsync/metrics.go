@@ -11,6 +11,7 @@ type Metrics struct {
 	states          *prometheus.GaugeVec
 	kymaInformer    *prometheus.CounterVec
 	runtimeInformer *prometheus.CounterVec
+	eventsLastSync  prometheus.Gauge
 }
 
 func NewMetrics(reg prometheus.Registerer, namespace string) *Metrics {
@@ -55,7 +56,12 @@ func NewMetrics(reg prometheus.Registerer, namespace string) *Metrics {
 			Name:      "dry_run",
 			Help:      "Resources are not updated.",
 		}),
+		eventsLastSync: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "cis_events_last_sync_timestamp_seconds",
+			Help:      "Time of the last successful CIS events synchronization.",
+		}),
 	}
-	reg.MustRegister(m.queue, m.queueOps, m.states, m.kymaInformer, m.runtimeInformer, m.cisRequests, m.timeInQueue, m.dryRun)
+	reg.MustRegister(m.queue, m.queueOps, m.states, m.kymaInformer, m.runtimeInformer, m.cisRequests, m.timeInQueue, m.dryRun, m.eventsLastSync)
 	return m
 }
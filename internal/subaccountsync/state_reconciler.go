@@ -32,7 +32,12 @@ func (reconciler *stateReconcilerType) recreateStateFromDB() {
 	for _, subaccount := range dbStates {
 		//create subaccount state in inMemoryState
 		reconciler.inMemoryState[subaccountIDType(subaccount.ID)] = subaccountStateType{
-			cisState: CisStateType{isBetaEnabledTrue(subaccount.BetaEnabled), subaccount.UsedForProduction, subaccount.ModifiedAt},
+			cisState: CisStateType{
+				BetaEnabled:       isBetaEnabledTrue(subaccount.BetaEnabled),
+				UsedForProduction: subaccount.UsedForProduction,
+				ModifiedDate:      subaccount.ModifiedAt,
+				Attributes:        reconciler.syncedAttributes.selectAttributes(subaccount.Attributes),
+			},
 		}
 	}
 
@@ -84,7 +89,7 @@ func (reconciler *stateReconcilerType) setMetrics() {
 
 	usedForProduction := make(map[string]int)
 	for _, state := range reconciler.inMemoryState {
-		if state.cisState != (CisStateType{}) {
+		if !state.cisState.isEmpty() {
 			if state.cisState.BetaEnabled {
 				betaEnabledCount++
 			} else {
@@ -130,7 +135,7 @@ func (reconciler *stateReconcilerType) periodicAccountsSync() {
 	var found, notfound, failures int
 	for subaccountID := range subaccountsSet {
 		subaccountDataFromCis, err := reconciler.accountsClient.GetSubaccountData(string(subaccountID))
-		if subaccountDataFromCis == (CisStateType{}) && err == nil {
+		if subaccountDataFromCis.isEmpty() && err == nil {
 			logs.Warn(fmt.Sprintf("subaccount %s not found in CIS", subaccountID))
 			notfound++
 			continue
@@ -144,6 +149,7 @@ func (reconciler *stateReconcilerType) periodicAccountsSync() {
 		}
 	}
 	logs.Debug(fmt.Sprintf("Accounts synchronization finished: found: %d, notfound %d, failures: %d", found, notfound, failures))
+	reconciler.updateAccountsCursor()
 }
 
 func (reconciler *stateReconcilerType) periodicEventsSync() {
@@ -151,19 +157,32 @@ func (reconciler *stateReconcilerType) periodicEventsSync() {
 	eventsClient := reconciler.eventsClient
 	subaccountsSet := reconciler.getAllSubaccountIDsFromState()
 
-	logs.Info(fmt.Sprintf("Running CIS events synchronization for %d subaccounts", len(subaccountsSet)))
+	windowSize := reconciler.eventWindow.GetWindowSize()
 
-	eventsOfInterest, err := eventsClient.getEventsForSubaccounts(*logs, subaccountsSet)
+	logs.Info(fmt.Sprintf("Running CIS events synchronization for %d subaccounts, window size: %s", len(subaccountsSet), windowSize))
+
+	eventsOfInterest, latestCisEventTime, err := eventsClient.getEventsForSubaccounts(*logs, subaccountsSet, windowSize)
 	if err != nil {
 		logs.Error(fmt.Sprintf("while getting subaccount events: %s", err))
 		// we will retry in the next run
 	}
 
+	processed := reconciler.processedEventIDs(eventsOfInterest)
+	var lastEvent *Event
+	var skipped int
 	for _, event := range eventsOfInterest {
+		if _, ok := processed[event.ID]; ok && event.ID != 0 {
+			skipped++
+			continue
+		}
 		reconciler.reconcileCisEvent(event)
+		reconciler.persistEvent(event)
+		processed[event.ID] = struct{}{}
 		reconciler.eventWindow.UpdateToTime(event.ActionTime)
+		lastEvent = &event
 	}
-	logs.Debug(fmt.Sprintf("Events synchronization finished, the most recent reconciled event time: %d", reconciler.eventWindow.lastToTime))
+	reconciler.updateEventsCursor(lastEvent, latestCisEventTime, err == nil)
+	logs.Debug(fmt.Sprintf("Events synchronization finished, already processed events skipped: %d, the most recent reconciled event time: %d", skipped, reconciler.eventWindow.lastToTime))
 }
 
 func (reconciler *stateReconcilerType) getAllSubaccountIDsFromState() subaccountsSetType {
@@ -215,6 +234,7 @@ func (reconciler *stateReconcilerType) reconcileCisAccount(subaccountID subaccou
 		logs.Warn(fmt.Sprintf("subaccount %s not found in state when syncing accounts service - ignoring", subaccountID))
 		return
 	}
+	newCisState.Attributes = reconciler.syncedAttributes.selectAttributes(newCisState.Attributes)
 	if newCisState.ModifiedDate >= state.cisState.ModifiedDate {
 		state.cisState = newCisState
 		reconciler.inMemoryState[subaccountID] = state
@@ -236,11 +256,8 @@ func (reconciler *stateReconcilerType) reconcileCisEvent(event Event) {
 		return
 	}
 	if event.ActionTime >= state.cisState.ModifiedDate {
-		cisState := CisStateType{
-			BetaEnabled:       event.Details.BetaEnabled,
-			UsedForProduction: event.Details.UsedForProduction,
-			ModifiedDate:      event.ActionTime,
-		}
+		cisState := event.toCisState()
+		cisState.Attributes = reconciler.syncedAttributes.selectAttributes(cisState.Attributes)
 		state.cisState = cisState
 		reconciler.inMemoryState[subaccount] = state
 		reconciler.enqueueSubaccountIfOutdated(subaccount, state)
@@ -271,7 +288,7 @@ func (reconciler *stateReconcilerType) reconcileResourceUpdate(subaccountID suba
 	reconciler.setMetrics()
 }
 
-func (reconciler *stateReconcilerType) reconcileRuntimeResourceUpdate(subaccountID subaccountIDType, runtimeID runtimeIDType, usedForProduction string) {
+func (reconciler *stateReconcilerType) reconcileRuntimeResourceUpdate(subaccountID subaccountIDType, runtimeID runtimeIDType, runtimeCRState runtimeCRStateType) {
 	reconciler.mutex.Lock()
 	defer reconciler.mutex.Unlock()
 
@@ -279,7 +296,7 @@ func (reconciler *stateReconcilerType) reconcileRuntimeResourceUpdate(subaccount
 	if !ok {
 		reconciler.logger.Debug(fmt.Sprintf("subaccount %s not found in state - creating state", subaccountID))
 		reconciler.inMemoryState[subaccountID] = subaccountStateType{
-			resourcesState: subaccountRuntimesType{runtimeID: resourceStateType{runtimeCRState: runtimeCRStateType{usedForProduction: usedForProduction}}},
+			resourcesState: subaccountRuntimesType{runtimeID: resourceStateType{runtimeCRState: runtimeCRState}},
 		}
 		return
 	}
@@ -287,7 +304,7 @@ func (reconciler *stateReconcilerType) reconcileRuntimeResourceUpdate(subaccount
 		state.resourcesState = make(subaccountRuntimesType)
 	}
 	existing := state.resourcesState[runtimeID]
-	existing.runtimeCRState.usedForProduction = usedForProduction
+	existing.runtimeCRState = runtimeCRState
 	state.resourcesState[runtimeID] = existing
 	reconciler.inMemoryState[subaccountID] = state
 	reconciler.logger.Debug(fmt.Sprintf("subaccount %s runtime CR state updated, check if outdated", subaccountID))
@@ -332,7 +349,13 @@ func (reconciler *stateReconcilerType) enqueueSubaccountIfOutdated(subaccountID
 	if reconciler.isResourceOutdated(subaccountID, state) {
 		reconciler.logger.Debug(fmt.Sprintf("Subaccount %s is outdated, enqueuing, setting betaEnabled %t", subaccountID, state.cisState.BetaEnabled))
 		state := reconciler.inMemoryState[subaccountID]
-		element := syncqueues.QueueElement{SubaccountID: string(subaccountID), ModifiedAt: state.cisState.ModifiedDate, BetaEnabled: fmt.Sprintf("%t", state.cisState.BetaEnabled), UsedForProduction: state.cisState.UsedForProduction}
+		element := syncqueues.QueueElement{
+			SubaccountID:      string(subaccountID),
+			ModifiedAt:        state.cisState.ModifiedDate,
+			BetaEnabled:       fmt.Sprintf("%t", state.cisState.BetaEnabled),
+			UsedForProduction: state.cisState.UsedForProduction,
+			Labels:            reconciler.syncedAttributes.desiredLabels(state.cisState.Attributes),
+		}
 		reconciler.syncQueue.Insert(element)
 	} else {
 		reconciler.logger.Debug(fmt.Sprintf("Subaccount %s is not to be updated", subaccountID))
@@ -345,6 +368,7 @@ func (reconciler *stateReconcilerType) isResourceOutdated(subaccountID subaccoun
 	if state.resourcesState != nil && state.cisState.ModifiedDate > 0 {
 		runtimes := state.resourcesState
 		cisState := state.cisState
+		desiredLabels := reconciler.syncedAttributes.desiredLabels(cisState.Attributes)
 		for _, resourceState := range runtimes {
 			outdated = outdated || resourceState.kymaState.betaEnabled == ""
 			outdated = outdated || resourceState.kymaState.usedForProduction == ""
@@ -353,6 +377,8 @@ func (reconciler *stateReconcilerType) isResourceOutdated(subaccountID subaccoun
 			outdated = outdated || cisState.UsedForProduction != resourceState.kymaState.usedForProduction
 			outdated = outdated || resourceState.runtimeCRState.usedForProduction == ""
 			outdated = outdated || cisState.UsedForProduction != resourceState.runtimeCRState.usedForProduction
+			outdated = outdated || !labelsMatch(desiredLabels, resourceState.kymaState.labels)
+			outdated = outdated || !labelsMatch(desiredLabels, resourceState.runtimeCRState.labels)
 		}
 		reconciler.logger.Debug(fmt.Sprintf("Subaccount %s has %d runtimes, outdated: %t", subaccountID, len(runtimes), outdated))
 	} else {
@@ -382,12 +408,7 @@ func (reconciler *stateReconcilerType) storeStateInDb() {
 			delete(reconciler.inMemoryState, subaccount)
 			logs.Debug(fmt.Sprintf("Subaccount %s state deleted from persistent storage", subaccount))
 		} else {
-			err := reconciler.db.SubaccountStates().UpsertState(internal.SubaccountState{
-				ID:                string(subaccount),
-				BetaEnabled:       fmt.Sprintf("%t", state.cisState.BetaEnabled),
-				UsedForProduction: state.cisState.UsedForProduction,
-				ModifiedAt:        state.cisState.ModifiedDate,
-			})
+			err := reconciler.db.SubaccountStates().UpsertState(toSubaccountState(subaccount, state))
 			if err != nil {
 				failureCnt++
 				logs.Error(fmt.Sprintf("while updating subaccount:%s state from persistent storage: %s", subaccount, err))
@@ -399,6 +420,16 @@ func (reconciler *stateReconcilerType) storeStateInDb() {
 	logs.Info(fmt.Sprintf("State synced to persistent storage: %d upserts, %d deletes, %d failures", upsertCnt, deleteCnt, failureCnt))
 }
 
+func toSubaccountState(subaccount subaccountIDType, state subaccountStateType) internal.SubaccountState {
+	return internal.SubaccountState{
+		ID:                string(subaccount),
+		BetaEnabled:       fmt.Sprintf("%t", state.cisState.BetaEnabled),
+		UsedForProduction: state.cisState.UsedForProduction,
+		ModifiedAt:        state.cisState.ModifiedDate,
+		Attributes:        state.cisState.Attributes,
+	}
+}
+
 func (reconciler *stateReconcilerType) getDistinctSubaccountsFromInstances() (subaccountsSetType, error) {
 	reconciler.mutex.Lock()
 	defer reconciler.mutex.Unlock()
@@ -2,11 +2,14 @@ package subaccountsync
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"log/slog"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"strconv"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -15,6 +18,7 @@ import (
 	"golang.org/x/time/rate"
 
 	"github.com/kyma-project/kyma-environment-broker/internal"
+	"github.com/kyma-project/kyma-environment-broker/internal/customresources"
 	"github.com/kyma-project/kyma-environment-broker/internal/storage"
 	queues "github.com/kyma-project/kyma-environment-broker/internal/syncqueues"
 	"github.com/stretchr/testify/assert"
@@ -456,6 +460,78 @@ func TestStateReconcilerWithFakeCisServer(t *testing.T) {
 	})
 }
 
+func TestStateReconcilerSyncCursor(t *testing.T) {
+	srv, err := cis.NewFakeServer()
+	require.NoError(t, err)
+	defer srv.Close()
+
+	cisConfig := CisEndpointConfig{
+		ServiceURL:             srv.URL,
+		RateLimitingInterval:   time.Minute * 10,
+		MaxRequestsPerInterval: 1000,
+	}
+	brokerStorage := storage.NewMemoryStorage()
+	reconciler := createNewReconcilerWithFakeCisServer(brokerStorage, srv.Client(), cisConfig)
+
+	t.Run("should not apply already processed events again", func(t *testing.T) {
+		// given
+		reconciler.reconcileResourceUpdate(cis.FakeSubaccountID1, runtimeId11, resourceStateType{kymaState: kymaStateType{betaEnabled: "false", usedForProduction: "NOT_USED_FOR_PRODUCTION"}, runtimeCRState: runtimeCRStateType{usedForProduction: "NOT_USED_FOR_PRODUCTION"}})
+
+		// when
+		reconciler.periodicEventsSync()
+
+		// then
+		element, ok := reconciler.syncQueue.Extract()
+		require.True(t, ok)
+		assert.Equal(t, cis.FakeSubaccountID1, element.SubaccountID)
+		assert.Equal(t, "true", element.BetaEnabled)
+		states, err := brokerStorage.SubaccountStates().ListStates()
+		require.NoError(t, err)
+		require.Len(t, states, 1)
+		assert.Equal(t, "true", states[0].BetaEnabled)
+
+		// when the same events come in the next window
+		reconciler.periodicEventsSync()
+
+		// then
+		assert.True(t, reconciler.syncQueue.IsEmpty())
+	})
+
+	t.Run("should restore the cursor after restart", func(t *testing.T) {
+		// given
+		cursor, err := brokerStorage.SubaccountStates().GetSyncCursor()
+		require.NoError(t, err)
+		assert.NotZero(t, cursor.LastEventID)
+		assert.NotZero(t, cursor.EventsSyncedAt)
+		restarted := createNewReconcilerWithFakeCisServer(brokerStorage, srv.Client(), cisConfig)
+
+		// when
+		restarted.restoreCursorFromDB()
+
+		// then
+		assert.Equal(t, cursor.LastEventActionTime, restarted.eventWindow.lastToTime)
+		assert.Equal(t, cursor, restarted.cursor)
+	})
+
+	t.Run("should serve sync status", func(t *testing.T) {
+		// given
+		recorder := httptest.NewRecorder()
+
+		// when
+		reconciler.statusHandler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/status", nil))
+
+		// then
+		require.Equal(t, http.StatusOK, recorder.Code)
+		var status SyncStatus
+		require.NoError(t, json.NewDecoder(recorder.Body).Decode(&status))
+		assert.Equal(t, 1, status.Subaccounts)
+		assert.Equal(t, reconciler.cursor.LastEventID, status.LastEventID)
+		assert.GreaterOrEqual(t, status.LatestCisEventActionTime, status.LastEventActionTime)
+		require.NotNil(t, status.EventsLagSeconds)
+		assert.Equal(t, (status.LatestCisEventActionTime-status.LastEventActionTime)/1000, *status.EventsLagSeconds)
+	})
+}
+
 func TestOutdatedPredicate(t *testing.T) {
 
 	reconciler := createNewReconciler(nil)
@@ -1544,7 +1620,7 @@ func TestReconcileRuntimeResourceUpdate(t *testing.T) {
 		reconciler.reconcileCisAccount(subaccountId1, CisStateType{BetaEnabled: true, UsedForProduction: "USED_FOR_PRODUCTION", ModifiedDate: veryOldTime})
 		reconciler.syncQueue.Extract()
 
-		reconciler.reconcileRuntimeResourceUpdate(subaccountId1, runtimeId11, "NOT_USED_FOR_PRODUCTION")
+		reconciler.reconcileRuntimeResourceUpdate(subaccountId1, runtimeId11, runtimeCRStateType{usedForProduction: "NOT_USED_FOR_PRODUCTION"})
 
 		assert.False(t, reconciler.syncQueue.IsEmpty())
 		element, ok := reconciler.syncQueue.Extract()
@@ -1557,14 +1633,14 @@ func TestReconcileRuntimeResourceUpdate(t *testing.T) {
 		reconciler.reconcileCisAccount(subaccountId1, CisStateType{BetaEnabled: true, UsedForProduction: "USED_FOR_PRODUCTION", ModifiedDate: veryOldTime})
 		reconciler.syncQueue.Extract()
 
-		reconciler.reconcileRuntimeResourceUpdate(subaccountId1, runtimeId11, "USED_FOR_PRODUCTION")
+		reconciler.reconcileRuntimeResourceUpdate(subaccountId1, runtimeId11, runtimeCRStateType{usedForProduction: "USED_FOR_PRODUCTION"})
 
 		assert.True(t, reconciler.syncQueue.IsEmpty())
 	})
 	t.Run("should create state for unknown subaccount without enqueuing", func(t *testing.T) {
 		reconciler := createNewReconciler(nil)
 
-		reconciler.reconcileRuntimeResourceUpdate(subaccountId1, runtimeId11, "USED_FOR_PRODUCTION")
+		reconciler.reconcileRuntimeResourceUpdate(subaccountId1, runtimeId11, runtimeCRStateType{usedForProduction: "USED_FOR_PRODUCTION"})
 
 		assert.Equal(t, 1, len(reconciler.inMemoryState))
 		assert.True(t, reconciler.syncQueue.IsEmpty())
@@ -1580,6 +1656,150 @@ func TestReconcileRuntimeResourceUpdate(t *testing.T) {
 	})
 }
 
+func TestSyncedAttributes(t *testing.T) {
+	syncedAttributes := AttributeLabels{
+		AttributeDisplayName:                "kyma-project.io/subaccount-name",
+		AttributeRegion:                     "kyma-project.io/subaccount-region",
+		customPropertyPrefix + "costCenter": "kyma-project.io/cost-center",
+	}
+	upToDateKyma := kymaStateType{betaEnabled: "true", usedForProduction: "USED_FOR_PRODUCTION"}
+	upToDateRuntime := runtimeCRStateType{usedForProduction: "USED_FOR_PRODUCTION"}
+
+	t.Run("should detect missing attribute label", func(t *testing.T) {
+		// given
+		reconciler := createNewReconciler(nil)
+		reconciler.syncedAttributes = syncedAttributes
+		state := subaccountStateType{
+			cisState: CisStateType{BetaEnabled: true, UsedForProduction: "USED_FOR_PRODUCTION", ModifiedDate: veryOldTime,
+				Attributes: map[string]string{AttributeDisplayName: "My Subaccount", AttributeRegion: "eu10"}},
+			resourcesState: subaccountRuntimesType{
+				runtimeId11: resourceStateType{kymaState: upToDateKyma, runtimeCRState: upToDateRuntime},
+			},
+		}
+
+		// when / then
+		assert.True(t, reconciler.isResourceOutdated(subaccountId1, state))
+	})
+	t.Run("should treat matching attribute labels as up-to-date", func(t *testing.T) {
+		// given
+		reconciler := createNewReconciler(nil)
+		reconciler.syncedAttributes = syncedAttributes
+		labels := map[string]string{"kyma-project.io/subaccount-name": "My-Subaccount", "kyma-project.io/subaccount-region": "eu10"}
+		kyma, runtime := upToDateKyma, upToDateRuntime
+		kyma.labels, runtime.labels = labels, labels
+		state := subaccountStateType{
+			cisState: CisStateType{BetaEnabled: true, UsedForProduction: "USED_FOR_PRODUCTION", ModifiedDate: veryOldTime,
+				Attributes: map[string]string{AttributeDisplayName: "My Subaccount", AttributeRegion: "eu10"}},
+			resourcesState: subaccountRuntimesType{
+				runtimeId11: resourceStateType{kymaState: kyma, runtimeCRState: runtime},
+			},
+		}
+
+		// when / then
+		assert.False(t, reconciler.isResourceOutdated(subaccountId1, state))
+	})
+	t.Run("should detect label of removed attribute", func(t *testing.T) {
+		// given
+		reconciler := createNewReconciler(nil)
+		reconciler.syncedAttributes = syncedAttributes
+		labels := map[string]string{"kyma-project.io/subaccount-region": "eu10", "kyma-project.io/cost-center": "101"}
+		kyma, runtime := upToDateKyma, upToDateRuntime
+		kyma.labels, runtime.labels = labels, labels
+		state := subaccountStateType{
+			cisState: CisStateType{BetaEnabled: true, UsedForProduction: "USED_FOR_PRODUCTION", ModifiedDate: veryOldTime,
+				Attributes: map[string]string{AttributeRegion: "eu10"}},
+			resourcesState: subaccountRuntimesType{
+				runtimeId11: resourceStateType{kymaState: kyma, runtimeCRState: runtime},
+			},
+		}
+
+		// when / then
+		assert.True(t, reconciler.isResourceOutdated(subaccountId1, state))
+	})
+	t.Run("should ignore attributes when no attributes are configured", func(t *testing.T) {
+		// given
+		reconciler := createNewReconciler(nil)
+		state := subaccountStateType{
+			cisState: CisStateType{BetaEnabled: true, UsedForProduction: "USED_FOR_PRODUCTION", ModifiedDate: veryOldTime,
+				Attributes: map[string]string{AttributeDisplayName: "My Subaccount"}},
+			resourcesState: subaccountRuntimesType{
+				runtimeId11: resourceStateType{kymaState: upToDateKyma, runtimeCRState: upToDateRuntime},
+			},
+		}
+
+		// when / then
+		assert.False(t, reconciler.isResourceOutdated(subaccountId1, state))
+	})
+	t.Run("should enqueue desired labels of synced attributes from CIS event", func(t *testing.T) {
+		// given
+		reconciler := createNewReconciler(nil)
+		reconciler.syncedAttributes = syncedAttributes
+		reconciler.reconcileResourceUpdate(subaccountId1, runtimeId11, resourceStateType{kymaState: upToDateKyma})
+		reconciler.reconcileRuntimeResourceUpdate(subaccountId1, runtimeId11, upToDateRuntime)
+
+		// when
+		reconciler.reconcileCisEvent(Event{
+			SubaccountID: string(subaccountId1),
+			ActionTime:   veryOldTime,
+			Details: EventDetails{
+				BetaEnabled:       true,
+				UsedForProduction: "USED_FOR_PRODUCTION",
+				DisplayName:       "My Subaccount",
+				Region:            "eu10",
+				Labels:            cisLabels{"costCenter": {"101"}},
+			},
+		})
+
+		// then
+		element, ok := reconciler.syncQueue.Extract()
+		require.True(t, ok)
+		assert.Equal(t, map[string]string{
+			"kyma-project.io/subaccount-name":   "My-Subaccount",
+			"kyma-project.io/subaccount-region": "eu10",
+			"kyma-project.io/cost-center":       "101",
+		}, element.Labels)
+	})
+}
+
+func TestAttributeLabels(t *testing.T) {
+	t.Run("should parse attribute mapping", func(t *testing.T) {
+		// given
+		var attributeLabels AttributeLabels
+
+		// when
+		err := attributeLabels.Unmarshal("displayName=kyma-project.io/subaccount-name, parentDirectoryID=kyma-project.io/directory-id,customProperties.costCenter=cost-center")
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, AttributeLabels{
+			AttributeDisplayName:                "kyma-project.io/subaccount-name",
+			AttributeParentDirectoryID:          "kyma-project.io/directory-id",
+			customPropertyPrefix + "costCenter": "cost-center",
+		}, attributeLabels)
+	})
+	t.Run("should reject unknown attribute", func(t *testing.T) {
+		var attributeLabels AttributeLabels
+		assert.Error(t, attributeLabels.Unmarshal("description=kyma-project.io/description"))
+	})
+	t.Run("should reject invalid label key", func(t *testing.T) {
+		var attributeLabels AttributeLabels
+		assert.Error(t, attributeLabels.Unmarshal("displayName=not a label"))
+	})
+	t.Run("should reject label keys managed by KEB and the Kyma operators", func(t *testing.T) {
+		for _, labelKey := range []string{customresources.RegionLabel, customresources.SubaccountIdLabel, "operator.kyma-project.io/region"} {
+			var attributeLabels AttributeLabels
+			assert.Error(t, attributeLabels.Unmarshal("region="+labelKey), labelKey)
+		}
+	})
+	t.Run("should sanitize label values", func(t *testing.T) {
+		assert.Equal(t, "My-Subaccount", labelValue("My Subaccount"))
+		assert.Equal(t, "cost_center", labelValue("cost_center"))
+		assert.Equal(t, "eu10", labelValue("eu10"))
+		assert.Equal(t, "a", labelValue("--a--"))
+		assert.Len(t, labelValue(strings.Repeat("x", 100)), 63)
+	})
+}
+
 // test fixtures
 
 func createNewReconciler(storage storage.BrokerStorage) stateReconcilerType {
@@ -8,6 +8,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/kyma-project/kyma-environment-broker/internal"
 	"github.com/kyma-project/kyma-environment-broker/internal/storage/dbmodel"
 
 	"github.com/kyma-project/kyma-environment-broker/internal/customresources"
@@ -36,9 +37,12 @@ type (
 	kymaStateType    struct {
 		betaEnabled       string
 		usedForProduction string
+		// labels are the current values of the labels of the synced attributes
+		labels map[string]string
 	}
 	runtimeCRStateType struct {
 		usedForProduction string
+		labels            map[string]string
 	}
 	resourceStateType struct {
 		kymaState      kymaStateType
@@ -63,6 +67,13 @@ type (
 		updater        *customresources.Updater
 		metrics        *Metrics
 		eventWindow    *EventWindow
+		// syncedAttributes are the subaccount attributes synchronized with labels in addition to betaEnabled and usedForProduction
+		syncedAttributes AttributeLabels
+
+		// cursor is the progress of the sync stored in the database, it is guarded by cursorMutex
+		cursor             internal.SubaccountSyncCursor
+		latestCisEventTime int64
+		cursorMutex        sync.Mutex
 	}
 )
 
@@ -150,19 +161,22 @@ func (s *SyncService) Run() {
 
 	// create state reconciler
 	stateReconciler := stateReconcilerType{
-		inMemoryState:  make(inMemoryStateType),
-		mutex:          sync.Mutex{},
-		eventsClient:   eventsClient,
-		accountsClient: accountsClient,
-		logger:         logger.With("component", "state-reconciler"),
-		db:             s.db,
-		updater:        updater,
-		syncQueue:      priorityQueue,
-		metrics:        metrics,
-		eventWindow:    NewEventWindow(s.cfg.EventsWindowSize.Milliseconds(), epochInMillis),
+		inMemoryState:    make(inMemoryStateType),
+		mutex:            sync.Mutex{},
+		eventsClient:     eventsClient,
+		accountsClient:   accountsClient,
+		logger:           logger.With("component", "state-reconciler"),
+		db:               s.db,
+		updater:          updater,
+		syncQueue:        priorityQueue,
+		metrics:          metrics,
+		eventWindow:      NewEventWindow(s.cfg.EventsWindowSize.Milliseconds(), epochInMillis),
+		syncedAttributes: s.cfg.SyncedAttributes,
 	}
 
 	stateReconciler.recreateStateFromDB()
+	stateReconciler.restoreCursorFromDB()
+	http.Handle("/status", stateReconciler.statusHandler())
 
 	factory := dynamicinformer.NewFilteredDynamicSharedInformerFactory(s.k8sClient, time.Minute, "kcp-system", nil)
 	kymaCRInformer := factory.ForResource(s.kymaGVR).Informer()
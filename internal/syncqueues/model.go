@@ -20,6 +20,8 @@ type QueueElement struct {
 	BetaEnabled       string
 	UsedForProduction string
 	ModifiedAt        int64
+	// Labels are the labels of the synced subaccount attributes, an empty value means the label is removed
+	Labels map[string]string
 }
 
 type EventHandler struct {
@@ -0,0 +1,50 @@
+package tracing
+
+import (
+	"fmt"
+)
+
+const (
+	ExporterOTLPHTTP = "otlp-http"
+	ExporterOTLPGRPC = "otlp-grpc"
+	ExporterStdout   = "stdout"
+	ExporterFile     = "file"
+	ExporterNone     = "none"
+)
+
+type Config struct {
+	Enabled bool `envconfig:"default=false"`
+	// Exporter is one of otlp-http, otlp-grpc, stdout, file or none
+	Exporter string `envconfig:"default=otlp-http"`
+	// Endpoint is the OTLP collector endpoint (host:port or URL), the OTEL_EXPORTER_OTLP_* environment variables are used if empty
+	Endpoint string `envconfig:"optional"`
+	Insecure bool   `envconfig:"default=false"`
+	// FilePath is the file the spans are appended to by the file exporter
+	FilePath string `envconfig:"default=/tmp/keb-traces.json"`
+	// SampleRatio is the fraction of operations which are traced, the decision is the same for all spans of an operation
+	SampleRatio float64 `envconfig:"default=1"`
+	ServiceName string  `envconfig:"default=kyma-environment-broker"`
+}
+
+func (c Config) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+	switch c.Exporter {
+	case ExporterOTLPHTTP, ExporterOTLPGRPC, ExporterStdout, ExporterNone:
+	case ExporterFile:
+		if c.FilePath == "" {
+			return fmt.Errorf("tracing file path must be set for the %s exporter", ExporterFile)
+		}
+	default:
+		return fmt.Errorf("unknown tracing exporter %q", c.Exporter)
+	}
+	if c.SampleRatio < 0 || c.SampleRatio > 1 {
+		return fmt.Errorf("tracing sample ratio must be between 0 and 1, got %v", c.SampleRatio)
+	}
+	return nil
+}
+
+func (c Config) String() string {
+	return fmt.Sprintf("(Enabled=%v; Exporter=%s; Endpoint=%s; SampleRatio=%v)", c.Enabled, c.Exporter, c.Endpoint, c.SampleRatio)
+}
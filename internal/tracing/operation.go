@@ -0,0 +1,108 @@
+package tracing
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"math/rand/v2"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// An operation is processed many times (every step retry restarts the processing, the broker can be restarted),
+// so the trace of an operation is not kept in memory. Its trace ID and the ID of its root span are derived from the operation ID
+// and the root span is recorded when the operation finishes.
+
+const (
+	AttributeOperationID   = attribute.Key("keb.operation.id")
+	AttributeOperationType = attribute.Key("keb.operation.type")
+	AttributeInstanceID    = attribute.Key("keb.instance.id")
+	AttributeRuntimeID     = attribute.Key("keb.runtime.id")
+	AttributePlanID        = attribute.Key("keb.plan.id")
+	AttributeStage         = attribute.Key("keb.stage")
+	AttributeStep          = attribute.Key("keb.step")
+	AttributeIteration     = attribute.Key("keb.step.iteration")
+	AttributeBackoff       = attribute.Key("keb.step.backoff")
+)
+
+// OperationTraceID returns the trace ID of the operation, which is the operation ID if it is a UUID.
+func OperationTraceID(operationID string) trace.TraceID {
+	var traceID trace.TraceID
+	if decoded, err := hex.DecodeString(strings.ReplaceAll(operationID, "-", "")); err == nil && len(decoded) == len(traceID) {
+		copy(traceID[:], decoded)
+	}
+	if !traceID.IsValid() {
+		sum := sha256.Sum256([]byte(operationID))
+		copy(traceID[:], sum[:])
+	}
+	return traceID
+}
+
+func operationRootSpanID(operationID string) trace.SpanID {
+	var spanID trace.SpanID
+	sum := sha256.Sum256([]byte("root/" + operationID))
+	copy(spanID[:], sum[:])
+	if !spanID.IsValid() {
+		spanID[len(spanID)-1] = 1
+	}
+	return spanID
+}
+
+// OperationContext returns the context with the root span of the operation as a remote parent.
+// Spans started from the context belong to the trace of the operation.
+func OperationContext(ctx context.Context, operationID string) context.Context {
+	return trace.ContextWithRemoteSpanContext(ctx, trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    OperationTraceID(operationID),
+		SpanID:     operationRootSpanID(operationID),
+		TraceFlags: trace.FlagsSampled,
+		Remote:     true,
+	}))
+}
+
+// RecordOperation records the root span of the operation from its start to now.
+func RecordOperation(ctx context.Context, operationID, name string, start time.Time, err error, attrs ...attribute.KeyValue) {
+	ctx = context.WithValue(ctx, rootSpanKey{}, operationID)
+	_, span := Tracer().Start(ctx, name,
+		trace.WithNewRoot(),
+		trace.WithTimestamp(start),
+		trace.WithAttributes(append(attrs, AttributeOperationID.String(operationID))...))
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+type rootSpanKey struct{}
+
+// idGenerator generates random IDs, except the IDs of the operation root spans which are derived from the operation ID
+type idGenerator struct{}
+
+func newIDGenerator() sdktrace.IDGenerator {
+	return idGenerator{}
+}
+
+func (idGenerator) NewIDs(ctx context.Context) (trace.TraceID, trace.SpanID) {
+	if operationID, ok := ctx.Value(rootSpanKey{}).(string); ok {
+		return OperationTraceID(operationID), operationRootSpanID(operationID)
+	}
+	var traceID trace.TraceID
+	for !traceID.IsValid() {
+		binary.NativeEndian.PutUint64(traceID[:8], rand.Uint64())
+		binary.NativeEndian.PutUint64(traceID[8:], rand.Uint64())
+	}
+	return traceID, idGenerator{}.NewSpanID(ctx, traceID)
+}
+
+func (idGenerator) NewSpanID(context.Context, trace.TraceID) trace.SpanID {
+	var spanID trace.SpanID
+	for !spanID.IsValid() {
+		binary.NativeEndian.PutUint64(spanID[:], rand.Uint64())
+	}
+	return spanID
+}
@@ -0,0 +1,130 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/kyma-project/kyma-environment-broker"
+
+// Tracer returns the tracer used for the spans created by KEB
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// Init sets the global tracer provider and the W3C trace context propagator. The returned function flushes
+// the remaining spans and must be called before the application exits. Nothing is set up if tracing is disabled.
+func Init(ctx context.Context, cfg Config, version string) (func(context.Context) error, error) {
+	if !cfg.Enabled {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.ServiceName(cfg.ServiceName),
+		semconv.ServiceVersion(version),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("while creating tracing resource: %w", err)
+	}
+
+	provider, closer, err := NewTracerProvider(ctx, cfg, res)
+	if err != nil {
+		return nil, err
+	}
+
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	return func(ctx context.Context) error {
+		err := provider.Shutdown(ctx)
+		if closer != nil {
+			if cErr := closer.Close(); cErr != nil && err == nil {
+				err = cErr
+			}
+		}
+		return err
+	}, nil
+}
+
+// NewTracerProvider creates the tracer provider exporting spans with the configured exporter, extended with the given options.
+// The returned closer, if not nil, must be closed after the provider is shut down.
+func NewTracerProvider(ctx context.Context, cfg Config, res *resource.Resource, options ...sdktrace.TracerProviderOption) (*sdktrace.TracerProvider, io.Closer, error) {
+	// the sampling decision depends only on the trace ID, which is derived from the operation ID,
+	// so all spans of an operation are sampled in the same way, also after the broker restart
+	sampler := sdktrace.TraceIDRatioBased(cfg.SampleRatio)
+	opts := []sdktrace.TracerProviderOption{
+		sdktrace.WithSampler(sdktrace.ParentBased(sampler, sdktrace.WithRemoteParentSampled(sampler))),
+		sdktrace.WithIDGenerator(newIDGenerator()),
+	}
+	if res != nil {
+		opts = append(opts, sdktrace.WithResource(res))
+	}
+
+	var closer io.Closer
+	var exporter sdktrace.SpanExporter
+	var err error
+	switch cfg.Exporter {
+	case ExporterOTLPHTTP:
+		var httpOpts []otlptracehttp.Option
+		if cfg.Endpoint != "" {
+			httpOpts = append(httpOpts, endpointOption(cfg.Endpoint, otlptracehttp.WithEndpoint, otlptracehttp.WithEndpointURL))
+		}
+		if cfg.Insecure {
+			httpOpts = append(httpOpts, otlptracehttp.WithInsecure())
+		}
+		exporter, err = otlptracehttp.New(ctx, httpOpts...)
+	case ExporterOTLPGRPC:
+		var grpcOpts []otlptracegrpc.Option
+		if cfg.Endpoint != "" {
+			grpcOpts = append(grpcOpts, endpointOption(cfg.Endpoint, otlptracegrpc.WithEndpoint, otlptracegrpc.WithEndpointURL))
+		}
+		if cfg.Insecure {
+			grpcOpts = append(grpcOpts, otlptracegrpc.WithInsecure())
+		}
+		exporter, err = otlptracegrpc.New(ctx, grpcOpts...)
+	case ExporterStdout:
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+	case ExporterFile:
+		var file *os.File
+		file, err = os.OpenFile(cfg.FilePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, nil, fmt.Errorf("while opening traces file %s: %w", cfg.FilePath, err)
+		}
+		closer = file
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(file))
+	case ExporterNone:
+	default:
+		return nil, nil, fmt.Errorf("unknown tracing exporter %q", cfg.Exporter)
+	}
+	if err != nil {
+		if closer != nil {
+			_ = closer.Close()
+		}
+		return nil, nil, fmt.Errorf("while creating %s trace exporter: %w", cfg.Exporter, err)
+	}
+	if exporter != nil {
+		opts = append(opts, sdktrace.WithBatcher(exporter))
+	}
+
+	return sdktrace.NewTracerProvider(append(opts, options...)...), closer, nil
+}
+
+func endpointOption[O any](endpoint string, withEndpoint, withEndpointURL func(string) O) O {
+	if strings.Contains(endpoint, "://") {
+		return withEndpointURL(endpoint)
+	}
+	return withEndpoint(endpoint)
+}
@@ -0,0 +1,139 @@
+package tracing
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+const operationID = "3b2f0c7e-6f1d-4a7b-9a2e-5d8c1e4f7a90"
+
+func setupRecorder(t *testing.T, cfg Config) *tracetest.SpanRecorder {
+	recorder := tracetest.NewSpanRecorder()
+	provider, _, err := NewTracerProvider(context.Background(), cfg, nil, sdktrace.WithSpanProcessor(recorder))
+	require.NoError(t, err)
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() {
+		otel.SetTracerProvider(noop.NewTracerProvider())
+	})
+	return recorder
+}
+
+func TestOperationTraceID(t *testing.T) {
+	t.Run("should use the operation ID if it is a UUID", func(t *testing.T) {
+		assert.Equal(t, "3b2f0c7e6f1d4a7b9a2e5d8c1e4f7a90", OperationTraceID(operationID).String())
+	})
+
+	t.Run("should derive the trace ID from other operation IDs", func(t *testing.T) {
+		traceID := OperationTraceID("op-0001234")
+
+		assert.True(t, traceID.IsValid())
+		assert.Equal(t, traceID, OperationTraceID("op-0001234"))
+		assert.NotEqual(t, traceID, OperationTraceID("op-0001235"))
+	})
+}
+
+func TestOperationContext(t *testing.T) {
+	// given
+	recorder := setupRecorder(t, Config{Exporter: ExporterNone, SampleRatio: 1})
+
+	// when
+	_, span := Tracer().Start(OperationContext(context.Background(), operationID), "step")
+	span.End()
+
+	// then
+	require.Len(t, recorder.Ended(), 1)
+	assert.Equal(t, OperationTraceID(operationID), recorder.Ended()[0].SpanContext().TraceID())
+	assert.Equal(t, operationRootSpanID(operationID), recorder.Ended()[0].Parent().SpanID())
+}
+
+func TestRecordOperation(t *testing.T) {
+	// given
+	recorder := setupRecorder(t, Config{Exporter: ExporterNone, SampleRatio: 1})
+	start := time.Now().Add(-time.Hour)
+
+	// when
+	RecordOperation(context.Background(), operationID, "provision operation", start, fmt.Errorf("step failed"), AttributeInstanceID.String("instance-id"))
+
+	// then
+	require.Len(t, recorder.Ended(), 1)
+	span := recorder.Ended()[0]
+	assert.Equal(t, "provision operation", span.Name())
+	assert.Equal(t, OperationTraceID(operationID), span.SpanContext().TraceID())
+	assert.Equal(t, operationRootSpanID(operationID), span.SpanContext().SpanID())
+	assert.False(t, span.Parent().IsValid())
+	assert.Equal(t, start, span.StartTime())
+	assert.Equal(t, codes.Error, span.Status().Code)
+	assert.Contains(t, span.Attributes(), AttributeOperationID.String(operationID))
+}
+
+func TestSampleRatio(t *testing.T) {
+	// given
+	recorder := setupRecorder(t, Config{Exporter: ExporterNone, SampleRatio: 0})
+
+	// when
+	_, span := Tracer().Start(OperationContext(context.Background(), operationID), "step")
+	span.End()
+	RecordOperation(context.Background(), operationID, "provision operation", time.Now(), nil)
+
+	// then
+	assert.Empty(t, recorder.Ended())
+}
+
+func TestFileExporter(t *testing.T) {
+	// given
+	path := filepath.Join(t.TempDir(), "traces.json")
+	provider, closer, err := NewTracerProvider(context.Background(), Config{Exporter: ExporterFile, FilePath: path, SampleRatio: 1}, nil)
+	require.NoError(t, err)
+
+	// when
+	_, span := provider.Tracer("test").Start(OperationContext(context.Background(), operationID), "step")
+	span.End()
+	require.NoError(t, provider.Shutdown(context.Background()))
+	require.NoError(t, closer.Close())
+
+	// then
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	var exported struct {
+		Name        string
+		SpanContext struct{ TraceID string }
+	}
+	require.NoError(t, json.Unmarshal(content, &exported))
+	assert.Equal(t, "step", exported.Name)
+	assert.Equal(t, OperationTraceID(operationID).String(), exported.SpanContext.TraceID)
+}
+
+func TestConfig_Validate(t *testing.T) {
+	for name, tc := range map[string]struct {
+		cfg   Config
+		valid bool
+	}{
+		"disabled":          {cfg: Config{Exporter: "unknown"}, valid: true},
+		"otlp-http":         {cfg: Config{Enabled: true, Exporter: ExporterOTLPHTTP, SampleRatio: 1}, valid: true},
+		"file without path": {cfg: Config{Enabled: true, Exporter: ExporterFile, SampleRatio: 1}},
+		"unknown exporter":  {cfg: Config{Enabled: true, Exporter: "zipkin", SampleRatio: 1}},
+		"wrong ratio":       {cfg: Config{Enabled: true, Exporter: ExporterStdout, SampleRatio: 2}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			err := tc.cfg.Validate()
+			if tc.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
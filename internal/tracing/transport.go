@@ -0,0 +1,47 @@
+package tracing
+
+import (
+	"net/http"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/trace"
+	"k8s.io/client-go/rest"
+)
+
+// NewTransport wraps the transport to record a client span for every request and to propagate the trace context
+// of the request context to the called service. The default transport is wrapped if base is nil.
+// Requests without a span in the context are passed through, so calls made outside a traced flow (for example, by background jobs)
+// do not start new traces.
+func NewTransport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return otelhttp.NewTransport(base, otelhttp.WithFilter(hasParentSpan))
+}
+
+func hasParentSpan(req *http.Request) bool {
+	return trace.SpanContextFromContext(req.Context()).IsValid()
+}
+
+// NewHTTPClient returns a copy of the client with the transport wrapped by NewTransport.
+func NewHTTPClient(client *http.Client) *http.Client {
+	if client == nil {
+		client = &http.Client{}
+	}
+	wrapped := *client
+	wrapped.Transport = NewTransport(client.Transport)
+	return &wrapped
+}
+
+// NewHandler wraps the handler to record a server span for every request, continuing the trace propagated by the caller.
+func NewHandler(handler http.Handler, operation string) http.Handler {
+	return otelhttp.NewHandler(handler, operation)
+}
+
+// WrapRestConfig makes the Kubernetes clients created from the config record spans of their requests.
+func WrapRestConfig(cfg *rest.Config) *rest.Config {
+	cfg.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return NewTransport(rt)
+	})
+	return cfg
+}
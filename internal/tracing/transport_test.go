@@ -0,0 +1,50 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTransport(t *testing.T) {
+	// given
+	recorder := setupRecorder(t, Config{Exporter: ExporterNone, SampleRatio: 1})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	client := NewHTTPClient(server.Client())
+
+	call := func(ctx context.Context) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+		require.NoError(t, err)
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+	}
+
+	t.Run("should not start a trace without a parent span", func(t *testing.T) {
+		// when
+		call(context.Background())
+
+		// then
+		assert.Empty(t, recorder.Ended())
+	})
+
+	t.Run("should record a child span of the parent span", func(t *testing.T) {
+		// given
+		ctx, parent := Tracer().Start(OperationContext(context.Background(), operationID), "step")
+		defer parent.End()
+
+		// when
+		call(ctx)
+
+		// then
+		require.Len(t, recorder.Ended(), 1)
+		span := recorder.Ended()[0]
+		assert.Equal(t, parent.SpanContext().SpanID(), span.Parent().SpanID())
+		assert.Equal(t, OperationTraceID(operationID), span.SpanContext().TraceID())
+	})
+}
@@ -47,13 +47,18 @@ func NewFromSchema(schema map[string]any) (*jsonschema.Schema, error) {
 }
 
 func FormatError(err error) string {
+	return strings.Join(ErrorMessages(err), ", ")
+}
+
+// ErrorMessages returns messages of all causes of the schema validation error
+func ErrorMessages(err error) []string {
 	var validationError *jsonschema.ValidationError
 	if errors.As(err, &validationError) {
 		var errMsgs []string
 		for _, cause := range validationError.Causes {
 			errMsgs = append(errMsgs, cause.Error())
 		}
-		return strings.Join(errMsgs, ", ")
+		return errMsgs
 	}
-	return "while formatting validation error"
+	return []string{"while formatting validation error"}
 }
@@ -0,0 +1,87 @@
+package webhook
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"slices"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	EventSucceeded = "succeeded"
+	EventFailed    = "failed"
+	EventRetried   = "retried"
+)
+
+var supportedEvents = []string{EventSucceeded, EventFailed, EventRetried}
+
+type Config struct {
+	Enabled           bool          `envconfig:"default=false"`
+	EndpointsFilePath string        `envconfig:"optional"`
+	MaxAttempts       int           `envconfig:"default=8"`
+	InitialBackoff    time.Duration `envconfig:"default=30s"`
+	MaxBackoff        time.Duration `envconfig:"default=30m"`
+	RequestTimeout    time.Duration `envconfig:"default=10s"`
+	PollingInterval   time.Duration `envconfig:"default=15s"`
+	BatchSize         int           `envconfig:"default=50"`
+	// ClaimDuration is the time other KEB instances skip the deliveries claimed for sending, it must cover sending the whole batch
+	ClaimDuration time.Duration `envconfig:"default=10m"`
+}
+
+// Endpoint is a webhook notified about operation lifecycle transitions
+type Endpoint struct {
+	Name string `yaml:"name"`
+	URL  string `yaml:"url"`
+	// Secret is the key used to sign the payloads with HMAC-SHA256
+	Secret string `yaml:"secret"`
+	// Events limits the notifications to the given events, all events are sent if empty
+	Events []string `yaml:"events"`
+}
+
+func (e Endpoint) subscribes(ev string) bool {
+	return len(e.Events) == 0 || slices.Contains(e.Events, ev)
+}
+
+// ReadEndpointsFromFile reads the webhook endpoints from the YAML file
+func ReadEndpointsFromFile(path string) ([]Endpoint, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("while reading webhook endpoints file %s: %w", path, err)
+	}
+	var endpoints []Endpoint
+	if err := yaml.Unmarshal(content, &endpoints); err != nil {
+		return nil, fmt.Errorf("while unmarshalling webhook endpoints: %w", err)
+	}
+	if err := validateEndpoints(endpoints); err != nil {
+		return nil, err
+	}
+	return endpoints, nil
+}
+
+func validateEndpoints(endpoints []Endpoint) error {
+	names := make(map[string]struct{})
+	for _, endpoint := range endpoints {
+		if endpoint.Name == "" {
+			return fmt.Errorf("webhook endpoint name must not be empty")
+		}
+		if _, found := names[endpoint.Name]; found {
+			return fmt.Errorf("webhook endpoint %s is defined more than once", endpoint.Name)
+		}
+		names[endpoint.Name] = struct{}{}
+		if u, err := url.Parse(endpoint.URL); err != nil || u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("webhook endpoint %s has invalid URL %q", endpoint.Name, endpoint.URL)
+		}
+		if endpoint.Secret == "" {
+			return fmt.Errorf("webhook endpoint %s has no secret", endpoint.Name)
+		}
+		for _, ev := range endpoint.Events {
+			if !slices.Contains(supportedEvents, ev) {
+				return fmt.Errorf("webhook endpoint %s has unsupported event %q, supported events: %v", endpoint.Name, ev, supportedEvents)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,326 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/kyma-project/kyma-environment-broker/internal"
+	"github.com/kyma-project/kyma-environment-broker/internal/event"
+	"github.com/kyma-project/kyma-environment-broker/internal/process"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage/dberr"
+	"github.com/pivotal-cf/brokerapi/v12/domain"
+)
+
+const (
+	EventHeader     = "X-KEB-Event"
+	DeliveryHeader  = "X-KEB-Delivery"
+	TimestampHeader = "X-KEB-Timestamp"
+	SignatureHeader = "X-KEB-Signature"
+
+	signaturePrefix = "sha256="
+)
+
+// Payload is the body of the webhook request
+type Payload struct {
+	Event     string    `json:"event"`
+	Timestamp time.Time `json:"timestamp"`
+
+	OperationID   string                    `json:"operationID"`
+	OperationType internal.OperationType    `json:"operationType"`
+	State         domain.LastOperationState `json:"state"`
+	Description   string                    `json:"description"`
+	FailedStep    string                    `json:"failedStep,omitempty"`
+	Error         string                    `json:"error,omitempty"`
+
+	InstanceID      string `json:"instanceID"`
+	RuntimeID       string `json:"runtimeID,omitempty"`
+	PlanID          string `json:"planID"`
+	PlanName        string `json:"planName"`
+	Region          string `json:"region,omitempty"`
+	PlatformRegion  string `json:"platformRegion,omitempty"`
+	GlobalAccountID string `json:"globalAccountID"`
+	SubAccountID    string `json:"subAccountID"`
+}
+
+// PlanNameFunc returns the name of the plan with the given ID or an empty string if the plan is not known
+type PlanNameFunc func(planID string) string
+
+// Notifier stores a delivery for every webhook subscribed to an operation lifecycle transition and sends the deliveries,
+// failed deliveries are retried with an exponential backoff until the maximum number of attempts is reached.
+// Deliveries are claimed before sending, so every delivery is sent by a single KEB instance.
+type Notifier struct {
+	cfg        Config
+	endpoints  map[string]Endpoint
+	deliveries storage.WebhookDeliveries
+	instances  storage.Instances
+	planName   PlanNameFunc
+	owner      string
+	client     *http.Client
+	trigger    chan struct{}
+	log        *slog.Logger
+}
+
+func NewNotifier(cfg Config, endpoints []Endpoint, deliveries storage.WebhookDeliveries, instances storage.Instances, planName PlanNameFunc, sub event.Subscriber, log *slog.Logger) *Notifier {
+	owner, err := os.Hostname()
+	if err != nil {
+		owner = uuid.NewString()
+	}
+	n := &Notifier{
+		cfg:        cfg,
+		endpoints:  make(map[string]Endpoint, len(endpoints)),
+		deliveries: deliveries,
+		instances:  instances,
+		planName:   planName,
+		owner:      owner,
+		client:     &http.Client{Timeout: cfg.RequestTimeout},
+		trigger:    make(chan struct{}, 1),
+		log:        log.With("service", "WebhookNotifier"),
+	}
+	for _, endpoint := range endpoints {
+		n.endpoints[endpoint.Name] = endpoint
+	}
+	sub.Subscribe(process.OperationFinished{}, n.onOperationFinished)
+	sub.Subscribe(process.OperationRetried{}, n.onOperationRetried)
+	return n
+}
+
+// Run sends the pending deliveries periodically and right after new deliveries are stored, until the context is done
+func (n *Notifier) Run(ctx context.Context) {
+	ticker := time.NewTicker(n.cfg.PollingInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-n.trigger:
+		}
+		n.SendPending(ctx)
+	}
+}
+
+// SendPending claims and sends the deliveries which are due
+func (n *Notifier) SendPending(ctx context.Context) {
+	now := time.Now()
+	pending, err := n.deliveries.ClaimPending(n.owner, now, now.Add(n.cfg.ClaimDuration), n.cfg.BatchSize)
+	if err != nil {
+		n.log.Error(fmt.Sprintf("unable to claim pending webhook deliveries: %s", err))
+		return
+	}
+	for _, delivery := range pending {
+		if ctx.Err() != nil {
+			return
+		}
+		n.send(ctx, delivery)
+	}
+}
+
+func (n *Notifier) onOperationFinished(_ context.Context, ev interface{}) error {
+	e, ok := ev.(process.OperationFinished)
+	if !ok {
+		return fmt.Errorf("expected process.OperationFinished but got %+v", ev)
+	}
+	switch e.Operation.State {
+	case domain.Succeeded:
+		return n.notify(EventSucceeded, e.Operation)
+	case domain.Failed:
+		return n.notify(EventFailed, e.Operation)
+	default:
+		return nil
+	}
+}
+
+func (n *Notifier) onOperationRetried(_ context.Context, ev interface{}) error {
+	e, ok := ev.(process.OperationRetried)
+	if !ok {
+		return fmt.Errorf("expected process.OperationRetried but got %+v", ev)
+	}
+	return n.notify(EventRetried, e.Operation)
+}
+
+func (n *Notifier) notify(ev string, operation internal.Operation) error {
+	var subscribed []Endpoint
+	for _, endpoint := range n.endpoints {
+		if endpoint.subscribes(ev) {
+			subscribed = append(subscribed, endpoint)
+		}
+	}
+	if len(subscribed) == 0 {
+		return nil
+	}
+
+	payload, err := json.Marshal(n.payload(ev, operation))
+	if err != nil {
+		return fmt.Errorf("while marshalling webhook payload for operation %s: %w", operation.ID, err)
+	}
+
+	now := time.Now()
+	for _, endpoint := range subscribed {
+		err := n.deliveries.Insert(internal.WebhookDelivery{
+			ID:            uuid.NewString(),
+			Webhook:       endpoint.Name,
+			Event:         ev,
+			InstanceID:    operation.InstanceID,
+			OperationID:   operation.ID,
+			Payload:       string(payload),
+			State:         internal.WebhookDeliveryPending,
+			NextAttemptAt: now,
+			CreatedAt:     now,
+			UpdatedAt:     now,
+		})
+		if err != nil {
+			return fmt.Errorf("while storing %s webhook delivery for operation %s: %w", endpoint.Name, operation.ID, err)
+		}
+	}
+
+	select {
+	case n.trigger <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+func (n *Notifier) payload(ev string, operation internal.Operation) Payload {
+	payload := Payload{
+		Event:           ev,
+		Timestamp:       time.Now(),
+		OperationID:     operation.ID,
+		OperationType:   operation.Type,
+		State:           operation.State,
+		Description:     operation.Description,
+		InstanceID:      operation.InstanceID,
+		RuntimeID:       operation.RuntimeID,
+		PlanID:          operation.ProvisioningParameters.PlanID,
+		PlanName:        n.planName(operation.ProvisioningParameters.PlanID),
+		PlatformRegion:  operation.ProvisioningParameters.PlatformRegion,
+		GlobalAccountID: operation.ProvisioningParameters.ErsContext.GlobalAccountID,
+		SubAccountID:    operation.ProvisioningParameters.ErsContext.SubAccountID,
+	}
+	if operation.ProviderValues != nil {
+		payload.Region = operation.ProviderValues.Region
+	}
+	if operation.State == domain.Failed {
+		payload.FailedStep = operation.LastError.Step
+		payload.Error = operation.LastError.Error()
+	}
+
+	// the instance contains the current data, it does not exist anymore after a successful deprovisioning
+	instance, err := n.instances.GetByID(operation.InstanceID)
+	switch {
+	case err == nil:
+		payload.RuntimeID = instance.RuntimeID
+		payload.PlanID = instance.ServicePlanID
+		payload.PlanName = instance.ServicePlanName
+		payload.GlobalAccountID = instance.GlobalAccountID
+		payload.SubAccountID = instance.SubAccountID
+		if instance.ProviderRegion != "" {
+			payload.Region = instance.ProviderRegion
+		}
+		if instance.Parameters.PlatformRegion != "" {
+			payload.PlatformRegion = instance.Parameters.PlatformRegion
+		}
+	case !dberr.IsNotFound(err):
+		n.log.Warn(fmt.Sprintf("unable to get instance %s, webhook payload is built from the operation: %s", operation.InstanceID, err))
+	}
+	return payload
+}
+
+func (n *Notifier) send(ctx context.Context, delivery internal.WebhookDelivery) {
+	logger := n.log.With("deliveryID", delivery.ID, "webhook", delivery.Webhook, "operationID", delivery.OperationID)
+
+	endpoint, found := n.endpoints[delivery.Webhook]
+	if !found {
+		delivery.State = internal.WebhookDeliveryFailed
+		delivery.LastError = "webhook is not configured"
+		n.update(delivery, logger)
+		return
+	}
+
+	delivery.Attempts++
+	err := n.post(ctx, endpoint, delivery)
+	switch {
+	case err == nil:
+		delivery.State = internal.WebhookDeliveryDelivered
+		delivery.LastError = ""
+		logger.Info(fmt.Sprintf("%s webhook delivered after %d attempt(s)", delivery.Event, delivery.Attempts))
+	case delivery.Attempts >= n.cfg.MaxAttempts:
+		delivery.State = internal.WebhookDeliveryFailed
+		delivery.LastError = err.Error()
+		logger.Error(fmt.Sprintf("%s webhook delivery failed after %d attempts: %s", delivery.Event, delivery.Attempts, err))
+	default:
+		delivery.LastError = err.Error()
+		delivery.NextAttemptAt = time.Now().Add(n.backoff(delivery.Attempts))
+		logger.Warn(fmt.Sprintf("%s webhook delivery attempt %d failed, next attempt at %s: %s", delivery.Event, delivery.Attempts, delivery.NextAttemptAt.Format(time.RFC3339), err))
+	}
+	n.update(delivery, logger)
+}
+
+func (n *Notifier) update(delivery internal.WebhookDelivery, logger *slog.Logger) {
+	err := n.deliveries.Update(delivery)
+	switch {
+	case dberr.IsConflict(err):
+		logger.Warn(fmt.Sprintf("webhook delivery was claimed by another instance, the result is not stored: %s", err))
+	case err != nil:
+		logger.Error(fmt.Sprintf("unable to update webhook delivery: %s", err))
+	}
+}
+
+func (n *Notifier) post(ctx context.Context, endpoint Endpoint, delivery internal.WebhookDelivery) error {
+	body := []byte(delivery.Payload)
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("while creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, delivery.Event)
+	req.Header.Set(DeliveryHeader, delivery.ID)
+	req.Header.Set(TimestampHeader, timestamp)
+	req.Header.Set(SignatureHeader, signaturePrefix+Sign(endpoint.Secret, timestamp, body))
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("while calling webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("webhook responded with status code %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (n *Notifier) backoff(attempts int) time.Duration {
+	backoff := n.cfg.InitialBackoff
+	for i := 1; i < attempts; i++ {
+		backoff *= 2
+		if backoff >= n.cfg.MaxBackoff {
+			return n.cfg.MaxBackoff
+		}
+	}
+	return backoff
+}
+
+// Sign returns the hex encoded HMAC-SHA256 of the timestamp and the body joined with a dot
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,336 @@
+package webhook_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kyma-project/kyma-environment-broker/internal"
+	kebError "github.com/kyma-project/kyma-environment-broker/internal/error"
+	"github.com/kyma-project/kyma-environment-broker/internal/event"
+	"github.com/kyma-project/kyma-environment-broker/internal/fixture"
+	"github.com/kyma-project/kyma-environment-broker/internal/process"
+	"github.com/kyma-project/kyma-environment-broker/internal/storage"
+	"github.com/kyma-project/kyma-environment-broker/internal/webhook"
+	"github.com/pivotal-cf/brokerapi/v12/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+const (
+	instanceID  = "instance-id"
+	operationID = "operation-id"
+	secret      = "webhook-secret"
+)
+
+func TestNotifier(t *testing.T) {
+	t.Run("should send signed notification about succeeded operation", func(t *testing.T) {
+		// given
+		server := newWebhookServer(http.StatusOK)
+		defer server.Close()
+		db, pubSub, notifier := fixNotifier(t, []webhook.Endpoint{{Name: "alerting", URL: server.URL, Secret: secret}})
+
+		operation := fixture.FixProvisioningOperation(operationID, instanceID)
+		operation.State = domain.Succeeded
+		operation.Description = "Processing finished"
+
+		// when
+		pubSub.Publish(context.Background(), process.OperationFinished{Operation: operation})
+		deliveries := waitForDeliveries(t, db, 1)
+		notifier.SendPending(context.Background())
+
+		// then
+		requests := server.Requests()
+		require.Len(t, requests, 1)
+		req := requests[0]
+		assert.Equal(t, webhook.EventSucceeded, req.header.Get(webhook.EventHeader))
+		assert.Equal(t, deliveries[0].ID, req.header.Get(webhook.DeliveryHeader))
+		assert.Equal(t, "sha256="+webhook.Sign(secret, req.header.Get(webhook.TimestampHeader), req.body), req.header.Get(webhook.SignatureHeader))
+
+		var payload webhook.Payload
+		require.NoError(t, json.Unmarshal(req.body, &payload))
+		assert.Equal(t, webhook.EventSucceeded, payload.Event)
+		assert.Equal(t, operationID, payload.OperationID)
+		assert.Equal(t, instanceID, payload.InstanceID)
+		assert.Equal(t, "Processing finished", payload.Description)
+		assert.Equal(t, fixture.PlanId, payload.PlanID)
+		assert.Equal(t, fixture.PlanName, payload.PlanName)
+		assert.Equal(t, "eastus2", payload.Region)
+		assert.Equal(t, fixture.GlobalAccountId, payload.GlobalAccountID)
+		assert.Empty(t, payload.FailedStep)
+
+		deliveries, err := db.WebhookDeliveries().ListByOperationID(operationID)
+		require.NoError(t, err)
+		require.Len(t, deliveries, 1)
+		assert.Equal(t, internal.WebhookDeliveryDelivered, deliveries[0].State)
+		assert.Equal(t, 1, deliveries[0].Attempts)
+	})
+
+	t.Run("should send failed step only to webhooks subscribed to failed operations", func(t *testing.T) {
+		// given
+		server := newWebhookServer(http.StatusOK)
+		defer server.Close()
+		db, pubSub, notifier := fixNotifier(t, []webhook.Endpoint{
+			{Name: "alerting", URL: server.URL, Secret: secret, Events: []string{webhook.EventFailed}},
+			{Name: "chat", URL: server.URL, Secret: secret, Events: []string{webhook.EventSucceeded}},
+		})
+
+		operation := fixture.FixProvisioningOperation(operationID, instanceID)
+		operation.State = domain.Failed
+		operation.LastError = kebError.LastError{Message: "runtime resource not ready", Step: "Check_RuntimeResource_Create"}
+
+		// when
+		pubSub.Publish(context.Background(), process.OperationFinished{Operation: operation})
+		deliveries := waitForDeliveries(t, db, 1)
+		notifier.SendPending(context.Background())
+
+		// then
+		assert.Equal(t, "alerting", deliveries[0].Webhook)
+		requests := server.Requests()
+		require.Len(t, requests, 1)
+		var payload webhook.Payload
+		require.NoError(t, json.Unmarshal(requests[0].body, &payload))
+		assert.Equal(t, webhook.EventFailed, payload.Event)
+		assert.Equal(t, "Check_RuntimeResource_Create", payload.FailedStep)
+		assert.Equal(t, "runtime resource not ready", payload.Error)
+	})
+
+	t.Run("should send delivery only once if several notifiers share the storage", func(t *testing.T) {
+		// given
+		server := newWebhookServer(http.StatusOK)
+		defer server.Close()
+		db, pubSub, notifier := fixNotifier(t, []webhook.Endpoint{{Name: "alerting", URL: server.URL, Secret: secret}})
+		log := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}))
+		otherNotifier := webhook.NewNotifier(webhook.Config{MaxAttempts: 3, RequestTimeout: time.Second, BatchSize: 10, ClaimDuration: time.Minute},
+			[]webhook.Endpoint{{Name: "alerting", URL: server.URL, Secret: secret}}, db.WebhookDeliveries(), db.Instances(), func(string) string { return "" }, event.NewPubSub(log), log)
+
+		operation := fixture.FixProvisioningOperation(operationID, instanceID)
+		operation.State = domain.Succeeded
+
+		// when
+		pubSub.Publish(context.Background(), process.OperationFinished{Operation: operation})
+		waitForDeliveries(t, db, 1)
+		var wg sync.WaitGroup
+		for _, n := range []*webhook.Notifier{notifier, otherNotifier, notifier, otherNotifier} {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				n.SendPending(context.Background())
+			}()
+		}
+		wg.Wait()
+
+		// then
+		assert.Len(t, server.Requests(), 1)
+	})
+
+	t.Run("should retry failed delivery with backoff", func(t *testing.T) {
+		// given
+		server := newWebhookServer(http.StatusInternalServerError)
+		defer server.Close()
+		db, pubSub, notifier := fixNotifier(t, []webhook.Endpoint{{Name: "alerting", URL: server.URL, Secret: secret}})
+
+		operation := fixture.FixProvisioningOperation(operationID, instanceID)
+
+		// when
+		pubSub.Publish(context.Background(), process.OperationRetried{Operation: operation})
+		waitForDeliveries(t, db, 1)
+		notifier.SendPending(context.Background())
+
+		// then
+		deliveries, err := db.WebhookDeliveries().ListByOperationID(operationID)
+		require.NoError(t, err)
+		assert.Equal(t, internal.WebhookDeliveryPending, deliveries[0].State)
+		assert.Equal(t, 1, deliveries[0].Attempts)
+		assert.Equal(t, "webhook responded with status code 500", deliveries[0].LastError)
+		assert.True(t, deliveries[0].NextAttemptAt.After(time.Now()))
+
+		// when
+		notifier.SendPending(context.Background())
+
+		// then
+		assert.Len(t, server.Requests(), 1, "the delivery must not be sent before the backoff elapses")
+
+		// when
+		server.SetStatusCode(http.StatusNoContent)
+		time.Sleep(20 * time.Millisecond)
+		notifier.SendPending(context.Background())
+
+		// then
+		deliveries, err = db.WebhookDeliveries().ListByOperationID(operationID)
+		require.NoError(t, err)
+		assert.Equal(t, internal.WebhookDeliveryDelivered, deliveries[0].State)
+		assert.Equal(t, 2, deliveries[0].Attempts)
+		assert.Len(t, server.Requests(), 2)
+	})
+
+	t.Run("should stop retrying after maximum number of attempts", func(t *testing.T) {
+		// given
+		server := newWebhookServer(http.StatusBadGateway)
+		defer server.Close()
+		db, pubSub, notifier := fixNotifier(t, []webhook.Endpoint{{Name: "alerting", URL: server.URL, Secret: secret}})
+
+		operation := fixture.FixProvisioningOperation(operationID, instanceID)
+
+		// when
+		pubSub.Publish(context.Background(), process.OperationRetried{Operation: operation})
+		waitForDeliveries(t, db, 1)
+		for i := 0; i < 5; i++ {
+			notifier.SendPending(context.Background())
+			time.Sleep(20 * time.Millisecond)
+		}
+
+		// then
+		deliveries, err := db.WebhookDeliveries().ListByOperationID(operationID)
+		require.NoError(t, err)
+		assert.Equal(t, internal.WebhookDeliveryFailed, deliveries[0].State)
+		assert.Equal(t, 3, deliveries[0].Attempts)
+		assert.Len(t, server.Requests(), 3)
+	})
+}
+
+func TestReadEndpointsFromFile(t *testing.T) {
+	for name, tc := range map[string]struct {
+		content       string
+		expectedError string
+	}{
+		"valid endpoints": {
+			content: `
+- name: alerting
+  url: https://alerting.example.com/hooks/keb
+  secret: secret-1
+  events: [failed, retried]
+- name: chat
+  url: https://chat.example.com/hooks/keb
+  secret: secret-2
+`,
+		},
+		"unsupported event": {
+			content: `
+- name: alerting
+  url: https://alerting.example.com/hooks/keb
+  secret: secret-1
+  events: [canceled]
+`,
+			expectedError: `webhook endpoint alerting has unsupported event "canceled"`,
+		},
+		"missing secret": {
+			content: `
+- name: alerting
+  url: https://alerting.example.com/hooks/keb
+`,
+			expectedError: "webhook endpoint alerting has no secret",
+		},
+		"duplicated name": {
+			content: `
+- name: alerting
+  url: https://alerting.example.com/hooks/keb
+  secret: secret-1
+- name: alerting
+  url: https://chat.example.com/hooks/keb
+  secret: secret-2
+`,
+			expectedError: "webhook endpoint alerting is defined more than once",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			// given
+			path := filepath.Join(t.TempDir(), "webhooks.yaml")
+			require.NoError(t, os.WriteFile(path, []byte(tc.content), 0600))
+
+			// when
+			endpoints, err := webhook.ReadEndpointsFromFile(path)
+
+			// then
+			if tc.expectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, endpoints, 2)
+			assert.Equal(t, []string{webhook.EventFailed, webhook.EventRetried}, endpoints[0].Events)
+			assert.Equal(t, "secret-2", endpoints[1].Secret)
+		})
+	}
+}
+
+func fixNotifier(t *testing.T, endpoints []webhook.Endpoint) (storage.BrokerStorage, *event.PubSub, *webhook.Notifier) {
+	log := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}))
+	db := storage.NewMemoryStorage()
+	instance := fixture.FixInstance(instanceID)
+	instance.ProviderRegion = "eastus2"
+	require.NoError(t, db.Instances().Insert(instance))
+
+	pubSub := event.NewPubSub(log)
+	notifier := webhook.NewNotifier(webhook.Config{
+		MaxAttempts:    3,
+		InitialBackoff: 10 * time.Millisecond,
+		MaxBackoff:     10 * time.Millisecond,
+		RequestTimeout: time.Second,
+		BatchSize:      10,
+		ClaimDuration:  time.Minute,
+	}, endpoints, db.WebhookDeliveries(), db.Instances(), func(string) string { return fixture.PlanName }, pubSub, log)
+	return db, pubSub, notifier
+}
+
+func waitForDeliveries(t *testing.T, db storage.BrokerStorage, count int) []internal.WebhookDelivery {
+	var deliveries []internal.WebhookDelivery
+	err := wait.PollUntilContextTimeout(context.Background(), 10*time.Millisecond, 2*time.Second, true, func(ctx context.Context) (bool, error) {
+		var err error
+		deliveries, err = db.WebhookDeliveries().ListByOperationID(operationID)
+		return len(deliveries) == count, err
+	})
+	require.NoError(t, err)
+	return deliveries
+}
+
+type receivedRequest struct {
+	header http.Header
+	body   []byte
+}
+
+type webhookServer struct {
+	*httptest.Server
+	mu         sync.Mutex
+	statusCode int
+	requests   []receivedRequest
+}
+
+func newWebhookServer(statusCode int) *webhookServer {
+	s := &webhookServer{statusCode: statusCode}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if !strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+			w.WriteHeader(http.StatusUnsupportedMediaType)
+			return
+		}
+		s.requests = append(s.requests, receivedRequest{header: r.Header.Clone(), body: body})
+		w.WriteHeader(s.statusCode)
+	}))
+	return s
+}
+
+func (s *webhookServer) SetStatusCode(statusCode int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.statusCode = statusCode
+}
+
+func (s *webhookServer) Requests() []receivedRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]receivedRequest{}, s.requests...)
+}
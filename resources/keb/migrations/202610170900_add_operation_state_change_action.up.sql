@@ -0,0 +1 @@
+ALTER TYPE action_type ADD VALUE IF NOT EXISTS 'operation_state_change';
@@ -0,0 +1,21 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id              varchar(255) NOT NULL PRIMARY KEY,
+    webhook         varchar(255) NOT NULL,
+    event           varchar(64) NOT NULL,
+    instance_id     varchar(255) NOT NULL,
+    operation_id    varchar(255) NOT NULL,
+    payload         text NOT NULL,
+    state           varchar(32) NOT NULL,
+    attempts        integer NOT NULL DEFAULT 0,
+    last_error      text NOT NULL DEFAULT '',
+    next_attempt_at timestamp with time zone NOT NULL,
+    created_at      timestamp with time zone NOT NULL,
+    updated_at      timestamp with time zone NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS webhook_deliveries_state_next_attempt_at ON webhook_deliveries USING btree (state, next_attempt_at);
+CREATE INDEX IF NOT EXISTS webhook_deliveries_operation_id ON webhook_deliveries USING btree (operation_id);
+
+COMMIT;
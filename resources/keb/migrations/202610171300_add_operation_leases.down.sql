@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS operations_by_lease_owner;
+
+ALTER TABLE operations
+    DROP COLUMN IF EXISTS lease_owner,
+    DROP COLUMN IF EXISTS lease_heartbeat_at,
+    DROP COLUMN IF EXISTS lease_expires_at;
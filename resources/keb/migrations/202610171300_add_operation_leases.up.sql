@@ -0,0 +1,6 @@
+ALTER TABLE operations
+    ADD COLUMN IF NOT EXISTS lease_owner varchar(255),
+    ADD COLUMN IF NOT EXISTS lease_heartbeat_at timestamp with time zone,
+    ADD COLUMN IF NOT EXISTS lease_expires_at timestamp with time zone;
+
+CREATE INDEX IF NOT EXISTS operations_by_lease_owner ON operations USING btree (lease_owner);
@@ -0,0 +1,7 @@
+BEGIN;
+
+DROP INDEX IF EXISTS events_created_at;
+DROP INDEX IF EXISTS events_instance_id_created_at;
+DROP TABLE events_archived;
+
+COMMIT;
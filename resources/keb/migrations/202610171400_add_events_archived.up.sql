@@ -0,0 +1,17 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS events_archived (
+    id           varchar(255) NOT NULL PRIMARY KEY,
+    level        event_level NOT NULL,
+    instance_id  varchar(255),
+    operation_id varchar(255),
+    message      text NOT NULL,
+    created_at   timestamp with time zone NOT NULL,
+    archived_at  timestamp with time zone NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS events_archived_instance_id ON events_archived USING btree (instance_id);
+CREATE INDEX IF NOT EXISTS events_instance_id_created_at ON events USING btree (instance_id, created_at);
+CREATE INDEX IF NOT EXISTS events_created_at ON events USING btree (created_at);
+
+COMMIT;
@@ -0,0 +1,2 @@
+ALTER TABLE bindings
+    ADD COLUMN IF NOT EXISTS scope text NOT NULL DEFAULT '';
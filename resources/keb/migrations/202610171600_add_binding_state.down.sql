@@ -0,0 +1,3 @@
+ALTER TABLE bindings
+    DROP COLUMN IF EXISTS state,
+    DROP COLUMN IF EXISTS description;
@@ -0,0 +1,3 @@
+ALTER TABLE bindings
+    ADD COLUMN IF NOT EXISTS state varchar(32) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS description text NOT NULL DEFAULT '';
@@ -0,0 +1 @@
+alter table instances add column if not exists "expiration_extensions" integer default 0;
@@ -0,0 +1,10 @@
+BEGIN;
+
+DELETE FROM actions WHERE type IN ('expiration_warning', 'expiration_extension');
+
+ALTER TYPE action_type RENAME TO action_type_old;
+CREATE TYPE action_type AS ENUM ('plan_update', 'subaccount_movement', 'operation_state_change', 'kubeconfig_download');
+ALTER TABLE actions ALTER COLUMN type TYPE action_type USING type::text::action_type;
+DROP TYPE action_type_old;
+
+COMMIT;
@@ -0,0 +1,2 @@
+ALTER TYPE action_type ADD VALUE IF NOT EXISTS 'expiration_warning';
+ALTER TYPE action_type ADD VALUE IF NOT EXISTS 'expiration_extension';
@@ -0,0 +1,22 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS quota_reservations (
+    instance_id    varchar(255) NOT NULL,
+    sub_account_id varchar(255) NOT NULL,
+    plan_id        varchar(255) NOT NULL,
+    operation_id   varchar(255) NOT NULL DEFAULT '',
+    state          varchar(32) NOT NULL,
+    created_at     timestamp with time zone NOT NULL,
+    updated_at     timestamp with time zone NOT NULL,
+    PRIMARY KEY (instance_id, plan_id)
+);
+
+CREATE INDEX IF NOT EXISTS quota_reservations_sub_account_id_plan_id ON quota_reservations USING btree (sub_account_id, plan_id);
+CREATE INDEX IF NOT EXISTS quota_reservations_operation_id ON quota_reservations USING btree (operation_id);
+
+INSERT INTO quota_reservations (instance_id, sub_account_id, plan_id, operation_id, state, created_at, updated_at)
+SELECT instance_id, sub_account_id, service_plan_id, '', 'confirmed', now(), now()
+FROM instances
+ON CONFLICT DO NOTHING;
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP TABLE IF EXISTS subaccount_sync_processed_events;
+DROP TABLE IF EXISTS subaccount_sync_cursor;
+
+COMMIT;
@@ -0,0 +1,20 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS subaccount_sync_cursor (
+    id                     varchar(255) PRIMARY KEY,
+    last_event_id          BIGINT NOT NULL DEFAULT 0,
+    last_event_action_time BIGINT NOT NULL DEFAULT 0,
+    events_synced_at       BIGINT NOT NULL DEFAULT 0,
+    accounts_synced_at     BIGINT NOT NULL DEFAULT 0
+);
+
+CREATE TABLE IF NOT EXISTS subaccount_sync_processed_events (
+    event_id       BIGINT PRIMARY KEY,
+    sub_account_id varchar(255) NOT NULL,
+    action_time    BIGINT NOT NULL,
+    processed_at   BIGINT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS subaccount_sync_processed_events_action_time ON subaccount_sync_processed_events USING btree (action_time);
+
+COMMIT;
@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE subaccount_states DROP COLUMN IF EXISTS attributes;
+
+COMMIT;
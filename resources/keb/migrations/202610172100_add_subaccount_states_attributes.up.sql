@@ -0,0 +1,5 @@
+BEGIN;
+
+ALTER TABLE subaccount_states ADD COLUMN IF NOT EXISTS attributes TEXT NOT NULL DEFAULT '{}';
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE webhook_deliveries DROP COLUMN IF EXISTS claimed_until;
+ALTER TABLE webhook_deliveries DROP COLUMN IF EXISTS claimed_by;
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE webhook_deliveries ADD COLUMN IF NOT EXISTS claimed_by varchar(255);
+ALTER TABLE webhook_deliveries ADD COLUMN IF NOT EXISTS claimed_until timestamp with time zone;
+
+COMMIT;
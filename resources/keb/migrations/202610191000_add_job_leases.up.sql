@@ -0,0 +1,9 @@
+BEGIN;
+
+CREATE TABLE IF NOT EXISTS job_leases (
+    name varchar(255) PRIMARY KEY,
+    owner varchar(255) NOT NULL,
+    expires_at timestamp with time zone NOT NULL
+);
+
+COMMIT;
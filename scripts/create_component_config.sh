@@ -25,4 +25,5 @@ images:
   - europe-docker.pkg.dev/kyma-project/prod/kyma-environment-subaccount-sync:${TAG}
   - europe-docker.pkg.dev/kyma-project/prod/kyma-environment-broker-schema-migrator:${TAG}
   - europe-docker.pkg.dev/kyma-project/prod/kyma-environment-service-binding-cleanup-job:${TAG}
+  - europe-docker.pkg.dev/kyma-project/prod/kyma-environment-events-retention-job:${TAG}
 EOF
@@ -25,6 +25,7 @@ declare -a IMAGES=(
     "kyma-environment-subaccount-sync:Dockerfile.subaccountsync:BIN=subaccount-sync"
     "kyma-environment-broker-schema-migrator:Dockerfile.schemamigrator:"
     "kyma-environment-service-binding-cleanup-job:Dockerfile.job:BIN=servicebindingcleanup"
+    "kyma-environment-events-retention-job:Dockerfile.job:BIN=eventsretention"
     "keb-analytics:Dockerfile.keb-analytics:VERSION=${VERSION}"
 )
 